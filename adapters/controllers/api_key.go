@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type APIKeyController struct {
+	ports.APIKeyService
+}
+
+// Create godoc
+// @Summary Create an API key
+// @Description Issue a new API key for a client. The key is only returned in this response; store it right away. Requires the apikeys:manage permission.
+// @ID create-api-key
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAPIKeyRequest true "Name, scopes and optional expiry"
+// @Success 201 {object} dto.WebResponse{data=dto.CreateAPIKeyResponse} "API key created"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload or expiry in the past"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 403 {object} dto.WebResponse "Forbidden"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /admin/api-keys [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *APIKeyController) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	var req dto.CreateAPIKeyRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	key := &entities.APIKey{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if userID, ok := ctx.Value(middleware.UserIDKey).(string); ok {
+		if id, err := uuid.Parse(userID); err == nil {
+			key.CreatedBy = &id
+		}
+	}
+
+	result, plain, err := c.APIKeyService.Create(ctx, key)
+	if err != nil {
+		logger.Error("Failed to create api key:", err)
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "API key created, store it now as it will not be shown again",
+		Status:  1,
+		Data: dto.CreateAPIKeyResponse{
+			APIKeyResponse: toAPIKeyResponse(result),
+			Key:            plain,
+		},
+	}, http.StatusCreated)
+}
+
+// List godoc
+// @Summary List API keys
+// @Description List every API key, including revoked and expired ones. Keys are identified by their prefix. Requires the apikeys:manage permission.
+// @ID list-api-keys
+// @Tags API Keys
+// @Produce json
+// @Success 200 {object} dto.WebResponse{data=[]dto.APIKeyResponse} "API keys"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 403 {object} dto.WebResponse "Forbidden"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /admin/api-keys [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *APIKeyController) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	keys, err := c.APIKeyService.List(ctx)
+	if err != nil {
+		logger.Error("Failed to list api keys:", err)
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	data := make([]dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		data[i] = toAPIKeyResponse(key)
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "success get api keys",
+		Status:  1,
+		Data:    data,
+	}, http.StatusOK)
+}
+
+// Revoke godoc
+// @Summary Revoke an API key
+// @Description Revoke an API key. Requests made with it are rejected right away. Requires the apikeys:manage permission.
+// @ID revoke-api-key
+// @Tags API Keys
+// @Produce json
+// @Param keyId path string true "API key ID (UUID)"
+// @Success 200 {object} dto.WebResponse "API key revoked"
+// @Failure 400 {object} dto.WebResponse "Invalid key ID"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 403 {object} dto.WebResponse "Forbidden"
+// @Failure 404 {object} dto.WebResponse "API key not found or already revoked"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /admin/api-keys/{keyId} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *APIKeyController) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	keyIdStr := r.PathValue("keyId")
+	keyId, err := uuid.Parse(keyIdStr)
+	if err != nil {
+		logger.Warnf("Invalid keyId UUID: %s", keyIdStr)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid keyId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.APIKeyService.Revoke(ctx, keyId); err != nil {
+		logger.Error("Failed to revoke api key:", err)
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "API key revoked",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+func toAPIKeyResponse(key *entities.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func writeAPIKeyError(w http.ResponseWriter, err error) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: appErr.Message,
+			Status:  0,
+			Data:    nil,
+		}, int64(appErr.StatusCode))
+		return
+	}
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "An unexpected error occurred",
+		Status:  0,
+		Data:    nil,
+	}, http.StatusInternalServerError)
+}
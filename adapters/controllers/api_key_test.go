@@ -0,0 +1,118 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyController_Create_Success(t *testing.T) {
+	mockService := new(mocks.MockAPIKeyService)
+	controller := &controllers.APIKeyController{
+		APIKeyService: mockService,
+	}
+
+	adminID := uuid.New()
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.UserIDKey, adminID.String())
+
+	body, _ := json.Marshal(dto.CreateAPIKeyRequest{Name: "billing", Scopes: []string{"read"}})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/api-keys", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	req = req.WithContext(ctx)
+
+	created := &entities.APIKey{ID: uuid.New(), Name: "billing", Prefix: "gbk_abcdefgh", Scopes: []string{"read"}, CreatedAt: time.Now()}
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(key *entities.APIKey) bool {
+		return key.Name == "billing" && key.CreatedBy != nil && *key.CreatedBy == adminID
+	})).Return(created, "gbk_abcdefgh-rest", nil).Once()
+
+	controller.Create(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var response struct {
+		Data dto.CreateAPIKeyResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "gbk_abcdefgh-rest", response.Data.Key)
+	assert.Equal(t, created.ID.String(), response.Data.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIKeyController_Create_InvalidScope(t *testing.T) {
+	mockService := new(mocks.MockAPIKeyService)
+	controller := &controllers.APIKeyController{
+		APIKeyService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/api-keys", bytes.NewReader([]byte(`{"name":"billing","scopes":["admin"]}`)))
+	rec := httptest.NewRecorder()
+	req = req.WithContext(ctx)
+
+	controller.Create(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "Create")
+}
+
+func TestAPIKeyController_List(t *testing.T) {
+	mockService := new(mocks.MockAPIKeyService)
+	controller := &controllers.APIKeyController{
+		APIKeyService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/api-keys", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(ctx)
+
+	mockService.On("List", mock.Anything).Return([]*entities.APIKey{
+		{ID: uuid.New(), Name: "billing", Prefix: "gbk_abcdefgh", KeyHash: "secret-hash"},
+	}, nil).Once()
+
+	controller.List(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "gbk_abcdefgh")
+	assert.NotContains(t, rec.Body.String(), "secret-hash")
+}
+
+func TestAPIKeyController_Revoke_NotFound(t *testing.T) {
+	mockService := new(mocks.MockAPIKeyService)
+	controller := &controllers.APIKeyController{
+		APIKeyService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	keyID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/admin/api-keys/"+keyID.String(), nil)
+	req.SetPathValue("keyId", keyID.String())
+	rec := httptest.NewRecorder()
+	req = req.WithContext(ctx)
+
+	mockService.On("Revoke", mock.Anything, keyID).Return(appErrors.NewNotFoundError("API key not found or already revoked", nil)).Once()
+
+	controller.Revoke(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type AuditController struct {
+	ports.AuditService
+}
+
+// List godoc
+// @Summary Query the audit log
+// @Description List audit events, newest first. Every filter is optional. Requires the audit:read permission.
+// @ID list-audit-events
+// @Tags Audit
+// @Produce json
+// @Param actor_id query string false "User who acted (UUID)"
+// @Param impersonator_id query string false "Admin who impersonated the actor (UUID)"
+// @Param action query string false "Action, e.g. auth.sign_in or post.delete"
+// @Param target_type query string false "Target type, user or post"
+// @Param target_id query string false "Target ID"
+// @Param outcome query string false "success or failure"
+// @Param from query string false "Earliest time, RFC 3339"
+// @Param to query string false "Latest time (exclusive), RFC 3339"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Events per page (default: 50, max: 200)"
+// @Success 200 {object} dto.WebResponse{data=dto.AuditEventListResponse} "Audit events"
+// @Failure 400 {object} dto.WebResponse "Invalid filter"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 403 {object} dto.WebResponse "Forbidden"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /audit [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *AuditController) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	filter, err := auditFilterFromQuery(r)
+	if err != nil {
+		writeAuditError(w, err)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	events, total, err := c.AuditService.List(ctx, filter, page, limit)
+	if err != nil {
+		logger.Error("Failed to list audit events:", err)
+		writeAuditError(w, err)
+		return
+	}
+
+	data := dto.AuditEventListResponse{
+		Events: make([]dto.AuditEventResponse, len(events)),
+		Page:   page,
+		Limit:  limit,
+		Total:  total,
+	}
+	for i, event := range events {
+		data.Events[i] = dto.AuditEventResponse{
+			ID:             event.ID.String(),
+			OccurredAt:     event.OccurredAt,
+			ActorID:        event.ActorID,
+			ImpersonatorID: event.ImpersonatorID,
+			Action:         event.Action,
+			TargetType:     event.TargetType,
+			TargetID:       event.TargetID,
+			IP:             event.IP,
+			RequestID:      event.RequestID,
+			Outcome:        event.Outcome,
+			Details:        event.Details,
+		}
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "success get audit events",
+		Status:  1,
+		Data:    data,
+	}, http.StatusOK)
+}
+
+func auditFilterFromQuery(r *http.Request) (entities.AuditEventFilter, error) {
+	query := r.URL.Query()
+	filter := entities.AuditEventFilter{
+		ActorID:        query.Get("actor_id"),
+		ImpersonatorID: query.Get("impersonator_id"),
+		Action:         query.Get("action"),
+		TargetType:     query.Get("target_type"),
+		TargetID:       query.Get("target_id"),
+		Outcome:        query.Get("outcome"),
+	}
+
+	if filter.ActorID != "" {
+		actorID, err := uuid.Parse(filter.ActorID)
+		if err != nil {
+			return filter, appErrors.NewBadRequestError("Invalid actor_id format", err)
+		}
+		filter.ActorID = actorID.String()
+	}
+	if filter.ImpersonatorID != "" {
+		impersonatorID, err := uuid.Parse(filter.ImpersonatorID)
+		if err != nil {
+			return filter, appErrors.NewBadRequestError("Invalid impersonator_id format", err)
+		}
+		filter.ImpersonatorID = impersonatorID.String()
+	}
+	if filter.Outcome != "" && filter.Outcome != entities.AuditOutcomeSuccess && filter.Outcome != entities.AuditOutcomeFailure {
+		return filter, appErrors.NewBadRequestError("outcome must be success or failure", nil)
+	}
+
+	var err error
+	if v := query.Get("from"); v != "" {
+		if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, appErrors.NewBadRequestError("Invalid from time, expected RFC 3339", err)
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, appErrors.NewBadRequestError("Invalid to time, expected RFC 3339", err)
+		}
+	}
+
+	return filter, nil
+}
+
+func writeAuditError(w http.ResponseWriter, err error) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: appErr.Message,
+			Status:  0,
+			Data:    nil,
+		}, int64(appErr.StatusCode))
+		return
+	}
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "An unexpected error occurred",
+		Status:  0,
+		Data:    nil,
+	}, http.StatusInternalServerError)
+}
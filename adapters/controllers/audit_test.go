@@ -0,0 +1,121 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditController_List_Filters(t *testing.T) {
+	mockService := new(mocks.MockAuditService)
+	controller := &controllers.AuditController{
+		AuditService: mockService,
+	}
+
+	actorID := uuid.NewString()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/audit?actor_id="+actorID+"&action=auth.sign_in&outcome=failure&from=2026-01-01T00:00:00Z&page=2&limit=20", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+
+	filter := entities.AuditEventFilter{
+		ActorID: actorID,
+		Action:  entities.AuditActionSignIn,
+		Outcome: entities.AuditOutcomeFailure,
+		From:    from,
+	}
+	mockService.On("List", mock.Anything, filter, 2, 20).Return([]*entities.AuditEvent{
+		{ID: uuid.New(), Action: entities.AuditActionSignIn, Outcome: entities.AuditOutcomeFailure, Details: map[string]string{"reason": "invalid_password"}},
+	}, 21, nil).Once()
+
+	controller.List(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data dto.AuditEventListResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 21, response.Data.Total)
+	assert.Equal(t, 2, response.Data.Page)
+	assert.Len(t, response.Data.Events, 1)
+	assert.Equal(t, "invalid_password", response.Data.Events[0].Details["reason"])
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditController_List_NormalizesIDs(t *testing.T) {
+	mockService := new(mocks.MockAuditService)
+	controller := &controllers.AuditController{
+		AuditService: mockService,
+	}
+
+	actorID := uuid.New()
+	impersonatorID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/audit?actor_id="+strings.ToUpper(actorID.String())+"&impersonator_id="+strings.ToUpper(impersonatorID.String()), nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+
+	mockService.On("List", mock.Anything, entities.AuditEventFilter{ActorID: actorID.String(), ImpersonatorID: impersonatorID.String()}, 1, mock.Anything).
+		Return([]*entities.AuditEvent{}, 0, nil).Once()
+
+	controller.List(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditController_List_ClampsLimit(t *testing.T) {
+	mockService := new(mocks.MockAuditService)
+	controller := &controllers.AuditController{
+		AuditService: mockService,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/audit?limit=1000", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+
+	mockService.On("List", mock.Anything, entities.AuditEventFilter{}, 1, 200).Return([]*entities.AuditEvent{}, 0, nil).Once()
+
+	controller.List(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data dto.AuditEventListResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 200, response.Data.Limit)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditController_List_InvalidFilter(t *testing.T) {
+	mockService := new(mocks.MockAuditService)
+	controller := &controllers.AuditController{
+		AuditService: mockService,
+	}
+
+	for _, query := range []string{"actor_id=nope", "outcome=maybe", "from=yesterday"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit?"+query, nil)
+		rec := httptest.NewRecorder()
+		req = req.WithContext(context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+
+		controller.List(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+	mockService.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,15 +2,20 @@ package controllers
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
 	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
 	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/domain/ports"
 	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
 	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
@@ -26,8 +31,10 @@ type AuthController struct {
 // @Accept json
 // @Produce json
 // @Param user body dto.AuthSignInRequest true "SignIn request"
-// @Success 200 {object} dto.WebResponse{data=dto.AuthResponse} "Successfully signed in user"
+// @Success 200 {object} dto.WebResponse{data=dto.AuthResponse} "Successfully signed in user, or dto.MFAChallengeResponse when two-factor is enabled"
 // @Failure 400 {object} dto.WebResponse "Invalid request payload or failed to sign in"
+// @Failure 403 {object} dto.WebResponse "Email address is not verified"
+// @Failure 429 {object} dto.WebResponse "Too many failed attempts, retry after the Retry-After header"
 // @Router /signin [post]
 // @Security ApiKeyAuth
 func (c *AuthController) SignIn(w http.ResponseWriter, r *http.Request) {
@@ -50,9 +57,21 @@ func (c *AuthController) SignIn(w http.ResponseWriter, r *http.Request) {
 		Password: req.Password,
 	}
 
-	user, token, err := c.AuthService.SignIn(ctx, payload)
+	user, tokens, err := c.AuthService.SignIn(ctx, payload)
 	if err != nil {
 		logger.Error("Failed to authenticate user:", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) && (appErr.StatusCode == http.StatusForbidden || appErr.StatusCode == http.StatusTooManyRequests) {
+			if appErr.RetryAfter > 0 {
+				setRetryAfter(w, appErr.RetryAfter)
+			}
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
 		helper.WriteResponse(w, dto.WebResponse{
 			Message: "Invalid email or password",
 			Status:  0,
@@ -61,15 +80,95 @@ func (c *AuthController) SignIn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if tokens.MFAToken != "" {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Two-factor authentication required",
+			Status:  1,
+			Data: dto.MFAChallengeResponse{
+				MFARequired: true,
+				MFAToken:    tokens.MFAToken,
+			},
+		}, http.StatusOK)
+		return
+	}
+
+	resp := dto.WebResponse{
+		Message: "Successfully signed in",
+		Status:  1,
+		Data: dto.AuthResponse{
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			User: dto.UserResponse{
+				Id:        user.ID.String(),
+				Email:     user.Email,
+				CreatedAt: user.CreatedAt,
+				Roles:     user.Roles,
+			},
+		},
+	}
+	helper.WriteResponse(w, &resp, http.StatusOK)
+}
+
+// SignInMFA godoc
+// @Summary Complete sign in with a second factor
+// @Description Exchange the mfa_token returned by /signin and an authenticator or recovery code for access and refresh tokens.
+// @ID auth-signin-mfa
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.SignInMFARequest true "Second factor request"
+// @Success 200 {object} dto.WebResponse{data=dto.AuthResponse} "Successfully signed in user"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload"
+// @Failure 401 {object} dto.WebResponse "Invalid code or expired two-factor session"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /signin/mfa [post]
+// @Security ApiKeyAuth
+func (c *AuthController) SignInMFA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	var req dto.SignInMFARequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	user, tokens, err := c.AuthService.SignInMFA(ctx, req.MFAToken, req.Code)
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to complete two-factor sign in:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
 	resp := dto.WebResponse{
 		Message: "Successfully signed in",
 		Status:  1,
 		Data: dto.AuthResponse{
-			Token: token,
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
 			User: dto.UserResponse{
 				Id:        user.ID.String(),
 				Email:     user.Email,
 				CreatedAt: user.CreatedAt,
+				Roles:     user.Roles,
 			},
 		},
 	}
@@ -145,7 +244,88 @@ func (c *AuthController) SignUp(w http.ResponseWriter, r *http.Request) {
 		Password: req.Password,
 	}
 
-	user, token, err := c.AuthService.SignUp(ctx, payload)
+	user, tokens, err := c.AuthService.SignUp(ctx, payload)
+	if err != nil {
+		var validationErr *appErrors.ValidationErrors
+		if errors.As(err, &validationErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: strings.Join(validationErr.Messages, ", "),
+				Status:  0,
+				Data:    nil,
+			}, http.StatusBadRequest)
+			return
+		}
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		} else {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: "An unexpected error occurred",
+				Status:  0,
+				Data:    nil,
+			}, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	data := dto.AuthResponse{
+		User: dto.UserResponse{
+			Id:        user.ID.String(),
+			Email:     user.Email,
+			CreatedAt: user.CreatedAt,
+			Roles:     user.Roles,
+		},
+	}
+	message := "Successfully signed up"
+	if tokens != nil {
+		data.Token = tokens.AccessToken
+		data.RefreshToken = tokens.RefreshToken
+	} else {
+		message = "Successfully signed up, check your email to verify your account"
+	}
+
+	resp := dto.WebResponse{
+		Message: message,
+		Status:  1,
+		Data:    data,
+	}
+	helper.WriteResponse(w, &resp, http.StatusCreated)
+}
+
+// Refresh godoc
+// @Summary Refresh an access token
+// @Description Exchange a refresh token for a new access and refresh token pair. Each refresh token can be used once.
+// @ID auth-refresh
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "Refresh request"
+// @Success 200 {object} dto.WebResponse{data=dto.TokenResponse} "Successfully refreshed token"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload"
+// @Failure 401 {object} dto.WebResponse "Invalid, expired or revoked refresh token"
+// @Router /refresh [post]
+// @Security ApiKeyAuth
+func (c *AuthController) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	var req dto.RefreshTokenRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := c.AuthService.Refresh(ctx, req.RefreshToken)
 	if err != nil {
 		var appErr *appErrors.AppError
 		if errors.As(err, &appErr) {
@@ -166,16 +346,488 @@ func (c *AuthController) SignUp(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := dto.WebResponse{
-		Message: "Successfully signed up",
+		Message: "Successfully refreshed token",
+		Status:  1,
+		Data: dto.TokenResponse{
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+		},
+	}
+	helper.WriteResponse(w, &resp, http.StatusOK)
+}
+
+// SignOut godoc
+// @Summary Sign out the current session
+// @Description Revoke the current access token and every refresh token issued with it.
+// @ID auth-signout
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dto.WebResponse "Successfully signed out"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /signout [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *AuthController) SignOut(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	claims, ok := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	if err := c.AuthService.SignOut(ctx, claims); err != nil {
+		logger.Error("Failed to sign out:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully signed out",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset code
+// @Description Send a one-time password reset code to the email address. The response is the same whether or not the address belongs to an account.
+// @ID auth-forgot-password
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Forgot password request"
+// @Success 200 {object} dto.WebResponse "Reset code sent if the account exists"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload"
+// @Failure 429 {object} dto.WebResponse "A reset code was sent recently"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /password/forgot [post]
+// @Security ApiKeyAuth
+func (c *AuthController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	var req dto.ForgotPasswordRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.AuthService.ForgotPassword(ctx, req.Email); err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to request password reset:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "If the account exists, a reset code has been sent",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+// ResetPassword godoc
+// @Summary Reset password with a code
+// @Description Set a new password using the code sent by /password/forgot. The code can be used once, and every existing session is signed out.
+// @ID auth-reset-password
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Reset password request"
+// @Success 200 {object} dto.WebResponse "Successfully reset password"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload or invalid code"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /password/reset [post]
+// @Security ApiKeyAuth
+func (c *AuthController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	var req dto.ResetPasswordRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.AuthService.ResetPassword(ctx, req.Email, req.Code, req.Password); err != nil {
+		var validationErr *appErrors.ValidationErrors
+		if errors.As(err, &validationErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: strings.Join(validationErr.Messages, ", "),
+				Status:  0,
+				Data:    nil,
+			}, http.StatusBadRequest)
+			return
+		}
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to reset password:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully reset password",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+// VerifyEmail godoc
+// @Summary Verify an email address
+// @Description Confirm ownership of an email address with the link sent on sign up. This endpoint does not require the API key, so the link can be opened from a mail client.
+// @ID auth-verify-email
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} dto.WebResponse "Successfully verified email"
+// @Failure 400 {object} dto.WebResponse "Invalid or expired verification link"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /verify-email [get]
+func (c *AuthController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Missing verification token",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.AuthService.VerifyEmail(ctx, token); err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to verify email:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully verified email",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+// ResendVerification godoc
+// @Summary Resend the verification email
+// @Description Send a new verification link. Limited to one email per address per interval; the response does not reveal whether the address has an account.
+// @ID auth-resend-verification
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResendVerificationRequest true "Resend verification request"
+// @Success 200 {object} dto.WebResponse "Verification email sent if the account needs it"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload"
+// @Failure 429 {object} dto.WebResponse "Verification email was sent recently"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /verify-email/resend [post]
+// @Security ApiKeyAuth
+func (c *AuthController) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	var req dto.ResendVerificationRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.AuthService.ResendVerification(ctx, req.Email); err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to resend verification email:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "If the account needs verification, a new email has been sent",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+// StartSocialLogin godoc
+// @Summary Start signing in with an identity provider
+// @Description Return the URL of the identity provider to send the user to. The provider redirects back to /auth/oidc/{provider}/callback.
+// @ID auth-oidc-start
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Identity provider name"
+// @Success 200 {object} dto.WebResponse{data=dto.SocialLoginResponse} "Authorization URL"
+// @Failure 404 {object} dto.WebResponse "Unknown identity provider"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /auth/oidc/{provider} [get]
+// @Security ApiKeyAuth
+func (c *AuthController) StartSocialLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	authURL, err := c.AuthService.StartSocialLogin(ctx, r.PathValue("provider"))
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to start social login:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Redirect the user to the authorization URL",
+		Status:  1,
+		Data:    dto.SocialLoginResponse{AuthorizationURL: authURL},
+	}, http.StatusOK)
+}
+
+// CompleteSocialLogin godoc
+// @Summary Finish signing in with an identity provider
+// @Description Redirect target of the identity provider. Links the external account to the user with the same verified email, or creates one, and returns tokens. This endpoint does not require the API key, since the browser is redirected here by the provider.
+// @ID auth-oidc-callback
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Identity provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State returned by the provider"
+// @Success 200 {object} dto.WebResponse{data=dto.AuthResponse} "Successfully signed in user, or dto.MFAChallengeResponse when two-factor is enabled"
+// @Failure 400 {object} dto.WebResponse "Missing code or invalid state"
+// @Failure 401 {object} dto.WebResponse "Sign in with the identity provider failed"
+// @Failure 403 {object} dto.WebResponse "The identity provider did not confirm the email address"
+// @Failure 404 {object} dto.WebResponse "Unknown identity provider"
+// @Failure 409 {object} dto.WebResponse "An unverified account with the same email exists"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /auth/oidc/{provider}/callback [get]
+func (c *AuthController) CompleteSocialLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		logger.Warnf("Identity provider returned error: %s", providerErr)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Sign in with the identity provider failed",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+	if query.Get("code") == "" || query.Get("state") == "" {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Missing code or state",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	user, tokens, err := c.AuthService.CompleteSocialLogin(ctx, r.PathValue("provider"), query.Get("code"), query.Get("state"))
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to complete social login:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	if tokens.MFAToken != "" {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Two-factor authentication required",
+			Status:  1,
+			Data: dto.MFAChallengeResponse{
+				MFARequired: true,
+				MFAToken:    tokens.MFAToken,
+			},
+		}, http.StatusOK)
+		return
+	}
+
+	resp := dto.WebResponse{
+		Message: "Successfully signed in",
 		Status:  1,
 		Data: dto.AuthResponse{
-			Token: token,
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
 			User: dto.UserResponse{
 				Id:        user.ID.String(),
 				Email:     user.Email,
 				CreatedAt: user.CreatedAt,
+				Roles:     user.Roles,
 			},
 		},
 	}
-	helper.WriteResponse(w, &resp, http.StatusCreated)
+	helper.WriteResponse(w, &resp, http.StatusOK)
+}
+
+// Impersonate godoc
+// @Summary Impersonate a user
+// @Description Issue a short-lived access token to act as the user for support. The token carries the user's permissions and names the admin; deleting anything, changing the account, and managing tokens are refused while impersonating, and every request is logged. No refresh token is issued.
+// @ID auth-impersonate
+// @Tags Admin
+// @Produce json
+// @Param userId path string true "User ID (UUID)"
+// @Success 200 {object} dto.WebResponse{data=dto.ImpersonationResponse} "Impersonation token"
+// @Failure 400 {object} dto.WebResponse "Invalid user ID, or impersonating yourself"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 403 {object} dto.WebResponse "Missing users:impersonate, or the user cannot be impersonated"
+// @Failure 404 {object} dto.WebResponse "User not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /admin/impersonate/{userId} [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *AuthController) Impersonate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	impersonatorID, ok := ctx.Value(middleware.UserIDKey).(string)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	userId := r.PathValue("userId")
+	if _, err := uuid.Parse(userId); err != nil {
+		logger.Warn("Invalid userId UUID:", userId)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid userId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	token, err := c.AuthService.Impersonate(ctx, impersonatorID, userId)
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to impersonate user:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Impersonation started",
+		Status:  1,
+		Data: dto.ImpersonationResponse{
+			Token:          token,
+			UserID:         userId,
+			ImpersonatorID: impersonatorID,
+		},
+	}, http.StatusOK)
+}
+
+// setRetryAfter sets the Retry-After header in whole seconds, rounded up.
+func setRetryAfter(w http.ResponseWriter, d time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
 }
@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type CommentController struct {
+	ports.CommentService
+}
+
+// CreateComment godoc
+// @Summary Comment on a post
+// @Description Adds a comment to a post, or a reply to one of its comments with parent_id. Anyone signed in who can read the post may comment.
+// @ID create-comment
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Param request body dto.CreateCommentRequest true "Comment"
+// @Success 201 {object} dto.WebResponse{data=dto.CommentResponse} "The new comment"
+// @Failure 400 {object} dto.WebResponse "Empty or too long comment"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 404 {object} dto.WebResponse "Post or parent comment not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/comments [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *CommentController) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postId, ok := postIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if !commentPayload(w, r, logger, &req) {
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	result, err := c.CommentService.Create(ctx, claims, &entities.Comment{
+		PostID:   postId,
+		ParentID: req.ParentID,
+		Body:     req.Body,
+	})
+	if err != nil {
+		logger.Error("Failed to create comment:", err)
+		writePostError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully Create comment",
+		Status:  1,
+		Data:    commentResponse(result),
+	}, http.StatusCreated)
+}
+
+// ListComments godoc
+// @Summary List the comments of a post
+// @Description Returns a page of the comments of a post, oldest first. The flat shape pages through every comment; the tree shape pages through top-level comments, each with its oldest 50 replies nested below it and a reply_count of all of them. Deleted comments stay as placeholders without body and author.
+// @ID list-comments
+// @Tags Comments
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Param shape query string false "flat (default) or tree" Enums(flat, tree)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Comments, or top-level comments for a tree, per page (default: 20, max: 100)"
+// @Success 200 {object} dto.WebResponse{data=dto.CommentPageResponse} "A page of comments"
+// @Failure 400 {object} dto.WebResponse "Unknown shape or invalid cursor"
+// @Failure 404 {object} dto.WebResponse "Post not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/comments [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *CommentController) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postId, ok := postIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var tree bool
+	switch r.URL.Query().Get("shape") {
+	case "", "flat":
+	case "tree":
+		tree = true
+	default:
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "shape must be flat or tree",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	page, err := c.CommentService.List(ctx, claims, postId, r.URL.Query().Get("cursor"), limit, tree)
+	if err != nil {
+		logger.Error("Failed to list comments:", err)
+		writePostError(w, err)
+		return
+	}
+
+	data := dto.CommentPageResponse{
+		Comments:   commentResponses(page.Comments),
+		NextCursor: page.NextCursor,
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully Get comments",
+		Status:  1,
+		Data:    data,
+	}, http.StatusOK)
+}
+
+// UpdateComment godoc
+// @Summary Edit a comment
+// @Description Replaces the body of a comment. Only its author may edit it.
+// @ID update-comment
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Param commentId path string true "ID of the comment"
+// @Param request body dto.UpdateCommentRequest true "New body"
+// @Success 200 {object} dto.WebResponse{data=dto.CommentResponse} "The edited comment"
+// @Failure 400 {object} dto.WebResponse "Empty or too long comment"
+// @Failure 403 {object} dto.WebResponse "Not the author of the comment"
+// @Failure 404 {object} dto.WebResponse "Comment not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/comments/{commentId} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *CommentController) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postId, ok := postIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	commentId, ok := commentIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	if !commentPayload(w, r, logger, &req) {
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	result, err := c.CommentService.Update(ctx, claims, postId, commentId, req.Body)
+	if err != nil {
+		logger.Error("Failed to update comment:", err)
+		writePostError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully Update comment",
+		Status:  1,
+		Data:    commentResponse(result),
+	}, http.StatusOK)
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description Deletes a comment, leaving a placeholder so that its replies stay in the thread. Its author, or an admin, may delete it.
+// @ID delete-comment
+// @Tags Comments
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Param commentId path string true "ID of the comment"
+// @Success 200 {object} dto.WebResponse "Comment deleted"
+// @Failure 403 {object} dto.WebResponse "Not the author of the comment"
+// @Failure 404 {object} dto.WebResponse "Comment not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/comments/{commentId} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *CommentController) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postId, ok := postIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	commentId, ok := commentIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	if err := c.CommentService.Delete(ctx, claims, postId, commentId); err != nil {
+		logger.Error("Failed to delete comment:", err)
+		writePostError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully Delete comment",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+func commentIDFromPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	commentId, err := uuid.Parse(r.PathValue("commentId"))
+	if err != nil {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid commentId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return commentId, true
+}
+
+// commentPayload reads the request body into req. It writes the error response and returns
+// false when the body is not valid.
+func commentPayload(w http.ResponseWriter, r *http.Request, logger logrus.FieldLogger, req interface{}) bool {
+	err := helper.GetPayload(r, req)
+	if err == nil {
+		return true
+	}
+
+	var validationErr *appErrors.ValidationErrors
+	if errors.As(err, &validationErr) {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: strings.Join(validationErr.Messages, ", "),
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return false
+	}
+
+	logger.Warn("Failed to get payload:", err)
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Failed to process request payload",
+		Status:  0,
+		Data:    nil,
+	}, http.StatusBadRequest)
+	return false
+}
+
+func commentResponse(comment *entities.Comment) dto.CommentResponse {
+	resp := dto.CommentResponse{
+		ID:         comment.ID,
+		PostID:     comment.PostID,
+		ParentID:   comment.ParentID,
+		Body:       comment.Body,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+		Deleted:    comment.DeletedAt != nil,
+		Replies:    commentResponses(comment.Replies),
+		ReplyCount: comment.ReplyCount,
+	}
+	if comment.AuthorID != uuid.Nil {
+		resp.AuthorID = comment.AuthorID.String()
+	}
+	return resp
+}
+
+func commentResponses(comments []*entities.Comment) []dto.CommentResponse {
+	if comments == nil {
+		return nil
+	}
+	resp := make([]dto.CommentResponse, len(comments))
+	for i, comment := range comments {
+		resp[i] = commentResponse(comment)
+	}
+	return resp
+}
@@ -0,0 +1,110 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newCommentRequest(method, target, body string, postID uuid.UUID, claims *entities.TokenClaims) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("postId", postID.String())
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.ClaimsKey, claims)
+	return req.WithContext(ctx)
+}
+
+func TestCommentController_Create(t *testing.T) {
+	mockService := new(mocks.MockCommentService)
+	controller := &controllers.CommentController{CommentService: mockService}
+
+	postID := uuid.New()
+	parentID := uuid.New()
+	authorID := uuid.New()
+	claims := &entities.TokenClaims{UserID: authorID.String()}
+	mockService.On("Create", mock.Anything, claims, &entities.Comment{PostID: postID, ParentID: &parentID, Body: "Nice"}).
+		Return(&entities.Comment{ID: uuid.New(), PostID: postID, ParentID: &parentID, AuthorID: authorID, Body: "Nice", CreatedAt: time.Now()}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	controller.Create(rec, newCommentRequest(http.MethodPost, "/post/x/comments", `{"body":"Nice","parent_id":"`+parentID.String()+`"}`, postID, claims))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"author_id":"`+authorID.String()+`"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestCommentController_Create_EmptyBody(t *testing.T) {
+	mockService := new(mocks.MockCommentService)
+	controller := &controllers.CommentController{CommentService: mockService}
+
+	rec := httptest.NewRecorder()
+	controller.Create(rec, newCommentRequest(http.MethodPost, "/post/x/comments", `{"body":""}`, uuid.New(), nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentController_List_Tree(t *testing.T) {
+	mockService := new(mocks.MockCommentService)
+	controller := &controllers.CommentController{CommentService: mockService}
+
+	postID := uuid.New()
+	deletedAt := time.Now()
+	root := &entities.Comment{ID: uuid.New(), PostID: postID, DeletedAt: &deletedAt}
+	root.Replies = []*entities.Comment{{ID: uuid.New(), PostID: postID, ParentID: &root.ID, AuthorID: uuid.New(), Body: "reply"}}
+	mockService.On("List", mock.Anything, (*entities.TokenClaims)(nil), postID, "abc", 5, true).
+		Return(&entities.CommentPage{Comments: []*entities.Comment{root}, NextCursor: "next"}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	controller.List(rec, newCommentRequest(http.MethodGet, "/post/x/comments?shape=tree&cursor=abc&limit=5", "", postID, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `"deleted":true`)
+	assert.Contains(t, body, `"body":"reply"`)
+	assert.Contains(t, body, `"next_cursor":"next"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestCommentController_List_UnknownShape(t *testing.T) {
+	mockService := new(mocks.MockCommentService)
+	controller := &controllers.CommentController{CommentService: mockService}
+
+	rec := httptest.NewRecorder()
+	controller.List(rec, newCommentRequest(http.MethodGet, "/post/x/comments?shape=graph", "", uuid.New(), nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCommentController_Delete_Forbidden(t *testing.T) {
+	mockService := new(mocks.MockCommentService)
+	controller := &controllers.CommentController{CommentService: mockService}
+
+	postID := uuid.New()
+	commentID := uuid.New()
+	mockService.On("Delete", mock.Anything, mock.Anything, postID, commentID).
+		Return(appErrors.NewForbiddenError("You are not allowed to modify this comment", nil)).Once()
+
+	req := newCommentRequest(http.MethodDelete, "/post/x/comments/y", "", postID, nil)
+	req.SetPathValue("commentId", commentID.String())
+	rec := httptest.NewRecorder()
+	controller.Delete(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+)
+
+type JWKSController struct {
+	ports.TokenManager
+}
+
+// JWKS godoc
+// @Summary Get the JSON Web Key Set
+// @Description Public keys used to verify access and refresh tokens. Retired keys stay listed until their tokens expire.
+// @ID jwks
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dto.JWKSResponse "JSON Web Key Set"
+// @Router /.well-known/jwks.json [get]
+func (c *JWKSController) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	helper.WriteResponse(w, dto.JWKSResponse{
+		Keys: c.TokenManager.JWKS(),
+	}, http.StatusOK)
+}
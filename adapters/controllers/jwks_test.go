@@ -0,0 +1,38 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKSController_JWKS(t *testing.T) {
+	mockTokenManager := new(mocks.MockTokenManager)
+	controller := &controllers.JWKSController{
+		TokenManager: mockTokenManager,
+	}
+
+	keys := []entities.JSONWebKey{
+		{KeyType: "OKP", KeyID: "current", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "abc"},
+	}
+	mockTokenManager.On("JWKS").Return(keys)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+
+	controller.JWKS(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response dto.JWKSResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, keys, response.Keys)
+	mockTokenManager.AssertExpectations(t)
+}
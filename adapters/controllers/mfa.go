@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type MFAController struct {
+	ports.MFAService
+}
+
+// Enroll godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret and recovery codes for the current user. Two-factor stays off until confirmed with /mfa/verify. The recovery codes are only shown once.
+// @ID mfa-enroll
+// @Tags MFA
+// @Produce json
+// @Success 200 {object} dto.WebResponse{data=dto.MFAEnrollResponse} "Enrollment started"
+// @Failure 400 {object} dto.WebResponse "Two-factor authentication is already enabled"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /mfa/enroll [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *MFAController) Enroll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(string)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	enrollment, err := c.MFAService.Enroll(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to enroll two-factor:", err)
+		writeMFAError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Scan the URI with an authenticator app and confirm with a code",
+		Status:  1,
+		Data: dto.MFAEnrollResponse{
+			Secret:        enrollment.Secret,
+			OTPAuthURI:    enrollment.URI,
+			RecoveryCodes: enrollment.RecoveryCodes,
+		},
+	}, http.StatusOK)
+}
+
+// Verify godoc
+// @Summary Confirm two-factor enrollment
+// @Description Turn two-factor authentication on with a code from the authenticator app.
+// @ID mfa-verify
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param request body dto.MFACodeRequest true "Authenticator code"
+// @Success 200 {object} dto.WebResponse "Two-factor authentication enabled"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload or invalid code"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /mfa/verify [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *MFAController) Verify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(string)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.MFAService.Verify(ctx, userID, req.Code); err != nil {
+		logger.Error("Failed to verify two-factor enrollment:", err)
+		writeMFAError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Two-factor authentication enabled",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+// Disable godoc
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off. Requires a current authenticator code or a recovery code.
+// @ID mfa-disable
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param request body dto.MFACodeRequest true "Authenticator or recovery code"
+// @Success 200 {object} dto.WebResponse "Two-factor authentication disabled"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload or invalid code"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /mfa/disable [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *MFAController) Disable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(string)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.MFAService.Disable(ctx, userID, req.Code); err != nil {
+		logger.Error("Failed to disable two-factor:", err)
+		writeMFAError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Two-factor authentication disabled",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+func writeMFAError(w http.ResponseWriter, err error) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: appErr.Message,
+			Status:  0,
+			Data:    nil,
+		}, int64(appErr.StatusCode))
+		return
+	}
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "An unexpected error occurred",
+		Status:  0,
+		Data:    nil,
+	}, http.StatusInternalServerError)
+}
@@ -0,0 +1,112 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMFAController_Enroll_Success(t *testing.T) {
+	mockService := new(mocks.MockMFAService)
+	controller := &controllers.MFAController{
+		MFAService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.UserIDKey, "user-1")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/mfa/enroll", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(ctx)
+
+	mockService.On("Enroll", mock.Anything, "user-1").Return(&entities.MFAEnrollment{
+		Secret:        "JBSWY3DPEHPK3PXP",
+		URI:           "otpauth://totp/App:user@mail.com?secret=JBSWY3DPEHPK3PXP",
+		RecoveryCodes: []string{"abcde-fghjk"},
+	}, nil)
+
+	controller.Enroll(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data dto.MFAEnrollResponse `json:"data"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", response.Data.Secret)
+	assert.Equal(t, []string{"abcde-fghjk"}, response.Data.RecoveryCodes)
+}
+
+func TestMFAController_Enroll_Unauthorized(t *testing.T) {
+	mockService := new(mocks.MockMFAService)
+	controller := &controllers.MFAController{
+		MFAService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/mfa/enroll", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(ctx)
+
+	controller.Enroll(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNotCalled(t, "Enroll", mock.Anything, mock.Anything)
+}
+
+func TestMFAController_Verify_InvalidCode(t *testing.T) {
+	mockService := new(mocks.MockMFAService)
+	controller := &controllers.MFAController{
+		MFAService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.UserIDKey, "user-1")
+
+	bodyBytes, _ := json.Marshal(&dto.MFACodeRequest{Code: "000000"})
+	req := httptest.NewRequest(http.MethodPost, "/api/mfa/verify", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	req = req.WithContext(ctx)
+
+	mockService.On("Verify", mock.Anything, "user-1", "000000").Return(appErrors.NewBadRequestError("Invalid authentication code", nil))
+
+	controller.Verify(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestMFAController_Disable_Success(t *testing.T) {
+	mockService := new(mocks.MockMFAService)
+	controller := &controllers.MFAController{
+		MFAService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.UserIDKey, "user-1")
+
+	bodyBytes, _ := json.Marshal(&dto.MFACodeRequest{Code: "abcde-fghjk"})
+	req := httptest.NewRequest(http.MethodPost, "/api/mfa/disable", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	req = req.WithContext(ctx)
+
+	mockService.On("Disable", mock.Anything, "user-1", "abcde-fghjk").Return(nil)
+
+	controller.Disable(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
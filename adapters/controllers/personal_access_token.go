@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type PersonalAccessTokenController struct {
+	ports.PersonalAccessTokenService
+}
+
+// Create godoc
+// @Summary Create a personal access token
+// @Description Mint a token for scripts, sent as "Authorization: Bearer <token>" in place of a session token. Scopes are permissions the current user holds. The token is only returned in this response; store it right away. Requires a session token.
+// @ID create-personal-access-token
+// @Tags Personal Access Tokens
+// @Accept json
+// @Produce json
+// @Param request body dto.CreatePersonalAccessTokenRequest true "Name, scopes and optional expiry"
+// @Success 201 {object} dto.WebResponse{data=dto.CreatePersonalAccessTokenResponse} "Personal access token created"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload, scope not granted or expiry in the past"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 403 {object} dto.WebResponse "Forbidden"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /tokens [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *PersonalAccessTokenController) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(string)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.CreatePersonalAccessTokenRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	result, plain, err := c.PersonalAccessTokenService.Create(ctx, userID, &entities.PersonalAccessToken{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		logger.Error("Failed to create personal access token:", err)
+		writePersonalAccessTokenError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Personal access token created, store it now as it will not be shown again",
+		Status:  1,
+		Data: dto.CreatePersonalAccessTokenResponse{
+			PersonalAccessTokenResponse: toPersonalAccessTokenResponse(result),
+			Token:                       plain,
+		},
+	}, http.StatusCreated)
+}
+
+// List godoc
+// @Summary List personal access tokens
+// @Description List the personal access tokens of the current user, including revoked and expired ones. Tokens are identified by their prefix.
+// @ID list-personal-access-tokens
+// @Tags Personal Access Tokens
+// @Produce json
+// @Success 200 {object} dto.WebResponse{data=[]dto.PersonalAccessTokenResponse} "Personal access tokens"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /tokens [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *PersonalAccessTokenController) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(string)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := c.PersonalAccessTokenService.List(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list personal access tokens:", err)
+		writePersonalAccessTokenError(w, err)
+		return
+	}
+
+	data := make([]dto.PersonalAccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		data[i] = toPersonalAccessTokenResponse(token)
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "success get personal access tokens",
+		Status:  1,
+		Data:    data,
+	}, http.StatusOK)
+}
+
+// Revoke godoc
+// @Summary Revoke a personal access token
+// @Description Revoke a personal access token of the current user. Requests made with it are rejected right away.
+// @ID revoke-personal-access-token
+// @Tags Personal Access Tokens
+// @Produce json
+// @Param tokenId path string true "Token ID (UUID)"
+// @Success 200 {object} dto.WebResponse "Personal access token revoked"
+// @Failure 400 {object} dto.WebResponse "Invalid token ID"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 404 {object} dto.WebResponse "Personal access token not found or already revoked"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /tokens/{tokenId} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *PersonalAccessTokenController) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(string)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	tokenIdStr := r.PathValue("tokenId")
+	tokenId, err := uuid.Parse(tokenIdStr)
+	if err != nil {
+		logger.Warnf("Invalid tokenId UUID: %s", tokenIdStr)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid tokenId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.PersonalAccessTokenService.Revoke(ctx, userID, tokenId); err != nil {
+		logger.Error("Failed to revoke personal access token:", err)
+		writePersonalAccessTokenError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Personal access token revoked",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+func toPersonalAccessTokenResponse(token *entities.PersonalAccessToken) dto.PersonalAccessTokenResponse {
+	return dto.PersonalAccessTokenResponse{
+		ID:         token.ID.String(),
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+func writePersonalAccessTokenError(w http.ResponseWriter, err error) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: appErr.Message,
+			Status:  0,
+			Data:    nil,
+		}, int64(appErr.StatusCode))
+		return
+	}
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "An unexpected error occurred",
+		Status:  0,
+		Data:    nil,
+	}, http.StatusInternalServerError)
+}
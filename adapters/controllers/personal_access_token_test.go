@@ -0,0 +1,114 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func personalAccessTokenContext(userID string) context.Context {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	return context.WithValue(ctx, middleware.UserIDKey, userID)
+}
+
+func TestPersonalAccessTokenController_Create_Success(t *testing.T) {
+	mockService := new(mocks.MockPersonalAccessTokenService)
+	controller := &controllers.PersonalAccessTokenController{
+		PersonalAccessTokenService: mockService,
+	}
+
+	body, _ := json.Marshal(dto.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{entities.PermissionPostsWrite}})
+	req := httptest.NewRequest(http.MethodPost, "/api/tokens", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	req = req.WithContext(personalAccessTokenContext("user-1"))
+
+	created := &entities.PersonalAccessToken{ID: uuid.New(), Name: "ci", Prefix: "gbp_abcdefgh", TokenHash: "secret-hash", CreatedAt: time.Now()}
+	mockService.On("Create", mock.Anything, "user-1", mock.MatchedBy(func(token *entities.PersonalAccessToken) bool {
+		return token.Name == "ci" && len(token.Scopes) == 1
+	})).Return(created, "gbp_abcdefgh-rest", nil).Once()
+
+	controller.Create(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var response struct {
+		Data dto.CreatePersonalAccessTokenResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "gbp_abcdefgh-rest", response.Data.Token)
+	assert.Equal(t, created.ID.String(), response.Data.ID)
+	assert.NotContains(t, rec.Body.String(), "secret-hash")
+	mockService.AssertExpectations(t)
+}
+
+func TestPersonalAccessTokenController_Create_MissingScopes(t *testing.T) {
+	mockService := new(mocks.MockPersonalAccessTokenService)
+	controller := &controllers.PersonalAccessTokenController{
+		PersonalAccessTokenService: mockService,
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tokens", bytes.NewReader([]byte(`{"name":"ci"}`)))
+	rec := httptest.NewRecorder()
+	req = req.WithContext(personalAccessTokenContext("user-1"))
+
+	controller.Create(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPersonalAccessTokenController_List(t *testing.T) {
+	mockService := new(mocks.MockPersonalAccessTokenService)
+	controller := &controllers.PersonalAccessTokenController{
+		PersonalAccessTokenService: mockService,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tokens", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(personalAccessTokenContext("user-1"))
+
+	mockService.On("List", mock.Anything, "user-1").Return([]*entities.PersonalAccessToken{
+		{ID: uuid.New(), Name: "ci", Prefix: "gbp_abcdefgh", TokenHash: "secret-hash"},
+	}, nil).Once()
+
+	controller.List(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "gbp_abcdefgh")
+	assert.NotContains(t, rec.Body.String(), "secret-hash")
+}
+
+func TestPersonalAccessTokenController_Revoke_NotFound(t *testing.T) {
+	mockService := new(mocks.MockPersonalAccessTokenService)
+	controller := &controllers.PersonalAccessTokenController{
+		PersonalAccessTokenService: mockService,
+	}
+
+	tokenID := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/api/tokens/"+tokenID.String(), nil)
+	req.SetPathValue("tokenId", tokenID.String())
+	rec := httptest.NewRecorder()
+	req = req.WithContext(personalAccessTokenContext("user-1"))
+
+	mockService.On("Revoke", mock.Anything, "user-1", tokenID).Return(appErrors.NewNotFoundError("Personal access token not found or already revoked", nil)).Once()
+
+	controller.Revoke(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
 	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
 	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
 	"github.com/chud-lori/go-boilerplate/domain/entities"
@@ -24,7 +25,7 @@ type PostController struct {
 
 // CreatePost godoc
 // @Summary Create a new post
-// @Description Creates a new post with the provided title, body, and author ID.
+// @Description Creates a new post with the provided title and body, authored by the authenticated user. It is published unless a status is given, or a publish_at schedules it as a draft.
 // @ID create-post
 // @Tags Posts
 // @Accept json
@@ -32,6 +33,7 @@ type PostController struct {
 // @Param request body dto.CreatePostRequest true "Post creation request"
 // @Success 201 {object} dto.WebResponse{data=dto.PostResponse} "Successfully created post"
 // @Failure 400 {object} dto.WebResponse "Bad request or validation error"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
 // @Failure 500 {object} dto.WebResponse "Internal server error"
 // @Router /post [post]
 // @Security ApiKeyAuth
@@ -40,9 +42,20 @@ func (c *PostController) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
 
+	userID, _ := ctx.Value(middleware.UserIDKey).(string)
+	authorID, err := uuid.Parse(userID)
+	if err != nil {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
 	var req dto.CreatePostRequest
 
-	err := helper.GetPayload(r, &req)
+	err = helper.GetPayload(r, &req)
 	if err != nil {
 		var validationErr *appErrors.ValidationErrors
 		if errors.As(err, &validationErr) {
@@ -76,13 +89,16 @@ func (c *PostController) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user := &entities.User{
-		ID: req.AuthorID,
+		ID: authorID,
 	}
 
 	payload := &entities.Post{
-		Title: req.Title,
-		Body:  req.Body,
-		User:  user,
+		Title:     req.Title,
+		Body:      req.Body,
+		User:      user,
+		Status:    entities.PostStatus(req.Status),
+		PublishAt: req.PublishAt,
+		Tags:      req.Tags,
 	}
 
 	result, err := c.PostService.Create(ctx, payload)
@@ -110,11 +126,16 @@ func (c *PostController) Create(w http.ResponseWriter, r *http.Request) {
 		Message: "Successfully Create post",
 		Status:  1,
 		Data: dto.PostResponse{
-			ID:        result.ID,
-			Title:     result.Title,
-			Body:      result.Body,
-			AuthorID:  result.User.ID,
-			CreatedAt: result.CreatedAt,
+			ID:           result.ID,
+			Title:        result.Title,
+			Body:         result.Body,
+			AuthorID:     result.User.ID,
+			CreatedAt:    result.CreatedAt,
+			Version:      result.Version,
+			Status:       result.Status,
+			PublishAt:    result.PublishAt,
+			Tags:         result.Tags,
+			CommentCount: result.CommentCount,
 		},
 	}
 
@@ -123,26 +144,48 @@ func (c *PostController) Create(w http.ResponseWriter, r *http.Request) {
 
 // UpdatePost godoc
 // @Summary Update an existing post
-// @Description Updates an existing post with the provided title, body, and author ID.
+// @Description Updates the title and body of an existing post. Only the author or an admin may update it.
 // @ID update-post
 // @Tags Posts
 // @Accept json
 // @Produce json
+// @Param postId path string true "ID of the post to update"
+// @Param If-Match header string true "ETag of the version being updated, or *"
 // @Param request body dto.CreatePostRequest true "Post update request"
 // @Success 200 {object} dto.WebResponse{data=dto.PostResponse} "Successfully updated post"
 // @Failure 400 {object} dto.WebResponse "Bad request or validation error"
+// @Failure 403 {object} dto.WebResponse "Not the author of the post"
 // @Failure 404 {object} dto.WebResponse "Post not found"
+// @Failure 412 {object} dto.WebResponse "The post was modified since the given version"
+// @Failure 428 {object} dto.WebResponse "If-Match header missing"
 // @Failure 500 {object} dto.WebResponse "Internal server error"
-// @Router /post [put]
+// @Router /post/{postId} [put]
 // @Security ApiKeyAuth
 // @Security BearerAuth
 func (c *PostController) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
 
+	postIdStr := r.PathValue("postId")
+	postId, err := uuid.Parse(postIdStr)
+	if err != nil {
+		logger.Warnf("Invalid postId UUID: %s", postIdStr)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid postId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
 	var req dto.CreatePostRequest
 
-	err := helper.GetPayload(r, &req)
+	err = helper.GetPayload(r, &req)
 	if err != nil {
 		var validationErr *appErrors.ValidationErrors
 		if errors.As(err, &validationErr) {
@@ -175,16 +218,16 @@ func (c *PostController) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user := &entities.User{
-		ID: req.AuthorID,
-	}
 	payload := &entities.Post{
-		Title: req.Title,
-		Body:  req.Body,
-		User:  user,
+		ID:      postId,
+		Title:   req.Title,
+		Body:    req.Body,
+		Version: version,
+		Tags:    req.Tags,
 	}
 
-	result, err := c.PostService.Update(ctx, payload)
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	result, err := c.PostService.Update(ctx, claims, payload)
 
 	if err != nil {
 		var appErr *appErrors.AppError
@@ -205,31 +248,154 @@ func (c *PostController) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	w.Header().Set("ETag", helper.VersionETag(result.Version))
 	resp := &dto.WebResponse{
 		Message: "Successfully Update post",
 		Status:  1,
 		Data: dto.PostResponse{
-			ID:        result.ID,
-			Title:     result.Title,
-			Body:      result.Body,
-			AuthorID:  result.User.ID,
-			CreatedAt: result.CreatedAt,
+			ID:           result.ID,
+			Title:        result.Title,
+			Body:         result.Body,
+			AuthorID:     result.User.ID,
+			CreatedAt:    result.CreatedAt,
+			Version:      result.Version,
+			Status:       result.Status,
+			PublishAt:    result.PublishAt,
+			Tags:         result.Tags,
+			CommentCount: result.CommentCount,
 		},
 	}
 
 	helper.WriteResponse(w, resp, http.StatusOK)
 }
 
+// PatchPost godoc
+// @Summary Partially update a post
+// @Description Changes only the given fields of a post. The body is a JSON Merge Patch (RFC 7396, application/merge-patch+json or application/json) or a JSON Patch (RFC 6902, application/json-patch+json) with add, replace and remove operations on /title and /body. Only the author or an admin may patch it.
+// @ID patch-post
+// @Tags Posts
+// @Accept json
+// @Produce json
+// @Param postId path string true "ID of the post to patch"
+// @Param If-Match header string true "ETag of the version being patched, or *"
+// @Param request body dto.UpdatePostRequest true "Fields to change"
+// @Success 200 {object} dto.WebResponse{data=dto.PostResponse} "Successfully patched post"
+// @Failure 400 {object} dto.WebResponse "Bad request, unsupported operation or validation error"
+// @Failure 403 {object} dto.WebResponse "Not the author of the post"
+// @Failure 404 {object} dto.WebResponse "Post not found"
+// @Failure 412 {object} dto.WebResponse "The post was modified since the given version"
+// @Failure 415 {object} dto.WebResponse "Unsupported patch format"
+// @Failure 428 {object} dto.WebResponse "If-Match header missing"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId} [patch]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *PostController) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postIdStr := r.PathValue("postId")
+	postId, err := uuid.Parse(postIdStr)
+	if err != nil {
+		logger.Warnf("Invalid postId UUID: %s", postIdStr)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid postId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdatePostRequest
+	if err := helper.GetPatchPayload(r, &req); err != nil {
+		var validationErr *appErrors.ValidationErrors
+		if errors.As(err, &validationErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: strings.Join(validationErr.Messages, ", "),
+				Status:  0,
+				Data:    nil,
+			}, http.StatusBadRequest)
+			return
+		}
+
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+
+		logger.Error("Failed to get payload with unexpected error:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Failed to process request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	result, err := c.PostService.Patch(ctx, claims, postId, version, entities.PostPatch{Title: req.Title, Body: req.Body})
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to patch post:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", helper.VersionETag(result.Version))
+	helper.WriteResponse(w, &dto.WebResponse{
+		Message: "Successfully patched post",
+		Status:  1,
+		Data: dto.PostResponse{
+			ID:           result.ID,
+			Title:        result.Title,
+			Body:         result.Body,
+			AuthorID:     result.User.ID,
+			CreatedAt:    result.CreatedAt,
+			Version:      result.Version,
+			Status:       result.Status,
+			PublishAt:    result.PublishAt,
+			Tags:         result.Tags,
+			CommentCount: result.CommentCount,
+		},
+	}, http.StatusOK)
+}
+
 // DeletePost godoc
 // @Summary Delete a post by ID
-// @Description Deletes a post based on the provided post ID.
+// @Description Deletes a post based on the provided post ID. Only the author or an admin may delete it.
 // @ID delete-post
 // @Tags Posts
 // @Produce json
 // @Param postId path string true "ID of the post to delete"
+// @Param If-Match header string true "ETag of the version being deleted, or *"
 // @Success 200 {object} dto.WebResponse "Successfully deleted post"
 // @Failure 400 {object} dto.WebResponse "Invalid post ID format"
+// @Failure 403 {object} dto.WebResponse "Not the author of the post"
 // @Failure 404 {object} dto.WebResponse "Post not found"
+// @Failure 412 {object} dto.WebResponse "The post was modified since the given version"
+// @Failure 428 {object} dto.WebResponse "If-Match header missing"
 // @Failure 500 {object} dto.WebResponse "Internal server error"
 // @Router /post/{postId} [delete]
 // @Security ApiKeyAuth
@@ -250,7 +416,13 @@ func (c *PostController) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = c.PostService.Delete(ctx, postId)
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	err = c.PostService.Delete(ctx, claims, postId, version)
 
 	if err != nil {
 		var appErr *appErrors.AppError
@@ -280,19 +452,93 @@ func (c *PostController) Delete(w http.ResponseWriter, r *http.Request) {
 	helper.WriteResponse(w, resp, http.StatusOK)
 }
 
+// RestorePost godoc
+// @Summary Restore a deleted post
+// @Description Takes a post out of the trash. Only the author or an admin may restore it, and only until the trash is purged.
+// @ID restore-post
+// @Tags Posts
+// @Produce json
+// @Param postId path string true "ID of the post to restore"
+// @Success 200 {object} dto.WebResponse{data=dto.PostResponse} "Successfully restored post"
+// @Failure 400 {object} dto.WebResponse "Invalid post ID format"
+// @Failure 403 {object} dto.WebResponse "Not the author of the post"
+// @Failure 404 {object} dto.WebResponse "Post not found in trash"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/restore [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *PostController) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postIdStr := r.PathValue("postId")
+	postId, err := uuid.Parse(postIdStr)
+	if err != nil {
+		logger.Warnf("Invalid postId UUID: %s", postIdStr)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid postId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	result, err := c.PostService.Restore(ctx, claims, postId)
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to restore post:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", helper.VersionETag(result.Version))
+	helper.WriteResponse(w, &dto.WebResponse{
+		Message: "Successfully restored post",
+		Status:  1,
+		Data: dto.PostResponse{
+			ID:           result.ID,
+			Title:        result.Title,
+			Body:         result.Body,
+			AuthorID:     result.User.ID,
+			CreatedAt:    result.CreatedAt,
+			Version:      result.Version,
+			Status:       result.Status,
+			PublishAt:    result.PublishAt,
+			Tags:         result.Tags,
+			CommentCount: result.CommentCount,
+		},
+	}, http.StatusOK)
+}
+
 // GetPostByID godoc
 // @Summary Get a post by ID
-// @Description Retrieves a single post based on the provided post ID.
+// @Description Retrieves a single post based on the provided post ID. The ETag header carries its version, send it back in If-Match to change the post. Drafts and archived posts are only found by their author and admins.
 // @ID get-post-by-id
 // @Tags Posts
 // @Produce json
 // @Param postId path string true "ID of the post to retrieve"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} dto.WebResponse{data=entities.Post} "Successfully retrieved post"
+// @Success 304 "The cached copy is current"
 // @Failure 400 {object} dto.WebResponse "Invalid post ID format"
 // @Failure 404 {object} dto.WebResponse "Post not found"
 // @Failure 500 {object} dto.WebResponse "Internal server error"
 // @Router /post/{postId} [get]
 // @Security ApiKeyAuth
+// @Security BearerAuth
 func (c *PostController) GetById(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
@@ -309,7 +555,8 @@ func (c *PostController) GetById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	post, err := c.PostService.GetById(ctx, postId)
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	post, err := c.PostService.GetById(ctx, claims, postId)
 
 	if err != nil {
 		var appErr *appErrors.AppError
@@ -330,6 +577,13 @@ func (c *PostController) GetById(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	etag := helper.VersionETag(post.Version)
+	w.Header().Set("ETag", etag)
+	if helper.NoneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	resp := &dto.WebResponse{
 		Message: "Successfully Get post",
 		Status:  1,
@@ -339,19 +593,42 @@ func (c *PostController) GetById(w http.ResponseWriter, r *http.Request) {
 	helper.WriteResponse(w, resp, http.StatusOK)
 }
 
+// ifMatchVersion reads the If-Match header of a write, see helper.IfMatchVersion. It writes
+// the error response and returns false when the request cannot go on.
+func ifMatchVersion(w http.ResponseWriter, r *http.Request) (int, bool) {
+	version, err := helper.IfMatchVersion(r)
+	if err != nil {
+		var appErr *appErrors.AppError
+		errors.As(err, &appErr)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: appErr.Message,
+			Status:  0,
+			Data:    nil,
+		}, int64(appErr.StatusCode))
+		return 0, false
+	}
+	return version, true
+}
+
 // GetAllPosts godoc
 // @Summary Get all posts
-// @Description Retrieves a list of all posts. Supports optional filtering by search query and pagination.
+// @Description Retrieves a list of the published posts. Supports optional full-text search and filtering by tags, and pagination. Searches are ranked by relevance and each match carries a highlight of the body. With a status, it lists the authenticated user's own posts in that status instead.
 // @ID get-all-posts
 // @Tags Posts
 // @Produce json
-// @Param search query string false "Search term to filter posts by title or body"
+// @Param search query string false "Words to search in title and body, in web search syntax: \"quoted phrase\", or, -excluded"
+// @Param status query string false "List your own posts in this status" Enums(draft, published, archived)
+// @Param tag query []string false "Only posts with these tags, by slug or name" collectionFormat(multi)
+// @Param match query string false "Whether posts need any (default) or all of the tags" Enums(any, all)
 // @Param page query int false "Page number for pagination (default: 1)"
 // @Param limit query int false "Number of posts per page (default: 10)"
-// @Success 200 {object} dto.WebResponse{data=[]dto.PostResponse} "Successfully retrieved all posts"
+// @Success 200 {object} dto.WebResponse{data=[]entities.Post} "Successfully retrieved all posts"
+// @Failure 400 {object} dto.WebResponse "Unknown status or tag match"
+// @Failure 401 {object} dto.WebResponse "A status was given without signing in"
 // @Failure 500 {object} dto.WebResponse "Internal server error"
 // @Router /post [get]
 // @Security ApiKeyAuth
+// @Security BearerAuth
 func (c *PostController) GetAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	//logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
@@ -370,7 +647,30 @@ func (c *PostController) GetAll(w http.ResponseWriter, r *http.Request) {
 		limit = 10 // Default limit
 	}
 
-	posts, err := c.PostService.GetAll(ctx, search, page, limit)
+	filter := entities.PostFilter{
+		Search: search,
+		Tags:   r.URL.Query()["tag"],
+	}
+	switch r.URL.Query().Get("match") {
+	case "", "any":
+	case "all":
+		filter.MatchAllTags = true
+	default:
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "match must be any or all",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	var posts []entities.Post
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	if status := r.URL.Query().Get("status"); status != "" {
+		posts, err = c.PostService.ListOwn(ctx, claims, entities.PostStatus(status), page, limit)
+	} else {
+		posts, err = c.PostService.GetAll(ctx, claims, filter, page, limit)
+	}
 
 	if err != nil {
 		var appErr *appErrors.AppError
@@ -412,6 +712,8 @@ func (c *PostController) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Param file_type formData string true "File type"
 // @Success 202 {object} dto.WebResponse{data=map[string]string} "Accepted, returns upload_id"
 // @Failure 400 {object} dto.WebResponse "Bad request or validation error"
+// @Failure 403 {object} dto.WebResponse "Not allowed to modify this post"
+// @Failure 404 {object} dto.WebResponse "Post not found"
 // @Failure 500 {object} dto.WebResponse "Internal server error"
 // @Router /post/{postId}/upload [post]
 // @Security ApiKeyAuth
@@ -482,8 +784,18 @@ func (c *PostController) UploadAttachment(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	uploadID, err := c.PostService.StartAsyncUpload(ctx, postID, fileName, fileType, fileData) // Changed from JobQueue to PostService
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	uploadID, err := c.PostService.StartAsyncUpload(ctx, claims, postID, fileName, fileType, fileData) // Changed from JobQueue to PostService
 	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
 		logger.WithError(err).Error("Failed to start async upload")
 		helper.WriteResponse(w, dto.WebResponse{
 			Message: "Failed to start async upload",
@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ListPostRevisions godoc
+// @Summary List the revisions of a post
+// @Description Lists every saved version of a post, newest first. A revision is stored on every write, its number is the post version it belongs to.
+// @ID list-post-revisions
+// @Tags Posts
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Success 200 {object} dto.WebResponse{data=[]dto.PostRevisionResponse} "Revisions of the post"
+// @Failure 400 {object} dto.WebResponse "Invalid post ID format"
+// @Failure 404 {object} dto.WebResponse "Post not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/revisions [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *PostController) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postId, ok := postIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	revisions, err := c.PostService.ListRevisions(ctx, claims, postId)
+	if err != nil {
+		logger.Error("Failed to list post revisions:", err)
+		writePostError(w, err)
+		return
+	}
+
+	data := make([]dto.PostRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		data[i] = dto.PostRevisionResponse{
+			Revision:  revision.Revision,
+			Title:     revision.Title,
+			EditorID:  editorIDString(revision),
+			CreatedAt: revision.CreatedAt,
+		}
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully get post revisions",
+		Status:  1,
+		Data:    data,
+	}, http.StatusOK)
+}
+
+// GetPostRevision godoc
+// @Summary Get a revision of a post
+// @Description Retrieves one revision of a post with a unified diff of its title and body against the current version.
+// @ID get-post-revision
+// @Tags Posts
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} dto.WebResponse{data=dto.PostRevisionDetailResponse} "The revision and its diff"
+// @Failure 400 {object} dto.WebResponse "Invalid post ID or revision"
+// @Failure 404 {object} dto.WebResponse "Post or revision not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/revisions/{rev} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *PostController) GetRevision(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postId, ok := postIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	rev, ok := revisionFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	result, err := c.PostService.GetRevision(ctx, claims, postId, rev)
+	if err != nil {
+		logger.Error("Failed to get post revision:", err)
+		writePostError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully get post revision",
+		Status:  1,
+		Data: dto.PostRevisionDetailResponse{
+			Revision:       result.Revision.Revision,
+			Title:          result.Revision.Title,
+			Body:           result.Revision.Body,
+			EditorID:       editorIDString(result.Revision),
+			CreatedAt:      result.Revision.CreatedAt,
+			CurrentVersion: result.CurrentVersion,
+			Diff:           result.Diff,
+		},
+	}, http.StatusOK)
+}
+
+// RestorePostRevision godoc
+// @Summary Restore a revision of a post
+// @Description Writes the title and body of an earlier revision as a new version of the post. Only the author or an admin may do so.
+// @ID restore-post-revision
+// @Tags Posts
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Param rev path int true "Revision number"
+// @Param If-Match header string true "ETag of the current version, or *"
+// @Success 200 {object} dto.WebResponse{data=dto.PostResponse} "The post at its new version"
+// @Failure 400 {object} dto.WebResponse "Invalid post ID or revision"
+// @Failure 403 {object} dto.WebResponse "Not the author of the post"
+// @Failure 404 {object} dto.WebResponse "Post or revision not found"
+// @Failure 412 {object} dto.WebResponse "The post was modified since the given version"
+// @Failure 428 {object} dto.WebResponse "If-Match header missing"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/revisions/{rev}/restore [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *PostController) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postId, ok := postIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	rev, ok := revisionFromPath(w, r)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	result, err := c.PostService.RestoreRevision(ctx, claims, postId, rev, version)
+	if err != nil {
+		logger.Error("Failed to restore post revision:", err)
+		writePostError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", helper.VersionETag(result.Version))
+	helper.WriteResponse(w, &dto.WebResponse{
+		Message: "Successfully restored post revision",
+		Status:  1,
+		Data: dto.PostResponse{
+			ID:           result.ID,
+			Title:        result.Title,
+			Body:         result.Body,
+			AuthorID:     result.User.ID,
+			CreatedAt:    result.CreatedAt,
+			Version:      result.Version,
+			Status:       result.Status,
+			PublishAt:    result.PublishAt,
+			Tags:         result.Tags,
+			CommentCount: result.CommentCount,
+		},
+	}, http.StatusOK)
+}
+
+func postIDFromPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid postId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return postId, true
+}
+
+func revisionFromPath(w http.ResponseWriter, r *http.Request) (int, bool) {
+	rev, err := strconv.Atoi(r.PathValue("rev"))
+	if err != nil || rev < 1 {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid revision",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return 0, false
+	}
+	return rev, true
+}
+
+func editorIDString(revision *entities.PostRevision) string {
+	if revision.EditorID == uuid.Nil {
+		return ""
+	}
+	return revision.EditorID.String()
+}
+
+func writePostError(w http.ResponseWriter, err error) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: appErr.Message,
+			Status:  0,
+			Data:    nil,
+		}, int64(appErr.StatusCode))
+		return
+	}
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "An unexpected error occurred",
+		Status:  0,
+		Data:    nil,
+	}, http.StatusInternalServerError)
+}
@@ -0,0 +1,152 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRevisionRequest(method string, postID uuid.UUID, rev string) *http.Request {
+	target := "/post/" + postID.String() + "/revisions"
+	if rev != "" {
+		target += "/" + rev
+	}
+	req := httptest.NewRequest(method, target, nil)
+	req.SetPathValue("postId", postID.String())
+	req.SetPathValue("rev", rev)
+	return req.WithContext(context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+}
+
+func TestPostController_ListRevisions(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	postID := uuid.New()
+	editorID := uuid.New()
+	mockService.On("ListRevisions", mock.Anything, (*entities.TokenClaims)(nil), postID).Return([]*entities.PostRevision{
+		{PostID: postID, Revision: 2, Title: "Edited", EditorID: editorID, CreatedAt: time.Now()},
+		{PostID: postID, Revision: 1, Title: "First"},
+	}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	controller.ListRevisions(rec, newRevisionRequest(http.MethodGet, postID, ""))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data []dto.PostRevisionResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, editorID.String(), response.Data[0].EditorID)
+	assert.Empty(t, response.Data[1].EditorID)
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_GetRevision(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	postID := uuid.New()
+	mockService.On("GetRevision", mock.Anything, (*entities.TokenClaims)(nil), postID, 2).Return(&entities.PostRevisionDiff{
+		Revision:       &entities.PostRevision{PostID: postID, Revision: 2, Title: "Old", Body: "Old body"},
+		CurrentVersion: 3,
+		Diff:           "--- title\trevision 2\n+++ title\tversion 3\n@@ -1 +1 @@\n-Old\n+New\n",
+	}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	controller.GetRevision(rec, newRevisionRequest(http.MethodGet, postID, "2"))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data dto.PostRevisionDetailResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.Data.CurrentVersion)
+	assert.Contains(t, response.Data.Diff, "+New")
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_GetRevision_InvalidRevision(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	for _, rev := range []string{"0", "-1", "latest"} {
+		rec := httptest.NewRecorder()
+		controller.GetRevision(rec, newRevisionRequest(http.MethodGet, uuid.New(), rev))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rev)
+	}
+	mockService.AssertNotCalled(t, "GetRevision", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostController_RestoreRevision(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	postID := uuid.New()
+	mockService.On("RestoreRevision", mock.Anything, mock.Anything, postID, 1, 3).
+		Return(&entities.Post{ID: postID, Title: "First", User: &entities.User{ID: uuid.New()}, Version: 4}, nil).Once()
+
+	req := newRevisionRequest(http.MethodPost, postID, "1")
+	req.Header.Set("If-Match", `"3"`)
+	rec := httptest.NewRecorder()
+	controller.RestoreRevision(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_RestoreRevision_RequiresIfMatch(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	rec := httptest.NewRecorder()
+	controller.RestoreRevision(rec, newRevisionRequest(http.MethodPost, uuid.New(), "1"))
+
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	mockService.AssertNotCalled(t, "RestoreRevision", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostController_RestoreRevision_NotFound(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	postID := uuid.New()
+	mockService.On("RestoreRevision", mock.Anything, mock.Anything, postID, 7, 0).
+		Return(nil, appErrors.NewNotFoundError("Revision not found", nil)).Once()
+
+	req := newRevisionRequest(http.MethodPost, postID, "7")
+	req.Header.Set("If-Match", "*")
+	rec := httptest.NewRecorder()
+	controller.RestoreRevision(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// SetPostStatus godoc
+// @Summary Publish, archive or schedule a post
+// @Description Moves a post to draft, published or archived. A draft with a publish_at is published by the post publisher worker once the time has come. Only the author or an admin may change the status.
+// @ID set-post-status
+// @Tags Posts
+// @Accept json
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Param If-Match header string true "ETag of the current version, or *"
+// @Param request body dto.SetPostStatusRequest true "New status"
+// @Success 200 {object} dto.WebResponse{data=dto.PostResponse} "The post at its new version"
+// @Failure 400 {object} dto.WebResponse "Invalid status or schedule"
+// @Failure 403 {object} dto.WebResponse "Not the author of the post"
+// @Failure 404 {object} dto.WebResponse "Post not found"
+// @Failure 412 {object} dto.WebResponse "The post was modified since the given version"
+// @Failure 428 {object} dto.WebResponse "If-Match header missing"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/status [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *PostController) SetStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postId, ok := postIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.SetPostStatusRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		var validationErr *appErrors.ValidationErrors
+		if errors.As(err, &validationErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: strings.Join(validationErr.Messages, ", "),
+				Status:  0,
+				Data:    nil,
+			}, http.StatusBadRequest)
+			return
+		}
+
+		logger.Warn("Failed to get payload:", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Failed to process request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	result, err := c.PostService.SetStatus(ctx, claims, postId, version, entities.PostStatus(req.Status), req.PublishAt)
+	if err != nil {
+		logger.Error("Failed to set post status:", err)
+		writePostError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", helper.VersionETag(result.Version))
+	helper.WriteResponse(w, &dto.WebResponse{
+		Message: "Successfully set post status",
+		Status:  1,
+		Data: dto.PostResponse{
+			ID:           result.ID,
+			Title:        result.Title,
+			Body:         result.Body,
+			AuthorID:     result.User.ID,
+			CreatedAt:    result.CreatedAt,
+			Version:      result.Version,
+			Status:       result.Status,
+			PublishAt:    result.PublishAt,
+			Tags:         result.Tags,
+			CommentCount: result.CommentCount,
+		},
+	}, http.StatusOK)
+}
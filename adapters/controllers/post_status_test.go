@@ -0,0 +1,110 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newStatusRequest(postID uuid.UUID, body string, claims *entities.TokenClaims) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/post/"+postID.String()+"/status", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("postId", postID.String())
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.ClaimsKey, claims)
+	return req.WithContext(ctx)
+}
+
+func TestPostController_SetStatus(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	postID := uuid.New()
+	claims := &entities.TokenClaims{UserID: uuid.New().String()}
+	publishAt := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	mockService.On("SetStatus", mock.Anything, claims, postID, 2, entities.PostStatusDraft, mock.MatchedBy(func(at *time.Time) bool {
+		return at != nil && at.Equal(publishAt)
+	})).Return(&entities.Post{ID: postID, User: &entities.User{ID: uuid.New()}, Version: 3, Status: entities.PostStatusDraft, PublishAt: &publishAt}, nil).Once()
+
+	req := newStatusRequest(postID, `{"status":"draft","publish_at":"2030-01-02T09:00:00Z"}`, claims)
+	req.Header.Set("If-Match", `"2"`)
+	rec := httptest.NewRecorder()
+	controller.SetStatus(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), `"status":"draft"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_SetStatus_UnknownStatus(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	req := newStatusRequest(uuid.New(), `{"status":"hidden"}`, nil)
+	req.Header.Set("If-Match", "*")
+	rec := httptest.NewRecorder()
+	controller.SetStatus(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "SetStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostController_SetStatus_ServiceError(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	postID := uuid.New()
+	mockService.On("SetStatus", mock.Anything, mock.Anything, postID, 0, entities.PostStatusArchived, (*time.Time)(nil)).
+		Return(nil, appErrors.NewForbiddenError("You are not allowed to modify this post", nil)).Once()
+
+	req := newStatusRequest(postID, `{"status":"archived"}`, nil)
+	req.Header.Set("If-Match", "*")
+	rec := httptest.NewRecorder()
+	controller.SetStatus(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_GetAll_OwnPostsByStatus(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	claims := &entities.TokenClaims{UserID: uuid.New().String()}
+	drafts := []entities.Post{{ID: uuid.New(), Title: "Draft", Status: entities.PostStatusDraft}}
+	mockService.On("ListOwn", mock.Anything, claims, entities.PostStatusDraft, 1, 10).Return(drafts, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/post?status=draft", nil)
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.ClaimsKey, claims)
+	rec := httptest.NewRecorder()
+	controller.GetAll(rec, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"draft"`)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
 	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/mocks"
@@ -29,23 +30,27 @@ func TestPostController_Create_Success(t *testing.T) {
 		PostService: mockService,
 	}
 
-	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
-
 	authorID := uuid.New()
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.UserIDKey, authorID.String())
+
+	// The author_id in the body belongs to someone else and must be ignored.
+	bodyBytes, _ := json.Marshal(map[string]string{
+		"title":     "Test Post Title",
+		"body":      "This is the body of the test post.",
+		"author_id": uuid.NewString(),
+	})
 	reqBody := &dto.CreatePostRequest{
-		Title:    "Test Post Title",
-		Body:     "This is the body of the test post.",
-		AuthorID: authorID,
+		Title: "Test Post Title",
+		Body:  "This is the body of the test post.",
 	}
-
-	bodyBytes, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest(http.MethodPost, "/post", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 	req = req.WithContext(ctx)
 
 	user := &entities.User{
-		ID: reqBody.AuthorID,
+		ID: authorID,
 	}
 
 	expectedPost := &entities.Post{
@@ -57,7 +62,7 @@ func TestPostController_Create_Success(t *testing.T) {
 	}
 
 	mockService.On("Create", mock.Anything, mock.MatchedBy(func(post *entities.Post) bool {
-		return post.Title == reqBody.Title && post.Body == reqBody.Body && post.User.ID == reqBody.AuthorID
+		return post.Title == reqBody.Title && post.Body == reqBody.Body && post.User.ID == authorID
 	})).Return(expectedPost, nil).Once()
 
 	controller.Create(rec, req)
@@ -89,9 +94,10 @@ func TestPostController_Create_InvalidPayload(t *testing.T) {
 	}
 
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.UserIDKey, uuid.NewString())
 
-	// Missing required fields for validation error
-	reqBody := `{"title": "","body": "","author_id": "invalid-uuid"}`
+	// Title of the wrong type fails decoding
+	reqBody := `{"title": 123,"body": ""}`
 	req := httptest.NewRequest(http.MethodPost, "/post", bytes.NewReader([]byte(reqBody)))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
@@ -119,12 +125,11 @@ func TestPostController_Create_ServiceError(t *testing.T) {
 	}
 
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.UserIDKey, uuid.NewString())
 
-	authorID := uuid.New()
 	reqBody := &dto.CreatePostRequest{
-		Title:    "Test Post Title",
-		Body:     "This is the body of the test post.",
-		AuthorID: authorID,
+		Title: "Test Post Title",
+		Body:  "This is the body of the test post.",
 	}
 
 	bodyBytes, _ := json.Marshal(reqBody)
@@ -152,7 +157,7 @@ func TestPostController_Create_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
-func TestPostController_Update_Success(t *testing.T) {
+func TestPostController_Create_Unauthenticated(t *testing.T) {
 	mockService := new(mocks.MockPostService)
 	controller := &controllers.PostController{
 		PostService: mockService,
@@ -160,22 +165,44 @@ func TestPostController_Update_Success(t *testing.T) {
 
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
 
+	bodyBytes, _ := json.Marshal(&dto.CreatePostRequest{Title: "Title", Body: "Body"})
+	req := httptest.NewRequest(http.MethodPost, "/post", bytes.NewReader(bodyBytes))
+	rec := httptest.NewRecorder()
+	req = req.WithContext(ctx)
+
+	controller.Create(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNotCalled(t, "Create")
+}
+
+func TestPostController_Update_Success(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
 	authorID := uuid.New()
-	postID := uuid.New()               // Assuming the update payload includes the ID
-	reqBody := &dto.CreatePostRequest{ // Assuming Update uses CreatePostRequest struct
-		Title:    "Updated Post Title",
-		Body:     "This is the updated body.",
-		AuthorID: authorID,
+	claims := &entities.TokenClaims{UserID: authorID.String()}
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.ClaimsKey, claims)
+
+	postID := uuid.New()
+	reqBody := &dto.CreatePostRequest{
+		Title: "Updated Post Title",
+		Body:  "This is the updated body.",
 	}
 
 	bodyBytes, _ := json.Marshal(reqBody)
-	req := httptest.NewRequest(http.MethodPut, "/post", bytes.NewReader(bodyBytes))
+	req := httptest.NewRequest(http.MethodPut, "/post/"+postID.String(), bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
 	rec := httptest.NewRecorder()
+	req.SetPathValue("postId", postID.String())
 	req = req.WithContext(ctx)
 
 	user := &entities.User{
-		ID: reqBody.AuthorID,
+		ID: authorID,
 	}
 
 	updatedPost := &entities.Post{
@@ -185,21 +212,17 @@ func TestPostController_Update_Success(t *testing.T) {
 		User:      user,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		Version:   4,
 	}
 
-	mockService.On("Update", mock.Anything, mock.MatchedBy(func(post *entities.Post) bool {
-		// Note: Your controller's Update method does not extract ID from URL path
-		// and it seems to expect the ID to be part of the 'payload' (req.Title, req.Body, req.AuthorID).
-		// For a real update, the payload/request DTO would typically include the ID.
-		// If ID is meant to come from the path, the controller needs to retrieve it.
-		// For this test, we assume the 'payload' passed to service would contain the ID.
-		// A more robust test would ensure the ID is correctly parsed from the URL and passed.
-		return post.Title == reqBody.Title && post.Body == reqBody.Body && post.User.ID == reqBody.AuthorID
+	mockService.On("Update", mock.Anything, claims, mock.MatchedBy(func(post *entities.Post) bool {
+		return post.ID == postID && post.Title == reqBody.Title && post.Body == reqBody.Body && post.Version == 3
 	})).Return(updatedPost, nil).Once()
 
 	controller.Update(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code) // Controller returns StatusCreated for success
+	assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
 	var response dto.WebResponse
 	err := json.Unmarshal(rec.Body.Bytes(), &response)
 	assert.NoError(t, err)
@@ -224,21 +247,22 @@ func TestPostController_Update_PostNotFound(t *testing.T) {
 
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
 
-	authorID := uuid.New()
+	postID := uuid.New()
 	reqBody := &dto.CreatePostRequest{
-		Title:    "Updated Post Title",
-		Body:     "This is the updated body.",
-		AuthorID: authorID,
+		Title: "Updated Post Title",
+		Body:  "This is the updated body.",
 	}
 
 	bodyBytes, _ := json.Marshal(reqBody)
-	req := httptest.NewRequest(http.MethodPut, "/post", bytes.NewReader(bodyBytes))
+	req := httptest.NewRequest(http.MethodPut, "/post/"+postID.String(), bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", "*")
 	rec := httptest.NewRecorder()
+	req.SetPathValue("postId", postID.String())
 	req = req.WithContext(ctx)
 
 	mockError := appErrors.NewNotFoundError("Post not found", nil)
-	mockService.On("Update", mock.Anything, mock.AnythingOfType("*entities.Post")).Return(nil, mockError).Once()
+	mockService.On("Update", mock.Anything, mock.Anything, mock.AnythingOfType("*entities.Post")).Return(nil, mockError).Once()
 
 	controller.Update(rec, req)
 
@@ -255,6 +279,31 @@ func TestPostController_Update_PostNotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestPostController_Update_Forbidden(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	postID := uuid.New()
+	bodyBytes, _ := json.Marshal(&dto.CreatePostRequest{Title: "Title", Body: "Body"})
+	req := httptest.NewRequest(http.MethodPut, "/post/"+postID.String(), bytes.NewReader(bodyBytes))
+	req.Header.Set("If-Match", "*")
+	rec := httptest.NewRecorder()
+	req.SetPathValue("postId", postID.String())
+	req = req.WithContext(ctx)
+
+	mockError := appErrors.NewForbiddenError("You are not allowed to modify this post", nil)
+	mockService.On("Update", mock.Anything, mock.Anything, mock.AnythingOfType("*entities.Post")).Return(nil, mockError).Once()
+
+	controller.Update(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestPostController_Delete_Success(t *testing.T) {
 	mockService := new(mocks.MockPostService)
 	controller := &controllers.PostController{
@@ -266,11 +315,12 @@ func TestPostController_Delete_Success(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodDelete, "/post/"+postID.String(), nil)
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
 	rec := httptest.NewRecorder()
 	req.SetPathValue("postId", postID.String())
 	req = req.WithContext(ctx)
 
-	mockService.On("Delete", mock.Anything, postID).Return(nil).Once()
+	mockService.On("Delete", mock.Anything, mock.Anything, postID, 2).Return(nil).Once()
 
 	controller.Delete(rec, req)
 
@@ -326,12 +376,13 @@ func TestPostController_Delete_PostNotFound(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodDelete, "/post/"+postID.String(), nil)
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
 	rec := httptest.NewRecorder()
 	req.SetPathValue("postId", postID.String())
 	req = req.WithContext(ctx)
 
 	mockError := appErrors.NewNotFoundError("Post not found", nil)
-	mockService.On("Delete", mock.Anything, postID).Return(mockError).Once()
+	mockService.On("Delete", mock.Anything, mock.Anything, postID, 2).Return(mockError).Once()
 
 	controller.Delete(rec, req)
 
@@ -372,7 +423,7 @@ func TestPostController_GetById_Success(t *testing.T) {
 	req.SetPathValue("postId", postID.String())
 	rec := httptest.NewRecorder()
 
-	mockService.On("GetById", mock.Anything, postID).Return(expectedPost, nil).Once()
+	mockService.On("GetById", mock.Anything, (*entities.TokenClaims)(nil), postID).Return(expectedPost, nil).Once()
 
 	controller.GetById(rec, req)
 
@@ -439,7 +490,7 @@ func TestPostController_GetById_PostNotFound(t *testing.T) {
 	rec := httptest.NewRecorder()
 
 	mockErr := appErrors.NewNotFoundError("Post not found", nil)
-	mockService.On("GetById", mock.Anything, postID).Return(nil, mockErr).Once()
+	mockService.On("GetById", mock.Anything, (*entities.TokenClaims)(nil), postID).Return(nil, mockErr).Once()
 
 	controller.GetById(rec, req)
 
@@ -477,7 +528,7 @@ func TestPostController_GetAll_Success(t *testing.T) {
 	req = req.WithContext(ctx)
 	rec := httptest.NewRecorder()
 
-	mockService.On("GetAll", mock.Anything, "test", 2, 5).Return(expectedPosts, nil).Once()
+	mockService.On("GetAll", mock.Anything, mock.Anything, entities.PostFilter{Search: "test"}, 2, 5).Return(expectedPosts, nil).Once()
 
 	controller.GetAll(rec, req)
 
@@ -523,7 +574,7 @@ func TestPostController_GetAll_DefaultQueryParams(t *testing.T) {
 	req = req.WithContext(ctx)
 	rec := httptest.NewRecorder()
 
-	mockService.On("GetAll", mock.Anything, "", 1, 10).Return(expectedPosts, nil).Once() // Expect defaults
+	mockService.On("GetAll", mock.Anything, mock.Anything, entities.PostFilter{}, 1, 10).Return(expectedPosts, nil).Once() // Expect defaults
 
 	controller.GetAll(rec, req)
 
@@ -552,7 +603,7 @@ func TestPostController_GetAll_ServiceError(t *testing.T) {
 	rec := httptest.NewRecorder()
 
 	mockErr := errors.New("Error")
-	mockService.On("GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, mockErr).Once()
+	mockService.On("GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, mockErr).Once()
 
 	controller.GetAll(rec, req)
 
@@ -591,7 +642,7 @@ func TestPostController_UploadAttachment_Success(t *testing.T) {
 	req = req.WithContext(ctx)
 	rec := httptest.NewRecorder()
 
-	mockService.On("StartAsyncUpload", mock.Anything, postID, "file.txt", "text/plain", mock.AnythingOfType("[]uint8")).Return(uuid.New(), nil)
+	mockService.On("StartAsyncUpload", mock.Anything, mock.Anything, postID, "file.txt", "text/plain", mock.AnythingOfType("[]uint8")).Return(uuid.New(), nil)
 
 	controller.UploadAttachment(rec, req)
 	assert.Equal(t, http.StatusAccepted, rec.Code)
@@ -691,7 +742,7 @@ func TestPostController_UploadAttachment_ServiceError(t *testing.T) {
 	req = req.WithContext(ctx)
 	rec := httptest.NewRecorder()
 
-	mockService.On("StartAsyncUpload", mock.Anything, postID, "file.txt", "text/plain", mock.AnythingOfType("[]uint8")).Return(uuid.Nil, errors.New("service error"))
+	mockService.On("StartAsyncUpload", mock.Anything, mock.Anything, postID, "file.txt", "text/plain", mock.AnythingOfType("[]uint8")).Return(uuid.Nil, errors.New("service error"))
 
 	controller.UploadAttachment(rec, req)
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
@@ -704,6 +755,44 @@ func TestPostController_UploadAttachment_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestPostController_UploadAttachment_Forbidden(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	claims := &entities.TokenClaims{UserID: uuid.New().String()}
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.ClaimsKey, claims)
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fw, _ := w.CreateFormFile("file", "file.txt")
+	fw.Write([]byte("filedata"))
+	w.WriteField("file_name", "file.txt")
+	w.WriteField("file_type", "text/plain")
+	w.Close()
+
+	postID := uuid.New()
+	url := "/post/" + postID.String() + "/upload"
+	req := httptest.NewRequest(http.MethodPost, url, &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req = req.WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	mockService.On("StartAsyncUpload", mock.Anything, claims, postID, "file.txt", "text/plain", mock.AnythingOfType("[]uint8")).
+		Return(uuid.Nil, appErrors.NewForbiddenError("You are not allowed to modify this post", nil))
+
+	controller.UploadAttachment(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	var response dto.WebResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "You are not allowed to modify this post", response.Message)
+	assert.Equal(t, 0, response.Status)
+	mockService.AssertExpectations(t)
+}
+
 func TestPostController_UploadStatusSSE_Success(t *testing.T) {
 	mockService := new(mocks.MockPostService)
 	controller := &controllers.PostController{
@@ -807,3 +896,267 @@ func TestPostController_UploadStatusSSE_ServiceError(t *testing.T) {
 	assert.Equal(t, 0, response.Status)
 	assert.Nil(t, response.Data)
 }
+
+func newPatchRequest(postID uuid.UUID, contentType, body string, claims *entities.TokenClaims) *http.Request {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.ClaimsKey, claims)
+
+	req := httptest.NewRequest(http.MethodPatch, "/post/"+postID.String(), bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("If-Match", "*")
+	req.SetPathValue("postId", postID.String())
+	return req.WithContext(ctx)
+}
+
+func TestPostController_Patch_MergePatch(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	authorID := uuid.New()
+	claims := &entities.TokenClaims{UserID: authorID.String()}
+	postID := uuid.New()
+	req := newPatchRequest(postID, "application/merge-patch+json", `{"title":"New Title"}`, claims)
+	rec := httptest.NewRecorder()
+
+	mockService.On("Patch", mock.Anything, claims, postID, 0, mock.MatchedBy(func(patch entities.PostPatch) bool {
+		return patch.Title != nil && *patch.Title == "New Title" && patch.Body == nil
+	})).Return(&entities.Post{ID: postID, Title: "New Title", Body: "Old body", User: &entities.User{ID: authorID}}, nil).Once()
+
+	controller.Patch(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data dto.PostResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "New Title", response.Data.Title)
+	assert.Equal(t, "Old body", response.Data.Body)
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_Patch_JSONPatch(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	authorID := uuid.New()
+	claims := &entities.TokenClaims{UserID: authorID.String()}
+	postID := uuid.New()
+	body := `[{"op":"replace","path":"/title","value":"First"},{"op":"replace","path":"/title","value":"Second"},{"op":"remove","path":"/body"}]`
+	req := newPatchRequest(postID, "application/json-patch+json", body, claims)
+	rec := httptest.NewRecorder()
+
+	mockService.On("Patch", mock.Anything, claims, postID, 0, mock.MatchedBy(func(patch entities.PostPatch) bool {
+		return patch.Title != nil && *patch.Title == "Second" && patch.Body != nil && *patch.Body == ""
+	})).Return(&entities.Post{ID: postID, Title: "Second", User: &entities.User{ID: authorID}}, nil).Once()
+
+	controller.Patch(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_Patch_InvalidDocuments(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"title removed", "application/merge-patch+json", `{"title":null}`, http.StatusBadRequest},
+		{"unknown field", "application/merge-patch+json", `{"author_id":"someone"}`, http.StatusBadRequest},
+		{"wrong type", "application/merge-patch+json", `{"title":42}`, http.StatusBadRequest},
+		{"unsupported operation", "application/json-patch+json", `[{"op":"test","path":"/title","value":"x"}]`, http.StatusBadRequest},
+		{"nested path", "application/json-patch+json", `[{"op":"replace","path":"/author/id","value":"x"}]`, http.StatusBadRequest},
+		{"unsupported media type", "text/plain", `title=x`, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(mocks.MockPostService)
+			controller := &controllers.PostController{
+				PostService: mockService,
+			}
+
+			req := newPatchRequest(uuid.New(), tc.contentType, tc.body, &entities.TokenClaims{UserID: uuid.NewString()})
+			rec := httptest.NewRecorder()
+
+			controller.Patch(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+			mockService.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPostController_GetById_SetsETag(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	postID := uuid.New()
+	post := &entities.Post{ID: postID, Title: "Versioned", User: &entities.User{ID: uuid.New()}, Version: 7}
+	mockService.On("GetById", mock.Anything, (*entities.TokenClaims)(nil), postID).Return(post, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/post/"+postID.String(), nil).WithContext(ctx)
+	req.SetPathValue("postId", postID.String())
+	rec := httptest.NewRecorder()
+
+	controller.GetById(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"7"`, rec.Header().Get("ETag"))
+
+	// A client holding the current version gets no body back.
+	for _, ifNoneMatch := range []string{`"7"`, `W/"7"`, `"6", "7"`, "*"} {
+		req = httptest.NewRequest(http.MethodGet, "/post/"+postID.String(), nil).WithContext(ctx)
+		req.SetPathValue("postId", postID.String())
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		rec = httptest.NewRecorder()
+
+		controller.GetById(rec, req)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code, ifNoneMatch)
+		assert.Equal(t, `"7"`, rec.Header().Get("ETag"))
+		assert.Empty(t, rec.Body.Bytes())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/post/"+postID.String(), nil).WithContext(ctx)
+	req.SetPathValue("postId", postID.String())
+	req.Header.Set("If-None-Match", `"6"`)
+	rec = httptest.NewRecorder()
+
+	controller.GetById(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestPostController_Writes_RequireIfMatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		ifMatch string
+		status  int
+	}{
+		{"missing", "", http.StatusPreconditionRequired},
+		{"weak tag", `W/"3"`, http.StatusPreconditionFailed},
+		{"not a version", `"abc"`, http.StatusPreconditionFailed},
+		{"unquoted", "3", http.StatusPreconditionFailed},
+		{"several tags", `"2", "3"`, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(mocks.MockPostService)
+			controller := &controllers.PostController{
+				PostService: mockService,
+			}
+
+			postID := uuid.New()
+			bodyBytes, _ := json.Marshal(&dto.CreatePostRequest{Title: "Title", Body: "Body"})
+			writes := map[string]http.HandlerFunc{
+				http.MethodPut:    controller.Update,
+				http.MethodPatch:  controller.Patch,
+				http.MethodDelete: controller.Delete,
+			}
+			for method, handler := range writes {
+				ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+				req := httptest.NewRequest(method, "/post/"+postID.String(), bytes.NewReader(bodyBytes)).WithContext(ctx)
+				req.Header.Set("Content-Type", "application/json")
+				if tc.ifMatch != "" {
+					req.Header.Set("If-Match", tc.ifMatch)
+				}
+				req.SetPathValue("postId", postID.String())
+				rec := httptest.NewRecorder()
+
+				handler(rec, req)
+
+				assert.Equal(t, tc.status, rec.Code, method)
+			}
+			mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+			mockService.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockService.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPostController_Update_StaleVersion(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	postID := uuid.New()
+	bodyBytes, _ := json.Marshal(&dto.CreatePostRequest{Title: "Title", Body: "Body"})
+	req := httptest.NewRequest(http.MethodPut, "/post/"+postID.String(), bytes.NewReader(bodyBytes)).WithContext(ctx)
+	req.Header.Set("If-Match", `"1"`)
+	req.SetPathValue("postId", postID.String())
+	rec := httptest.NewRecorder()
+
+	mockError := appErrors.NewPreconditionFailedError("Post was modified by someone else, reload it and try again", appErrors.ErrVersionConflict)
+	mockService.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(post *entities.Post) bool {
+		return post.Version == 1
+	})).Return(nil, mockError).Once()
+
+	controller.Update(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_Restore_Success(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	postID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/post/"+postID.String()+"/restore", nil)
+	rec := httptest.NewRecorder()
+	req.SetPathValue("postId", postID.String())
+	req = req.WithContext(ctx)
+
+	mockService.On("Restore", mock.Anything, mock.Anything, postID).
+		Return(&entities.Post{ID: postID, Title: "Back again", User: &entities.User{ID: uuid.New()}, Version: 4}, nil).Once()
+
+	controller.Restore(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	var response struct {
+		Data dto.PostResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Back again", response.Data.Title)
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_Restore_NotInTrash(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{
+		PostService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	postID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/post/"+postID.String()+"/restore", nil)
+	rec := httptest.NewRecorder()
+	req.SetPathValue("postId", postID.String())
+	req = req.WithContext(ctx)
+
+	mockService.On("Restore", mock.Anything, mock.Anything, postID).
+		Return(nil, appErrors.NewNotFoundError("Post not found in trash", nil)).Once()
+
+	controller.Restore(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
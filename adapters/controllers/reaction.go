@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ReactionController struct {
+	ports.ReactionService
+}
+
+// React godoc
+// @Summary React to a post
+// @Description Leaves a reaction of the given type on a post. Each user reacts at most once per type, reacting again changes nothing. Anyone signed in who can read the post may react.
+// @ID react-to-post
+// @Tags Reactions
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Param type path string true "Reaction type" Enums(like, love, laugh, wow, sad, angry)
+// @Success 200 {object} dto.WebResponse{data=dto.ReactionsResponse} "The reactions on the post"
+// @Failure 400 {object} dto.WebResponse "Unknown reaction type"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 404 {object} dto.WebResponse "Post not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/reactions/{type} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *ReactionController) React(w http.ResponseWriter, r *http.Request) {
+	c.change(w, r, c.ReactionService.React, "Successfully react to post")
+}
+
+// Unreact godoc
+// @Summary Take back a reaction
+// @Description Removes the caller's reaction of the given type from a post. Removing a reaction that was never left changes nothing.
+// @ID unreact-to-post
+// @Tags Reactions
+// @Produce json
+// @Param postId path string true "ID of the post"
+// @Param type path string true "Reaction type" Enums(like, love, laugh, wow, sad, angry)
+// @Success 200 {object} dto.WebResponse{data=dto.ReactionsResponse} "The reactions on the post"
+// @Failure 400 {object} dto.WebResponse "Unknown reaction type"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 404 {object} dto.WebResponse "Post not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /post/{postId}/reactions/{type} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *ReactionController) Unreact(w http.ResponseWriter, r *http.Request) {
+	c.change(w, r, c.ReactionService.Unreact, "Successfully remove reaction")
+}
+
+// change runs React or Unreact for the post and type in the path.
+func (c *ReactionController) change(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, reaction entities.ReactionType) (*entities.PostReactions, error), message string) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	postId, ok := postIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	claims, _ := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	result, err := apply(ctx, claims, postId, entities.ReactionType(r.PathValue("type")))
+	if err != nil {
+		logger.Error("Failed to change reaction:", err)
+		writePostError(w, err)
+		return
+	}
+
+	counts := result.Counts
+	if counts == nil {
+		counts = entities.ReactionCounts{}
+	}
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: message,
+		Status:  1,
+		Data: dto.ReactionsResponse{
+			Counts: counts,
+			Mine:   result.Mine,
+		},
+	}, http.StatusOK)
+}
@@ -0,0 +1,79 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newReactionRequest(method string, postID uuid.UUID, reaction string, claims *entities.TokenClaims) *http.Request {
+	req := httptest.NewRequest(method, "/post/"+postID.String()+"/reactions/"+reaction, nil)
+	req.SetPathValue("postId", postID.String())
+	req.SetPathValue("type", reaction)
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.ClaimsKey, claims)
+	return req.WithContext(ctx)
+}
+
+func TestReactionController_React(t *testing.T) {
+	mockService := new(mocks.MockReactionService)
+	controller := &controllers.ReactionController{ReactionService: mockService}
+
+	postID := uuid.New()
+	claims := &entities.TokenClaims{UserID: uuid.New().String()}
+	mockService.On("React", mock.Anything, claims, postID, entities.ReactionLike).Return(&entities.PostReactions{
+		Counts: entities.ReactionCounts{entities.ReactionLike: 3},
+		Mine:   []entities.ReactionType{entities.ReactionLike},
+	}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	controller.React(rec, newReactionRequest(http.MethodPut, postID, "like", claims))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"counts":{"like":3}`)
+	assert.Contains(t, rec.Body.String(), `"mine":["like"]`)
+	mockService.AssertExpectations(t)
+}
+
+func TestReactionController_Unreact(t *testing.T) {
+	mockService := new(mocks.MockReactionService)
+	controller := &controllers.ReactionController{ReactionService: mockService}
+
+	postID := uuid.New()
+	mockService.On("Unreact", mock.Anything, mock.Anything, postID, entities.ReactionSad).
+		Return(&entities.PostReactions{Mine: []entities.ReactionType{}}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	controller.Unreact(rec, newReactionRequest(http.MethodDelete, postID, "sad", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"counts":{}`)
+	assert.Contains(t, rec.Body.String(), `"mine":[]`)
+}
+
+func TestReactionController_React_ServiceError(t *testing.T) {
+	mockService := new(mocks.MockReactionService)
+	controller := &controllers.ReactionController{ReactionService: mockService}
+
+	postID := uuid.New()
+	mockService.On("React", mock.Anything, mock.Anything, postID, entities.ReactionType("meh")).
+		Return(nil, appErrors.NewBadRequestError("Unknown reaction type", nil)).Once()
+
+	rec := httptest.NewRecorder()
+	controller.React(rec, newReactionRequest(http.MethodPut, postID, "meh", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Unknown reaction type")
+}
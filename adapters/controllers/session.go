@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type SessionController struct {
+	ports.SessionService
+}
+
+// List godoc
+// @Summary List active sessions
+// @Description List the signed in sessions of the current user with the device and address they were started from. The session of this request is marked as current.
+// @ID list-sessions
+// @Tags Sessions
+// @Produce json
+// @Success 200 {object} dto.WebResponse{data=[]dto.SessionResponse} "Active sessions"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /sessions [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *SessionController) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	claims, ok := ctx.Value(middleware.ClaimsKey).(*entities.TokenClaims)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := c.SessionService.List(ctx, claims.UserID)
+	if err != nil {
+		logger.Error("Failed to list sessions:", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	data := make([]dto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		data[i] = dto.SessionResponse{
+			ID:         session.ID.String(),
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID.String() == claims.FamilyID,
+		}
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "success get sessions",
+		Status:  1,
+		Data:    data,
+	}, http.StatusOK)
+}
+
+// Revoke godoc
+// @Summary Revoke a session
+// @Description Sign out one session of the current user. Its tokens are rejected right away.
+// @ID revoke-session
+// @Tags Sessions
+// @Produce json
+// @Param sessionId path string true "Session ID (UUID)"
+// @Success 200 {object} dto.WebResponse "Session revoked"
+// @Failure 400 {object} dto.WebResponse "Invalid session ID"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 404 {object} dto.WebResponse "Session not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /sessions/{sessionId} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *SessionController) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(string)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	sessionIdStr := r.PathValue("sessionId")
+	sessionId, err := uuid.Parse(sessionIdStr)
+	if err != nil {
+		logger.Warnf("Invalid sessionId UUID: %s", sessionIdStr)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid sessionId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.SessionService.Revoke(ctx, userID, sessionId); err != nil {
+		logger.Error("Failed to revoke session:", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Session revoked",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+// RevokeAll godoc
+// @Summary Sign out everywhere
+// @Description Revoke every session of the current user, including the one making this request.
+// @ID revoke-all-sessions
+// @Tags Sessions
+// @Produce json
+// @Success 200 {object} dto.WebResponse "Signed out of all sessions"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /sessions [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *SessionController) RevokeAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(string)
+	if !ok {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Unauthorized",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	if err := c.SessionService.RevokeAll(ctx, userID); err != nil {
+		logger.Error("Failed to revoke sessions:", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Signed out of all sessions",
+		Status:  1,
+		Data:    nil,
+	}, http.StatusOK)
+}
+
+func writeSessionError(w http.ResponseWriter, err error) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: appErr.Message,
+			Status:  0,
+			Data:    nil,
+		}, int64(appErr.StatusCode))
+		return
+	}
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "An unexpected error occurred",
+		Status:  0,
+		Data:    nil,
+	}, http.StatusInternalServerError)
+}
@@ -0,0 +1,110 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func sessionContext(userID, familyID string) context.Context {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, middleware.UserIDKey, userID)
+	return context.WithValue(ctx, middleware.ClaimsKey, &entities.TokenClaims{UserID: userID, FamilyID: familyID})
+}
+
+func TestSessionController_List_MarksCurrent(t *testing.T) {
+	mockService := new(mocks.MockSessionService)
+	controller := &controllers.SessionController{
+		SessionService: mockService,
+	}
+
+	current, other := uuid.New(), uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/sessions", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(sessionContext("user-1", current.String()))
+
+	mockService.On("List", mock.Anything, "user-1").Return([]*entities.Session{
+		{ID: other, UserAgent: "curl/8.0"},
+		{ID: current, UserAgent: "Mozilla/5.0"},
+	}, nil).Once()
+
+	controller.List(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data []dto.SessionResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.False(t, response.Data[0].Current)
+	assert.True(t, response.Data[1].Current)
+}
+
+func TestSessionController_Revoke_InvalidID(t *testing.T) {
+	mockService := new(mocks.MockSessionService)
+	controller := &controllers.SessionController{
+		SessionService: mockService,
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/sessions/not-a-uuid", nil)
+	req.SetPathValue("sessionId", "not-a-uuid")
+	rec := httptest.NewRecorder()
+	req = req.WithContext(sessionContext("user-1", ""))
+
+	controller.Revoke(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSessionController_Revoke_NotFound(t *testing.T) {
+	mockService := new(mocks.MockSessionService)
+	controller := &controllers.SessionController{
+		SessionService: mockService,
+	}
+
+	sessionID := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/api/sessions/"+sessionID.String(), nil)
+	req.SetPathValue("sessionId", sessionID.String())
+	rec := httptest.NewRecorder()
+	req = req.WithContext(sessionContext("user-1", ""))
+
+	mockService.On("Revoke", mock.Anything, "user-1", sessionID).Return(appErrors.NewNotFoundError("Session not found", nil)).Once()
+
+	controller.Revoke(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSessionController_RevokeAll(t *testing.T) {
+	mockService := new(mocks.MockSessionService)
+	controller := &controllers.SessionController{
+		SessionService: mockService,
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/sessions", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(sessionContext("user-1", ""))
+
+	mockService.On("RevokeAll", mock.Anything, "user-1").Return(nil).Once()
+
+	controller.RevokeAll(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type TagController struct {
+	ports.TagService
+}
+
+// ListTags godoc
+// @Summary List tags
+// @Description Lists the tags of published posts with the number of such posts carrying each, most used first. Use a slug with GET /post?tag= to list its posts.
+// @ID list-tags
+// @Tags Tags
+// @Produce json
+// @Success 200 {object} dto.WebResponse{data=[]dto.TagResponse} "Tags in use"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /tags [get]
+// @Security ApiKeyAuth
+func (c *TagController) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	tags, err := c.TagService.List(ctx)
+	if err != nil {
+		logger.Error("Failed to list tags:", err)
+		writePostError(w, err)
+		return
+	}
+
+	data := make([]dto.TagResponse, len(tags))
+	for i, tag := range tags {
+		data[i] = dto.TagResponse{
+			Slug:      tag.Slug,
+			Name:      tag.Name,
+			PostCount: tag.PostCount,
+		}
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "Successfully Get tags",
+		Status:  1,
+		Data:    data,
+	}, http.StatusOK)
+}
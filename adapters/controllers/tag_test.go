@@ -0,0 +1,75 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTagRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	return req.WithContext(ctx)
+}
+
+func TestTagController_List(t *testing.T) {
+	mockService := new(mocks.MockTagService)
+	controller := &controllers.TagController{TagService: mockService}
+
+	mockService.On("List", mock.Anything).Return([]entities.Tag{{Slug: "go", Name: "Go", PostCount: 2}}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	controller.List(rec, newTagRequest("/tags"))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `{"slug":"go","name":"Go","post_count":2}`)
+	mockService.AssertExpectations(t)
+}
+
+func TestTagController_List_ServiceError(t *testing.T) {
+	mockService := new(mocks.MockTagService)
+	controller := &controllers.TagController{TagService: mockService}
+
+	mockService.On("List", mock.Anything).Return(nil, errors.New("db down")).Once()
+
+	rec := httptest.NewRecorder()
+	controller.List(rec, newTagRequest("/tags"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestPostController_GetAll_TagFilter(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{PostService: mockService}
+
+	mockService.On("GetAll", mock.Anything, mock.Anything, entities.PostFilter{Tags: []string{"go", "web"}, MatchAllTags: true}, 1, 10).
+		Return([]entities.Post{{Title: "Tagged", Tags: []string{"go", "web"}}}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	controller.GetAll(rec, newTagRequest("/post?tag=go&tag=web&match=all"))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"tags":["go","web"]`)
+	mockService.AssertExpectations(t)
+}
+
+func TestPostController_GetAll_UnknownTagMatch(t *testing.T) {
+	mockService := new(mocks.MockPostService)
+	controller := &controllers.PostController{PostService: mockService}
+
+	rec := httptest.NewRecorder()
+	controller.GetAll(rec, newTagRequest("/post?tag=go&match=some"))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type TrashController struct {
+	ports.TrashService
+}
+
+// ListPosts godoc
+// @Summary List deleted posts
+// @Description Lists the posts in the trash, most recently deleted first. Posts stay restorable until the trash retention runs out. Requires the posts:manage permission.
+// @ID list-trashed-posts
+// @Tags Trash
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Posts per page (default: 20, max: 100)"
+// @Success 200 {object} dto.WebResponse{data=dto.TrashedPostListResponse} "Deleted posts"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 403 {object} dto.WebResponse "Forbidden"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /admin/trash/posts [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *TrashController) ListPosts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	page, limit := trashPagination(r)
+	posts, err := c.TrashService.ListPosts(ctx, page, limit)
+	if err != nil {
+		logger.Error("Failed to list deleted posts:", err)
+		writeTrashError(w, err)
+		return
+	}
+
+	data := dto.TrashedPostListResponse{
+		Posts: make([]dto.TrashedPostResponse, len(posts)),
+		Page:  page,
+		Limit: limit,
+	}
+	for i, post := range posts {
+		data.Posts[i] = dto.TrashedPostResponse{
+			ID:        post.ID,
+			Title:     post.Title,
+			Body:      post.Body,
+			AuthorID:  post.User.ID,
+			CreatedAt: post.CreatedAt,
+		}
+		if post.DeletedAt != nil {
+			data.Posts[i].DeletedAt = *post.DeletedAt
+		}
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "success get deleted posts",
+		Status:  1,
+		Data:    data,
+	}, http.StatusOK)
+}
+
+// ListUsers godoc
+// @Summary List deleted users
+// @Description Lists the users in the trash, most recently deleted first. Their posts are hidden until they are restored. Requires the users:manage permission.
+// @ID list-trashed-users
+// @Tags Trash
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Users per page (default: 20, max: 100)"
+// @Success 200 {object} dto.WebResponse{data=dto.TrashedUserListResponse} "Deleted users"
+// @Failure 401 {object} dto.WebResponse "Unauthorized"
+// @Failure 403 {object} dto.WebResponse "Forbidden"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /admin/trash/users [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (c *TrashController) ListUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	page, limit := trashPagination(r)
+	users, err := c.TrashService.ListUsers(ctx, page, limit)
+	if err != nil {
+		logger.Error("Failed to list deleted users:", err)
+		writeTrashError(w, err)
+		return
+	}
+
+	data := dto.TrashedUserListResponse{
+		Users: make([]dto.TrashedUserResponse, len(users)),
+		Page:  page,
+		Limit: limit,
+	}
+	for i, user := range users {
+		data.Users[i] = dto.TrashedUserResponse{
+			Id:        user.ID.String(),
+			Email:     user.Email,
+			CreatedAt: user.CreatedAt,
+		}
+		if user.DeletedAt != nil {
+			data.Users[i].DeletedAt = *user.DeletedAt
+		}
+	}
+
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "success get deleted users",
+		Status:  1,
+		Data:    data,
+	}, http.StatusOK)
+}
+
+func trashPagination(r *http.Request) (int, int) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
+
+func writeTrashError(w http.ResponseWriter, err error) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: appErr.Message,
+			Status:  0,
+			Data:    nil,
+		}, int64(appErr.StatusCode))
+		return
+	}
+	helper.WriteResponse(w, dto.WebResponse{
+		Message: "An unexpected error occurred",
+		Status:  0,
+		Data:    nil,
+	}, http.StatusInternalServerError)
+}
@@ -0,0 +1,96 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTrashController_ListPosts(t *testing.T) {
+	mockService := new(mocks.MockTrashService)
+	controller := &controllers.TrashController{
+		TrashService: mockService,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/trash/posts?page=2&limit=500", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+
+	deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	authorID := uuid.New()
+	mockService.On("ListPosts", mock.Anything, 2, 100).Return([]entities.Post{
+		{ID: uuid.New(), Title: "Gone", User: &entities.User{ID: authorID}, DeletedAt: &deletedAt},
+	}, nil).Once()
+
+	controller.ListPosts(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data dto.TrashedPostListResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 100, response.Data.Limit)
+	assert.Len(t, response.Data.Posts, 1)
+	assert.Equal(t, authorID, response.Data.Posts[0].AuthorID)
+	assert.True(t, deletedAt.Equal(response.Data.Posts[0].DeletedAt))
+	mockService.AssertExpectations(t)
+}
+
+func TestTrashController_ListUsers(t *testing.T) {
+	mockService := new(mocks.MockTrashService)
+	controller := &controllers.TrashController{
+		TrashService: mockService,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/trash/users", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+
+	deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("ListUsers", mock.Anything, 1, 20).Return([]*entities.User{
+		{ID: uuid.New(), Email: "gone@example.com", DeletedAt: &deletedAt},
+	}, nil).Once()
+
+	controller.ListUsers(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data dto.TrashedUserListResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Data.Users, 1)
+	assert.Equal(t, "gone@example.com", response.Data.Users[0].Email)
+	mockService.AssertExpectations(t)
+}
+
+func TestTrashController_ListUsers_ServiceError(t *testing.T) {
+	mockService := new(mocks.MockTrashService)
+	controller := &controllers.TrashController{
+		TrashService: mockService,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/trash/users", nil)
+	rec := httptest.NewRecorder()
+	req = req.WithContext(context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+
+	mockService.On("ListUsers", mock.Anything, 1, 20).Return(nil, errors.New("db down")).Once()
+
+	controller.ListUsers(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	mockService.AssertExpectations(t)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/chud-lori/go-boilerplate/adapters/web/dto"
 	"github.com/chud-lori/go-boilerplate/adapters/web/helper"
@@ -71,6 +72,7 @@ func (controller *UserController) Create(w http.ResponseWriter, r *http.Request)
 			Id:        result.ID.String(),
 			Email:     result.Email,
 			CreatedAt: result.CreatedAt,
+			Roles:     result.Roles,
 		},
 	}
 	helper.WriteResponse(w, &response, http.StatusCreated)
@@ -127,6 +129,15 @@ func (controller *UserController) Update(w http.ResponseWriter, r *http.Request)
 	userResponse, err := controller.UserService.Update(ctx, userPayload)
 
 	if err != nil {
+		var validationErr *appErrors.ValidationErrors
+		if errors.As(err, &validationErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: strings.Join(validationErr.Messages, ", "),
+				Status:  0,
+				Data:    nil,
+			}, http.StatusBadRequest)
+			return
+		}
 		var appErr *appErrors.AppError
 		if errors.As(err, &appErr) {
 			helper.WriteResponse(w, dto.WebResponse{
@@ -200,6 +211,70 @@ func (controller *UserController) Delete(w http.ResponseWriter, r *http.Request)
 	helper.WriteResponse(w, &response, http.StatusOK)
 }
 
+// Restore godoc
+// @Summary Restore a deleted user
+// @Description Takes a user and their posts out of the trash. Requires the users:manage permission.
+// @ID restore-user
+// @Tags Users
+// @Produce json
+// @Param userId path string true "User ID (UUID)"
+// @Success 200 {object} dto.WebResponse{data=dto.UserResponse} "Successfully restored user"
+// @Failure 400 {object} dto.WebResponse "Invalid user ID"
+// @Failure 403 {object} dto.WebResponse "Forbidden"
+// @Failure 404 {object} dto.WebResponse "User not found in trash"
+// @Failure 409 {object} dto.WebResponse "The email address is used by another account"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /user/{userId}/restore [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (controller *UserController) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userId := r.PathValue("userId")
+	if _, err := uuid.Parse(userId); err != nil {
+		logger.Warn("Invalid userId UUID:", userId)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid userId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	result, err := controller.UserService.Restore(ctx, userId)
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		logger.Error("Failed to restore user: ", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.WebResponse{
+		Message: "success restore user",
+		Status:  1,
+		Data: dto.UserResponse{
+			Id:        result.ID.String(),
+			Email:     result.Email,
+			CreatedAt: result.CreatedAt,
+			Roles:     result.Roles,
+		},
+	}
+	helper.WriteResponse(w, &response, http.StatusOK)
+}
+
 // FindById godoc
 // @Summary Get user by ID
 // @Description Retrieves a single user's details by their ID.
@@ -292,3 +367,79 @@ func (controller *UserController) FindAll(w http.ResponseWriter, r *http.Request
 
 	helper.WriteResponse(w, &response, http.StatusOK)
 }
+
+// SetRoles godoc
+// @Summary Set the roles of a user
+// @Description Replaces the roles of a user. Requires the users:manage permission. The new roles apply once the user refreshes their tokens.
+// @ID set-user-roles
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param userId path string true "User ID (UUID)"
+// @Param request body dto.UserRolesRequest true "Roles to assign"
+// @Success 200 {object} dto.WebResponse{data=dto.UserResponse} "Successfully updated roles"
+// @Failure 400 {object} dto.WebResponse "Invalid request payload, invalid user ID or unknown role"
+// @Failure 403 {object} dto.WebResponse "Forbidden"
+// @Failure 404 {object} dto.WebResponse "User not found"
+// @Failure 500 {object} dto.WebResponse "Internal server error"
+// @Router /user/{userId}/roles [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (controller *UserController) SetRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := ctx.Value(logger.LoggerContextKey).(*logrus.Entry)
+
+	userId := r.PathValue("userId")
+	if _, err := uuid.Parse(userId); err != nil {
+		logger.Warn("Invalid userId UUID:", userId)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid userId format",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	var req dto.UserRolesRequest
+	if err := helper.GetPayload(r, &req); err != nil {
+		logger.Error("Failed to get Payload: ", err)
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "Invalid request payload",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusBadRequest)
+		return
+	}
+
+	result, err := controller.UserService.SetRoles(ctx, userId, req.Roles)
+	if err != nil {
+		logger.Error("Failed to set roles: ", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			helper.WriteResponse(w, dto.WebResponse{
+				Message: appErr.Message,
+				Status:  0,
+				Data:    nil,
+			}, int64(appErr.StatusCode))
+			return
+		}
+		helper.WriteResponse(w, dto.WebResponse{
+			Message: "An unexpected error occurred",
+			Status:  0,
+			Data:    nil,
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.WebResponse{
+		Message: "success set user roles",
+		Status:  1,
+		Data: dto.UserResponse{
+			Id:        result.ID.String(),
+			Email:     result.Email,
+			CreatedAt: result.CreatedAt,
+			Roles:     result.Roles,
+		},
+	}
+	helper.WriteResponse(w, &response, http.StatusOK)
+}
@@ -530,3 +530,113 @@ func TestUserController_FindAll_Failed(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestUserController_SetRoles_Success(t *testing.T) {
+	mockService := new(mocks.MockUserService)
+	controller := &controllers.UserController{
+		UserService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	userId := uuid.New()
+	bodyBytes, _ := json.Marshal(&dto.UserRolesRequest{Roles: []string{"admin"}})
+	req := httptest.NewRequest(http.MethodPut, "/api/user/"+userId.String()+"/roles", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	req.SetPathValue("userId", userId.String())
+	req = req.WithContext(ctx)
+
+	mockService.On("SetRoles", mock.Anything, userId.String(), []string{"admin"}).
+		Return(&entities.User{ID: userId, Email: "user@mail.com", Roles: []string{"admin"}}, nil)
+
+	controller.SetRoles(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data dto.UserResponse `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, []string{"admin"}, response.Data.Roles)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserController_SetRoles_UnknownRole(t *testing.T) {
+	mockService := new(mocks.MockUserService)
+	controller := &controllers.UserController{
+		UserService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	userId := uuid.New()
+	bodyBytes, _ := json.Marshal(&dto.UserRolesRequest{Roles: []string{"superuser"}})
+	req := httptest.NewRequest(http.MethodPut, "/api/user/"+userId.String()+"/roles", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	req.SetPathValue("userId", userId.String())
+	req = req.WithContext(ctx)
+
+	mockService.On("SetRoles", mock.Anything, userId.String(), []string{"superuser"}).
+		Return(nil, appErrors.NewBadRequestError("Unknown role", appErrors.ErrDataNotFound))
+
+	controller.SetRoles(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var response dto.WebResponse
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, "Unknown role", response.Message)
+}
+
+func TestUserController_Restore_Success(t *testing.T) {
+	mockService := new(mocks.MockUserService)
+	controller := &controllers.UserController{
+		UserService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	userId := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/user/"+userId.String()+"/restore", nil)
+	rec := httptest.NewRecorder()
+	req.SetPathValue("userId", userId.String())
+	req = req.WithContext(ctx)
+
+	mockService.On("Restore", mock.Anything, userId.String()).
+		Return(&entities.User{ID: userId, Email: "user@mail.com"}, nil)
+
+	controller.Restore(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data dto.UserResponse `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, "user@mail.com", response.Data.Email)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserController_Restore_EmailTaken(t *testing.T) {
+	mockService := new(mocks.MockUserService)
+	controller := &controllers.UserController{
+		UserService: mockService,
+	}
+
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	userId := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/user/"+userId.String()+"/restore", nil)
+	rec := httptest.NewRecorder()
+	req.SetPathValue("userId", userId.String())
+	req = req.WithContext(ctx)
+
+	mockService.On("Restore", mock.Anything, userId.String()).
+		Return(nil, appErrors.NewConflictError("The email address is used by another account now", nil))
+
+	controller.Restore(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
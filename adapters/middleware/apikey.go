@@ -1,30 +1,81 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
 	"github.com/sirupsen/logrus"
 )
 
-func APIKeyMiddleware(next http.Handler, apiKey string, logger *logrus.Logger) http.Handler {
-	mwLogger := logger.WithFields(logrus.Fields{
+// APIClientKey holds the *entities.APIKey the request was made with.
+const APIClientKey contextKey = "apiClient"
+
+// APIKeyMiddleware authenticates the X-API-KEY header and checks the key has the scope the
+// method needs. The key is stored under APIClientKey and its name is added to the request logger.
+func APIKeyMiddleware(next http.Handler, apiKeys ports.APIKeyService, baseLogger *logrus.Logger) http.Handler {
+	mwLogger := baseLogger.WithFields(logrus.Fields{
 		"layer": "middleware",
 	})
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip API key check for Swagger docs
-		if strings.HasPrefix(r.URL.Path, "/docs/") {
+		// Skip API key check for Swagger docs, the public JWKS, the emailed verification link
+		// and the identity provider redirecting the browser back after social login
+		if strings.HasPrefix(r.URL.Path, "/docs/") || strings.HasPrefix(r.URL.Path, "/.well-known/") ||
+			(r.Method == http.MethodGet && r.URL.Path == "/api/verify-email") ||
+			(r.Method == http.MethodGet && isSocialLoginCallback(r.URL.Path)) {
 			next.ServeHTTP(w, r)
 			return
 		}
-		reqApiKey := r.Header.Get("X-API-KEY")
 
-		if reqApiKey != apiKey {
-			mwLogger.Error("Invalid API KEY")
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apiKey, err := apiKeys.Authenticate(r.Context(), r.Header.Get("X-API-KEY"))
+		if err != nil {
+			var appErr *appErrors.AppError
+			if errors.As(err, &appErr) && appErr.StatusCode == http.StatusUnauthorized {
+				mwLogger.Warnf("Invalid API KEY: %s", appErr.Message)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			mwLogger.WithError(err).Error("Failed to authenticate API KEY")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
-		next.ServeHTTP(w, r)
+
+		scope := requiredScope(r.Method)
+		if !apiKey.HasScope(scope) {
+			mwLogger.Warnf("API client %s lacks the %s scope", apiKey.Name, scope)
+			http.Error(w, "Forbidden: API key lacks the "+scope+" scope", http.StatusForbidden)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), APIClientKey, apiKey)
+		if reqLogger, ok := ctx.Value(logger.LoggerContextKey).(*logrus.Entry); ok {
+			ctx = context.WithValue(ctx, logger.LoggerContextKey, reqLogger.WithField("APIClient", apiKey.Name))
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func requiredScope(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return entities.APIKeyScopeRead
+	default:
+		return entities.APIKeyScopeWrite
+	}
+}
+
+// isSocialLoginCallback matches /api/auth/oidc/{provider}/callback.
+func isSocialLoginCallback(path string) bool {
+	provider, ok := strings.CutPrefix(path, "/api/auth/oidc/")
+	if !ok {
+		return false
+	}
+	provider, ok = strings.CutSuffix(provider, "/callback")
+	return ok && provider != "" && !strings.Contains(provider, "/")
+}
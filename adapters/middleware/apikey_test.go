@@ -1,19 +1,39 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
 )
 
+func newAPIKeyService() *mocks.MockAPIKeyService {
+	svc := new(mocks.MockAPIKeyService)
+	svc.On("Authenticate", mock.Anything, "secret").Return(&entities.APIKey{
+		Name:   "billing",
+		Scopes: []string{entities.APIKeyScopeRead, entities.APIKeyScopeWrite},
+	}, nil).Maybe()
+	svc.On("Authenticate", mock.Anything, "read-only").Return(&entities.APIKey{
+		Name:   "dashboard",
+		Scopes: []string{entities.APIKeyScopeRead},
+	}, nil).Maybe()
+	svc.On("Authenticate", mock.Anything, "broken").Return(nil, errors.New("database down")).Maybe()
+	svc.On("Authenticate", mock.Anything, mock.Anything).Return(nil, appErrors.NewUnauthorizedError("Invalid API key", nil)).Maybe()
+	return svc
+}
+
 func TestAPIKeyMiddleware_ValidKey(t *testing.T) {
 	logger := logrus.New()
-	called := false
+	var client *entities.APIKey
 	h := APIKeyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		called = true
-	}), "secret", logger)
+		client, _ = r.Context().Value(APIClientKey).(*entities.APIKey)
+	}), newAPIKeyService(), logger)
 
 	req := httptest.NewRequest("GET", "/", nil)
 	req.Header.Set("X-API-KEY", "secret")
@@ -21,8 +41,11 @@ func TestAPIKeyMiddleware_ValidKey(t *testing.T) {
 
 	h.ServeHTTP(rw, req)
 
-	if !called {
-		t.Error("expected next handler to be called")
+	if client == nil {
+		t.Fatal("expected next handler to be called")
+	}
+	if client.Name != "billing" {
+		t.Errorf("expected client billing in context, got %q", client.Name)
 	}
 }
 
@@ -30,7 +53,7 @@ func TestAPIKeyMiddleware_InvalidKey(t *testing.T) {
 	logger := logrus.New()
 	h := APIKeyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should not call next handler")
-	}), "secret", logger)
+	}), newAPIKeyService(), logger)
 
 	req := httptest.NewRequest("GET", "/", nil)
 	req.Header.Set("X-API-KEY", "wrong")
@@ -47,7 +70,7 @@ func TestAPIKeyMiddleware_MissingKey(t *testing.T) {
 	logger := logrus.New()
 	h := APIKeyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should not call next handler")
-	}), "secret", logger)
+	}), newAPIKeyService(), logger)
 
 	req := httptest.NewRequest("GET", "/", nil)
 	rw := httptest.NewRecorder()
@@ -59,12 +82,56 @@ func TestAPIKeyMiddleware_MissingKey(t *testing.T) {
 	}
 }
 
+func TestAPIKeyMiddleware_LookupError(t *testing.T) {
+	logger := logrus.New()
+	h := APIKeyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), newAPIKeyService(), logger)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-API-KEY", "broken")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rw.Code)
+	}
+}
+
+func TestAPIKeyMiddleware_ScopeByMethod(t *testing.T) {
+	logger := logrus.New()
+	called := false
+	h := APIKeyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), newAPIKeyService(), logger)
+
+	req := httptest.NewRequest("GET", "/api/post", nil)
+	req.Header.Set("X-API-KEY", "read-only")
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	if !called {
+		t.Error("expected a read-only key to be allowed to GET")
+	}
+
+	called = false
+	req = httptest.NewRequest("POST", "/api/post", nil)
+	req.Header.Set("X-API-KEY", "read-only")
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	if called || rw.Code != http.StatusForbidden {
+		t.Errorf("expected a read-only key to get 403 on POST, got %d", rw.Code)
+	}
+}
+
 func TestAPIKeyMiddleware_SkipDocs(t *testing.T) {
 	logger := logrus.New()
 	called := false
 	h := APIKeyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		called = true
-	}), "secret", logger)
+	}), newAPIKeyService(), logger)
 
 	req := httptest.NewRequest("GET", "/docs/index.html", nil)
 	rw := httptest.NewRecorder()
@@ -74,4 +141,75 @@ func TestAPIKeyMiddleware_SkipDocs(t *testing.T) {
 	if !called {
 		t.Error("expected next handler to be called for /docs/ path")
 	}
-} 
\ No newline at end of file
+}
+
+func TestAPIKeyMiddleware_SkipJWKS(t *testing.T) {
+	logger := logrus.New()
+	called := false
+	h := APIKeyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), newAPIKeyService(), logger)
+
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if !called {
+		t.Error("expected next handler to be called for /.well-known/ path")
+	}
+}
+
+func TestAPIKeyMiddleware_SkipVerifyEmailLink(t *testing.T) {
+	logger := logrus.New()
+	called := false
+	h := APIKeyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), newAPIKeyService(), logger)
+
+	req := httptest.NewRequest("GET", "/api/verify-email?token=abc", nil)
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if !called {
+		t.Error("expected next handler to be called for the verification link")
+	}
+
+	called = false
+	req = httptest.NewRequest("POST", "/api/verify-email/resend", nil)
+	rw = httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if called || rw.Code != http.StatusUnauthorized {
+		t.Error("expected resend endpoint to require the API key")
+	}
+}
+
+func TestAPIKeyMiddleware_SkipSocialLoginCallback(t *testing.T) {
+	logger := logrus.New()
+	called := false
+	h := APIKeyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), newAPIKeyService(), logger)
+
+	req := httptest.NewRequest("GET", "/api/auth/oidc/google/callback?code=abc&state=xyz", nil)
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if !called {
+		t.Error("expected next handler to be called for the provider redirect")
+	}
+
+	called = false
+	req = httptest.NewRequest("GET", "/api/auth/oidc/google", nil)
+	rw = httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if called || rw.Code != http.StatusUnauthorized {
+		t.Error("expected starting a social login to require the API key")
+	}
+}
@@ -5,20 +5,37 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/audit"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
 	"github.com/sirupsen/logrus"
 )
 
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+const (
+	UserIDKey contextKey = "userID"
+	ClaimsKey contextKey = "claims"
+	// ImpersonatorIDKey holds the admin acting as UserIDKey, set only while impersonating.
+	ImpersonatorIDKey contextKey = "impersonatorID"
+)
 
-func JWTMiddleware(next http.Handler, tokenManager ports.TokenManager, logger *logrus.Logger) http.Handler {
+func JWTMiddleware(next http.Handler, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) http.Handler {
 	mwLogger := logger.WithFields(logrus.Fields{
 		"layer": "middleware",
 	})
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Personal access tokens are checked against the database, they have no cache markers.
+		if claims, ok := personalAccessClaims(r); ok {
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, ClaimsKey, claims)
+			ctx = audit.WithActor(ctx, claims.UserID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
 
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
@@ -27,14 +44,111 @@ func JWTMiddleware(next http.Handler, tokenManager ports.TokenManager, logger *l
 		}
 
 		token := strings.TrimPrefix(authHeader, "Bearer ")
-		userID, err := tokenManager.ValidateToken(token)
+		claims, err := tokenManager.ValidateToken(token)
 		if err != nil {
 			mwLogger.Warnf("Invalid token: %v", err)
 			http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), UserIDKey, userID)
+		if claims.Type != entities.TokenTypeAccess {
+			mwLogger.Warnf("Rejected %s token used as access token", claims.Type)
+			http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		revoked, err := isRevoked(r.Context(), cache, claims)
+		if err != nil {
+			mwLogger.WithError(err).Error("Failed to check token revocation")
+			http.Error(w, "Unauthorized: unable to verify token", http.StatusUnauthorized)
+			return
+		}
+		if revoked {
+			mwLogger.Warnf("Revoked token used by user %s", claims.UserID)
+			http.Error(w, "Unauthorized: token revoked", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+		ctx = context.WithValue(ctx, ClaimsKey, claims)
+		ctx = audit.WithActor(ctx, claims.UserID)
+
+		if claims.Impersonating() {
+			impLogger := mwLogger.WithFields(logrus.Fields{
+				"user_id":         claims.UserID,
+				"impersonator_id": claims.ImpersonatorID,
+			})
+			// Nothing can be deleted while impersonating, other destructive routes use NotImpersonating.
+			if r.Method == http.MethodDelete {
+				impLogger.Warnf("Impersonated request refused: %s %s", r.Method, r.URL.Path)
+				http.Error(w, "Forbidden: not allowed while impersonating", http.StatusForbidden)
+				return
+			}
+			impLogger.Infof("Impersonated request: %s %s", r.Method, r.URL.Path)
+			ctx = impersonationContext(ctx, claims)
+		}
+
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// OptionalJWTMiddleware lets anonymous requests through to next and authenticates the others
+// as JWTMiddleware does, so public routes can tell who is asking. A token that does not
+// authenticate is refused rather than ignored.
+func OptionalJWTMiddleware(next http.Handler, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) http.Handler {
+	authenticated := JWTMiddleware(next, tokenManager, cache, logger)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := personalAccessClaims(r); !ok && r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
+
+// impersonationContext names the impersonating admin in the context, for the audit log and
+// on every line logged while handling the request.
+func impersonationContext(ctx context.Context, claims *entities.TokenClaims) context.Context {
+	ctx = context.WithValue(ctx, ImpersonatorIDKey, claims.ImpersonatorID)
+	ctx = audit.WithImpersonator(ctx, claims.ImpersonatorID)
+	if reqLogger, ok := ctx.Value(logger.LoggerContextKey).(*logrus.Entry); ok {
+		ctx = context.WithValue(ctx, logger.LoggerContextKey, reqLogger.WithField("impersonator_id", claims.ImpersonatorID))
+	}
+	return ctx
+}
+
+func isRevoked(ctx context.Context, cache ports.Cache, claims *entities.TokenClaims) (bool, error) {
+	if claims.TokenID != "" {
+		val, err := cache.Get(ctx, auth.RevokedTokenKey(claims.TokenID))
+		if err != nil || val != "" {
+			return val != "", err
+		}
+	}
+
+	// The family is the session the token was issued for, so this also covers revoked sessions.
+	if claims.FamilyID != "" {
+		val, err := cache.Get(ctx, auth.RevokedFamilyKey(claims.FamilyID))
+		if err != nil || val != "" {
+			return val != "", err
+		}
+	}
+
+	revokedAt, err := cache.Get(ctx, auth.RevokedUserKey(claims.UserID))
+	if err != nil {
+		return false, err
+	}
+	if auth.IsRevokedBefore(revokedAt, claims.IssuedAt) {
+		return true, nil
+	}
+
+	// Signing the admin out everywhere also ends their impersonations.
+	if claims.Impersonating() {
+		revokedAt, err = cache.Get(ctx, auth.RevokedUserKey(claims.ImpersonatorID))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return auth.IsRevokedBefore(revokedAt, claims.IssuedAt), nil
+}
@@ -1,21 +1,30 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/audit"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	baseLogger "github.com/chud-lori/go-boilerplate/pkg/logger"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestJWTMiddleware_MissingToken(t *testing.T) {
 	logger := logrus.New()
 	m := &mocks.MockTokenManager{}
+	c := &mocks.MockCache{}
 	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should not call next handler")
-	}), m, logger)
+	}), m, c, logger)
 
 	req := httptest.NewRequest("GET", "/", nil)
 	rw := httptest.NewRecorder()
@@ -30,11 +39,12 @@ func TestJWTMiddleware_MissingToken(t *testing.T) {
 func TestJWTMiddleware_InvalidToken(t *testing.T) {
 	logger := logrus.New()
 	m := &mocks.MockTokenManager{}
-	m.On("ValidateToken", "badtoken").Return("", errors.New("invalid"))
+	m.On("ValidateToken", "badtoken").Return(nil, errors.New("invalid"))
+	c := &mocks.MockCache{}
 
 	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should not call next handler")
-	}), m, logger)
+	}), m, c, logger)
 
 	req := httptest.NewRequest("GET", "/", nil)
 	req.Header.Set("Authorization", "Bearer badtoken")
@@ -50,7 +60,16 @@ func TestJWTMiddleware_InvalidToken(t *testing.T) {
 func TestJWTMiddleware_ValidToken(t *testing.T) {
 	logger := logrus.New()
 	m := &mocks.MockTokenManager{}
-	m.On("ValidateToken", "goodtoken").Return("user123", nil)
+	m.On("ValidateToken", "goodtoken").Return(&entities.TokenClaims{
+		UserID:   "user123",
+		TokenID:  "jti-1",
+		FamilyID: "family-1",
+		Type:     entities.TokenTypeAccess,
+	}, nil)
+	c := &mocks.MockCache{}
+	c.On("Get", mock.Anything, auth.RevokedTokenKey("jti-1")).Return("", nil)
+	c.On("Get", mock.Anything, auth.RevokedFamilyKey("family-1")).Return("", nil)
+	c.On("Get", mock.Anything, auth.RevokedUserKey("user123")).Return("", nil)
 
 	called := false
 	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -59,7 +78,10 @@ func TestJWTMiddleware_ValidToken(t *testing.T) {
 		if uid != "user123" {
 			t.Errorf("expected userID to be injected, got %v", uid)
 		}
-	}), m, logger)
+		if actor := audit.ActorFromContext(r.Context()); actor != "user123" {
+			t.Errorf("expected audit actor to be injected, got %q", actor)
+		}
+	}), m, c, logger)
 
 	req := httptest.NewRequest("GET", "/", nil)
 	req.Header.Set("Authorization", "Bearer goodtoken")
@@ -70,4 +92,279 @@ func TestJWTMiddleware_ValidToken(t *testing.T) {
 	if !called {
 		t.Error("expected next handler to be called")
 	}
-} 
\ No newline at end of file
+}
+
+func TestJWTMiddleware_RevokedToken(t *testing.T) {
+	logger := logrus.New()
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "revokedtoken").Return(&entities.TokenClaims{
+		UserID:   "user123",
+		TokenID:  "jti-1",
+		FamilyID: "family-1",
+		Type:     entities.TokenTypeAccess,
+	}, nil)
+	c := &mocks.MockCache{}
+	c.On("Get", mock.Anything, auth.RevokedTokenKey("jti-1")).Return("user123", nil)
+
+	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), m, c, logger)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer revokedtoken")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rw.Code)
+	}
+}
+
+func TestJWTMiddleware_RevokedFamily(t *testing.T) {
+	logger := logrus.New()
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "signedout").Return(&entities.TokenClaims{
+		UserID:   "user123",
+		TokenID:  "jti-2",
+		FamilyID: "family-1",
+		Type:     entities.TokenTypeAccess,
+	}, nil)
+	c := &mocks.MockCache{}
+	c.On("Get", mock.Anything, auth.RevokedTokenKey("jti-2")).Return("", nil)
+	c.On("Get", mock.Anything, auth.RevokedFamilyKey("family-1")).Return("1", nil)
+
+	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), m, c, logger)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer signedout")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rw.Code)
+	}
+}
+
+func TestJWTMiddleware_RefreshTokenRejected(t *testing.T) {
+	logger := logrus.New()
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "refreshtoken").Return(&entities.TokenClaims{
+		UserID: "user123",
+		Type:   entities.TokenTypeRefresh,
+	}, nil)
+	c := &mocks.MockCache{}
+
+	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), m, c, logger)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer refreshtoken")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rw.Code)
+	}
+}
+
+func TestJWTMiddleware_RevokedUserSessions(t *testing.T) {
+	logger := logrus.New()
+	issuedAt := time.Now().Add(-time.Hour)
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "beforereset").Return(&entities.TokenClaims{
+		UserID:   "user123",
+		TokenID:  "jti-3",
+		FamilyID: "family-2",
+		Type:     entities.TokenTypeAccess,
+		IssuedAt: issuedAt,
+	}, nil)
+	c := &mocks.MockCache{}
+	c.On("Get", mock.Anything, auth.RevokedTokenKey("jti-3")).Return("", nil)
+	c.On("Get", mock.Anything, auth.RevokedFamilyKey("family-2")).Return("", nil)
+	c.On("Get", mock.Anything, auth.RevokedUserKey("user123")).Return(strconv.FormatInt(time.Now().Unix(), 10), nil)
+
+	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), m, c, logger)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer beforereset")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rw.Code)
+	}
+}
+
+func impersonationClaims() *entities.TokenClaims {
+	return &entities.TokenClaims{
+		UserID:         "user123",
+		TokenID:        "jti-4",
+		FamilyID:       "family-3",
+		Type:           entities.TokenTypeAccess,
+		ImpersonatorID: "admin-1",
+	}
+}
+
+func TestJWTMiddleware_ImpersonationToken(t *testing.T) {
+	logger := logrus.New()
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "impersonation").Return(impersonationClaims(), nil)
+	c := &mocks.MockCache{}
+	c.On("Get", mock.Anything, mock.Anything).Return("", nil)
+
+	called := false
+	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if uid := r.Context().Value(UserIDKey); uid != "user123" {
+			t.Errorf("expected impersonated user to be injected, got %v", uid)
+		}
+		if admin := r.Context().Value(ImpersonatorIDKey); admin != "admin-1" {
+			t.Errorf("expected impersonator to be injected, got %v", admin)
+		}
+		if admin := audit.ImpersonatorFromContext(r.Context()); admin != "admin-1" {
+			t.Errorf("expected audit impersonator to be injected, got %q", admin)
+		}
+		reqLogger := r.Context().Value(baseLogger.LoggerContextKey).(*logrus.Entry)
+		if reqLogger.Data["impersonator_id"] != "admin-1" {
+			t.Errorf("expected request logger to name the impersonator, got %v", reqLogger.Data)
+		}
+	}), m, c, logger)
+
+	req := httptest.NewRequest("PUT", "/post/1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), baseLogger.LoggerContextKey, logrus.NewEntry(logger)))
+	req.Header.Set("Authorization", "Bearer impersonation")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if !called {
+		t.Error("expected next handler to be called")
+	}
+	c.AssertCalled(t, "Get", mock.Anything, auth.RevokedUserKey("admin-1"))
+}
+
+func TestJWTMiddleware_ImpersonationBlocksDelete(t *testing.T) {
+	logger := logrus.New()
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "impersonation").Return(impersonationClaims(), nil)
+	c := &mocks.MockCache{}
+	c.On("Get", mock.Anything, mock.Anything).Return("", nil)
+
+	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), m, c, logger)
+
+	req := httptest.NewRequest("DELETE", "/post/1", nil)
+	req.Header.Set("Authorization", "Bearer impersonation")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rw.Code)
+	}
+}
+
+func TestJWTMiddleware_ImpersonationEndsWhenAdminRevoked(t *testing.T) {
+	logger := logrus.New()
+	claims := impersonationClaims()
+	claims.IssuedAt = time.Now().Add(-time.Minute)
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "impersonation").Return(claims, nil)
+	c := &mocks.MockCache{}
+	c.On("Get", mock.Anything, auth.RevokedUserKey("admin-1")).Return(strconv.FormatInt(time.Now().Unix(), 10), nil)
+	c.On("Get", mock.Anything, mock.Anything).Return("", nil)
+
+	h := JWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), m, c, logger)
+
+	req := httptest.NewRequest("GET", "/post/1", nil)
+	req.Header.Set("Authorization", "Bearer impersonation")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rw.Code)
+	}
+}
+
+func TestOptionalJWTMiddleware_Anonymous(t *testing.T) {
+	logger := logrus.New()
+	m := &mocks.MockTokenManager{}
+	c := &mocks.MockCache{}
+	called := false
+	h := OptionalJWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if r.Context().Value(ClaimsKey) != nil {
+			t.Error("expected no claims for an anonymous request")
+		}
+	}), m, c, logger)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if !called {
+		t.Error("expected next handler to be called")
+	}
+	m.AssertNotCalled(t, "ValidateToken", mock.Anything)
+}
+
+func TestOptionalJWTMiddleware_InvalidTokenRefused(t *testing.T) {
+	logger := logrus.New()
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "badtoken").Return(nil, errors.New("invalid"))
+	c := &mocks.MockCache{}
+
+	h := OptionalJWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), m, c, logger)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer badtoken")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rw.Code)
+	}
+}
+
+func TestOptionalJWTMiddleware_ValidToken(t *testing.T) {
+	logger := logrus.New()
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "goodtoken").Return(&entities.TokenClaims{
+		UserID: "user123",
+		Type:   entities.TokenTypeAccess,
+	}, nil)
+	c := &mocks.MockCache{}
+	c.On("Get", mock.Anything, auth.RevokedUserKey("user123")).Return("", nil)
+
+	var claims *entities.TokenClaims
+	h := OptionalJWTMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ = r.Context().Value(ClaimsKey).(*entities.TokenClaims)
+	}), m, c, logger)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer goodtoken")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if claims == nil || claims.UserID != "user123" {
+		t.Errorf("expected claims of user123, got %+v", claims)
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chud-lori/go-boilerplate/pkg/clientip"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// ClientIPMiddleware stores the client IP and user agent in the request context and adds the IP to the request logger.
+// Set trustProxyHeaders only when the server runs behind a proxy that overwrites X-Forwarded-For.
+func ClientIPMiddleware(next http.Handler, trustProxyHeaders bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := clientip.FromRequest(r, trustProxyHeaders)
+
+		ctx := clientip.WithIP(r.Context(), ip)
+		ctx = clientip.WithUserAgent(ctx, r.UserAgent())
+		if reqLogger, ok := ctx.Value(logger.LoggerContextKey).(*logrus.Entry); ok {
+			ctx = context.WithValue(ctx, logger.LoggerContextKey, reqLogger.WithField("ClientIP", ip))
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
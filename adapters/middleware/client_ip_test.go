@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/pkg/clientip"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+func TestClientIPMiddleware_StoresIPAndLoggerField(t *testing.T) {
+	var gotIP, gotUserAgent string
+	var gotField interface{}
+	h := ClientIPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIP = clientip.FromContext(r.Context())
+		gotUserAgent = clientip.UserAgentFromContext(r.Context())
+		gotField = r.Context().Value(logger.LoggerContextKey).(*logrus.Entry).Data["ClientIP"]
+	}), false)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	req.Header.Set("User-Agent", "curl/8.0")
+	req = req.WithContext(context.WithValue(req.Context(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if gotIP != "203.0.113.7" {
+		t.Errorf("expected 203.0.113.7, got %q", gotIP)
+	}
+	if gotUserAgent != "curl/8.0" {
+		t.Errorf("expected user agent curl/8.0, got %q", gotUserAgent)
+	}
+	if gotField != "203.0.113.7" {
+		t.Errorf("expected ClientIP log field, got %v", gotField)
+	}
+}
+
+func TestClientIPMiddleware_TrustsProxyHeaders(t *testing.T) {
+	var gotIP string
+	h := ClientIPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIP = clientip.FromContext(r.Context())
+	}), true)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Forwarded-For", "198.51.100.1, 10.0.0.1")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if gotIP != "198.51.100.1" {
+		t.Errorf("expected 198.51.100.1, got %q", gotIP)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
@@ -58,6 +59,7 @@ func LogTrafficMiddleware(next http.Handler, baseLogger *logrus.Logger) http.Han
 
 		newLogger := baseLogger.WithField("RequestID", requestID)
 		ctx := context.WithValue(r.Context(), "logger", newLogger)
+		ctx = context.WithValue(ctx, logger.RequestIDContextKey, requestID)
 		r = r.WithContext(ctx)
 
 		var requestBodyLog interface{}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	baseLogger "github.com/chud-lori/go-boilerplate/pkg/logger"
 	"github.com/sirupsen/logrus"
 )
 
@@ -61,4 +62,22 @@ func TestLogTrafficMiddleware_MasksSensitiveFields(t *testing.T) {
 	if !strings.Contains(logOutput, "foo") {
 		t.Errorf("expected foo to be logged, got %q", logOutput)
 	}
-} 
\ No newline at end of file
+} 
+
+func TestLogTrafficMiddleware_StoresRequestID(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+
+	var got string
+	h := LogTrafficMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = baseLogger.RequestIDFromContext(r.Context())
+	}), logger)
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "req-42" {
+		t.Errorf("expected request id req-42 in context, got %q", got)
+	}
+}
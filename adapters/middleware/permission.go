@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/sirupsen/logrus"
+)
+
+// Policy decides whether the authenticated caller may use a route.
+type Policy func(r *http.Request, claims *entities.TokenClaims) bool
+
+// Permission allows callers whose token grants every listed permission.
+func Permission(required ...string) Policy {
+	return func(r *http.Request, claims *entities.TokenClaims) bool {
+		return claims.HasPermission(required...)
+	}
+}
+
+// SelfOrPermission allows callers acting on their own account, named by the path
+// value param, and callers granted permission to act on any account.
+func SelfOrPermission(param, permission string) Policy {
+	return func(r *http.Request, claims *entities.TokenClaims) bool {
+		return r.PathValue(param) == claims.UserID || claims.HasPermission(permission)
+	}
+}
+
+// SessionToken allows callers signed in with a session token and refuses personal access
+// tokens, so a leaked token cannot be used to mint more of them.
+func SessionToken() Policy {
+	return func(r *http.Request, claims *entities.TokenClaims) bool {
+		return claims.Type == entities.TokenTypeAccess
+	}
+}
+
+// NotImpersonating refuses admins impersonating a user, for routes that change the account
+// or its credentials. DELETE requests are refused by JWTMiddleware already.
+func NotImpersonating() Policy {
+	return func(r *http.Request, claims *entities.TokenClaims) bool {
+		return !claims.Impersonating()
+	}
+}
+
+// RequirePermission rejects the request with 403 unless every policy allows it.
+// It has to run inside JWTMiddleware, which provides the claims.
+func RequirePermission(next http.Handler, logger *logrus.Logger, policies ...Policy) http.Handler {
+	mwLogger := logger.WithFields(logrus.Fields{
+		"layer": "middleware",
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := r.Context().Value(ClaimsKey).(*entities.TokenClaims)
+		if !ok {
+			http.Error(w, "Unauthorized: missing token", http.StatusUnauthorized)
+			return
+		}
+
+		for _, allowed := range policies {
+			if !allowed(r, claims) {
+				mwLogger.Warnf("User %s denied %s %s", claims.UserID, r.Method, r.URL.Path)
+				http.Error(w, "Forbidden: insufficient permissions", http.StatusForbidden)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
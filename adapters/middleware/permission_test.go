@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/sirupsen/logrus"
+)
+
+// serveWithClaims routes the request through a mux so path values are set, as in production.
+func serveWithClaims(pattern string, h http.Handler, req *http.Request, claims *entities.TokenClaims) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.Handle(pattern, h)
+	if claims != nil {
+		req = req.WithContext(context.WithValue(req.Context(), ClaimsKey, claims))
+	}
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, req)
+	return rw
+}
+
+func TestRequirePermission_Allowed(t *testing.T) {
+	called := false
+	h := RequirePermission(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), logrus.New(), Permission(entities.PermissionUsersManage))
+
+	claims := &entities.TokenClaims{UserID: "admin-1", Permissions: []string{entities.PermissionUsersManage}}
+	rw := serveWithClaims("GET /user", h, httptest.NewRequest("GET", "/user", nil), claims)
+
+	if rw.Code != http.StatusOK || !called {
+		t.Errorf("expected next handler to be called, got %d", rw.Code)
+	}
+}
+
+func TestRequirePermission_MissingPermission(t *testing.T) {
+	h := RequirePermission(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), logrus.New(), Permission(entities.PermissionUsersManage))
+
+	claims := &entities.TokenClaims{UserID: "user-1", Permissions: []string{entities.PermissionUsersRead}}
+	rw := serveWithClaims("GET /user", h, httptest.NewRequest("GET", "/user", nil), claims)
+
+	if rw.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rw.Code)
+	}
+}
+
+func TestRequirePermission_MissingClaims(t *testing.T) {
+	h := RequirePermission(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), logrus.New(), Permission(entities.PermissionUsersRead))
+
+	rw := serveWithClaims("GET /user", h, httptest.NewRequest("GET", "/user", nil), nil)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rw.Code)
+	}
+}
+
+func TestRequirePermission_SelfOrPermission(t *testing.T) {
+	h := RequirePermission(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		logrus.New(), Permission(entities.PermissionUsersWrite), SelfOrPermission("userId", entities.PermissionUsersManage))
+
+	user := &entities.TokenClaims{UserID: "user-1", Permissions: []string{entities.PermissionUsersWrite}}
+	admin := &entities.TokenClaims{UserID: "admin-1", Permissions: []string{entities.PermissionUsersWrite, entities.PermissionUsersManage}}
+
+	tests := []struct {
+		name   string
+		path   string
+		claims *entities.TokenClaims
+		want   int
+	}{
+		{"own account", "/user/user-1", user, http.StatusOK},
+		{"other account", "/user/user-2", user, http.StatusForbidden},
+		{"admin on other account", "/user/user-2", admin, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := serveWithClaims("PUT /user/{userId}", h, httptest.NewRequest("PUT", tt.path, nil), tt.claims)
+			if rw.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, rw.Code)
+			}
+		})
+	}
+}
+
+func TestSessionToken_RefusesPersonalAccessToken(t *testing.T) {
+	h := RequirePermission(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), logrus.New(), SessionToken())
+
+	claims := &entities.TokenClaims{UserID: "user-1", Type: entities.TokenTypePersonalAccess}
+	rw := serveWithClaims("POST /tokens", h, httptest.NewRequest("POST", "/tokens", nil), claims)
+
+	if rw.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rw.Code)
+	}
+}
+
+func TestNotImpersonating_RefusesImpersonationToken(t *testing.T) {
+	h := RequirePermission(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), logrus.New(), NotImpersonating())
+
+	claims := &entities.TokenClaims{UserID: "user-1", Type: entities.TokenTypeAccess, ImpersonatorID: "admin-1"}
+	rw := serveWithClaims("PUT /user/{userId}", h, httptest.NewRequest("PUT", "/user/user-1", nil), claims)
+
+	if rw.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rw.Code)
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// personalAccessClaimsKey holds the claims of a personal access token until JWTMiddleware
+// picks them up. It is unexported so nothing else can place claims there.
+const personalAccessClaimsKey contextKey = "personalAccessClaims"
+
+// PersonalAccessTokenMiddleware authenticates bearer tokens that are personal access tokens
+// rather than JWTs. Protected routes still go through JWTMiddleware, which accepts the
+// claims resolved here in place of a session token. Other requests pass through untouched.
+func PersonalAccessTokenMiddleware(next http.Handler, tokens ports.PersonalAccessTokenService, logger *logrus.Logger) http.Handler {
+	mwLogger := logger.WithFields(logrus.Fields{
+		"layer": "middleware",
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || !auth.IsPersonalAccessToken(token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, err := tokens.Authenticate(r.Context(), token)
+		if err != nil {
+			var appErr *appErrors.AppError
+			if errors.As(err, &appErr) && appErr.StatusCode == http.StatusUnauthorized {
+				mwLogger.Warnf("Invalid personal access token: %s", appErr.Message)
+				http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
+				return
+			}
+			mwLogger.WithError(err).Error("Failed to authenticate personal access token")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), personalAccessClaimsKey, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// personalAccessClaims returns the claims PersonalAccessTokenMiddleware resolved for the request.
+func personalAccessClaims(r *http.Request) (*entities.TokenClaims, bool) {
+	claims, ok := r.Context().Value(personalAccessClaimsKey).(*entities.TokenClaims)
+	return claims, ok
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPersonalAccessTokenService() *mocks.MockPersonalAccessTokenService {
+	svc := new(mocks.MockPersonalAccessTokenService)
+	svc.On("Authenticate", mock.Anything, "gbp_valid").Return(&entities.TokenClaims{
+		UserID:      "user123",
+		TokenID:     "pat-1",
+		Type:        entities.TokenTypePersonalAccess,
+		Permissions: []string{entities.PermissionPostsWrite},
+	}, nil).Maybe()
+	svc.On("Authenticate", mock.Anything, "gbp_broken").Return(nil, errors.New("database down")).Maybe()
+	svc.On("Authenticate", mock.Anything, mock.Anything).Return(nil, appErrors.NewUnauthorizedError("Invalid personal access token", nil)).Maybe()
+	return svc
+}
+
+// withPersonalAccessTokens puts PersonalAccessTokenMiddleware in front of JWTMiddleware, as in production.
+func withPersonalAccessTokens(next http.Handler, tokenManager *mocks.MockTokenManager) http.Handler {
+	logger := logrus.New()
+	return PersonalAccessTokenMiddleware(JWTMiddleware(next, tokenManager, &mocks.MockCache{}, logger), newPersonalAccessTokenService(), logger)
+}
+
+func TestPersonalAccessTokenMiddleware_ValidToken(t *testing.T) {
+	m := &mocks.MockTokenManager{}
+	var claims *entities.TokenClaims
+	h := withPersonalAccessTokens(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ = r.Context().Value(ClaimsKey).(*entities.TokenClaims)
+		if uid := r.Context().Value(UserIDKey); uid != "user123" {
+			t.Errorf("expected userID to be injected, got %v", uid)
+		}
+	}), m)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer gbp_valid")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if claims == nil {
+		t.Fatal("expected next handler to be called")
+	}
+	if claims.Type != entities.TokenTypePersonalAccess {
+		t.Errorf("expected personal access claims, got %s", claims.Type)
+	}
+	m.AssertNotCalled(t, "ValidateToken", mock.Anything)
+}
+
+func TestPersonalAccessTokenMiddleware_InvalidToken(t *testing.T) {
+	h := withPersonalAccessTokens(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), &mocks.MockTokenManager{})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer gbp_revoked")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rw.Code)
+	}
+}
+
+func TestPersonalAccessTokenMiddleware_ServiceError(t *testing.T) {
+	h := withPersonalAccessTokens(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), &mocks.MockTokenManager{})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer gbp_broken")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rw.Code)
+	}
+}
+
+func TestPersonalAccessTokenMiddleware_PassesJWTThrough(t *testing.T) {
+	m := &mocks.MockTokenManager{}
+	m.On("ValidateToken", "jwt").Return(nil, errors.New("invalid"))
+
+	h := withPersonalAccessTokens(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not call next handler")
+	}), m)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer jwt")
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rw.Code)
+	}
+	m.AssertCalled(t, "ValidateToken", "jwt")
+}
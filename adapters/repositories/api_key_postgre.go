@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const apiKeyColumns = "id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_by, created_at"
+
+type APIKeyRepositoryPostgre struct {
+}
+
+func (r *APIKeyRepositoryPostgre) Save(ctx context.Context, tx ports.Transaction, key *entities.APIKey) (*entities.APIKey, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `
+            INSERT INTO api_keys (name, prefix, key_hash, scopes, expires_at, created_by)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id, created_at`
+	err := tx.QueryRowContext(ctx, query, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), key.ExpiresAt, key.CreatedBy).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		logger.WithError(err).Error("Failed to insert api key")
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepositoryPostgre) FindByHash(ctx context.Context, tx ports.Transaction, keyHash string) (*entities.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = $1"
+	key, err := scanAPIKey(tx.QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepositoryPostgre) FindAll(ctx context.Context, tx ports.Transaction) ([]*entities.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys ORDER BY created_at DESC"
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*entities.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepositoryPostgre) Revoke(ctx context.Context, tx ports.Transaction, id uuid.UUID) (*entities.APIKey, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := "UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL RETURNING " + apiKeyColumns
+	key, err := scanAPIKey(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.ErrDataNotFound
+		}
+		logger.WithError(err).Error("Error Revoke")
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepositoryPostgre) TouchLastUsed(ctx context.Context, tx ports.Transaction, id uuid.UUID) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	if _, err := tx.ExecContext(ctx, "UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1", id); err != nil {
+		logger.WithError(err).Error("Failed to update api key last use")
+		return err
+	}
+
+	return nil
+}
+
+// scanAPIKey reads a row selected with apiKeyColumns.
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*entities.APIKey, error) {
+	var key entities.APIKey
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.KeyHash, pq.Array(&key.Scopes),
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedBy, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,60 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepository_Lifecycle(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.APIKeyRepository, error) {
+			return &repositories.APIKeyRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.APIKeyRepository, tx ports.Transaction) {
+			expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+			saved, err := repo.Save(ctx, tx, &entities.APIKey{
+				Name:      "billing",
+				Prefix:    "gbk_abcdefgh",
+				KeyHash:   "hash-1",
+				Scopes:    []string{entities.APIKeyScopeRead},
+				ExpiresAt: &expiresAt,
+			})
+			require.NoError(t, err)
+			require.NotZero(t, saved.ID)
+
+			found, err := repo.FindByHash(ctx, tx, "hash-1")
+			require.NoError(t, err)
+			require.Equal(t, "billing", found.Name)
+			require.Equal(t, []string{entities.APIKeyScopeRead}, found.Scopes)
+			require.Nil(t, found.LastUsedAt)
+
+			require.NoError(t, repo.TouchLastUsed(ctx, tx, saved.ID))
+			found, err = repo.FindByHash(ctx, tx, "hash-1")
+			require.NoError(t, err)
+			require.NotNil(t, found.LastUsedAt)
+
+			keys, err := repo.FindAll(ctx, tx)
+			require.NoError(t, err)
+			require.Len(t, keys, 1)
+
+			revoked, err := repo.Revoke(ctx, tx, saved.ID)
+			require.NoError(t, err)
+			require.NotNil(t, revoked.RevokedAt)
+			require.Equal(t, "hash-1", revoked.KeyHash)
+
+			_, err = repo.Revoke(ctx, tx, saved.ID)
+			require.ErrorIs(t, err, appErrors.ErrDataNotFound)
+
+			_, err = repo.FindByHash(ctx, tx, "missing")
+			require.ErrorIs(t, err, appErrors.ErrDataNotFound)
+		},
+	)
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type AuditRepositoryPostgre struct {
+}
+
+func (r *AuditRepositoryPostgre) Save(ctx context.Context, tx ports.Transaction, event *entities.AuditEvent) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	details, err := json.Marshal(event.Details)
+	if err != nil {
+		return err
+	}
+	if event.Details == nil {
+		details = []byte("{}")
+	}
+
+	query := `
+            INSERT INTO audit_events (actor_id, impersonator_id, action, target_type, target_id, ip, request_id, outcome, details)
+            VALUES (NULLIF($1, '')::uuid, NULLIF($2, '')::uuid, $3, $4, $5, $6, $7, $8, $9)
+            RETURNING id, occurred_at`
+	err = tx.QueryRowContext(ctx, query, event.ActorID, event.ImpersonatorID, event.Action, event.TargetType, event.TargetID,
+		event.IP, event.RequestID, event.Outcome, details).
+		Scan(&event.ID, &event.OccurredAt)
+	if err != nil {
+		logger.WithError(err).Error("Failed to insert audit event")
+		return err
+	}
+
+	return nil
+}
+
+func (r *AuditRepositoryPostgre) Find(ctx context.Context, tx ports.Transaction, filter entities.AuditEventFilter, pagination entities.PaginationParams) ([]*entities.AuditEvent, int, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	// Ids are compared as uuid so that their indexes are used.
+	if filter.ActorID != "" {
+		where("actor_id = $%d::uuid", filter.ActorID)
+	}
+	if filter.ImpersonatorID != "" {
+		where("impersonator_id = $%d::uuid", filter.ImpersonatorID)
+	}
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		where("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		where("target_id = $%d", filter.TargetID)
+	}
+	if filter.Outcome != "" {
+		where("outcome = $%d", filter.Outcome)
+	}
+	if !filter.From.IsZero() {
+		where("occurred_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("occurred_at < $%d", filter.To)
+	}
+
+	query := `
+            SELECT id, occurred_at, COALESCE(actor_id::text, ''), COALESCE(impersonator_id::text, ''), action, target_type, target_id,
+                   ip, request_id, outcome, details, COUNT(*) OVER ()
+            FROM audit_events`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY occurred_at DESC, id LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).Error("Failed query audit events")
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	events := []*entities.AuditEvent{}
+	total := 0
+	for rows.Next() {
+		var event entities.AuditEvent
+		var details []byte
+		if err := rows.Scan(&event.ID, &event.OccurredAt, &event.ActorID, &event.ImpersonatorID, &event.Action, &event.TargetType,
+			&event.TargetID, &event.IP, &event.RequestID, &event.Outcome, &details, &total); err != nil {
+			return nil, 0, err
+		}
+		if err := json.Unmarshal(details, &event.Details); err != nil {
+			return nil, 0, err
+		}
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// A page past the end has no rows to carry the total.
+	if len(events) == 0 && pagination.Page > 1 {
+		countQuery := "SELECT COUNT(*) FROM audit_events"
+		if len(conditions) > 0 {
+			countQuery += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		if err := tx.QueryRowContext(ctx, countQuery, args[:len(args)-2]...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,99 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository_SaveAndFind(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.AuditRepository, error) {
+			return &repositories.AuditRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.AuditRepository, tx ports.Transaction) {
+			actor := uuid.NewString()
+			target := uuid.NewString()
+
+			signIn := &entities.AuditEvent{
+				ActorID:    actor,
+				Action:     entities.AuditActionSignIn,
+				TargetType: entities.AuditTargetUser,
+				TargetID:   actor,
+				IP:         "203.0.113.7",
+				RequestID:  "req-1",
+				Outcome:    entities.AuditOutcomeSuccess,
+			}
+			require.NoError(t, repo.Save(ctx, tx, signIn))
+			require.NotEqual(t, uuid.Nil, signIn.ID)
+
+			require.NoError(t, repo.Save(ctx, tx, &entities.AuditEvent{
+				Action:     entities.AuditActionSignIn,
+				TargetType: entities.AuditTargetUser,
+				Outcome:    entities.AuditOutcomeFailure,
+				Details:    map[string]string{"reason": "unknown_email"},
+			}))
+			impersonator := uuid.NewString()
+			require.NoError(t, repo.Save(ctx, tx, &entities.AuditEvent{
+				ActorID:        actor,
+				ImpersonatorID: impersonator,
+				Action:         entities.AuditActionPostDelete,
+				TargetType:     entities.AuditTargetPost,
+				TargetID:       target,
+				Outcome:        entities.AuditOutcomeSuccess,
+			}))
+
+			page := entities.PaginationParams{Page: 1, Limit: 10}
+
+			events, total, err := repo.Find(ctx, tx, entities.AuditEventFilter{ActorID: actor}, page)
+			require.NoError(t, err)
+			require.Equal(t, 2, total)
+			require.Len(t, events, 2)
+
+			events, total, err = repo.Find(ctx, tx, entities.AuditEventFilter{Outcome: entities.AuditOutcomeFailure, Action: entities.AuditActionSignIn}, page)
+			require.NoError(t, err)
+			require.Equal(t, 1, total)
+			require.Empty(t, events[0].ActorID)
+			require.Equal(t, "unknown_email", events[0].Details["reason"])
+
+			events, _, err = repo.Find(ctx, tx, entities.AuditEventFilter{TargetType: entities.AuditTargetPost, TargetID: target}, page)
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			require.Equal(t, entities.AuditActionPostDelete, events[0].Action)
+			require.Equal(t, impersonator, events[0].ImpersonatorID)
+
+			events, _, err = repo.Find(ctx, tx, entities.AuditEventFilter{ImpersonatorID: impersonator}, page)
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+
+			events, total, err = repo.Find(ctx, tx, entities.AuditEventFilter{ActorID: actor}, entities.PaginationParams{Page: 3, Limit: 1})
+			require.NoError(t, err)
+			require.Empty(t, events)
+			require.Equal(t, 2, total)
+
+			events, _, err = repo.Find(ctx, tx, entities.AuditEventFilter{ActorID: actor, From: time.Now().Add(time.Hour)}, page)
+			require.NoError(t, err)
+			require.Empty(t, events)
+		})
+}
+
+func TestAuditRepository_AppendOnly(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.AuditRepository, error) {
+			return &repositories.AuditRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.AuditRepository, tx ports.Transaction) {
+			event := &entities.AuditEvent{Action: entities.AuditActionSignUp, Outcome: entities.AuditOutcomeSuccess}
+			require.NoError(t, repo.Save(ctx, tx, event))
+
+			_, err := tx.ExecContext(ctx, "UPDATE audit_events SET outcome = 'failure' WHERE id = $1", event.ID)
+			require.ErrorContains(t, err, "append-only")
+		})
+}
@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type CommentRepositoryPostgre struct {
+}
+
+const commentColumns = "id, post_id, parent_id, author_id, body, created_at, updated_at, deleted_at"
+
+func (r *CommentRepositoryPostgre) Save(ctx context.Context, tx ports.Transaction, comment *entities.Comment) (*entities.Comment, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `
+            INSERT INTO comments (post_id, parent_id, author_id, body)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id, created_at`
+	err := tx.QueryRowContext(ctx, query, comment.PostID, comment.ParentID, comment.AuthorID, comment.Body).
+		Scan(&comment.ID, &comment.CreatedAt)
+	if err != nil {
+		logger.WithError(err).Error("Failed to insert comment")
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (r *CommentRepositoryPostgre) GetById(ctx context.Context, tx ports.Transaction, id uuid.UUID) (*entities.Comment, error) {
+	query := "SELECT " + commentColumns + " FROM comments WHERE id = $1 AND deleted_at IS NULL"
+	comment, err := scanComment(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (r *CommentRepositoryPostgre) Update(ctx context.Context, tx ports.Transaction, id uuid.UUID, body string) (*entities.Comment, error) {
+	query := `
+            UPDATE comments SET body = $2, updated_at = CURRENT_TIMESTAMP
+            WHERE id = $1 AND deleted_at IS NULL
+            RETURNING ` + commentColumns
+	comment, err := scanComment(tx.QueryRowContext(ctx, query, id, body))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (r *CommentRepositoryPostgre) Delete(ctx context.Context, tx ports.Transaction, id uuid.UUID) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	result, err := tx.ExecContext(ctx, "UPDATE comments SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return appErrors.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (r *CommentRepositoryPostgre) ListByPost(ctx context.Context, tx ports.Transaction, postID uuid.UUID, after *entities.CommentCursor, limit int) ([]*entities.Comment, error) {
+	query := "SELECT " + commentColumns + " FROM comments WHERE post_id = $1"
+	args := []interface{}{postID}
+	if after != nil {
+		query += " AND (created_at, id) > ($2, $3)"
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	query += fmt.Sprintf(" ORDER BY created_at, id LIMIT $%d", len(args)+1)
+	args = append(args, limit)
+
+	return queryComments(ctx, tx, query, args...)
+}
+
+func (r *CommentRepositoryPostgre) ListThreads(ctx context.Context, tx ports.Transaction, postID uuid.UUID, after *entities.CommentCursor, limit, maxReplies int) ([]*entities.Comment, error) {
+	roots := "SELECT " + commentColumns + " FROM comments WHERE post_id = $1 AND parent_id IS NULL"
+	args := []interface{}{postID}
+	if after != nil {
+		roots += " AND (created_at, id) > ($2, $3)"
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	roots += fmt.Sprintf(" ORDER BY created_at, id LIMIT $%d", len(args)+1)
+	args = append(args, limit)
+
+	// Every reply remembers its top-level comment, which is ranked first in its thread. A reply
+	// is younger than its parent, so the oldest replies kept never miss their parent.
+	query := fmt.Sprintf(`
+            WITH RECURSIVE thread AS (
+                SELECT r.*, r.id AS root_id FROM (`+roots+`) r
+                UNION ALL
+                SELECT c.id, c.post_id, c.parent_id, c.author_id, c.body, c.created_at, c.updated_at, c.deleted_at, t.root_id
+                FROM comments c
+                JOIN thread t ON c.parent_id = t.id
+            )
+            SELECT `+commentColumns+`, reply_count FROM (
+                SELECT thread.*,
+                    ROW_NUMBER() OVER (PARTITION BY root_id ORDER BY id <> root_id, created_at, id) AS position,
+                    COUNT(*) OVER (PARTITION BY root_id) - 1 AS reply_count
+                FROM thread
+            ) ranked
+            WHERE position <= $%d
+            ORDER BY created_at, id`, len(args)+1)
+	args = append(args, maxReplies+1)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*entities.Comment{}
+	for rows.Next() {
+		var replyCount int
+		comment, err := scanComment(replyCountScanner{rows, &replyCount})
+		if err != nil {
+			return nil, err
+		}
+		if comment.ParentID == nil {
+			comment.ReplyCount = replyCount
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// replyCountScanner scans the reply_count column ListThreads selects after the comment columns.
+type replyCountScanner struct {
+	rows       *sql.Rows
+	replyCount *int
+}
+
+func (s replyCountScanner) Scan(dest ...interface{}) error {
+	return s.rows.Scan(append(dest, s.replyCount)...)
+}
+
+// rowScanner is the Scan of *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanComment(row rowScanner) (*entities.Comment, error) {
+	var comment entities.Comment
+	var parentID uuid.NullUUID
+	err := row.Scan(&comment.ID, &comment.PostID, &parentID, &comment.AuthorID, &comment.Body,
+		&comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		comment.ParentID = &parentID.UUID
+	}
+	return &comment, nil
+}
+
+func queryComments(ctx context.Context, tx ports.Transaction, query string, args ...interface{}) ([]*entities.Comment, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*entities.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
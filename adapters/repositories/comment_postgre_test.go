@@ -0,0 +1,94 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentRepository_Threads(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.CommentRepository, error) {
+			return &repositories.CommentRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.CommentRepository, tx ports.Transaction) {
+			users := &repositories.UserRepositoryPostgre{}
+			user, err := users.Save(ctx, tx, &entities.User{Email: "comments@example.com", Password: "hashed"})
+			require.NoError(t, err)
+
+			posts := &repositories.PostRepositoryPostgre{}
+			post, err := posts.Save(ctx, tx, &entities.Post{Title: "Discussed", User: &entities.User{ID: user.ID}})
+			require.NoError(t, err)
+
+			root, err := repo.Save(ctx, tx, &entities.Comment{PostID: post.ID, AuthorID: user.ID, Body: "root"})
+			require.NoError(t, err)
+			reply, err := repo.Save(ctx, tx, &entities.Comment{PostID: post.ID, ParentID: &root.ID, AuthorID: user.ID, Body: "reply"})
+			require.NoError(t, err)
+			_, err = repo.Save(ctx, tx, &entities.Comment{PostID: post.ID, ParentID: &reply.ID, AuthorID: user.ID, Body: "nested"})
+			require.NoError(t, err)
+			other, err := repo.Save(ctx, tx, &entities.Comment{PostID: post.ID, AuthorID: user.ID, Body: "other"})
+			require.NoError(t, err)
+
+			edited, err := repo.Update(ctx, tx, other.ID, "edited")
+			require.NoError(t, err)
+			require.Equal(t, "edited", edited.Body)
+			require.NotNil(t, edited.UpdatedAt)
+
+			// A deleted comment stays listed, but can no longer be changed.
+			require.NoError(t, repo.Delete(ctx, tx, root.ID))
+			_, err = repo.GetById(ctx, tx, root.ID)
+			require.ErrorIs(t, err, appErrors.ErrDataNotFound)
+			require.ErrorIs(t, repo.Delete(ctx, tx, root.ID), appErrors.ErrDataNotFound)
+
+			got, err := posts.GetById(ctx, tx, post.ID)
+			require.NoError(t, err)
+			require.Equal(t, 3, got.CommentCount)
+
+			// Paging through the flat list visits every comment once.
+			seen := map[string]bool{}
+			var after *entities.CommentCursor
+			for i := 0; i < 4; i++ {
+				page, err := repo.ListByPost(ctx, tx, post.ID, after, 1)
+				require.NoError(t, err)
+				require.Len(t, page, 1)
+				seen[page[0].ID.String()] = true
+				after = &entities.CommentCursor{CreatedAt: page[0].CreatedAt, ID: page[0].ID}
+			}
+			require.Len(t, seen, 4)
+			last, err := repo.ListByPost(ctx, tx, post.ID, after, 1)
+			require.NoError(t, err)
+			require.Empty(t, last)
+
+			threads, err := repo.ListThreads(ctx, tx, post.ID, nil, 10, 50)
+			require.NoError(t, err)
+			require.Len(t, threads, 4)
+			roots := 0
+			for _, comment := range threads {
+				if comment.ParentID == nil {
+					roots++
+				}
+				if comment.ID == root.ID {
+					require.NotNil(t, comment.DeletedAt)
+					require.Equal(t, 2, comment.ReplyCount)
+				}
+			}
+			require.Equal(t, 2, roots)
+
+			// Only the oldest replies of each thread are listed, the count still covers them all.
+			threads, err = repo.ListThreads(ctx, tx, post.ID, nil, 10, 1)
+			require.NoError(t, err)
+			require.Len(t, threads, 3)
+			require.Equal(t, root.ID, threads[0].ID)
+			require.Equal(t, 2, threads[0].ReplyCount)
+			require.Equal(t, reply.ID, threads[1].ID)
+			require.Equal(t, other.ID, threads[2].ID)
+			require.Zero(t, threads[2].ReplyCount)
+		},
+	)
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type IdentityRepositoryPostgre struct {
+}
+
+func (r *IdentityRepositoryPostgre) Save(ctx context.Context, tx ports.Transaction, identity *entities.UserIdentity) (*entities.UserIdentity, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `
+            INSERT INTO user_identities (provider, subject, user_id, email)
+            VALUES ($1, $2, $3, $4)
+            RETURNING created_at`
+	err := tx.QueryRowContext(ctx, query, identity.Provider, identity.Subject, identity.UserID, identity.Email).Scan(&identity.CreatedAt)
+	if err != nil {
+		logger.WithError(err).Error("Failed to insert user identity")
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (r *IdentityRepositoryPostgre) FindByProviderSubject(ctx context.Context, tx ports.Transaction, provider, subject string) (*entities.UserIdentity, error) {
+	query := `
+            SELECT provider, subject, user_id, email, created_at
+            FROM user_identities
+            WHERE provider = $1 AND subject = $2`
+	var identity entities.UserIdentity
+	err := tx.QueryRowContext(ctx, query, provider, subject).Scan(
+		&identity.Provider, &identity.Subject, &identity.UserID, &identity.Email, &identity.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &identity, nil
+}
@@ -0,0 +1,43 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityRepository_SaveAndFind(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.IdentityRepository, error) {
+			return &repositories.IdentityRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.IdentityRepository, tx ports.Transaction) {
+			users := &repositories.UserRepositoryPostgre{}
+			user, err := users.Save(ctx, tx, &entities.User{Email: "social@example.com", Password: "hashed"})
+			require.NoError(t, err)
+
+			saved, err := repo.Save(ctx, tx, &entities.UserIdentity{
+				Provider: "google",
+				Subject:  "1234567890",
+				UserID:   user.ID,
+				Email:    user.Email,
+			})
+			require.NoError(t, err)
+			require.False(t, saved.CreatedAt.IsZero())
+
+			found, err := repo.FindByProviderSubject(ctx, tx, "google", "1234567890")
+			require.NoError(t, err)
+			require.Equal(t, user.ID, found.UserID)
+			require.Equal(t, "social@example.com", found.Email)
+
+			_, err = repo.FindByProviderSubject(ctx, tx, "github", "1234567890")
+			require.ErrorIs(t, err, appErrors.ErrDataNotFound)
+		},
+	)
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const personalAccessTokenColumns = "id, user_id, name, prefix, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at"
+
+type PersonalAccessTokenRepositoryPostgre struct {
+}
+
+func (r *PersonalAccessTokenRepositoryPostgre) Save(ctx context.Context, tx ports.Transaction, token *entities.PersonalAccessToken) (*entities.PersonalAccessToken, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `
+            INSERT INTO personal_access_tokens (user_id, name, prefix, token_hash, scopes, expires_at)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id, created_at`
+	err := tx.QueryRowContext(ctx, query, token.UserID, token.Name, token.Prefix, token.TokenHash, pq.Array(token.Scopes), token.ExpiresAt).
+		Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		logger.WithError(err).Error("Failed to insert personal access token")
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (r *PersonalAccessTokenRepositoryPostgre) FindByHash(ctx context.Context, tx ports.Transaction, tokenHash string) (*entities.PersonalAccessToken, error) {
+	// Tokens of users in the trash stop working until the user is restored.
+	query := "SELECT " + personalAccessTokenColumns + ` FROM personal_access_tokens
+	WHERE token_hash = $1
+	  AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = user_id AND u.deleted_at IS NOT NULL)`
+	token, err := scanPersonalAccessToken(tx.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (r *PersonalAccessTokenRepositoryPostgre) FindByUser(ctx context.Context, tx ports.Transaction, userID string) ([]*entities.PersonalAccessToken, error) {
+	query := "SELECT " + personalAccessTokenColumns + " FROM personal_access_tokens WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []*entities.PersonalAccessToken{}
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *PersonalAccessTokenRepositoryPostgre) Revoke(ctx context.Context, tx ports.Transaction, userID string, id uuid.UUID) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `
+            UPDATE personal_access_tokens
+            SET revoked_at = CURRENT_TIMESTAMP
+            WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		logger.WithError(err).Error("Error Revoke personal access token")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return appErrors.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (r *PersonalAccessTokenRepositoryPostgre) TouchLastUsed(ctx context.Context, tx ports.Transaction, id uuid.UUID) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	if _, err := tx.ExecContext(ctx, "UPDATE personal_access_tokens SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1", id); err != nil {
+		logger.WithError(err).Error("Failed to update personal access token last use")
+		return err
+	}
+
+	return nil
+}
+
+// scanPersonalAccessToken reads a row selected with personalAccessTokenColumns.
+func scanPersonalAccessToken(row interface{ Scan(dest ...any) error }) (*entities.PersonalAccessToken, error) {
+	var token entities.PersonalAccessToken
+	err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.Prefix, &token.TokenHash, pq.Array(&token.Scopes),
+		&token.ExpiresAt, &token.LastUsedAt, &token.RevokedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
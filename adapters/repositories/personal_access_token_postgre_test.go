@@ -0,0 +1,68 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalAccessTokenRepository_Lifecycle(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.PersonalAccessTokenRepository, error) {
+			return &repositories.PersonalAccessTokenRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.PersonalAccessTokenRepository, tx ports.Transaction) {
+			users := &repositories.UserRepositoryPostgre{}
+			user, err := users.Save(ctx, tx, &entities.User{Email: "tokens@example.com", Password: "hashed"})
+			require.NoError(t, err)
+			userID := user.ID.String()
+
+			expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+			saved, err := repo.Save(ctx, tx, &entities.PersonalAccessToken{
+				UserID:    user.ID,
+				Name:      "ci",
+				Prefix:    "gbp_abcdefgh",
+				TokenHash: "hash-1",
+				Scopes:    []string{entities.PermissionPostsWrite},
+				ExpiresAt: &expiresAt,
+			})
+			require.NoError(t, err)
+			require.NotZero(t, saved.ID)
+
+			found, err := repo.FindByHash(ctx, tx, "hash-1")
+			require.NoError(t, err)
+			require.Equal(t, user.ID, found.UserID)
+			require.Equal(t, []string{entities.PermissionPostsWrite}, found.Scopes)
+			require.Nil(t, found.LastUsedAt)
+
+			require.NoError(t, repo.TouchLastUsed(ctx, tx, saved.ID))
+			found, err = repo.FindByHash(ctx, tx, "hash-1")
+			require.NoError(t, err)
+			require.NotNil(t, found.LastUsedAt)
+
+			tokens, err := repo.FindByUser(ctx, tx, userID)
+			require.NoError(t, err)
+			require.Len(t, tokens, 1)
+
+			// Another user's token cannot be revoked.
+			require.ErrorIs(t, repo.Revoke(ctx, tx, uuid.New().String(), saved.ID), appErrors.ErrDataNotFound)
+
+			require.NoError(t, repo.Revoke(ctx, tx, userID, saved.ID))
+			found, err = repo.FindByHash(ctx, tx, "hash-1")
+			require.NoError(t, err)
+			require.NotNil(t, found.RevokedAt)
+			require.ErrorIs(t, repo.Revoke(ctx, tx, userID, saved.ID), appErrors.ErrDataNotFound)
+
+			_, err = repo.FindByHash(ctx, tx, "missing")
+			require.ErrorIs(t, err, appErrors.ErrDataNotFound)
+		},
+	)
+}
@@ -5,12 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/domain/ports"
 	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
 	"github.com/chud-lori/go-boilerplate/pkg/logger"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/sirupsen/logrus"
 )
@@ -18,17 +21,40 @@ import (
 type PostRepositoryPostgre struct {
 }
 
+// Posts in the trash, and posts of authors in the trash, are left out of every read and write
+// except the trash methods below. Restoring the author brings their posts back.
+const postAuthorNotDeleted = "NOT EXISTS (SELECT 1 FROM users du WHERE du.id = author_id AND du.deleted_at IS NOT NULL)"
+
+// postTagSlugs selects the sorted tag slugs of the post in the posts row of the query, as an array.
+const postTagSlugs = "ARRAY(SELECT t.slug FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.post_id = posts.id ORDER BY t.slug)"
+
+// postCommentCount counts the comments of the post in the posts row of the query that are not deleted.
+const postCommentCount = "(SELECT COUNT(*) FROM comments c WHERE c.post_id = posts.id AND c.deleted_at IS NULL)"
+
+// postReactionCounts selects the last reconciled reaction counts of the post in the posts row of the query.
+const postReactionCounts = "COALESCE((SELECT rc.counts FROM post_reaction_counts rc WHERE rc.post_id = posts.id), '{}')"
+
+// postSearchConfig is the text search configuration of posts.search_vector. Queries have to
+// be parsed with the same one, or stemmed words would not match. Search is English-only: the
+// generated column is built with 'english' by migration 000018, so another language takes a
+// new migration recreating the column along with a change here.
+const postSearchConfig = "english"
+
+// postSearchHeadline marks the matches in up to two fragments of the body.
+const postSearchHeadline = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" ... \""
+
 func (r *PostRepositoryPostgre) Save(ctx context.Context, tx ports.Transaction, post *entities.Post) (*entities.Post, error) {
 	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
 	var id uuid.UUID
 	var title string
 
+	// A post saved without a status is published, as every post was before statuses existed.
 	query := `
-            INSERT INTO posts (title, body, author_id)
-            VALUES ($1, $2, $3)
-            RETURNING id, title`
-	err := tx.QueryRowContext(ctx, query, post.Title, post.Body, post.User.ID).Scan(&id, &title)
+            INSERT INTO posts (title, body, author_id, status, publish_at)
+            VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), 'published'), $5)
+            RETURNING id, title, version, status`
+	err := tx.QueryRowContext(ctx, query, post.Title, post.Body, post.User.ID, string(post.Status), post.PublishAt).Scan(&id, &title, &post.Version, &post.Status)
 	if err != nil {
 		logger.Error("Failed to post: ", err)
 		return nil, err
@@ -40,36 +66,97 @@ func (r *PostRepositoryPostgre) Save(ctx context.Context, tx ports.Transaction,
 	return post, nil
 }
 
+// Update replaces the title and body of post. A non-zero post.Version is the version the
+// caller expects, the update fails with ErrVersionConflict when the post has moved on.
+// post.Version is set to the new version.
 func (r *PostRepositoryPostgre) Update(ctx context.Context, tx ports.Transaction, post *entities.Post) (*entities.Post, error) {
 	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
-	query := "UPDATE posts SET title = $1, body = $2 WHERE id = $3"
-	result, err := tx.ExecContext(ctx, query, post.Title, post.Body, post.ID)
+	query := "UPDATE posts SET title = $1, body = $2, version = version + 1 WHERE id = $3 AND deleted_at IS NULL"
+	args := []interface{}{post.Title, post.Body, post.ID}
+	if post.Version > 0 {
+		query += " AND version = $4"
+		args = append(args, post.Version)
+	}
+	query += " RETURNING version"
 
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&post.Version)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.missingOrConflict(ctx, tx, post.ID)
+		}
 		logger.WithError(err).Error("Error Update")
 		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return post, nil
+}
+
+// missingOrConflict explains why a conditional write matched no row.
+func (r *PostRepositoryPostgre) missingOrConflict(ctx context.Context, tx ports.Transaction, id uuid.UUID) error {
+	var version int
+	err := tx.QueryRowContext(ctx, "SELECT version FROM posts WHERE id = $1 AND deleted_at IS NULL", id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return appErrors.ErrDataNotFound
+	}
 	if err != nil {
-		logger.WithError(err).Error("Failed get row affected")
-		return nil, err
+		return err
 	}
+	return appErrors.ErrVersionConflict
+}
 
-	if rowsAffected == 0 {
-		logger.Errorf("Post ID %s not found", post.ID)
-		return nil, appErrors.ErrDataNotFound
+func (r *PostRepositoryPostgre) Patch(ctx context.Context, tx ports.Transaction, id uuid.UUID, version int, patch entities.PostPatch) (*entities.Post, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	var sets []string
+	var args []interface{}
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if patch.Title != nil {
+		set("title", *patch.Title)
+	}
+	if patch.Body != nil {
+		set("body", *patch.Body)
+	}
+	if len(sets) == 0 {
+		return r.GetById(ctx, tx, id)
+	}
+	sets = append(sets, "version = version + 1")
+
+	args = append(args, id)
+	query := fmt.Sprintf("UPDATE posts SET %s WHERE id = $%d AND deleted_at IS NULL", strings.Join(sets, ", "), len(args))
+	if version > 0 {
+		args = append(args, version)
+		query += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+	query += " RETURNING id, title, COALESCE(body, ''), created_at, updated_at, version, status, publish_at"
+
+	post := &entities.Post{}
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&post.ID, &post.Title, &post.Body, &post.CreatedAt, &post.UpdatedAt, &post.Version, &post.Status, &post.PublishAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.missingOrConflict(ctx, tx, id)
+		}
+		logger.WithError(err).Error("Error Patch")
+		return nil, err
 	}
 
 	return post, nil
 }
 
-func (r *PostRepositoryPostgre) Delete(ctx context.Context, tx ports.Transaction, id uuid.UUID) error {
+// Delete moves the post to the trash. A non-zero version is the version the caller expects, as in Update.
+func (r *PostRepositoryPostgre) Delete(ctx context.Context, tx ports.Transaction, id uuid.UUID, version int) error {
 	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
-	query := "DELETE FROM posts WHERE id = $1"
-	result, err := tx.ExecContext(ctx, query, id)
+	query := "UPDATE posts SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
+	args := []interface{}{id}
+	if version > 0 {
+		query += " AND version = $2"
+		args = append(args, version)
+	}
+	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete post: %v", err)
 	}
@@ -81,7 +168,7 @@ func (r *PostRepositoryPostgre) Delete(ctx context.Context, tx ports.Transaction
 	}
 
 	if rowsAffected == 0 {
-		return appErrors.ErrDataNotFound
+		return r.missingOrConflict(ctx, tx, id)
 	}
 
 	return nil
@@ -93,11 +180,11 @@ func (r *PostRepositoryPostgre) GetById(ctx context.Context, tx ports.Transactio
 	post := &entities.Post{
 		User: &entities.User{},
 	}
-	query := `SELECT p.id, p.title, p.body, p.created_at, u.id, u.email, u.created_at
-	FROM posts p
-	JOIN users u on p.author_id = u.id
-	WHERE p.id = $1`
-	err := tx.QueryRowContext(ctx, query, id).Scan(&post.ID, &post.Title, &post.Body, &post.CreatedAt, &post.User.ID, &post.User.Email, &post.User.CreatedAt)
+	query := `SELECT posts.id, posts.title, posts.body, posts.created_at, posts.version, posts.status, posts.publish_at, ` + postTagSlugs + `, ` + postCommentCount + `, ` + postReactionCounts + `, u.id, u.email, u.created_at
+	FROM posts
+	JOIN users u on posts.author_id = u.id
+	WHERE posts.id = $1 AND posts.deleted_at IS NULL AND u.deleted_at IS NULL`
+	err := tx.QueryRowContext(ctx, query, id).Scan(&post.ID, &post.Title, &post.Body, &post.CreatedAt, &post.Version, &post.Status, &post.PublishAt, pq.Array(&post.Tags), &post.CommentCount, reactionCountsColumn{&post.Reactions}, &post.User.ID, &post.User.Email, &post.User.CreatedAt)
 
 	if err != nil {
 		logger.WithError(err).Error("Failed GetById Post")
@@ -110,19 +197,46 @@ func (r *PostRepositoryPostgre) GetById(ctx context.Context, tx ports.Transactio
 	return post, nil
 }
 
-func (r *PostRepositoryPostgre) GetAll(ctx context.Context, tx ports.Transaction, search string, pagination entities.PaginationParams) ([]entities.Post, error) {
+// GetAll lists the published posts matching filter, newest first.
+func (r *PostRepositoryPostgre) GetAll(ctx context.Context, tx ports.Transaction, filter entities.PostFilter, pagination entities.PaginationParams) ([]entities.Post, error) {
 	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
-	query := "SELECT id, title, body, author_id, created_at FROM posts WHERE 1=1"
+	columns := "id, title, body, author_id, created_at, version, status, publish_at, " + postTagSlugs + ", " + postCommentCount + ", " + postReactionCounts
+	from := "posts"
+	orderBy := "created_at DESC"
 	args := []interface{}{}
 	argCounter := 1
 
-	if search != "" {
-		query += fmt.Sprintf(" AND title ILIKE $%d", argCounter)
-		args = append(args, "%"+search+"%")
+	// The search takes web search syntax: quoted phrases, OR and -excluded words. Matches are
+	// ranked by relevance, with the matched words of the body marked in a highlight.
+	if filter.Search != "" {
+		from += fmt.Sprintf(", websearch_to_tsquery('%s', $%d) search", postSearchConfig, argCounter)
+		args = append(args, filter.Search)
+		argCounter++
+		columns += fmt.Sprintf(", ts_headline('%s', COALESCE(body, ''), search, '%s')", postSearchConfig, postSearchHeadline)
+		orderBy = "ts_rank_cd(search_vector, search) DESC, " + orderBy
+	} else {
+		columns += ", ''"
+	}
+
+	query := "SELECT " + columns + " FROM " + from + " WHERE deleted_at IS NULL AND status = 'published' AND " + postAuthorNotDeleted
+	if filter.Search != "" {
+		query += " AND search_vector @@ search"
+	}
+	if len(filter.Tags) > 0 {
+		taggedWith := fmt.Sprintf("FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.post_id = posts.id AND t.slug = ANY($%d)", argCounter)
+		args = append(args, pq.Array(filter.Tags))
 		argCounter++
+		if filter.MatchAllTags {
+			// The slugs are unique, a post has all of them when it has as many of them as asked for.
+			query += fmt.Sprintf(" AND (SELECT COUNT(*) %s) = $%d", taggedWith, argCounter)
+			args = append(args, len(filter.Tags))
+			argCounter++
+		} else {
+			query += " AND EXISTS (SELECT 1 " + taggedWith + ")"
+		}
 	}
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argCounter, argCounter+1)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", orderBy, argCounter, argCounter+1)
 	args = append(args, pagination.Limit, (pagination.Page-1)*pagination.Limit)
 
 	rows, err := tx.QueryContext(ctx, query, args...)
@@ -135,7 +249,7 @@ func (r *PostRepositoryPostgre) GetAll(ctx context.Context, tx ports.Transaction
 	for rows.Next() {
 		var post entities.Post
 		post.User = &entities.User{}
-		err := rows.Scan(&post.ID, &post.Title, &post.Body, &post.User.ID, &post.CreatedAt)
+		err := rows.Scan(&post.ID, &post.Title, &post.Body, &post.User.ID, &post.CreatedAt, &post.Version, &post.Status, &post.PublishAt, pq.Array(&post.Tags), &post.CommentCount, reactionCountsColumn{&post.Reactions}, &post.Highlight)
 
 		if err != nil {
 			return nil, fmt.Errorf("Failed to scan post row")
@@ -150,3 +264,157 @@ func (r *PostRepositoryPostgre) GetAll(ctx context.Context, tx ports.Transaction
 
 	return posts, nil
 }
+
+// GetByAuthor lists the posts of an author in the given status, newest first.
+func (r *PostRepositoryPostgre) GetByAuthor(ctx context.Context, tx ports.Transaction, authorID uuid.UUID, status entities.PostStatus, pagination entities.PaginationParams) ([]entities.Post, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `SELECT id, title, COALESCE(body, ''), author_id, created_at, version, status, publish_at, ` + postTagSlugs + `, ` + postCommentCount + `, ` + postReactionCounts + `
+	FROM posts
+	WHERE author_id = $1 AND status = $2 AND deleted_at IS NULL
+	ORDER BY created_at DESC LIMIT $3 OFFSET $4`
+	rows, err := tx.QueryContext(ctx, query, authorID, string(status), pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		logger.WithError(err).Error("Failed query GetByAuthor")
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []entities.Post{}
+	for rows.Next() {
+		var post entities.Post
+		post.User = &entities.User{}
+		if err := rows.Scan(&post.ID, &post.Title, &post.Body, &post.User.ID, &post.CreatedAt, &post.Version, &post.Status, &post.PublishAt, pq.Array(&post.Tags), &post.CommentCount, reactionCountsColumn{&post.Reactions}); err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// SetStatus moves the post to status with the given schedule and bumps its version. A non-zero
+// version is the version the caller expects, as in Update. The post is returned without its author.
+func (r *PostRepositoryPostgre) SetStatus(ctx context.Context, tx ports.Transaction, id uuid.UUID, version int, status entities.PostStatus, publishAt *time.Time) (*entities.Post, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := "UPDATE posts SET status = $1, publish_at = $2, version = version + 1 WHERE id = $3 AND deleted_at IS NULL"
+	args := []interface{}{string(status), publishAt, id}
+	if version > 0 {
+		query += " AND version = $4"
+		args = append(args, version)
+	}
+	query += " RETURNING id, title, COALESCE(body, ''), created_at, updated_at, version, status, publish_at"
+
+	post := &entities.Post{}
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&post.ID, &post.Title, &post.Body, &post.CreatedAt, &post.UpdatedAt, &post.Version, &post.Status, &post.PublishAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.missingOrConflict(ctx, tx, id)
+		}
+		logger.WithError(err).Error("Error SetStatus")
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// PublishDue publishes the drafts scheduled at or before now and returns their IDs. Each
+// published post moves to a new version.
+func (r *PostRepositoryPostgre) PublishDue(ctx context.Context, tx ports.Transaction, now time.Time) ([]uuid.UUID, error) {
+	query := `UPDATE posts SET status = 'published', version = version + 1
+	WHERE status = 'draft' AND publish_at <= $1 AND deleted_at IS NULL
+	RETURNING id`
+	rows, err := tx.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish due posts: %v", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Restore takes the post out of the trash and returns it with its author.
+func (r *PostRepositoryPostgre) Restore(ctx context.Context, tx ports.Transaction, id uuid.UUID) (*entities.Post, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	result, err := tx.ExecContext(ctx, "UPDATE posts SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		logger.WithError(err).Error("Error Restore")
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return nil, err
+	}
+
+	if rowsAffected == 0 {
+		return nil, appErrors.ErrDataNotFound
+	}
+
+	return r.GetById(ctx, tx, id)
+}
+
+// GetDeleted lists the posts in the trash, most recently deleted first.
+func (r *PostRepositoryPostgre) GetDeleted(ctx context.Context, tx ports.Transaction, pagination entities.PaginationParams) ([]entities.Post, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `SELECT id, title, COALESCE(body, ''), author_id, created_at, version, deleted_at
+	FROM posts
+	WHERE deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC LIMIT $1 OFFSET $2`
+	rows, err := tx.QueryContext(ctx, query, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		logger.WithError(err).Error("Failed query GetDeleted")
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []entities.Post{}
+	for rows.Next() {
+		var post entities.Post
+		post.User = &entities.User{}
+		if err := rows.Scan(&post.ID, &post.Title, &post.Body, &post.User.ID, &post.CreatedAt, &post.Version, &post.DeletedAt); err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// Purge removes for good the posts deleted before cutoff, and every post of an author
+// deleted before cutoff so that the author can be purged too.
+func (r *PostRepositoryPostgre) Purge(ctx context.Context, tx ports.Transaction, cutoff time.Time) (int64, error) {
+	query := `DELETE FROM posts
+	WHERE deleted_at < $1
+	   OR author_id IN (SELECT id FROM users WHERE deleted_at < $1)`
+	result, err := tx.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge posts: %v", err)
+	}
+	return result.RowsAffected()
+}
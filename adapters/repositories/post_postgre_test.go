@@ -127,6 +127,91 @@ func TestPostRepository_Update_NotFound(t *testing.T) {
 	)
 }
 
+func TestPostRepository_Patch(t *testing.T) {
+    t.Parallel()
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.PostRepository, error) {
+			return &repositories.PostRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, postRepo ports.PostRepository, tx ports.Transaction) {
+			userRepo := &repositories.UserRepositoryPostgre{}
+			savedUser, err := userRepo.Save(ctx, tx, &entities.User{
+				Email:    "testuser_patch@example.com",
+				Password: "secret",
+			})
+			require.NoError(t, err)
+
+			savedPost, err := postRepo.Save(ctx, tx, &entities.Post{
+				Title: "Original Title",
+				Body:  "Original Body",
+				User:  &entities.User{ID: savedUser.ID},
+			})
+			require.NoError(t, err)
+
+			title := "Patched Title"
+			patched, err := postRepo.Patch(ctx, tx, savedPost.ID, 0, entities.PostPatch{Title: &title})
+			require.NoError(t, err)
+			require.Equal(t, title, patched.Title)
+			require.Equal(t, "Original Body", patched.Body)
+
+			body := ""
+			patched, err = postRepo.Patch(ctx, tx, savedPost.ID, 0, entities.PostPatch{Body: &body})
+			require.NoError(t, err)
+			require.Equal(t, title, patched.Title)
+			require.Empty(t, patched.Body)
+
+			_, err = postRepo.Patch(ctx, tx, uuid.New(), 0, entities.PostPatch{Title: &title})
+			require.ErrorIs(t, err, appErrors.ErrDataNotFound)
+		})
+}
+
+func TestPostRepository_Version(t *testing.T) {
+    t.Parallel()
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.PostRepository, error) {
+			return &repositories.PostRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, postRepo ports.PostRepository, tx ports.Transaction) {
+			userRepo := &repositories.UserRepositoryPostgre{}
+			savedUser, err := userRepo.Save(ctx, tx, &entities.User{
+				Email:    "testuser_version@example.com",
+				Password: "secret",
+			})
+			require.NoError(t, err)
+
+			savedPost, err := postRepo.Save(ctx, tx, &entities.Post{
+				Title: "Original Title",
+				Body:  "Original Body",
+				User:  &entities.User{ID: savedUser.ID},
+			})
+			require.NoError(t, err)
+			require.Equal(t, 1, savedPost.Version)
+
+			updated, err := postRepo.Update(ctx, tx, &entities.Post{ID: savedPost.ID, Title: "Second", Body: "Body", Version: 1})
+			require.NoError(t, err)
+			require.Equal(t, 2, updated.Version)
+
+			title := "Third"
+			patched, err := postRepo.Patch(ctx, tx, savedPost.ID, 2, entities.PostPatch{Title: &title})
+			require.NoError(t, err)
+			require.Equal(t, 3, patched.Version)
+
+			// Writes expecting an older version are refused and change nothing.
+			_, err = postRepo.Update(ctx, tx, &entities.Post{ID: savedPost.ID, Title: "Stale", Body: "Body", Version: 2})
+			require.ErrorIs(t, err, appErrors.ErrVersionConflict)
+			_, err = postRepo.Patch(ctx, tx, savedPost.ID, 1, entities.PostPatch{Title: &title})
+			require.ErrorIs(t, err, appErrors.ErrVersionConflict)
+			require.ErrorIs(t, postRepo.Delete(ctx, tx, savedPost.ID, 2), appErrors.ErrVersionConflict)
+
+			retrieved, err := postRepo.GetById(ctx, tx, savedPost.ID)
+			require.NoError(t, err)
+			require.Equal(t, "Third", retrieved.Title)
+			require.Equal(t, 3, retrieved.Version)
+
+			require.NoError(t, postRepo.Delete(ctx, tx, savedPost.ID, 3))
+		})
+}
+
 func TestPostRepository_Delete(t *testing.T) {
     t.Parallel()
 	testutils.WithTransactionTest(t,
@@ -154,7 +239,7 @@ func TestPostRepository_Delete(t *testing.T) {
 			savedPost, err := postRepo.Save(ctx, tx, postToDelete)
 			require.NoError(t, err)
 
-			err = postRepo.Delete(ctx, tx, savedPost.ID)
+			err = postRepo.Delete(ctx, tx, savedPost.ID, 0)
 			require.NoError(t, err)
 
 			retrievedPost, err := postRepo.GetById(ctx, tx, savedPost.ID)
@@ -173,7 +258,7 @@ func TestPostRepository_Delete_NotFound(t *testing.T) {
 		},
 		func(ctx context.Context, postRepo ports.PostRepository, tx ports.Transaction) {
 			nonExistentID := uuid.New()
-			err := postRepo.Delete(ctx, tx, nonExistentID)
+			err := postRepo.Delete(ctx, tx, nonExistentID, 0)
 			require.Error(t, err)
 			assert.True(t, errors.Is(err, appErrors.ErrDataNotFound))
 		},
@@ -272,7 +357,7 @@ func TestPostRepository_GetAll(t *testing.T) {
 			}
 
 			pagination := entities.PaginationParams{Page: 1, Limit: 10}
-			posts, err := postRepo.GetAll(ctx, tx, "", pagination)
+			posts, err := postRepo.GetAll(ctx, tx, entities.PostFilter{}, pagination)
 			require.NoError(t, err)
 			require.Len(t, posts, 4)
 			require.Equal(t, postsToSave[0].Title, posts[0].Title)
@@ -281,43 +366,43 @@ func TestPostRepository_GetAll(t *testing.T) {
 			require.Equal(t, postsToSave[3].Title, posts[3].Title)
 
 			searchQuery := "post"
-			filteredPosts, err := postRepo.GetAll(ctx, tx, searchQuery, pagination)
+			filteredPosts, err := postRepo.GetAll(ctx, tx, entities.PostFilter{Search: searchQuery}, pagination)
 			require.NoError(t, err)
 			require.Len(t, filteredPosts, 4)
 
 			searchQuery = "fantastic"
-			filteredPosts, err = postRepo.GetAll(ctx, tx, searchQuery, pagination)
+			filteredPosts, err = postRepo.GetAll(ctx, tx, entities.PostFilter{Search: searchQuery}, pagination)
 			require.NoError(t, err)
 			require.Len(t, filteredPosts, 1)
 			require.Equal(t, "Fantastic Post B", filteredPosts[0].Title)
 
 			searchQuery = "search me"
-			filteredPosts, err = postRepo.GetAll(ctx, tx, searchQuery, pagination)
+			filteredPosts, err = postRepo.GetAll(ctx, tx, entities.PostFilter{Search: searchQuery}, pagination)
 			require.NoError(t, err)
 			require.Len(t, filteredPosts, 1)
 			require.Equal(t, "Search Me Post", filteredPosts[0].Title)
 
 			searchQuery = "nonexistent"
-			filteredPosts, err = postRepo.GetAll(ctx, tx, searchQuery, pagination)
+			filteredPosts, err = postRepo.GetAll(ctx, tx, entities.PostFilter{Search: searchQuery}, pagination)
 			require.NoError(t, err)
 			require.Len(t, filteredPosts, 0)
 
 			pagination = entities.PaginationParams{Page: 1, Limit: 2}
-			paginatedPosts, err := postRepo.GetAll(ctx, tx, "", pagination)
+			paginatedPosts, err := postRepo.GetAll(ctx, tx, entities.PostFilter{}, pagination)
 			require.NoError(t, err)
 			require.Len(t, paginatedPosts, 2)
 			require.Equal(t, postsToSave[0].Title, paginatedPosts[0].Title)
 			require.Equal(t, postsToSave[1].Title, paginatedPosts[1].Title)
 
 			pagination = entities.PaginationParams{Page: 2, Limit: 2}
-			paginatedPosts, err = postRepo.GetAll(ctx, tx, "", pagination)
+			paginatedPosts, err = postRepo.GetAll(ctx, tx, entities.PostFilter{}, pagination)
 			require.NoError(t, err)
 			require.Len(t, paginatedPosts, 2)
 			require.Equal(t, postsToSave[2].Title, paginatedPosts[0].Title)
 			require.Equal(t, postsToSave[3].Title, paginatedPosts[1].Title)
 
 			pagination = entities.PaginationParams{Page: 3, Limit: 2}
-			paginatedPosts, err = postRepo.GetAll(ctx, tx, "", pagination)
+			paginatedPosts, err = postRepo.GetAll(ctx, tx, entities.PostFilter{}, pagination)
 			require.NoError(t, err)
 			require.Len(t, paginatedPosts, 0)
 		},
@@ -332,9 +417,189 @@ func TestPostRepository_GetAll_NoRecords(t *testing.T) {
 		},
 		func(ctx context.Context, postRepo ports.PostRepository, tx ports.Transaction) {
 			pagination := entities.PaginationParams{Page: 1, Limit: 10}
-			posts, err := postRepo.GetAll(ctx, tx, "", pagination)
+			posts, err := postRepo.GetAll(ctx, tx, entities.PostFilter{}, pagination)
 			require.NoError(t, err)
 			require.Empty(t, posts)
 		},
 	)
 }
+
+func TestPostRepository_RestoreAndPurge(t *testing.T) {
+    t.Parallel()
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.PostRepository, error) {
+			return &repositories.PostRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, postRepo ports.PostRepository, tx ports.Transaction) {
+			userRepo := &repositories.UserRepositoryPostgre{}
+			savedUser, err := userRepo.Save(ctx, tx, &entities.User{Email: "testuser_trash@example.com", Password: "secret"})
+			require.NoError(t, err)
+
+			savedPost, err := postRepo.Save(ctx, tx, &entities.Post{Title: "Trashed", Body: "Back soon", User: &entities.User{ID: savedUser.ID}})
+			require.NoError(t, err)
+
+			require.NoError(t, postRepo.Delete(ctx, tx, savedPost.ID, 0))
+			err = postRepo.Delete(ctx, tx, savedPost.ID, 0)
+			assert.True(t, errors.Is(err, appErrors.ErrDataNotFound), "a deleted post cannot be deleted again")
+
+			trashed, err := postRepo.GetDeleted(ctx, tx, entities.PaginationParams{Page: 1, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, trashed, 1)
+			require.NotNil(t, trashed[0].DeletedAt)
+
+			restored, err := postRepo.Restore(ctx, tx, savedPost.ID)
+			require.NoError(t, err)
+			require.Equal(t, "Trashed", restored.Title)
+
+			_, err = postRepo.Restore(ctx, tx, savedPost.ID)
+			assert.True(t, errors.Is(err, appErrors.ErrDataNotFound), "only posts in the trash can be restored")
+
+			require.NoError(t, postRepo.Delete(ctx, tx, savedPost.ID, 0))
+			purged, err := postRepo.Purge(ctx, tx, time.Now().Add(-time.Hour))
+			require.NoError(t, err)
+			require.Zero(t, purged, "posts within the retention are kept")
+
+			purged, err = postRepo.Purge(ctx, tx, time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			require.Equal(t, int64(1), purged)
+
+			trashed, err = postRepo.GetDeleted(ctx, tx, entities.PaginationParams{Page: 1, Limit: 10})
+			require.NoError(t, err)
+			require.Empty(t, trashed)
+		},
+	)
+}
+
+func TestPostRepository_DeletedAuthorHidesPosts(t *testing.T) {
+    t.Parallel()
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.PostRepository, error) {
+			return &repositories.PostRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, postRepo ports.PostRepository, tx ports.Transaction) {
+			userRepo := &repositories.UserRepositoryPostgre{}
+			savedUser, err := userRepo.Save(ctx, tx, &entities.User{Email: "testuser_gone@example.com", Password: "secret"})
+			require.NoError(t, err)
+
+			savedPost, err := postRepo.Save(ctx, tx, &entities.Post{Title: "Orphan", Body: "Hidden", User: &entities.User{ID: savedUser.ID}})
+			require.NoError(t, err)
+
+			require.NoError(t, userRepo.Delete(ctx, tx, savedUser.ID.String()))
+
+			_, err = postRepo.GetById(ctx, tx, savedPost.ID)
+			assert.True(t, errors.Is(err, appErrors.ErrDataNotFound))
+			posts, err := postRepo.GetAll(ctx, tx, entities.PostFilter{Search: "orphan"}, entities.PaginationParams{Page: 1, Limit: 10})
+			require.NoError(t, err)
+			require.Empty(t, posts)
+
+			_, err = userRepo.Restore(ctx, tx, savedUser.ID.String())
+			require.NoError(t, err)
+
+			post, err := postRepo.GetById(ctx, tx, savedPost.ID)
+			require.NoError(t, err)
+			require.Equal(t, "Orphan", post.Title)
+		},
+	)
+}
+
+func TestPostRepository_StatusAndPublishDue(t *testing.T) {
+    t.Parallel()
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.PostRepository, error) {
+			return &repositories.PostRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, postRepo ports.PostRepository, tx ports.Transaction) {
+			userRepo := &repositories.UserRepositoryPostgre{}
+			savedUser, err := userRepo.Save(ctx, tx, &entities.User{Email: "testuser_drafts@example.com", Password: "secret"})
+			require.NoError(t, err)
+			author := &entities.User{ID: savedUser.ID}
+
+			published, err := postRepo.Save(ctx, tx, &entities.Post{Title: "Lifecycle public", User: author})
+			require.NoError(t, err)
+			require.Equal(t, entities.PostStatusPublished, published.Status, "posts are published by default")
+
+			publishAt := time.Now().Add(time.Hour)
+			draft, err := postRepo.Save(ctx, tx, &entities.Post{Title: "Lifecycle draft", User: author, Status: entities.PostStatusDraft, PublishAt: &publishAt})
+			require.NoError(t, err)
+
+			posts, err := postRepo.GetAll(ctx, tx, entities.PostFilter{Search: "lifecycle"}, entities.PaginationParams{Page: 1, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, posts, 1, "drafts are not listed")
+			require.Equal(t, published.ID, posts[0].ID)
+
+			drafts, err := postRepo.GetByAuthor(ctx, tx, savedUser.ID, entities.PostStatusDraft, entities.PaginationParams{Page: 1, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, drafts, 1)
+			require.Equal(t, draft.ID, drafts[0].ID)
+			require.NotNil(t, drafts[0].PublishAt)
+
+			due, err := postRepo.PublishDue(ctx, tx, time.Now())
+			require.NoError(t, err)
+			require.Empty(t, due, "the draft is not due yet")
+
+			due, err = postRepo.PublishDue(ctx, tx, publishAt.Add(time.Minute))
+			require.NoError(t, err)
+			require.Equal(t, []uuid.UUID{draft.ID}, due)
+
+			post, err := postRepo.GetById(ctx, tx, draft.ID)
+			require.NoError(t, err)
+			require.Equal(t, entities.PostStatusPublished, post.Status)
+			require.Equal(t, draft.Version+1, post.Version)
+
+			archived, err := postRepo.SetStatus(ctx, tx, draft.ID, post.Version, entities.PostStatusArchived, nil)
+			require.NoError(t, err)
+			require.Equal(t, entities.PostStatusArchived, archived.Status)
+			require.Nil(t, archived.PublishAt)
+
+			_, err = postRepo.SetStatus(ctx, tx, draft.ID, post.Version, entities.PostStatusPublished, nil)
+			assert.True(t, errors.Is(err, appErrors.ErrVersionConflict))
+		},
+	)
+}
+
+func TestPostRepository_FullTextSearch(t *testing.T) {
+    t.Parallel()
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.PostRepository, error) {
+			return &repositories.PostRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, postRepo ports.PostRepository, tx ports.Transaction) {
+			userRepo := &repositories.UserRepositoryPostgre{}
+			savedUser, err := userRepo.Save(ctx, tx, &entities.User{Email: "testuser_search@example.com", Password: "secret"})
+			require.NoError(t, err)
+			author := &entities.User{ID: savedUser.ID}
+
+			inBody, err := postRepo.Save(ctx, tx, &entities.Post{Title: "Weekend notes", Body: "We went running along the river before breakfast.", User: author})
+			require.NoError(t, err)
+			inTitle, err := postRepo.Save(ctx, tx, &entities.Post{Title: "Running routes", Body: "Shoes and the river path.", User: author})
+			require.NoError(t, err)
+			_, err = postRepo.Save(ctx, tx, &entities.Post{Title: "Cooking", Body: "A river of chocolate.", User: author})
+			require.NoError(t, err)
+
+			pagination := entities.PaginationParams{Page: 1, Limit: 10}
+
+			// Stemming matches running with run, title matches rank above body matches.
+			posts, err := postRepo.GetAll(ctx, tx, entities.PostFilter{Search: "run"}, pagination)
+			require.NoError(t, err)
+			require.Len(t, posts, 2)
+			require.Equal(t, inTitle.ID, posts[0].ID)
+			require.Equal(t, inBody.ID, posts[1].ID)
+			require.Contains(t, posts[1].Highlight, "<mark>running</mark>")
+
+			posts, err = postRepo.GetAll(ctx, tx, entities.PostFilter{Search: `river -chocolate "before breakfast"`}, pagination)
+			require.NoError(t, err)
+			require.Len(t, posts, 1)
+			require.Equal(t, inBody.ID, posts[0].ID)
+
+			posts, err = postRepo.GetAll(ctx, tx, entities.PostFilter{Search: "chocolate or shoes"}, pagination)
+			require.NoError(t, err)
+			require.Len(t, posts, 2)
+
+			// Listings without a search have no highlight.
+			posts, err = postRepo.GetAll(ctx, tx, entities.PostFilter{}, pagination)
+			require.NoError(t, err)
+			require.NotEmpty(t, posts)
+			require.Empty(t, posts[0].Highlight)
+		},
+	)
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type PostRevisionRepositoryPostgre struct {
+}
+
+func (r *PostRevisionRepositoryPostgre) Save(ctx context.Context, tx ports.Transaction, revision *entities.PostRevision) (*entities.PostRevision, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	var editorID interface{}
+	if revision.EditorID != uuid.Nil {
+		editorID = revision.EditorID
+	}
+
+	query := `
+            INSERT INTO post_revisions (post_id, revision, title, body, editor_id)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING created_at`
+	err := tx.QueryRowContext(ctx, query, revision.PostID, revision.Revision, revision.Title, revision.Body, editorID).
+		Scan(&revision.CreatedAt)
+	if err != nil {
+		logger.WithError(err).Error("Failed to insert post revision")
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+func (r *PostRevisionRepositoryPostgre) FindByPost(ctx context.Context, tx ports.Transaction, postID uuid.UUID) ([]*entities.PostRevision, error) {
+	query := `
+            SELECT post_id, revision, title, body, editor_id, created_at
+            FROM post_revisions
+            WHERE post_id = $1
+            ORDER BY revision DESC`
+	rows, err := tx.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []*entities.PostRevision{}
+	for rows.Next() {
+		var revision entities.PostRevision
+		if err := rows.Scan(&revision.PostID, &revision.Revision, &revision.Title, &revision.Body,
+			&revision.EditorID, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *PostRevisionRepositoryPostgre) FindByRevision(ctx context.Context, tx ports.Transaction, postID uuid.UUID, revision int) (*entities.PostRevision, error) {
+	query := `
+            SELECT post_id, revision, title, body, editor_id, created_at
+            FROM post_revisions
+            WHERE post_id = $1 AND revision = $2`
+
+	var result entities.PostRevision
+	err := tx.QueryRowContext(ctx, query, postID, revision).Scan(&result.PostID, &result.Revision, &result.Title, &result.Body,
+		&result.EditorID, &result.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
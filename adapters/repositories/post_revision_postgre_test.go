@@ -0,0 +1,53 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostRevisionRepository_Lifecycle(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.PostRevisionRepository, error) {
+			return &repositories.PostRevisionRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.PostRevisionRepository, tx ports.Transaction) {
+			users := &repositories.UserRepositoryPostgre{}
+			user, err := users.Save(ctx, tx, &entities.User{Email: "revisions@example.com", Password: "hashed"})
+			require.NoError(t, err)
+
+			posts := &repositories.PostRepositoryPostgre{}
+			post, err := posts.Save(ctx, tx, &entities.Post{Title: "First", Body: "one", User: &entities.User{ID: user.ID}})
+			require.NoError(t, err)
+
+			first, err := repo.Save(ctx, tx, &entities.PostRevision{PostID: post.ID, Revision: 1, Title: "First", Body: "one", EditorID: user.ID})
+			require.NoError(t, err)
+			require.False(t, first.CreatedAt.IsZero())
+
+			// An editor that is gone is stored as NULL and read back as uuid.Nil.
+			_, err = repo.Save(ctx, tx, &entities.PostRevision{PostID: post.ID, Revision: 2, Title: "Second", Body: "two"})
+			require.NoError(t, err)
+
+			revisions, err := repo.FindByPost(ctx, tx, post.ID)
+			require.NoError(t, err)
+			require.Len(t, revisions, 2)
+			require.Equal(t, 2, revisions[0].Revision)
+			require.Equal(t, uuid.Nil, revisions[0].EditorID)
+			require.Equal(t, user.ID, revisions[1].EditorID)
+
+			revision, err := repo.FindByRevision(ctx, tx, post.ID, 1)
+			require.NoError(t, err)
+			require.Equal(t, "one", revision.Body)
+
+			_, err = repo.FindByRevision(ctx, tx, post.ID, 3)
+			require.ErrorIs(t, err, appErrors.ErrDataNotFound)
+		},
+	)
+}
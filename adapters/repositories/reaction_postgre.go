@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type ReactionRepositoryPostgre struct {
+}
+
+func (r *ReactionRepositoryPostgre) Add(ctx context.Context, tx ports.Transaction, postID, userID uuid.UUID, reaction entities.ReactionType) (bool, error) {
+	query := `
+            INSERT INTO post_reactions (post_id, user_id, type)
+            VALUES ($1, $2, $3)
+            ON CONFLICT DO NOTHING`
+	return r.changed(ctx, tx, query, postID, userID, reaction)
+}
+
+func (r *ReactionRepositoryPostgre) Remove(ctx context.Context, tx ports.Transaction, postID, userID uuid.UUID, reaction entities.ReactionType) (bool, error) {
+	query := "DELETE FROM post_reactions WHERE post_id = $1 AND user_id = $2 AND type = $3"
+	return r.changed(ctx, tx, query, postID, userID, reaction)
+}
+
+func (r *ReactionRepositoryPostgre) changed(ctx context.Context, tx ports.Transaction, query string, args ...interface{}) (bool, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).Error("Failed to change reaction")
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *ReactionRepositoryPostgre) ListByUser(ctx context.Context, tx ports.Transaction, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID][]entities.ReactionType, error) {
+	ids := make([]string, len(postIDs))
+	for i, id := range postIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+            SELECT post_id, type
+            FROM post_reactions
+            WHERE user_id = $1 AND post_id = ANY($2::uuid[])
+            ORDER BY post_id, created_at, type`
+	rows, err := tx.QueryContext(ctx, query, userID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reactions := map[uuid.UUID][]entities.ReactionType{}
+	for rows.Next() {
+		var postID uuid.UUID
+		var reaction entities.ReactionType
+		if err := rows.Scan(&postID, &reaction); err != nil {
+			return nil, err
+		}
+		reactions[postID] = append(reactions[postID], reaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
+}
+
+func (r *ReactionRepositoryPostgre) Count(ctx context.Context, tx ports.Transaction, postID uuid.UUID) (entities.ReactionCounts, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT type, COUNT(*) FROM post_reactions WHERE post_id = $1 GROUP BY type", postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := entities.ReactionCounts{}
+	for rows.Next() {
+		var reaction entities.ReactionType
+		var n int64
+		if err := rows.Scan(&reaction, &n); err != nil {
+			return nil, err
+		}
+		counts[reaction] = n
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *ReactionRepositoryPostgre) SaveCounts(ctx context.Context, tx ports.Transaction, postID uuid.UUID, counts entities.ReactionCounts) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	raw, err := json.Marshal(counts)
+	if err != nil {
+		return err
+	}
+	if counts == nil {
+		raw = []byte("{}")
+	}
+
+	query := `
+            INSERT INTO post_reaction_counts (post_id, counts)
+            VALUES ($1, $2)
+            ON CONFLICT (post_id) DO UPDATE SET counts = EXCLUDED.counts, reconciled_at = CURRENT_TIMESTAMP`
+	if _, err := tx.ExecContext(ctx, query, postID, raw); err != nil {
+		logger.WithError(err).Error("Failed to save reaction counts")
+		return err
+	}
+
+	return nil
+}
+
+// reactionCountsColumn scans a JSONB object of reaction counts, see postReactionCounts.
+type reactionCountsColumn struct {
+	counts *entities.ReactionCounts
+}
+
+func (c reactionCountsColumn) Scan(src interface{}) error {
+	raw, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for reaction counts", src)
+	}
+	return json.Unmarshal(raw, c.counts)
+}
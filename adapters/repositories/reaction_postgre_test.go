@@ -0,0 +1,70 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReactionRepository(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.ReactionRepository, error) {
+			return &repositories.ReactionRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.ReactionRepository, tx ports.Transaction) {
+			users := &repositories.UserRepositoryPostgre{}
+			alice, err := users.Save(ctx, tx, &entities.User{Email: "alice-reacts@example.com", Password: "hashed"})
+			require.NoError(t, err)
+			bob, err := users.Save(ctx, tx, &entities.User{Email: "bob-reacts@example.com", Password: "hashed"})
+			require.NoError(t, err)
+
+			posts := &repositories.PostRepositoryPostgre{}
+			post, err := posts.Save(ctx, tx, &entities.Post{Title: "Liked", User: &entities.User{ID: alice.ID}})
+			require.NoError(t, err)
+
+			// One reaction per user and type, adding it again changes nothing.
+			added, err := repo.Add(ctx, tx, post.ID, alice.ID, entities.ReactionLike)
+			require.NoError(t, err)
+			require.True(t, added)
+			added, err = repo.Add(ctx, tx, post.ID, alice.ID, entities.ReactionLike)
+			require.NoError(t, err)
+			require.False(t, added)
+			_, err = repo.Add(ctx, tx, post.ID, alice.ID, entities.ReactionWow)
+			require.NoError(t, err)
+			_, err = repo.Add(ctx, tx, post.ID, bob.ID, entities.ReactionLike)
+			require.NoError(t, err)
+
+			removed, err := repo.Remove(ctx, tx, post.ID, alice.ID, entities.ReactionWow)
+			require.NoError(t, err)
+			require.True(t, removed)
+			removed, err = repo.Remove(ctx, tx, post.ID, alice.ID, entities.ReactionWow)
+			require.NoError(t, err)
+			require.False(t, removed)
+
+			mine, err := repo.ListByUser(ctx, tx, alice.ID, []uuid.UUID{post.ID, uuid.New()})
+			require.NoError(t, err)
+			require.Equal(t, map[uuid.UUID][]entities.ReactionType{post.ID: {entities.ReactionLike}}, mine)
+
+			counts, err := repo.Count(ctx, tx, post.ID)
+			require.NoError(t, err)
+			require.Equal(t, entities.ReactionCounts{entities.ReactionLike: 2}, counts)
+
+			// Posts are read with the reconciled counts.
+			got, err := posts.GetById(ctx, tx, post.ID)
+			require.NoError(t, err)
+			require.Empty(t, got.Reactions)
+
+			require.NoError(t, repo.SaveCounts(ctx, tx, post.ID, counts))
+			require.NoError(t, repo.SaveCounts(ctx, tx, post.ID, counts))
+			got, err = posts.GetById(ctx, tx, post.ID)
+			require.NoError(t, err)
+			require.Equal(t, counts, got.Reactions)
+		},
+	)
+}
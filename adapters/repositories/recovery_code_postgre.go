@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type RecoveryCodeRepositoryPostgre struct {
+}
+
+func (r *RecoveryCodeRepositoryPostgre) ReplaceAll(ctx context.Context, tx ports.Transaction, userID string, codeHashes []string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	if err := r.DeleteAll(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	query := "INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)"
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, query, userID, hash); err != nil {
+			logger.WithError(err).Error("Failed to insert recovery code")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *RecoveryCodeRepositoryPostgre) FindUnused(ctx context.Context, tx ports.Transaction, userID string) ([]*entities.RecoveryCode, error) {
+	query := `
+            SELECT id, user_id, code_hash, created_at
+            FROM user_recovery_codes
+            WHERE user_id = $1 AND used_at IS NULL`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []*entities.RecoveryCode
+	for rows.Next() {
+		var code entities.RecoveryCode
+		if err := rows.Scan(&code.ID, &code.UserID, &code.CodeHash, &code.CreatedAt); err != nil {
+			return nil, err
+		}
+		codes = append(codes, &code)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (r *RecoveryCodeRepositoryPostgre) MarkUsed(ctx context.Context, tx ports.Transaction, id string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	// The used_at condition makes concurrent use of the same code fail for all but one request.
+	query := "UPDATE user_recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		logger.WithError(err).Error("Error MarkUsed")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return appErrors.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (r *RecoveryCodeRepositoryPostgre) DeleteAll(ctx context.Context, tx ports.Transaction, userID string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userID); err != nil {
+		logger.WithError(err).Error("Failed to delete recovery codes")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryCodeRepository_ReplaceAndUse(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.RecoveryCodeRepository, error) {
+			return &repositories.RecoveryCodeRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.RecoveryCodeRepository, tx ports.Transaction) {
+			userRepo := &repositories.UserRepositoryPostgre{}
+			user, err := userRepo.Save(ctx, tx, &entities.User{Email: "mfa@example.com", Password: "pass123"})
+			require.NoError(t, err)
+			userID := user.ID.String()
+
+			require.NoError(t, repo.ReplaceAll(ctx, tx, userID, []string{"old-1", "old-2"}))
+			require.NoError(t, repo.ReplaceAll(ctx, tx, userID, []string{"new-1", "new-2", "new-3"}))
+
+			codes, err := repo.FindUnused(ctx, tx, userID)
+			require.NoError(t, err)
+			require.Len(t, codes, 3)
+
+			require.NoError(t, repo.MarkUsed(ctx, tx, codes[0].ID.String()))
+			require.ErrorIs(t, repo.MarkUsed(ctx, tx, codes[0].ID.String()), appErrors.ErrDataNotFound)
+
+			codes, err = repo.FindUnused(ctx, tx, userID)
+			require.NoError(t, err)
+			require.Len(t, codes, 2)
+
+			require.NoError(t, repo.DeleteAll(ctx, tx, userID))
+			codes, err = repo.FindUnused(ctx, tx, userID)
+			require.NoError(t, err)
+			require.Empty(t, codes)
+		},
+	)
+}
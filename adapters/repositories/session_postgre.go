@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type SessionRepositoryPostgre struct {
+}
+
+func (r *SessionRepositoryPostgre) Create(ctx context.Context, tx ports.Transaction, session *entities.Session) (*entities.Session, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `
+            INSERT INTO sessions (id, user_id, user_agent, ip, expires_at)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING created_at, last_seen_at`
+	err := tx.QueryRowContext(ctx, query, session.ID, session.UserID, session.UserAgent, session.IP, session.ExpiresAt).
+		Scan(&session.CreatedAt, &session.LastSeenAt)
+	if err != nil {
+		logger.WithError(err).Error("Failed to insert session")
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *SessionRepositoryPostgre) FindActiveByUser(ctx context.Context, tx ports.Transaction, userID string) ([]*entities.Session, error) {
+	query := `
+            SELECT id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, revoked_at
+            FROM sessions
+            WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+            ORDER BY last_seen_at DESC`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*entities.Session{}
+	for rows.Next() {
+		var session entities.Session
+		if err := rows.Scan(&session.ID, &session.UserID, &session.UserAgent, &session.IP,
+			&session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.RevokedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepositoryPostgre) Touch(ctx context.Context, tx ports.Transaction, id uuid.UUID, expiresAt time.Time) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `
+            UPDATE sessions
+            SET last_seen_at = CURRENT_TIMESTAMP, expires_at = $2
+            WHERE id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP`
+	result, err := tx.ExecContext(ctx, query, id, expiresAt)
+	if err != nil {
+		logger.WithError(err).Error("Error Touch session")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return appErrors.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (r *SessionRepositoryPostgre) Revoke(ctx context.Context, tx ports.Transaction, userID string, id uuid.UUID) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := `
+            UPDATE sessions
+            SET revoked_at = CURRENT_TIMESTAMP
+            WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		logger.WithError(err).Error("Error Revoke session")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return appErrors.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (r *SessionRepositoryPostgre) RevokeAll(ctx context.Context, tx ports.Transaction, userID string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := "UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL"
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		logger.WithError(err).Error("Error RevokeAll sessions")
+		return err
+	}
+
+	return nil
+}
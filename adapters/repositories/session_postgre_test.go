@@ -0,0 +1,68 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRepository_Lifecycle(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.SessionRepository, error) {
+			return &repositories.SessionRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.SessionRepository, tx ports.Transaction) {
+			users := &repositories.UserRepositoryPostgre{}
+			user, err := users.Save(ctx, tx, &entities.User{Email: "sessions@example.com", Password: "hashed"})
+			require.NoError(t, err)
+			userID := user.ID.String()
+
+			first, err := repo.Create(ctx, tx, &entities.Session{
+				ID:        uuid.New(),
+				UserID:    user.ID,
+				UserAgent: "Firefox",
+				IP:        "203.0.113.7",
+				ExpiresAt: time.Now().Add(time.Hour),
+			})
+			require.NoError(t, err)
+			require.False(t, first.CreatedAt.IsZero())
+
+			second, err := repo.Create(ctx, tx, &entities.Session{
+				ID:        uuid.New(),
+				UserID:    user.ID,
+				ExpiresAt: time.Now().Add(time.Hour),
+			})
+			require.NoError(t, err)
+
+			sessions, err := repo.FindActiveByUser(ctx, tx, userID)
+			require.NoError(t, err)
+			require.Len(t, sessions, 2)
+
+			require.NoError(t, repo.Touch(ctx, tx, first.ID, time.Now().Add(2*time.Hour)))
+
+			require.NoError(t, repo.Revoke(ctx, tx, userID, second.ID))
+			require.ErrorIs(t, repo.Revoke(ctx, tx, userID, second.ID), appErrors.ErrDataNotFound)
+			require.ErrorIs(t, repo.Touch(ctx, tx, second.ID, time.Now().Add(time.Hour)), appErrors.ErrDataNotFound)
+			// Sessions of another user cannot be revoked.
+			require.ErrorIs(t, repo.Revoke(ctx, tx, uuid.NewString(), first.ID), appErrors.ErrDataNotFound)
+
+			sessions, err = repo.FindActiveByUser(ctx, tx, userID)
+			require.NoError(t, err)
+			require.Len(t, sessions, 1)
+			require.Equal(t, "Firefox", sessions[0].UserAgent)
+
+			require.NoError(t, repo.RevokeAll(ctx, tx, userID))
+			sessions, err = repo.FindActiveByUser(ctx, tx, userID)
+			require.NoError(t, err)
+			require.Empty(t, sessions)
+		},
+	)
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type TagRepositoryPostgre struct {
+}
+
+func (r *TagRepositoryPostgre) SetPostTags(ctx context.Context, tx ports.Transaction, postID uuid.UUID, tags []entities.Tag) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM post_tags WHERE post_id = $1", postID); err != nil {
+		logger.WithError(err).Error("Failed to clear post tags")
+		return err
+	}
+
+	for _, tag := range tags {
+		// The no-op update makes RETURNING yield the id of an existing tag as well.
+		query := `
+            INSERT INTO tags (slug, name)
+            VALUES ($1, $2)
+            ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+            RETURNING id`
+		var tagID uuid.UUID
+		if err := tx.QueryRowContext(ctx, query, tag.Slug, tag.Name).Scan(&tagID); err != nil {
+			logger.WithError(err).Error("Failed to upsert tag")
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "INSERT INTO post_tags (post_id, tag_id) VALUES ($1, $2)", postID, tagID); err != nil {
+			logger.WithError(err).Error("Failed to tag post")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TagRepositoryPostgre) List(ctx context.Context, tx ports.Transaction) ([]entities.Tag, error) {
+	query := `
+            SELECT t.id, t.slug, t.name, COUNT(*)
+            FROM tags t
+            JOIN post_tags pt ON pt.tag_id = t.id
+            JOIN posts ON posts.id = pt.post_id
+            WHERE posts.deleted_at IS NULL AND posts.status = 'published' AND ` + postAuthorNotDeleted + `
+            GROUP BY t.id, t.slug, t.name
+            ORDER BY COUNT(*) DESC, t.slug`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []entities.Tag{}
+	for rows.Next() {
+		var tag entities.Tag
+		if err := rows.Scan(&tag.ID, &tag.Slug, &tag.Name, &tag.PostCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,63 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagRepository_FilterAndCounts(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.TagRepository, error) {
+			return &repositories.TagRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.TagRepository, tx ports.Transaction) {
+			users := &repositories.UserRepositoryPostgre{}
+			user, err := users.Save(ctx, tx, &entities.User{Email: "tags@example.com", Password: "hashed"})
+			require.NoError(t, err)
+
+			posts := &repositories.PostRepositoryPostgre{}
+			goWeb, err := posts.Save(ctx, tx, &entities.Post{Title: "Go on the web", User: &entities.User{ID: user.ID}})
+			require.NoError(t, err)
+			goOnly, err := posts.Save(ctx, tx, &entities.Post{Title: "Go alone", User: &entities.User{ID: user.ID}})
+			require.NoError(t, err)
+			draft, err := posts.Save(ctx, tx, &entities.Post{Title: "Go draft", Status: entities.PostStatusDraft, User: &entities.User{ID: user.ID}})
+			require.NoError(t, err)
+
+			require.NoError(t, repo.SetPostTags(ctx, tx, goWeb.ID, []entities.Tag{{Slug: "go", Name: "Go"}, {Slug: "web", Name: "Web"}}))
+			require.NoError(t, repo.SetPostTags(ctx, tx, goOnly.ID, []entities.Tag{{Slug: "go", Name: "golang"}}))
+			require.NoError(t, repo.SetPostTags(ctx, tx, draft.ID, []entities.Tag{{Slug: "go", Name: "Go"}, {Slug: "drafts", Name: "Drafts"}}))
+
+			anyTag, err := posts.GetAll(ctx, tx, entities.PostFilter{Tags: []string{"web", "go"}}, entities.PaginationParams{Page: 1, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, anyTag, 2)
+
+			allTags, err := posts.GetAll(ctx, tx, entities.PostFilter{Tags: []string{"web", "go"}, MatchAllTags: true}, entities.PaginationParams{Page: 1, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, allTags, 1)
+			require.Equal(t, goWeb.ID, allTags[0].ID)
+			require.Equal(t, []string{"go", "web"}, allTags[0].Tags)
+
+			// The existing tag keeps its first name, drafts are not counted.
+			tags, err := repo.List(ctx, tx)
+			require.NoError(t, err)
+			require.Len(t, tags, 2)
+			require.Equal(t, "go", tags[0].Slug)
+			require.Equal(t, "Go", tags[0].Name)
+			require.Equal(t, 2, tags[0].PostCount)
+			require.Equal(t, "web", tags[1].Slug)
+			require.Equal(t, 1, tags[1].PostCount)
+
+			// Setting tags replaces them, an empty list removes them all.
+			require.NoError(t, repo.SetPostTags(ctx, tx, goWeb.ID, []entities.Tag{}))
+			post, err := posts.GetById(ctx, tx, goWeb.ID)
+			require.NoError(t, err)
+			require.Empty(t, post.Tags)
+		},
+	)
+}
@@ -12,6 +12,7 @@ import (
 	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
 	"github.com/chud-lori/go-boilerplate/pkg/logger"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/sirupsen/logrus"
 )
@@ -37,13 +38,27 @@ func (repository *UserRepositoryPostgre) Save(ctx context.Context, tx ports.Tran
 	user.ID = id
 	user.CreatedAt = createdAt
 
+	// New accounts start as regular users.
+	if err := repository.SetRoles(ctx, tx, id.String(), []string{entities.RoleUser}); err != nil {
+		logger.Error("Failed to assign default role: ", err)
+		return nil, err
+	}
+	if err := repository.loadAccess(ctx, tx, user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
 func (repository *UserRepositoryPostgre) Update(ctx context.Context, tx ports.Transaction, user *entities.User) (*entities.User, error) {
 	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
-	query := "UPDATE users SET email = $1, password = $2 WHERE id = $3"
+	// Changing the address drops the verification, it has to be confirmed again.
+	query := `
+            UPDATE users
+            SET email = $1, password = $2,
+                verified_at = CASE WHEN email = $1 THEN verified_at ELSE NULL END
+            WHERE id = $3 AND deleted_at IS NULL`
 	result, err := tx.ExecContext(ctx, query, user.Email, user.Password, user.ID)
 
 	if err != nil {
@@ -65,10 +80,12 @@ func (repository *UserRepositoryPostgre) Update(ctx context.Context, tx ports.Tr
 	return user, nil
 }
 
+// Delete moves the user to the trash. Their posts are hidden with them until the user is
+// restored or purged.
 func (repository *UserRepositoryPostgre) Delete(ctx context.Context, tx ports.Transaction, id string) error {
 	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
-	query := "DELETE FROM users WHERE id = $1"
+	query := "UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
 	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %v", err)
@@ -89,8 +106,9 @@ func (repository *UserRepositoryPostgre) Delete(ctx context.Context, tx ports.Tr
 
 func (r *UserRepositoryPostgre) FindById(ctx context.Context, tx ports.Transaction, id string) (*entities.User, error) {
 	user := &entities.User{}
-	query := "SELECT id, email, created_at FROM users WHERE id = $1"
-	err := tx.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Email, &user.CreatedAt)
+	var totpSecret sql.NullString
+	query := "SELECT id, email, created_at, verified_at, totp_secret, mfa_enabled_at FROM users WHERE id = $1 AND deleted_at IS NULL"
+	err := tx.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Email, &user.CreatedAt, &user.VerifiedAt, &totpSecret, &user.MFAEnabledAt)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -98,14 +116,20 @@ func (r *UserRepositoryPostgre) FindById(ctx context.Context, tx ports.Transacti
 		}
 		return nil, err
 	}
+	user.TOTPSecret = totpSecret.String
+
+	if err := r.loadAccess(ctx, tx, user); err != nil {
+		return nil, err
+	}
 
 	return user, nil
 }
 
 func (r *UserRepositoryPostgre) FindByEmail(ctx context.Context, tx ports.Transaction, email string) (*entities.User, error) {
 	user := &entities.User{}
-	query := "SELECT id, password, email, created_at FROM users WHERE email = $1"
-	err := tx.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.Password, &user.Email, &user.CreatedAt)
+	var totpSecret sql.NullString
+	query := "SELECT id, password, email, created_at, verified_at, totp_secret, mfa_enabled_at FROM users WHERE email = $1 AND deleted_at IS NULL"
+	err := tx.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.Password, &user.Email, &user.CreatedAt, &user.VerifiedAt, &totpSecret, &user.MFAEnabledAt)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -113,12 +137,93 @@ func (r *UserRepositoryPostgre) FindByEmail(ctx context.Context, tx ports.Transa
 		}
 		return nil, err
 	}
+	user.TOTPSecret = totpSecret.String
+
+	if err := r.loadAccess(ctx, tx, user); err != nil {
+		return nil, err
+	}
 
 	return user, nil
 }
 
+// MarkVerified records the first verification of the user's email; verifying again keeps the original time.
+func (repository *UserRepositoryPostgre) MarkVerified(ctx context.Context, tx ports.Transaction, id string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := "UPDATE users SET verified_at = COALESCE(verified_at, CURRENT_TIMESTAMP) WHERE id = $1 AND deleted_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		logger.WithError(err).Error("Error MarkVerified")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return appErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetTOTPSecret stores a new, not yet confirmed, authenticator secret and turns two-factor off
+// until the secret is confirmed with EnableMFA. An empty secret removes the authenticator.
+func (repository *UserRepositoryPostgre) SetTOTPSecret(ctx context.Context, tx ports.Transaction, id, secret string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := "UPDATE users SET totp_secret = NULLIF($1, ''), mfa_enabled_at = NULL WHERE id = $2 AND deleted_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, secret, id)
+	if err != nil {
+		logger.WithError(err).Error("Error SetTOTPSecret")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return appErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (repository *UserRepositoryPostgre) EnableMFA(ctx context.Context, tx ports.Transaction, id string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	query := "UPDATE users SET mfa_enabled_at = CURRENT_TIMESTAMP WHERE id = $1 AND totp_secret IS NOT NULL AND deleted_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		logger.WithError(err).Error("Error EnableMFA")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return appErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (repository *UserRepositoryPostgre) FindAll(ctx context.Context, tx ports.Transaction) ([]*entities.User, error) {
-	query := "SELECT id, email, created_at FROM users"
+	query := `
+            SELECT u.id, u.email, u.created_at, u.verified_at,
+                   ARRAY(SELECT ur.role FROM user_roles ur WHERE ur.user_id = u.id ORDER BY ur.role)
+            FROM users u
+            WHERE u.deleted_at IS NULL`
 	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -128,7 +233,98 @@ func (repository *UserRepositoryPostgre) FindAll(ctx context.Context, tx ports.T
 	var users []*entities.User
 	for rows.Next() {
 		var user entities.User
-		err := rows.Scan(&user.ID, &user.Email, &user.CreatedAt)
+		err := rows.Scan(&user.ID, &user.Email, &user.CreatedAt, &user.VerifiedAt, pq.Array(&user.Roles))
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// SetRoles replaces the roles of the user. It returns ErrDataNotFound when one of
+// the roles does not exist.
+func (repository *UserRepositoryPostgre) SetRoles(ctx context.Context, tx ports.Transaction, id string, roles []string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_roles WHERE user_id = $1", id); err != nil {
+		logger.WithError(err).Error("Error SetRoles")
+		return err
+	}
+
+	query := `
+            INSERT INTO user_roles (user_id, role)
+            SELECT $1, name FROM roles WHERE name = ANY($2)`
+	result, err := tx.ExecContext(ctx, query, id, pq.Array(roles))
+	if err != nil {
+		logger.WithError(err).Error("Error SetRoles")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return err
+	}
+
+	if rowsAffected != int64(len(roles)) {
+		return appErrors.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// Restore takes the user out of the trash. It fails with ErrConflict when their email has
+// been taken by a new account in the meantime.
+func (repository *UserRepositoryPostgre) Restore(ctx context.Context, tx ports.Transaction, id string) (*entities.User, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	result, err := tx.ExecContext(ctx, "UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, appErrors.ErrConflict
+		}
+		logger.WithError(err).Error("Error Restore")
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.WithError(err).Error("Failed get row affected")
+		return nil, err
+	}
+
+	if rowsAffected == 0 {
+		return nil, appErrors.ErrUserNotFound
+	}
+
+	return repository.FindById(ctx, tx, id)
+}
+
+// FindDeleted lists the users in the trash, most recently deleted first.
+func (repository *UserRepositoryPostgre) FindDeleted(ctx context.Context, tx ports.Transaction, pagination entities.PaginationParams) ([]*entities.User, error) {
+	query := `
+            SELECT u.id, u.email, u.created_at, u.verified_at, u.deleted_at,
+                   ARRAY(SELECT ur.role FROM user_roles ur WHERE ur.user_id = u.id ORDER BY ur.role)
+            FROM users u
+            WHERE u.deleted_at IS NOT NULL
+            ORDER BY u.deleted_at DESC LIMIT $1 OFFSET $2`
+	rows, err := tx.QueryContext(ctx, query, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*entities.User{}
+	for rows.Next() {
+		var user entities.User
+		err := rows.Scan(&user.ID, &user.Email, &user.CreatedAt, &user.VerifiedAt, &user.DeletedAt, pq.Array(&user.Roles))
 		if err != nil {
 			return nil, err
 		}
@@ -141,3 +337,24 @@ func (repository *UserRepositoryPostgre) FindAll(ctx context.Context, tx ports.T
 
 	return users, nil
 }
+
+// Purge removes for good the users deleted before cutoff. Their posts have to be purged
+// first, see PostRepositoryPostgre.Purge; the rest of their data goes with them.
+func (repository *UserRepositoryPostgre) Purge(ctx context.Context, tx ports.Transaction, cutoff time.Time) (int64, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM users WHERE deleted_at < $1", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge users: %v", err)
+	}
+	return result.RowsAffected()
+}
+
+// loadAccess fills in the roles of user and the permissions they grant.
+func (repository *UserRepositoryPostgre) loadAccess(ctx context.Context, tx ports.Transaction, user *entities.User) error {
+	query := `
+            SELECT
+                ARRAY(SELECT ur.role FROM user_roles ur WHERE ur.user_id = $1 ORDER BY ur.role),
+                ARRAY(SELECT DISTINCT rp.permission
+                      FROM user_roles ur JOIN role_permissions rp ON rp.role = ur.role
+                      WHERE ur.user_id = $1 ORDER BY rp.permission)`
+	return tx.QueryRowContext(ctx, query, user.ID).Scan(pq.Array(&user.Roles), pq.Array(&user.Permissions))
+}
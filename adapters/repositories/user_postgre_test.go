@@ -111,6 +111,59 @@ func TestUserRepository_Update(t *testing.T) {
 	)
 }
 
+func TestUserRepository_Update_EmailChangeClearsVerification(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.UserRepository, error) {
+			return &repositories.UserRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.UserRepository, tx ports.Transaction) {
+			saved, _ := repo.Save(ctx, tx, &entities.User{Email: "reverify@example.com", Password: "pass123"})
+			require.NoError(t, repo.MarkVerified(ctx, tx, saved.ID.String()))
+
+			saved.Email = "reverified@example.com"
+			_, err := repo.Update(ctx, tx, saved)
+			require.NoError(t, err)
+
+			found, err := repo.FindById(ctx, tx, saved.ID.String())
+			require.NoError(t, err)
+			require.Nil(t, found.VerifiedAt)
+		},
+	)
+}
+
+func TestUserRepository_MarkVerified(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.UserRepository, error) {
+			return &repositories.UserRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.UserRepository, tx ports.Transaction) {
+			saved, _ := repo.Save(ctx, tx, &entities.User{Email: "verify@example.com", Password: "pass123"})
+
+			found, err := repo.FindByEmail(ctx, tx, saved.Email)
+			require.NoError(t, err)
+			require.False(t, found.IsVerified())
+
+			require.NoError(t, repo.MarkVerified(ctx, tx, saved.ID.String()))
+
+			found, err = repo.FindByEmail(ctx, tx, saved.Email)
+			require.NoError(t, err)
+			require.True(t, found.IsVerified())
+		},
+	)
+}
+
+func TestUserRepository_MarkVerified_NotFound(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.UserRepository, error) {
+			return &repositories.UserRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.UserRepository, tx ports.Transaction) {
+			err := repo.MarkVerified(ctx, tx, uuid.NewString())
+			require.ErrorIs(t, err, appErrors.ErrUserNotFound)
+		},
+	)
+}
+
 func TestUserRepository_Update_NotFound(t *testing.T) {
 	testutils.WithTransactionTest(t,
 		func(db ports.Database) (ports.UserRepository, error) {
@@ -167,3 +220,134 @@ func TestUserRepository_FindAll(t *testing.T) {
 		},
 	)
 }
+
+func TestUserRepository_TOTPSecretAndEnableMFA(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.UserRepository, error) {
+			return &repositories.UserRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.UserRepository, tx ports.Transaction) {
+			user, err := repo.Save(ctx, tx, &entities.User{Email: "totp@example.com", Password: "pass123"})
+			require.NoError(t, err)
+			userID := user.ID.String()
+
+			require.NoError(t, repo.SetTOTPSecret(ctx, tx, userID, "JBSWY3DPEHPK3PXP"))
+			found, err := repo.FindById(ctx, tx, userID)
+			require.NoError(t, err)
+			require.Equal(t, "JBSWY3DPEHPK3PXP", found.TOTPSecret)
+			require.False(t, found.MFAEnabled())
+
+			require.NoError(t, repo.EnableMFA(ctx, tx, userID))
+			found, err = repo.FindByEmail(ctx, tx, user.Email)
+			require.NoError(t, err)
+			require.True(t, found.MFAEnabled())
+
+			require.NoError(t, repo.SetTOTPSecret(ctx, tx, userID, ""))
+			found, err = repo.FindById(ctx, tx, userID)
+			require.NoError(t, err)
+			require.Empty(t, found.TOTPSecret)
+			require.False(t, found.MFAEnabled())
+		},
+	)
+}
+
+func TestUserRepository_Save_AssignsDefaultRole(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.UserRepository, error) {
+			return &repositories.UserRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.UserRepository, tx ports.Transaction) {
+			saved, err := repo.Save(ctx, tx, &entities.User{Email: "roles@example.com", Password: "pass123"})
+			require.NoError(t, err)
+			require.Equal(t, []string{entities.RoleUser}, saved.Roles)
+
+			found, err := repo.FindByEmail(ctx, tx, "roles@example.com")
+			require.NoError(t, err)
+			require.Equal(t, []string{entities.RoleUser}, found.Roles)
+			require.True(t, found.HasPermission(entities.PermissionUsersWrite))
+			require.False(t, found.HasPermission(entities.PermissionUsersManage))
+		},
+	)
+}
+
+func TestUserRepository_SetRoles(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.UserRepository, error) {
+			return &repositories.UserRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.UserRepository, tx ports.Transaction) {
+			saved, err := repo.Save(ctx, tx, &entities.User{Email: "admin@example.com", Password: "pass123"})
+			require.NoError(t, err)
+
+			err = repo.SetRoles(ctx, tx, saved.ID.String(), []string{entities.RoleAdmin, entities.RoleUser})
+			require.NoError(t, err)
+
+			found, err := repo.FindById(ctx, tx, saved.ID.String())
+			require.NoError(t, err)
+			require.Equal(t, []string{entities.RoleAdmin, entities.RoleUser}, found.Roles)
+			require.True(t, found.HasPermission(entities.PermissionUsersManage, entities.PermissionPostsManage))
+		},
+	)
+}
+
+func TestUserRepository_SetRoles_UnknownRole(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.UserRepository, error) {
+			return &repositories.UserRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.UserRepository, tx ports.Transaction) {
+			saved, err := repo.Save(ctx, tx, &entities.User{Email: "unknown-role@example.com", Password: "pass123"})
+			require.NoError(t, err)
+
+			err = repo.SetRoles(ctx, tx, saved.ID.String(), []string{"superuser"})
+			require.ErrorIs(t, err, appErrors.ErrDataNotFound)
+		},
+	)
+}
+
+func TestUserRepository_Delete_HidesUser(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.UserRepository, error) {
+			return &repositories.UserRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.UserRepository, tx ports.Transaction) {
+			saved, err := repo.Save(ctx, tx, &entities.User{Email: "trashed@example.com", Password: "pass123"})
+			require.NoError(t, err)
+			require.NoError(t, repo.Delete(ctx, tx, saved.ID.String()))
+
+			_, err = repo.FindById(ctx, tx, saved.ID.String())
+			require.ErrorIs(t, err, appErrors.ErrUserNotFound)
+			_, err = repo.FindByEmail(ctx, tx, "trashed@example.com")
+			require.ErrorIs(t, err, appErrors.ErrUserNotFound)
+
+			deleted, err := repo.FindDeleted(ctx, tx, entities.PaginationParams{Page: 1, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, deleted, 1)
+			require.Equal(t, saved.ID, deleted[0].ID)
+
+			restored, err := repo.Restore(ctx, tx, saved.ID.String())
+			require.NoError(t, err)
+			require.Equal(t, "trashed@example.com", restored.Email)
+		},
+	)
+}
+
+func TestUserRepository_Restore_EmailTaken(t *testing.T) {
+	testutils.WithTransactionTest(t,
+		func(db ports.Database) (ports.UserRepository, error) {
+			return &repositories.UserRepositoryPostgre{}, nil
+		},
+		func(ctx context.Context, repo ports.UserRepository, tx ports.Transaction) {
+			old, err := repo.Save(ctx, tx, &entities.User{Email: "reused@example.com", Password: "pass123"})
+			require.NoError(t, err)
+			require.NoError(t, repo.Delete(ctx, tx, old.ID.String()))
+
+			// The address is free again once its owner is in the trash.
+			_, err = repo.Save(ctx, tx, &entities.User{Email: "reused@example.com", Password: "pass123"})
+			require.NoError(t, err)
+
+			_, err = repo.Restore(ctx, tx, old.ID.String())
+			require.ErrorIs(t, err, appErrors.ErrConflict)
+		},
+	)
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest leaves Scopes empty to grant both read and write.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"omitempty,dive,oneof=read write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+type AuditEventResponse struct {
+	ID             string            `json:"id"`
+	OccurredAt     time.Time         `json:"occurred_at"`
+	ActorID        string            `json:"actor_id,omitempty"`
+	ImpersonatorID string            `json:"impersonator_id,omitempty"`
+	Action         string            `json:"action"`
+	TargetType     string            `json:"target_type,omitempty"`
+	TargetID       string            `json:"target_id,omitempty"`
+	IP             string            `json:"ip,omitempty"`
+	RequestID      string            `json:"request_id,omitempty"`
+	Outcome        string            `json:"outcome"`
+	Details        map[string]string `json:"details,omitempty"`
+}
+
+type AuditEventListResponse struct {
+	Events []AuditEventResponse `json:"events"`
+	Page   int                  `json:"page"`
+	Limit  int                  `json:"limit"`
+	Total  int                  `json:"total"`
+}
@@ -2,11 +2,30 @@ package dto
 
 type AuthSignInRequest struct {
 	Email    string `json:"email" validate:"required,email,max=200,min=1"`
-	Password string `json:"password" validate:"required,max=30,min=8"`
+	Password string `json:"password" validate:"required"`
 }
 
 type AuthSignUpRequest struct {
 	Email           string `json:"email" validate:"required,email,max=200,min=1"`
-	Password        string `json:"password" validate:"required,max=30,min=8"`
+	Password        string `json:"password" validate:"required"`
 	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=Password"`
 }
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email,max=200,min=1"`
+}
+
+type ResetPasswordRequest struct {
+	Email           string `json:"email" validate:"required,email,max=200,min=1"`
+	Code            string `json:"code" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=Password"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email,max=200,min=1"`
+}
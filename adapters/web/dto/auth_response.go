@@ -1,6 +1,23 @@
 package dto
 
+// AuthResponse carries no tokens after sign up when email verification is required.
 type AuthResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token        string       `json:"token,omitempty"`
+	RefreshToken string       `json:"refresh_token,omitempty"`
+	User         UserResponse `json:"user"`
+}
+
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type SocialLoginResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type ImpersonationResponse struct {
+	Token          string `json:"token"`
+	UserID         string `json:"user_id"`
+	ImpersonatorID string `json:"impersonator_id"`
 }
@@ -0,0 +1,13 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CreateCommentRequest comments on a post, or replies to one of its comments with parent_id.
+type CreateCommentRequest struct {
+	Body     string     `json:"body" validate:"required,max=10000"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentResponse is a comment. A deleted comment is a placeholder without body and author,
+// kept so that its replies stay in place.
+type CommentResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	PostID    uuid.UUID  `json:"post_id"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	AuthorID  string     `json:"author_id,omitempty"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Deleted   bool       `json:"deleted"`
+	// Replies are set in the tree shape only.
+	Replies []CommentResponse `json:"replies,omitempty"`
+	// ReplyCount is set on top-level comments in the tree shape. It counts every reply below
+	// the comment, also those left out of replies.
+	ReplyCount int `json:"reply_count,omitempty"`
+}
+
+// CommentPageResponse is a page of comments. Pass next_cursor as cursor to get the next page,
+// it is left out on the last page.
+type CommentPageResponse struct {
+	Comments   []CommentResponse `json:"comments"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
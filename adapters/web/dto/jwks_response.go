@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/chud-lori/go-boilerplate/domain/entities"
+
+// JWKSResponse is served as-is (not wrapped in WebResponse) so standard JWT libraries can consume it.
+type JWKSResponse struct {
+	Keys []entities.JSONWebKey `json:"keys"`
+}
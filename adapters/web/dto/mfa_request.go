@@ -0,0 +1,10 @@
+package dto
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,max=20"`
+}
+
+type SignInMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,max=20"`
+}
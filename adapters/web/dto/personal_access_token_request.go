@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// CreatePersonalAccessTokenRequest takes permissions as scopes, e.g. posts:write. Each one
+// must be granted to the user creating the token.
+type CreatePersonalAccessTokenRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
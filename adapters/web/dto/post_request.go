@@ -1,16 +1,28 @@
 package dto
 
-import "github.com/google/uuid"
+import "time"
 
 // CreatePostRequest represents the request body for creating a new post.
+// The author is always the authenticated user; an author_id in the body is ignored.
+// Status defaults to published, or to draft when publish_at schedules the post.
+// Tags are tag names or slugs; on update, leaving them out keeps the post's tags.
 type CreatePostRequest struct {
-	Title    string    `json:"title"`
-	Body     string    `json:"body"`
-	AuthorID uuid.UUID `json:"author_id"` // Assuming author_id comes from the client
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Status    string     `json:"status,omitempty" validate:"omitempty,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	Tags      []string   `json:"tags,omitempty" validate:"omitempty,max=10,dive,min=1,max=64"`
 }
 
 // UpdatePostRequest represents the request body for updating an existing post.
+// It is read as a JSON Merge Patch or a JSON Patch by PATCH /post/{postId}.
 type UpdatePostRequest struct {
-	Title *string `json:"title"` // Pointers allow distinguishing missing field from empty string
+	Title *string `json:"title" validate:"omitnil,min=1,max=255"` // Pointers allow distinguishing missing field from empty string
 	Body  *string `json:"body"`
 }
+
+// SetPostStatusRequest moves a post through its lifecycle. publish_at schedules a draft.
+type SetPostStatusRequest struct {
+	Status    string     `json:"status" validate:"required,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
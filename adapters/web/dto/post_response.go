@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/google/uuid"
 )
 
@@ -13,4 +14,30 @@ type PostResponse struct {
 	Body      string    `json:"body"`
 	AuthorID  uuid.UUID `json:"author_id"`
 	CreatedAt time.Time `json:"created_at"`
+	Version   int       `json:"version"`
+	// Status is draft, published or archived, PublishAt the time a draft is scheduled for.
+	Status    entities.PostStatus `json:"status"`
+	PublishAt *time.Time          `json:"publish_at,omitempty"`
+	// Tags are the slugs of the post's tags.
+	Tags         []string `json:"tags,omitempty"`
+	CommentCount int      `json:"comment_count"`
+}
+
+// PostRevisionResponse is one entry of a post's history.
+type PostRevisionResponse struct {
+	Revision  int       `json:"revision"`
+	Title     string    `json:"title"`
+	EditorID  string    `json:"editor_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostRevisionDetailResponse is a revision with a unified diff from it to the current version.
+type PostRevisionDetailResponse struct {
+	Revision       int       `json:"revision"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body"`
+	EditorID       string    `json:"editor_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	CurrentVersion int       `json:"current_version"`
+	Diff           string    `json:"diff"`
 }
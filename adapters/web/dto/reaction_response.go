@@ -0,0 +1,10 @@
+package dto
+
+import "github.com/chud-lori/go-boilerplate/domain/entities"
+
+// ReactionsResponse holds the reactions on a post: the count of every type it got and the
+// types the caller reacted with.
+type ReactionsResponse struct {
+	Counts entities.ReactionCounts `json:"counts"`
+	Mine   []entities.ReactionType `json:"mine"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// TagResponse is a tag in use with the number of published posts carrying it.
+type TagResponse struct {
+	Slug      string `json:"slug"`
+	Name      string `json:"name"`
+	PostCount int    `json:"post_count"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashedPostResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	AuthorID  uuid.UUID `json:"author_id"`
+	CreatedAt time.Time `json:"created_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashedUserResponse struct {
+	Id        string    `json:"id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashedPostListResponse struct {
+	Posts []TrashedPostResponse `json:"posts"`
+	Page  int                   `json:"page"`
+	Limit int                   `json:"limit"`
+}
+
+type TrashedUserListResponse struct {
+	Users []TrashedUserResponse `json:"users"`
+	Page  int                   `json:"page"`
+	Limit int                   `json:"limit"`
+}
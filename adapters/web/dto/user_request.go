@@ -2,5 +2,9 @@ package dto
 
 type UserRequest struct {
 	Email    string `validate:"required,max=200,min=1" json:"email"`
-	Password string `validate:"max=8,min=1" json:"password"`
+	Password string `validate:"required" json:"password"`
+}
+
+type UserRolesRequest struct {
+	Roles []string `validate:"required,min=1,dive,required,max=64" json:"roles"`
 }
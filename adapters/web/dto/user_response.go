@@ -6,4 +6,5 @@ type UserResponse struct {
 	Id        string    `json:"id"`
 	Email     string    `json:"email"`
 	CreatedAt time.Time `json:"created_at"`
+	Roles     []string  `json:"roles,omitempty"`
 }
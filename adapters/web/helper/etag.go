@@ -0,0 +1,61 @@
+package helper
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+)
+
+// VersionETag is the strong entity tag of a resource version, e.g. "3".
+func VersionETag(version int) string {
+	return fmt.Sprintf("%q", strconv.Itoa(version))
+}
+
+// IfMatchVersion reads the version a conditional write expects from the If-Match header.
+// The header is required, "*" matches any version and is returned as 0. Anything but a
+// single strong tag written by VersionETag can never match the current version.
+func IfMatchVersion(request *http.Request) (int, error) {
+	header := strings.TrimSpace(request.Header.Get("If-Match"))
+	if header == "" {
+		return 0, appErrors.NewPreconditionRequiredError("If-Match header is required, send the ETag of the version you are changing", nil)
+	}
+	if header == "*" {
+		return 0, nil
+	}
+	if strings.Contains(header, ",") {
+		return 0, appErrors.NewBadRequestError("If-Match must name a single version", nil)
+	}
+
+	version, ok := parseVersionETag(header)
+	if !ok || version < 1 {
+		return 0, appErrors.NewPreconditionFailedError("If-Match does not match the current version", nil)
+	}
+	return version, nil
+}
+
+// NoneMatch reports whether the If-None-Match header of request lists etag or is "*".
+// Tags are compared weakly, as RFC 9110 asks for GET and HEAD.
+func NoneMatch(request *http.Request, etag string) bool {
+	header := request.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func parseVersionETag(tag string) (int, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	return version, err == nil
+}
@@ -0,0 +1,141 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MergePatchContentType is JSON Merge Patch, RFC 7396. Plain application/json is read the same way.
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType is JSON Patch, RFC 6902.
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+// patchOperation is one operation of a JSON Patch document.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// GetPatchPayload reads a partial update, as a JSON Merge Patch or a JSON Patch picked by the
+// Content-Type, into result: a pointer to a struct whose fields are pointers, so that nil means
+// "leave unchanged". Only top level members can be patched. In a JSON Patch, "add" and
+// "replace" set a member and "remove" clears it; a null in a merge patch clears it too. A
+// cleared member is set to its zero value, the validate tags decide whether that is allowed.
+func GetPatchPayload(request *http.Request, result interface{}) error {
+	logger, ok := request.Context().Value(logger.LoggerContextKey).(*logrus.Entry)
+	if !ok {
+		logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+
+	var members map[string]json.RawMessage
+	var err error
+	switch mediaType {
+	case MergePatchContentType, "application/json", "":
+		members, err = readMergePatch(request)
+	case JSONPatchContentType:
+		members, err = readJSONPatch(request)
+	default:
+		return appErrors.NewUnsupportedMediaTypeError(
+			fmt.Sprintf("Content-Type must be %s or %s", MergePatchContentType, JSONPatchContentType), nil)
+	}
+	if err != nil {
+		logger.WithError(err).Warn("Failed to decode patch document")
+		return err
+	}
+
+	if err := applyPatchMembers(members, result); err != nil {
+		logger.WithError(err).Warn("Failed to apply patch document")
+		return err
+	}
+
+	if err := validate.Struct(result); err != nil {
+		logger.WithError(err).Warn("Validation failed")
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return appErrors.NewValidationErrors(validationErrors)
+		}
+		return appErrors.NewBadRequestError("Validation failed due to unexpected error", err)
+	}
+
+	return nil
+}
+
+func readMergePatch(request *http.Request) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.NewDecoder(request.Body).Decode(&members); err != nil {
+		return nil, appErrors.NewBadRequestError("Invalid payload format, expected a JSON object", err)
+	}
+	return members, nil
+}
+
+// readJSONPatch reduces the operations to the members they leave set, a later operation on the
+// same member wins. Operations that need the current document (test, move, copy) are refused.
+func readJSONPatch(request *http.Request) (map[string]json.RawMessage, error) {
+	var operations []patchOperation
+	if err := json.NewDecoder(request.Body).Decode(&operations); err != nil {
+		return nil, appErrors.NewBadRequestError("Invalid payload format, expected an array of operations", err)
+	}
+
+	members := map[string]json.RawMessage{}
+	for _, operation := range operations {
+		name, ok := strings.CutPrefix(operation.Path, "/")
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return nil, appErrors.NewBadRequestError(fmt.Sprintf("Unsupported patch path %q", operation.Path), nil)
+		}
+		name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+
+		switch operation.Op {
+		case "add", "replace":
+			if operation.Value == nil {
+				return nil, appErrors.NewBadRequestError(fmt.Sprintf("Operation %s on %s needs a value", operation.Op, operation.Path), nil)
+			}
+			members[name] = operation.Value
+		case "remove":
+			members[name] = json.RawMessage("null")
+		default:
+			return nil, appErrors.NewBadRequestError(fmt.Sprintf("Unsupported patch operation %q", operation.Op), nil)
+		}
+	}
+	return members, nil
+}
+
+// applyPatchMembers sets the fields of result named by the json tags of members.
+func applyPatchMembers(members map[string]json.RawMessage, result interface{}) error {
+	target := reflect.ValueOf(result).Elem()
+	fields := map[string]reflect.Value{}
+	for i := 0; i < target.NumField(); i++ {
+		name, _, _ := strings.Cut(target.Type().Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = target.Field(i)
+		}
+	}
+
+	for name, raw := range members {
+		field, ok := fields[name]
+		if !ok {
+			return appErrors.NewBadRequestError(fmt.Sprintf("Unknown field %q", name), nil)
+		}
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			field.Set(reflect.New(field.Type().Elem()))
+			continue
+		}
+		if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+			return appErrors.NewBadRequestError(fmt.Sprintf("Invalid value for %s", name), err)
+		}
+	}
+	return nil
+}
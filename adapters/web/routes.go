@@ -5,39 +5,165 @@ import (
 
 	"github.com/chud-lori/go-boilerplate/adapters/controllers"
 	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/domain/ports"
 	"github.com/sirupsen/logrus"
 )
 
-func UserRouter(controller *controllers.UserController, serve *http.ServeMux) {
-	serve.HandleFunc("POST /user", controller.Create)
-	serve.HandleFunc("PUT /user/{userId}", controller.Update)
-	serve.HandleFunc("DELETE /user/{userId}", controller.Delete)
-	serve.HandleFunc("GET /user/{userId}", controller.FindById)
-	serve.HandleFunc("GET /user", controller.FindAll)
+func UserRouter(controller *controllers.UserController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
+	// Users manage their own account; acting on anyone else's needs users:manage.
+	manageUsers := middleware.Permission(entities.PermissionUsersManage)
+	ownAccount := middleware.SelfOrPermission("userId", entities.PermissionUsersManage)
+	notImpersonating := middleware.NotImpersonating()
+
+	serve.Handle("POST /user", protect(controller.Create, tokenManager, cache, logger, manageUsers, notImpersonating))
+	serve.Handle("GET /user", protect(controller.FindAll, tokenManager, cache, logger, manageUsers))
+	serve.Handle("GET /user/{userId}", protect(controller.FindById, tokenManager, cache, logger, middleware.Permission(entities.PermissionUsersRead), ownAccount))
+	// Updates can change the email and password, which must not happen while impersonating.
+	serve.Handle("PUT /user/{userId}", protect(controller.Update, tokenManager, cache, logger, middleware.Permission(entities.PermissionUsersWrite), ownAccount, notImpersonating))
+	serve.Handle("DELETE /user/{userId}", protect(controller.Delete, tokenManager, cache, logger, middleware.Permission(entities.PermissionUsersWrite), ownAccount))
+	serve.Handle("PUT /user/{userId}/roles", protect(controller.SetRoles, tokenManager, cache, logger, manageUsers, notImpersonating))
+	// Deleted users cannot sign in, so only an admin can take them out of the trash.
+	serve.Handle("POST /user/{userId}/restore", protect(controller.Restore, tokenManager, cache, logger, manageUsers))
 }
 
-func AuthRouter(controller *controllers.AuthController, serve *http.ServeMux) {
+func AuthRouter(controller *controllers.AuthController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
 	serve.HandleFunc("POST /signin", controller.SignIn)
+	serve.HandleFunc("POST /signin/mfa", controller.SignInMFA)
 	serve.HandleFunc("POST /signup", controller.SignUp)
+	serve.HandleFunc("POST /refresh", controller.Refresh)
+	serve.HandleFunc("POST /password/forgot", controller.ForgotPassword)
+	serve.HandleFunc("POST /password/reset", controller.ResetPassword)
+	serve.HandleFunc("GET /verify-email", controller.VerifyEmail)
+	serve.HandleFunc("POST /verify-email/resend", controller.ResendVerification)
+	serve.HandleFunc("GET /auth/oidc/{provider}", controller.StartSocialLogin)
+	serve.HandleFunc("GET /auth/oidc/{provider}/callback", controller.CompleteSocialLogin)
+
+	// Personal access tokens are revoked through DELETE /tokens/{tokenId} instead.
+	signOutHandler := protect(controller.SignOut, tokenManager, cache, logger, middleware.SessionToken())
+	serve.Handle("POST /signout", signOutHandler)
+
+	// Only an admin's own session may impersonate: neither a personal access token, even one
+	// granting users:impersonate, nor an impersonation token can start an impersonation.
+	impersonate := protect(controller.Impersonate, tokenManager, cache, logger,
+		middleware.Permission(entities.PermissionUsersImpersonate), middleware.SessionToken(), middleware.NotImpersonating())
+	serve.Handle("POST /admin/impersonate/{userId}", impersonate)
+}
+
+func MFARouter(controller *controllers.MFAController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
+	// Protected endpoints, the second factor is the user's own and cannot be changed while
+	// impersonating or with a personal access token.
+	sessionOnly := middleware.SessionToken()
+	notImpersonating := middleware.NotImpersonating()
+
+	serve.Handle("POST /mfa/enroll", protect(controller.Enroll, tokenManager, cache, logger, sessionOnly, notImpersonating))
+	serve.Handle("POST /mfa/verify", protect(controller.Verify, tokenManager, cache, logger, sessionOnly, notImpersonating))
+	serve.Handle("POST /mfa/disable", protect(controller.Disable, tokenManager, cache, logger, sessionOnly, notImpersonating))
+}
+
+func APIKeyRouter(controller *controllers.APIKeyController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
+	manageKeys := middleware.Permission(entities.PermissionAPIKeysManage)
+
+	serve.Handle("POST /admin/api-keys", protect(controller.Create, tokenManager, cache, logger, manageKeys))
+	serve.Handle("GET /admin/api-keys", protect(controller.List, tokenManager, cache, logger, manageKeys))
+	serve.Handle("DELETE /admin/api-keys/{keyId}", protect(controller.Revoke, tokenManager, cache, logger, manageKeys))
+}
+
+func AuditRouter(controller *controllers.AuditController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
+	serve.Handle("GET /audit", protect(controller.List, tokenManager, cache, logger, middleware.Permission(entities.PermissionAuditRead)))
+}
+
+func TrashRouter(controller *controllers.TrashController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
+	serve.Handle("GET /admin/trash/posts", protect(controller.ListPosts, tokenManager, cache, logger, middleware.Permission(entities.PermissionPostsManage)))
+	serve.Handle("GET /admin/trash/users", protect(controller.ListUsers, tokenManager, cache, logger, middleware.Permission(entities.PermissionUsersManage)))
+}
+
+func SessionRouter(controller *controllers.SessionController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
+	// Sessions belong to the account, personal access tokens need the matching users scope.
+	readAccount := middleware.Permission(entities.PermissionUsersRead)
+	writeAccount := middleware.Permission(entities.PermissionUsersWrite)
+
+	serve.Handle("GET /sessions", protect(controller.List, tokenManager, cache, logger, readAccount))
+	serve.Handle("DELETE /sessions", protect(controller.RevokeAll, tokenManager, cache, logger, writeAccount))
+	serve.Handle("DELETE /sessions/{sessionId}", protect(controller.Revoke, tokenManager, cache, logger, writeAccount))
+}
+
+func PersonalAccessTokenRouter(controller *controllers.PersonalAccessTokenController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
+	sessionOnly := middleware.SessionToken()
+
+	serve.Handle("POST /tokens", protect(controller.Create, tokenManager, cache, logger, sessionOnly, middleware.NotImpersonating()))
+	serve.Handle("GET /tokens", protect(controller.List, tokenManager, cache, logger, middleware.Permission(entities.PermissionUsersRead)))
+	serve.Handle("DELETE /tokens/{tokenId}", protect(controller.Revoke, tokenManager, cache, logger, middleware.Permission(entities.PermissionUsersWrite)))
 }
 
-func PostRouter(controller *controllers.PostController, serve *http.ServeMux, tokenManager ports.TokenManager, logger *logrus.Logger) {
+func JWKSRouter(controller *controllers.JWKSController, serve *http.ServeMux) {
+	serve.HandleFunc("GET /.well-known/jwks.json", controller.JWKS)
+}
+
+func PostRouter(controller *controllers.PostController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
 	// Protected endpoints
-	createHandler := middleware.JWTMiddleware(http.HandlerFunc(controller.Create), tokenManager, logger)
+	writePosts := middleware.Permission(entities.PermissionPostsWrite)
+
+	createHandler := protect(controller.Create, tokenManager, cache, logger, writePosts)
 	serve.Handle("POST /post", createHandler)
 
-	updateHandler := middleware.JWTMiddleware(http.HandlerFunc(controller.Update), tokenManager, logger)
+	updateHandler := protect(controller.Update, tokenManager, cache, logger, writePosts)
 	serve.Handle("PUT /post/{postId}", updateHandler)
 
-	deleteHandler := middleware.JWTMiddleware(http.HandlerFunc(controller.Delete), tokenManager, logger)
+	patchHandler := protect(controller.Patch, tokenManager, cache, logger, writePosts)
+	serve.Handle("PATCH /post/{postId}", patchHandler)
+
+	deleteHandler := protect(controller.Delete, tokenManager, cache, logger, writePosts)
 	serve.Handle("DELETE /post/{postId}", deleteHandler)
 
-	uploadHandler := middleware.JWTMiddleware(http.HandlerFunc(controller.UploadAttachment), tokenManager, logger)
+	restoreHandler := protect(controller.Restore, tokenManager, cache, logger, writePosts)
+	serve.Handle("POST /post/{postId}/restore", restoreHandler)
+
+	statusHandler := protect(controller.SetStatus, tokenManager, cache, logger, writePosts)
+	serve.Handle("PUT /post/{postId}/status", statusHandler)
+
+	restoreRevisionHandler := protect(controller.RestoreRevision, tokenManager, cache, logger, writePosts)
+	serve.Handle("POST /post/{postId}/revisions/{rev}/restore", restoreRevisionHandler)
+
+	uploadHandler := protect(controller.UploadAttachment, tokenManager, cache, logger, writePosts)
 	serve.Handle("POST /post/{postId}/upload", uploadHandler)
 
-	// Public endpoints
-	serve.HandleFunc("GET /post/{postId}", controller.GetById)
-	serve.HandleFunc("GET /post", controller.GetAll)
+	// Public endpoints, signed in authors also see their own drafts and archived posts.
+	optionalAuth := func(handler http.HandlerFunc) http.Handler {
+		return middleware.OptionalJWTMiddleware(handler, tokenManager, cache, logger)
+	}
+	serve.Handle("GET /post/{postId}", optionalAuth(controller.GetById))
+	serve.Handle("GET /post/{postId}/revisions", optionalAuth(controller.ListRevisions))
+	serve.Handle("GET /post/{postId}/revisions/{rev}", optionalAuth(controller.GetRevision))
+	serve.Handle("GET /post", optionalAuth(controller.GetAll))
 	serve.HandleFunc("GET /uploads/{uploadId}/events", controller.UploadStatusSSE)
 }
+
+func CommentRouter(controller *controllers.CommentController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
+	writePosts := middleware.Permission(entities.PermissionPostsWrite)
+
+	serve.Handle("POST /post/{postId}/comments", protect(controller.Create, tokenManager, cache, logger, writePosts))
+	serve.Handle("PUT /post/{postId}/comments/{commentId}", protect(controller.Update, tokenManager, cache, logger, writePosts))
+	serve.Handle("DELETE /post/{postId}/comments/{commentId}", protect(controller.Delete, tokenManager, cache, logger, writePosts))
+
+	// Public, signed in authors also see the comments on their drafts.
+	serve.Handle("GET /post/{postId}/comments", middleware.OptionalJWTMiddleware(http.HandlerFunc(controller.List), tokenManager, cache, logger))
+}
+
+func ReactionRouter(controller *controllers.ReactionController, serve *http.ServeMux, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger) {
+	writePosts := middleware.Permission(entities.PermissionPostsWrite)
+
+	serve.Handle("PUT /post/{postId}/reactions/{type}", protect(controller.React, tokenManager, cache, logger, writePosts))
+	serve.Handle("DELETE /post/{postId}/reactions/{type}", protect(controller.Unreact, tokenManager, cache, logger, writePosts))
+}
+
+func TagRouter(controller *controllers.TagController, serve *http.ServeMux) {
+	serve.HandleFunc("GET /tags", controller.List)
+}
+
+// protect authenticates the route with JWTMiddleware, then checks its policies. Every route
+// names at least one, personal access tokens are only as narrow as the policies checked.
+func protect(handler http.HandlerFunc, tokenManager ports.TokenManager, cache ports.Cache, logger *logrus.Logger, policy middleware.Policy, more ...middleware.Policy) http.Handler {
+	h := middleware.RequirePermission(handler, logger, append([]middleware.Policy{policy}, more...)...)
+	return middleware.JWTMiddleware(h, tokenManager, cache, logger)
+}
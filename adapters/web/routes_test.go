@@ -0,0 +1,80 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/adapters/controllers"
+	"github.com/chud-lori/go-boilerplate/adapters/middleware"
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newScopedRouter serves the account and post routes behind PersonalAccessTokenMiddleware,
+// authenticating every gbp_ token as a personal access token granting only scopes.
+func newScopedRouter(scopes ...string) http.Handler {
+	logger := logrus.New()
+	tokenManager := new(mocks.MockTokenManager)
+	cache := new(mocks.MockCache)
+
+	tokens := new(mocks.MockPersonalAccessTokenService)
+	tokens.On("Authenticate", mock.Anything, mock.Anything).Return(&entities.TokenClaims{
+		UserID:      "user-1",
+		TokenID:     "pat-1",
+		Type:        entities.TokenTypePersonalAccess,
+		Permissions: scopes,
+	}, nil)
+
+	mux := http.NewServeMux()
+	AuthRouter(&controllers.AuthController{}, mux, tokenManager, cache, logger)
+	MFARouter(&controllers.MFAController{}, mux, tokenManager, cache, logger)
+	SessionRouter(&controllers.SessionController{}, mux, tokenManager, cache, logger)
+	PersonalAccessTokenRouter(&controllers.PersonalAccessTokenController{}, mux, tokenManager, cache, logger)
+	CommentRouter(&controllers.CommentController{}, mux, tokenManager, cache, logger)
+	ReactionRouter(&controllers.ReactionController{}, mux, tokenManager, cache, logger)
+
+	return middleware.PersonalAccessTokenMiddleware(mux, tokens, logger)
+}
+
+func TestRoutes_NarrowPersonalAccessTokenForbidden(t *testing.T) {
+	postsOnly := newScopedRouter(entities.PermissionPostsWrite)
+	readOnly := newScopedRouter(entities.PermissionUsersRead)
+	impersonator := newScopedRouter(entities.PermissionUsersImpersonate)
+
+	cases := []struct {
+		name   string
+		router http.Handler
+		method string
+		path   string
+	}{
+		{"sign out everywhere", postsOnly, http.MethodDelete, "/sessions"},
+		{"revoke session", postsOnly, http.MethodDelete, "/sessions/6f1c7d4e-0000-0000-0000-000000000001"},
+		{"list sessions", postsOnly, http.MethodGet, "/sessions"},
+		{"revoke token", postsOnly, http.MethodDelete, "/tokens/6f1c7d4e-0000-0000-0000-000000000002"},
+		{"list tokens", postsOnly, http.MethodGet, "/tokens"},
+		{"sign out", postsOnly, http.MethodPost, "/signout"},
+		{"disable mfa", postsOnly, http.MethodPost, "/mfa/disable"},
+		{"comment", readOnly, http.MethodPost, "/post/6f1c7d4e-0000-0000-0000-000000000003/comments"},
+		{"edit comment", readOnly, http.MethodPut, "/post/6f1c7d4e-0000-0000-0000-000000000003/comments/6f1c7d4e-0000-0000-0000-000000000004"},
+		{"delete comment", readOnly, http.MethodDelete, "/post/6f1c7d4e-0000-0000-0000-000000000003/comments/6f1c7d4e-0000-0000-0000-000000000004"},
+		{"react", readOnly, http.MethodPut, "/post/6f1c7d4e-0000-0000-0000-000000000003/reactions/like"},
+		{"unreact", readOnly, http.MethodDelete, "/post/6f1c7d4e-0000-0000-0000-000000000003/reactions/like"},
+		{"impersonate", impersonator, http.MethodPost, "/admin/impersonate/6f1c7d4e-0000-0000-0000-000000000005"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer gbp_narrow")
+			rec := httptest.NewRecorder()
+
+			tc.router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+		})
+	}
+}
@@ -13,11 +13,15 @@ import (
 	"github.com/chud-lori/go-boilerplate/adapters/web"
 	"github.com/chud-lori/go-boilerplate/config"
 	_ "github.com/chud-lori/go-boilerplate/docs"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
 	"github.com/chud-lori/go-boilerplate/domain/services"
 	"github.com/chud-lori/go-boilerplate/infrastructure/api_clients"
 	"github.com/chud-lori/go-boilerplate/infrastructure/cache"
+	"github.com/chud-lori/go-boilerplate/infrastructure/counter"
 	"github.com/chud-lori/go-boilerplate/infrastructure/datastore"
 	"github.com/chud-lori/go-boilerplate/infrastructure/grpc_clients"
+	"github.com/chud-lori/go-boilerplate/infrastructure/identity"
+	"github.com/chud-lori/go-boilerplate/infrastructure/locking"
 	"github.com/chud-lori/go-boilerplate/infrastructure/queue"
 	"github.com/chud-lori/go-boilerplate/internal/utils"
 	"github.com/chud-lori/go-boilerplate/pkg/auth"
@@ -73,12 +77,15 @@ func main() {
 		log.Fatal("did not connect to mail gRPC service: ", err)
 	}
 
-	// Use in service if needed
-	// lock, err := locking.NewRedisLocker(cfg.RedisAddr, cfg.RedisPassword, 1, baseLogger)
-	// if err != nil {
-	// 	log.Fatal("Failed to connect redis for locking: ", err)
-	// }
-	// defer lock.Close() Do it in goroutine
+	lock, err := locking.NewRedisLocker(cfg.RedisAddr, cfg.RedisPassword, 1, baseLogger)
+	if err != nil {
+		log.Fatal("Failed to connect redis for locking: ", err)
+	}
+
+	counters, err := counter.NewRedisCounterStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB, baseLogger)
+	if err != nil {
+		log.Fatal("Failed to connect redis for counters: ", err)
+	}
 
 	externalApiClient := api_clients.NewApiClient("ExternalService", baseLogger)
 
@@ -96,10 +103,50 @@ func main() {
 
 	// ========== Domain Service Dependencies ==========
 
-	encryptor := &auth.BcryptEncryptor{}
+	encryptor := &auth.Argon2idEncryptor{Params: auth.Argon2Params{
+		Memory:      uint32(cfg.PasswordHashMemory),
+		Iterations:  uint32(cfg.PasswordHashIterations),
+		Parallelism: uint8(cfg.PasswordHashParallelism),
+	}}
+	passwordPolicy := &services.PasswordPolicyServiceImpl{
+		MinLength:     cfg.PasswordMinLength,
+		MaxLength:     cfg.PasswordMaxLength,
+		RequireUpper:  cfg.PasswordRequireUpper,
+		RequireLower:  cfg.PasswordRequireLower,
+		RequireDigit:  cfg.PasswordRequireDigit,
+		RequireSymbol: cfg.PasswordRequireSymbol,
+	}
+	if cfg.BreachedPasswordsFile != "" {
+		passwordPolicy.Breached, err = auth.LoadBreachedPasswords(cfg.BreachedPasswordsFile)
+		if err != nil {
+			baseLogger.Fatal("Failed to load breached passwords: ", err)
+		}
+		baseLogger.Infof("Loaded %d breached password hashes", passwordPolicy.Breached.Len())
+	}
 	tokenManager := &auth.JWTManager{
-		SecretKey:  cfg.JwtSecret,
-		Expiration: 24 * time.Hour,
+		SecretKey:               cfg.JwtSecret,
+		Expiration:              cfg.AccessTokenTTL,
+		RefreshExpiration:       cfg.RefreshTokenTTL,
+		VerificationExpiration:  cfg.EmailVerificationTTL,
+		MFAExpiration:           cfg.MFATokenTTL,
+		ImpersonationExpiration: cfg.ImpersonationTTL,
+	}
+	if len(cfg.JwtKeyFiles) > 0 {
+		tokenManager.Keys, err = auth.LoadKeySet(cfg.JwtKeyFiles, cfg.JwtSigningKeyID)
+		if err != nil {
+			baseLogger.Fatal("Failed to load JWT signing keys: ", err)
+		}
+	}
+	identityProviders := map[string]ports.IdentityProvider{}
+	for _, p := range cfg.OIDCProviders {
+		identityProviders[p.Name] = identity.NewOIDCProvider(identity.OIDCConfig{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
 	}
 	mailService := &services.MailServiceImpl{
 		MailClient: mailClient,
@@ -109,17 +156,71 @@ func main() {
 
 	userRepo := &repositories.UserRepositoryPostgre{}
 	postRepo := &repositories.PostRepositoryPostgre{}
+	postRevisionRepo := &repositories.PostRevisionRepositoryPostgre{}
+	tagRepo := &repositories.TagRepositoryPostgre{}
+	commentRepo := &repositories.CommentRepositoryPostgre{}
+	reactionRepo := &repositories.ReactionRepositoryPostgre{}
+	recoveryCodeRepo := &repositories.RecoveryCodeRepositoryPostgre{}
+	apiKeyRepo := &repositories.APIKeyRepositoryPostgre{}
+	identityRepo := &repositories.IdentityRepositoryPostgre{}
+	sessionRepo := &repositories.SessionRepositoryPostgre{}
+	personalAccessTokenRepo := &repositories.PersonalAccessTokenRepositoryPostgre{}
+	auditRepo := &repositories.AuditRepositoryPostgre{}
 
 	// ========== Services ==========
 
+	// Audit events are written in their own transaction, so failed attempts are kept too.
+	auditService := &services.AuditServiceImpl{
+		DB:              db,
+		AuditRepository: auditRepo,
+		CtxTimeout:      ctxTimeout,
+	}
+
 	authService := &services.AuthServiceImpl{
-		DB:             db,
-		UserRepository: userRepo,
-		MailService:    mailService,
-		Encryptor:      encryptor,
-		TokenManager:   tokenManager,
-		ExternalApi:    externalApiClient,
-		CtxTimeout:     ctxTimeout,
+		DB:                     db,
+		UserRepository:         userRepo,
+		RecoveryCodeRepository: recoveryCodeRepo,
+		SessionRepository:      sessionRepo,
+		MailService:            mailService,
+		Encryptor:              encryptor,
+		TokenManager:           tokenManager,
+		Cache:                  cache,
+		ExternalApi:            externalApiClient,
+		RefreshTokenTTL:        cfg.RefreshTokenTTL,
+		PasswordResetTTL:       cfg.PasswordResetTTL,
+		CtxTimeout:             ctxTimeout,
+
+		RequireVerifiedEmail:       cfg.RequireEmailVerification,
+		VerificationURL:            cfg.EmailVerificationURL,
+		VerificationResendInterval: cfg.VerificationResendInterval,
+
+		PasswordResetResendInterval: cfg.PasswordResetResendInterval,
+
+		LoginThrottle: &services.LoginThrottle{
+			Cache:               cache,
+			Window:              cfg.LoginFailureWindow,
+			MaxFailuresPerEmail: cfg.LoginMaxFailuresPerEmail,
+			MaxFailuresPerIP:    cfg.LoginMaxFailuresPerIP,
+			LockoutDuration:     cfg.LoginLockoutDuration,
+			DelayAfter:          cfg.LoginDelayAfter,
+			DelayBase:           cfg.LoginDelayBase,
+		},
+		PasswordPolicy: passwordPolicy,
+		AuditLogger:    auditService,
+
+		IdentityProviders:  identityProviders,
+		IdentityRepository: identityRepo,
+		SocialLoginTTL:     cfg.OIDCStateTTL,
+	}
+
+	mfaService := &services.MFAServiceImpl{
+		DB:                     db,
+		UserRepository:         userRepo,
+		RecoveryCodeRepository: recoveryCodeRepo,
+		Encryptor:              encryptor,
+		Cache:                  cache,
+		Issuer:                 cfg.MFAIssuer,
+		CtxTimeout:             ctxTimeout,
 	}
 
 	userService := &services.UserServiceImpl{
@@ -127,24 +228,92 @@ func main() {
 		UserRepository: userRepo,
 		Encryptor:      encryptor,
 		Cache:          cache,
+		PasswordPolicy: passwordPolicy,
+		AuditLogger:    auditService,
 		CtxTimeout:     ctxTimeout,
 	}
 
+	// Reaction counts are served from Redis and reconciled into Postgres by the background job.
+	reactionService := &services.ReactionServiceImpl{
+		DB:                 db,
+		ReactionRepository: reactionRepo,
+		PostRepository:     postRepo,
+		Counters:           counters,
+		Locking:            lock,
+		CtxTimeout:         ctxTimeout,
+	}
+
 	postService := &services.PostServiceImpl{
+		DB:                     db,
+		PostRepository:         postRepo,
+		PostRevisionRepository: postRevisionRepo,
+		TagRepository:          tagRepo,
+		UserRepository:         userRepo,
+		Cache:                  cache,
+		JobQueue:               jobQueue,
+		AuditLogger:            auditService,
+		ReactionService:        reactionService,
+		CtxTimeout:             ctxTimeout,
+	}
+
+	tagService := &services.TagServiceImpl{
+		DB:            db,
+		TagRepository: tagRepo,
+		CtxTimeout:    ctxTimeout,
+	}
+
+	commentService := &services.CommentServiceImpl{
+		DB:                db,
+		CommentRepository: commentRepo,
+		PostRepository:    postRepo,
+		Cache:             cache,
+		CtxTimeout:        ctxTimeout,
+	}
+
+	trashService := &services.TrashServiceImpl{
 		DB:             db,
 		PostRepository: postRepo,
 		UserRepository: userRepo,
-		Cache:          cache,
-		JobQueue:       jobQueue,
+		Locking:        lock,
+		AuditLogger:    auditService,
+		Retention:      cfg.TrashRetention,
 		CtxTimeout:     ctxTimeout,
 	}
 
+	apiKeyService := &services.APIKeyServiceImpl{
+		DB:               db,
+		APIKeyRepository: apiKeyRepo,
+		Cache:            cache,
+		StaticKey:        cfg.APIKey,
+		CacheTTL:         cfg.APIKeyCacheTTL,
+		CtxTimeout:       ctxTimeout,
+	}
+
+	sessionService := &services.SessionServiceImpl{
+		DB:                db,
+		SessionRepository: sessionRepo,
+		Cache:             cache,
+		RefreshTokenTTL:   cfg.RefreshTokenTTL,
+		CtxTimeout:        ctxTimeout,
+	}
+
+	personalAccessTokenService := &services.PersonalAccessTokenServiceImpl{
+		DB:                            db,
+		PersonalAccessTokenRepository: personalAccessTokenRepo,
+		UserRepository:                userRepo,
+		CtxTimeout:                    ctxTimeout,
+	}
+
 	// ========== Controllers ==========
 
 	authController := &controllers.AuthController{
 		AuthService: authService,
 	}
 
+	mfaController := &controllers.MFAController{
+		MFAService: mfaService,
+	}
+
 	userController := &controllers.UserController{
 		UserService: userService,
 	}
@@ -153,6 +322,42 @@ func main() {
 		PostService: postService,
 	}
 
+	tagController := &controllers.TagController{
+		TagService: tagService,
+	}
+
+	commentController := &controllers.CommentController{
+		CommentService: commentService,
+	}
+
+	reactionController := &controllers.ReactionController{
+		ReactionService: reactionService,
+	}
+
+	apiKeyController := &controllers.APIKeyController{
+		APIKeyService: apiKeyService,
+	}
+
+	sessionController := &controllers.SessionController{
+		SessionService: sessionService,
+	}
+
+	personalAccessTokenController := &controllers.PersonalAccessTokenController{
+		PersonalAccessTokenService: personalAccessTokenService,
+	}
+
+	auditController := &controllers.AuditController{
+		AuditService: auditService,
+	}
+
+	trashController := &controllers.TrashController{
+		TrashService: trashService,
+	}
+
+	jwksController := &controllers.JWKSController{
+		TokenManager: tokenManager,
+	}
+
 	// ========== Routers ==========
 	router := http.NewServeMux()
 
@@ -161,24 +366,47 @@ func main() {
 		router.Handle("/docs/", httpSwagger.WrapHandler)
 	}
 
+	// Public key discovery (no /api prefix, no API key)
+	web.JWKSRouter(jwksController, router)
+
 	// Create a single API router that will contain all API routes
 	apiRouter := http.NewServeMux()
 
 	// Auth routes (public)
-	web.AuthRouter(authController, apiRouter)
+	web.AuthRouter(authController, apiRouter, tokenManager, cache, baseLogger)
+
+	// Two-factor management (protected)
+	web.MFARouter(mfaController, apiRouter, tokenManager, cache, baseLogger)
+
+	// Session management (protected)
+	web.SessionRouter(sessionController, apiRouter, tokenManager, cache, baseLogger)
+
+	// Personal access tokens (protected, minting needs a session token)
+	web.PersonalAccessTokenRouter(personalAccessTokenController, apiRouter, tokenManager, cache, baseLogger)
 
 	// Post routes (public + protected)
-	web.PostRouter(postController, apiRouter, tokenManager, baseLogger)
+	web.PostRouter(postController, apiRouter, tokenManager, cache, baseLogger)
+
+	// Tags with their post counts (public)
+	web.TagRouter(tagController, apiRouter)
+
+	// Comments on posts (public listing, writes protected)
+	web.CommentRouter(commentController, apiRouter, tokenManager, cache, baseLogger)
+
+	// Reactions on posts (protected)
+	web.ReactionRouter(reactionController, apiRouter, tokenManager, cache, baseLogger)
 
-	// User routes (protected)
-	userRouter := http.NewServeMux()
-	web.UserRouter(userController, userRouter)
-	var protectedUserHandler http.Handler = userRouter
-	protectedUserHandler = middleware.JWTMiddleware(protectedUserHandler, tokenManager, baseLogger)
+	// User routes (protected, with per-route permission policies)
+	web.UserRouter(userController, apiRouter, tokenManager, cache, baseLogger)
 
-	// Mount user routes to API router
-	apiRouter.Handle("/user", protectedUserHandler)
-	apiRouter.Handle("/user/", protectedUserHandler)
+	// API key administration (protected, apikeys:manage)
+	web.APIKeyRouter(apiKeyController, apiRouter, tokenManager, cache, baseLogger)
+
+	// Audit log queries (protected, audit:read)
+	web.AuditRouter(auditController, apiRouter, tokenManager, cache, baseLogger)
+
+	// Trash listings (protected, posts:manage and users:manage)
+	web.TrashRouter(trashController, apiRouter, tokenManager, cache, baseLogger)
 
 	// Single mount point for all API routes
 	router.Handle("/api/", http.StripPrefix("/api", apiRouter))
@@ -187,8 +415,14 @@ func main() {
 
 	var handler http.Handler = router
 	handler = middleware.RecoveryMiddleware(handler, baseLogger)
+	// Personal access tokens are resolved after the API key check, JWTMiddleware then
+	// accepts them on protected routes like session tokens.
+	handler = middleware.PersonalAccessTokenMiddleware(handler, personalAccessTokenService, baseLogger)
+	// The API key check runs inside the request logger, so rejected requests are logged
+	// and accepted ones carry the client name.
+	handler = middleware.APIKeyMiddleware(handler, apiKeyService, baseLogger)
+	handler = middleware.ClientIPMiddleware(handler, cfg.TrustProxyHeaders)
 	handler = middleware.LogTrafficMiddleware(handler, baseLogger)
-	handler = middleware.APIKeyMiddleware(handler, cfg.APIKey, baseLogger)
 	handler = middleware.CorsMiddleware(handler)
 
 	// ========== HTTP Server Setup ==========
@@ -206,6 +440,16 @@ func main() {
 		}
 	}()
 
+	// ========== Background Jobs ==========
+
+	// Hard-deletes posts and users that stayed in the trash longer than the retention.
+	purgeCtx, stopPurge := context.WithCancel(context.WithValue(context.Background(), logger.LoggerContextKey, baseLogger.WithField("job", "trash_purge")))
+	go trashService.RunPurgeJob(purgeCtx, cfg.TrashPurgeInterval)
+
+	// Writes the reaction counts kept in Redis into Postgres.
+	reconcileCtx, stopReconcile := context.WithCancel(context.WithValue(context.Background(), logger.LoggerContextKey, baseLogger.WithField("job", "reaction_reconcile")))
+	go reactionService.RunReconcileJob(reconcileCtx, cfg.ReactionReconcileInterval)
+
 	// ========== Graceful Shutdown ==========
 
 	wait := utils.GracefullShutdown(context.Background(), 5*time.Second, map[string]utils.Operation{
@@ -224,6 +468,20 @@ func main() {
 		"rabbitmq": func(ctx context.Context) error {
 			return jobQueue.Close()
 		},
+		"trash-purge": func(ctx context.Context) error {
+			stopPurge()
+			return nil
+		},
+		"reaction-reconcile": func(ctx context.Context) error {
+			stopReconcile()
+			return nil
+		},
+		"locking": func(ctx context.Context) error {
+			return lock.Close()
+		},
+		"counters": func(ctx context.Context) error {
+			return counters.Close()
+		},
 	})
 
 	<-wait
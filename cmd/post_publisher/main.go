@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/adapters/repositories"
+	"github.com/chud-lori/go-boilerplate/config"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/infrastructure/cache"
+	"github.com/chud-lori/go-boilerplate/infrastructure/datastore"
+	"github.com/chud-lori/go-boilerplate/infrastructure/locking"
+	"github.com/chud-lori/go-boilerplate/internal/utils"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+// The post publisher publishes scheduled drafts once their publish_at has come. Several
+// instances may run, the publish lock lets only one of them work at a time.
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("Failed to load environment variables")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	baseLogger := logger.NewLogger(cfg.LogLevel)
+	workerLogger := baseLogger.WithFields(logrus.Fields{
+		"layer": "worker",
+		"job":   "post_publish",
+	})
+
+	db, err := datastore.NewPostgreDatabase(cfg.DatabaseURL, baseLogger)
+	if err != nil {
+		baseLogger.Fatal("Failed to connect to database: ", err)
+	}
+
+	redisCache, err := cache.NewRedisCache(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB, baseLogger)
+	if err != nil {
+		baseLogger.Fatalf("Failed to connect to Redis: %v", err)
+	}
+
+	lock, err := locking.NewRedisLocker(cfg.RedisAddr, cfg.RedisPassword, 1, baseLogger)
+	if err != nil {
+		baseLogger.Fatalf("Failed to connect redis for locking: %v", err)
+	}
+
+	ctxTimeout := 60 * time.Second
+
+	auditService := &services.AuditServiceImpl{
+		DB:              db,
+		AuditRepository: &repositories.AuditRepositoryPostgre{},
+		CtxTimeout:      ctxTimeout,
+	}
+	publisher := &services.PostPublisherServiceImpl{
+		DB:             db,
+		PostRepository: &repositories.PostRepositoryPostgre{},
+		Cache:          redisCache,
+		Locking:        lock,
+		AuditLogger:    auditService,
+		CtxTimeout:     ctxTimeout,
+	}
+
+	ctx, stop := context.WithCancel(context.WithValue(context.Background(), logger.LoggerContextKey, workerLogger))
+	shutdown := utils.GracefullShutdown(context.Background(), 10*time.Second, map[string]utils.Operation{
+		"post-publisher": func(ctx context.Context) error {
+			stop()
+			return nil
+		},
+		"database": func(ctx context.Context) error {
+			return db.Close()
+		},
+		"redis": func(ctx context.Context) error {
+			return redisCache.Close()
+		},
+		"locking": func(ctx context.Context) error {
+			return lock.Close()
+		},
+	})
+
+	workerLogger.Infof("[PostPublisher] Publishing scheduled posts every %s...", cfg.PostPublishInterval)
+	go publisher.Run(ctx, cfg.PostPublishInterval)
+
+	<-shutdown
+	baseLogger.Println("Post publisher shutdown complete.")
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditActionSignIn        = "auth.sign_in"
+	AuditActionSignInMFA     = "auth.sign_in_mfa"
+	AuditActionSignInSocial  = "auth.sign_in_social"
+	AuditActionSignUp        = "auth.sign_up"
+	AuditActionSignOut       = "auth.sign_out"
+	AuditActionPasswordReset = "auth.password_reset"
+	AuditActionImpersonate   = "auth.impersonate"
+	AuditActionUserCreate    = "user.create"
+	AuditActionUserUpdate    = "user.update"
+	AuditActionUserDelete    = "user.delete"
+	AuditActionUserRestore   = "user.restore"
+	AuditActionUserSetRoles  = "user.set_roles"
+	AuditActionPostCreate    = "post.create"
+	AuditActionPostUpdate    = "post.update"
+	AuditActionPostDelete    = "post.delete"
+	AuditActionPostRestore   = "post.restore"
+	AuditActionPostRevert    = "post.revert"
+	AuditActionPostStatus    = "post.status"
+	AuditActionPostPublish   = "post.publish"
+	AuditActionTrashPurge    = "trash.purge"
+)
+
+const (
+	AuditTargetUser = "user"
+	AuditTargetPost = "post"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEvent is one entry of the append-only audit log.
+type AuditEvent struct {
+	ID         uuid.UUID
+	OccurredAt time.Time
+	// ActorID is the user who acted, empty when nobody was signed in, e.g. a failed sign in.
+	ActorID string
+	// ImpersonatorID is the admin who acted as ActorID, empty outside impersonation.
+	ImpersonatorID string
+	Action         string
+	TargetType     string
+	TargetID       string
+	IP             string
+	RequestID      string
+	Outcome        string
+	// Details hold action specific context such as the reason of a failure.
+	Details map[string]string
+}
+
+// AuditEventFilter narrows an audit log query. Empty fields and zero times match everything.
+type AuditEventFilter struct {
+	ActorID        string
+	ImpersonatorID string
+	Action         string
+	TargetType     string
+	TargetID       string
+	Outcome        string
+	From           time.Time
+	To             time.Time
+}
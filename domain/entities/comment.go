@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a comment on a post, or a reply to another comment when ParentID is set.
+type Comment struct {
+	ID       uuid.UUID  `json:"id"`
+	PostID   uuid.UUID  `json:"post_id"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	// AuthorID is uuid.Nil once the author is gone or the comment is deleted.
+	AuthorID  uuid.UUID  `json:"author_id"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// DeletedAt is set on the placeholder left in the thread by a deleted comment.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Replies are filled in only when comments are listed as a tree.
+	Replies []*Comment `json:"replies,omitempty"`
+	// ReplyCount is set on top-level comments listed as a tree. It counts every reply below
+	// the comment, including those left out of Replies.
+	ReplyCount int `json:"reply_count,omitempty"`
+}
+
+// CommentPage is one page of the comments of a post. NextCursor is empty on the last page.
+type CommentPage struct {
+	Comments   []*Comment
+	NextCursor string
+}
+
+// CommentCursor is the position of the last comment of a page, the next page starts after it.
+type CommentCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+var ErrInvalidCommentCursor = errors.New("invalid comment cursor")
+
+// String encodes the cursor as an opaque token for clients.
+func (c CommentCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + c.ID.String()))
+}
+
+// ParseCommentCursor decodes a token made by CommentCursor.String.
+func ParseCommentCursor(token string) (*CommentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCommentCursor
+	}
+	at, id, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return nil, ErrInvalidCommentCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, ErrInvalidCommentCursor
+	}
+	commentID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCommentCursor
+	}
+	return &CommentCursor{CreatedAt: createdAt, ID: commentID}, nil
+}
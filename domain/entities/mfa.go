@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode is a single-use replacement for a TOTP code. Only its hash is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAEnrollment is returned once, when a user starts enrolling an authenticator.
+// The plain recovery codes are never retrievable again.
+type MFAEnrollment struct {
+	Secret        string
+	URI           string
+	RecoveryCodes []string
+}
@@ -13,6 +13,63 @@ type Post struct {
 	User      *User     `json:"author,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Version starts at 1 and is bumped on every update, it is the post's ETag.
+	Version   int       `json:"version"`
+	// DeletedAt is set while the post is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Status decides who can read the post, only published posts are public.
+	Status    PostStatus `json:"status"`
+	// PublishAt schedules a draft, it is published once the time has come.
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	// Tags are the slugs of the post's tags. On create and update they may be written as tag
+	// names, nil leaves the tags of an updated post as they are.
+	Tags      []string   `json:"tags,omitempty"`
+	// CommentCount is the number of comments on the post that are not deleted.
+	CommentCount int `json:"comment_count"`
+	// Reactions counts the reactions on the post. MyReactions are those of the caller, set
+	// only for a signed in caller.
+	Reactions   ReactionCounts `json:"reactions"`
+	MyReactions []ReactionType `json:"my_reactions,omitempty"`
+	// Highlight is set by searches, a snippet of the body with the matched words in <mark> tags.
+	Highlight string `json:"highlight,omitempty"`
+}
+
+// PostFilter narrows a listing of posts. Search is a full-text query in web search syntax,
+// its matches are listed by relevance. Tags are slugs, a post matches when it has any of
+// them, or all of them with MatchAllTags.
+type PostFilter struct {
+	Search       string
+	Tags         []string
+	MatchAllTags bool
+}
+
+// PostStatus is the stage of a post's lifecycle.
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+// Valid reports whether s is one of the known statuses.
+func (s PostStatus) Valid() bool {
+	switch s {
+	case PostStatusDraft, PostStatusPublished, PostStatusArchived:
+		return true
+	}
+	return false
+}
+
+// PostPatch is a partial update of a post. Nil fields are left unchanged.
+type PostPatch struct {
+	Title *string
+	Body  *string
+}
+
+// IsEmpty reports whether the patch changes nothing.
+func (p PostPatch) IsEmpty() bool {
+	return p.Title == nil && p.Body == nil
 }
 
 type PaginationParams struct {
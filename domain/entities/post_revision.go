@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostRevision is the content of a post at one version. Every write to a post stores one,
+// Revision is the post version it belongs to.
+type PostRevision struct {
+	PostID   uuid.UUID
+	Revision int
+	Title    string
+	Body     string
+	// EditorID is the user who wrote this version, uuid.Nil once they are purged.
+	EditorID  uuid.UUID
+	CreatedAt time.Time
+}
+
+// PostRevisionDiff compares a revision with the current version of its post.
+type PostRevisionDiff struct {
+	Revision       *PostRevision
+	CurrentVersion int
+	// Diff is a unified diff of the title and body from the revision to the current version,
+	// empty when they are the same.
+	Diff string
+}
@@ -0,0 +1,35 @@
+package entities
+
+// ReactionType is one of the fixed set of reactions a user may leave on a post.
+type ReactionType string
+
+const (
+	ReactionLike  ReactionType = "like"
+	ReactionLove  ReactionType = "love"
+	ReactionLaugh ReactionType = "laugh"
+	ReactionWow   ReactionType = "wow"
+	ReactionSad   ReactionType = "sad"
+	ReactionAngry ReactionType = "angry"
+)
+
+// ReactionTypes lists every reaction type, in display order.
+var ReactionTypes = []ReactionType{ReactionLike, ReactionLove, ReactionLaugh, ReactionWow, ReactionSad, ReactionAngry}
+
+// Valid reports whether t is one of ReactionTypes.
+func (t ReactionType) Valid() bool {
+	for _, known := range ReactionTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ReactionCounts is the number of reactions of each type on a post. Types nobody used may be missing.
+type ReactionCounts map[ReactionType]int64
+
+// PostReactions are the reaction counts of a post and the reactions the caller left on it.
+type PostReactions struct {
+	Counts ReactionCounts `json:"counts"`
+	Mine   []ReactionType `json:"mine"`
+}
@@ -0,0 +1,31 @@
+package entities
+
+import "slices"
+
+// Roles and permissions seeded by the migrations. A role grants the permissions
+// linked to it in role_permissions; tokens carry the resulting permissions.
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+
+	PermissionUsersRead   = "users:read"
+	PermissionUsersWrite  = "users:write"
+	PermissionUsersManage = "users:manage" // act on any user, not only yourself
+	PermissionPostsWrite  = "posts:write"
+	PermissionPostsManage = "posts:manage" // edit or delete posts of other authors
+
+	PermissionUsersImpersonate = "users:impersonate" // sign in as another user for support
+
+	PermissionAPIKeysManage = "apikeys:manage"
+	PermissionAuditRead     = "audit:read"
+)
+
+// HasPermission reports whether permissions contains every one of required.
+func HasPermission(permissions []string, required ...string) bool {
+	for _, p := range required {
+		if !slices.Contains(permissions, p) {
+			return false
+		}
+	}
+	return true
+}
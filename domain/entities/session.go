@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is one sign in of a user on a device. Its ID is the family ID of the tokens issued
+// for it, so revoking the session revokes every token refreshed from that sign in.
+type Session struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	UserAgent string
+	IP        string
+	CreatedAt time.Time
+	// LastSeenAt is updated whenever the session's tokens are refreshed.
+	LastSeenAt time.Time
+	// ExpiresAt is when the last refresh token issued for the session expires.
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
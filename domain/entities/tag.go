@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Tag groups posts. Its slug is unique, the name is kept as it was first written.
+type Tag struct {
+	ID   uuid.UUID `json:"id"`
+	Slug string    `json:"slug"`
+	Name string    `json:"name"`
+	// PostCount is the number of published posts with the tag, set only when listing tags.
+	PostCount int `json:"post_count"`
+}
+
+// TagSlug is the slug of a tag name: lower case letters and digits, every other run of
+// characters turned into a single hyphen. "Go & Web Dev" becomes "go-web-dev".
+func TagSlug(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
+}
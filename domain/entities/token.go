@@ -0,0 +1,66 @@
+package entities
+
+import "time"
+
+// TokenType distinguishes short-lived access tokens from the refresh tokens used to rotate them.
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+	// TokenTypeEmailVerification is sent by email on sign up and never authenticates requests.
+	TokenTypeEmailVerification TokenType = "email_verification"
+	// TokenTypeMFAPending proves the password was checked; it is exchanged for a TokenPair
+	// once the second factor is verified.
+	TokenTypeMFAPending TokenType = "mfa_pending"
+	// TokenTypePersonalAccess marks claims built from a PersonalAccessToken rather than a JWT.
+	TokenTypePersonalAccess TokenType = "personal_access"
+)
+
+// TokenClaims is the validated content of a token issued by a TokenManager.
+// FamilyID is shared by every access/refresh token issued from the same sign-in,
+// so revoking the family ends the whole session.
+type TokenClaims struct {
+	UserID    string
+	TokenID   string
+	FamilyID  string
+	Email     string // set on email verification tokens only
+	Type      TokenType
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// Roles and Permissions are set on access tokens, as granted when the token was issued.
+	Roles       []string
+	Permissions []string
+	// ImpersonatorID is the admin acting as UserID, set on impersonation tokens only.
+	ImpersonatorID string
+}
+
+// Impersonating reports whether the token was issued to an admin acting as another user.
+func (c *TokenClaims) Impersonating() bool {
+	return c.ImpersonatorID != ""
+}
+
+// HasPermission reports whether the token grants every permission in required.
+func (c *TokenClaims) HasPermission(required ...string) bool {
+	return HasPermission(c.Permissions, required...)
+}
+
+// TokenPair is returned on sign in, sign up and refresh. When the user has two-factor
+// authentication enabled, sign in only sets MFAToken.
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+// JSONWebKey is the public part of a signing key, as published in a JWKS document (RFC 7517).
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
@@ -0,0 +1,7 @@
+package entities
+
+// TrashPurge counts what one purge of the trash removed for good.
+type TrashPurge struct {
+	Posts int64 `json:"posts"`
+	Users int64 `json:"users"`
+}
@@ -7,8 +7,34 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         uuid.UUID  `json:"id"`
+	Email      string     `json:"email"`
+	Password   string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	VerifiedAt *time.Time `json:"verified_at"`
+
+	TOTPSecret   string     `json:"-"`
+	MFAEnabledAt *time.Time `json:"-"`
+
+	Roles []string `json:"roles"`
+	// Permissions is the union of the permissions granted by Roles.
+	Permissions []string `json:"-"`
+
+	// DeletedAt is set while the user is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// IsVerified reports whether the user has confirmed ownership of their email address.
+func (u *User) IsVerified() bool {
+	return u.VerifiedAt != nil
+}
+
+// MFAEnabled reports whether sign in requires a second factor.
+func (u *User) MFAEnabled() bool {
+	return u.MFAEnabledAt != nil && u.TOTPSecret != ""
+}
+
+// HasPermission reports whether the user's roles grant every permission in required.
+func (u *User) HasPermission(required ...string) bool {
+	return HasPermission(u.Permissions, required...)
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, tx Transaction, key *entities.APIKey) (*entities.APIKey, error)
+	FindByHash(ctx context.Context, tx Transaction, keyHash string) (*entities.APIKey, error)
+	FindAll(ctx context.Context, tx Transaction) ([]*entities.APIKey, error)
+	// Revoke marks the key revoked and returns it; revoking twice yields ErrDataNotFound.
+	Revoke(ctx context.Context, tx Transaction, id uuid.UUID) (*entities.APIKey, error)
+	TouchLastUsed(ctx context.Context, tx Transaction, id uuid.UUID) error
+}
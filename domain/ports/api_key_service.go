@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type APIKeyService interface {
+	// Create stores key and returns it with the plain key, which is never retrievable again.
+	Create(ctx context.Context, key *entities.APIKey) (*entities.APIKey, string, error)
+	List(ctx context.Context) ([]*entities.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	// Authenticate returns the active key matching the plain key sent by a client.
+	Authenticate(ctx context.Context, key string) (*entities.APIKey, error)
+}
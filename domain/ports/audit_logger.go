@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+)
+
+type AuditLogger interface {
+	// Log appends event to the audit log. The actor, client IP and request ID are taken from
+	// ctx when the event leaves them empty. Failures are logged, never returned, so auditing
+	// cannot break the action being audited.
+	Log(ctx context.Context, event *entities.AuditEvent)
+}
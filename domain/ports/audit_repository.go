@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+)
+
+type AuditRepository interface {
+	Save(ctx context.Context, tx Transaction, event *entities.AuditEvent) error
+	// Find returns one page of the events matching filter, newest first, and how many match in total.
+	Find(ctx context.Context, tx Transaction, filter entities.AuditEventFilter, pagination entities.PaginationParams) ([]*entities.AuditEvent, int, error)
+}
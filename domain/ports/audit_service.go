@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+)
+
+type AuditService interface {
+	// List returns one page of the matching audit events, newest first, and the total number of matches.
+	List(ctx context.Context, filter entities.AuditEventFilter, page, limit int) ([]*entities.AuditEvent, int, error)
+}
@@ -7,6 +7,20 @@ import (
 )
 
 type AuthService interface {
-	SignIn(ctx context.Context, user *entities.User) (*entities.User, string, error)
-	SignUp(ctx context.Context, user *entities.User) (*entities.User, string, error)
+	SignIn(ctx context.Context, user *entities.User) (*entities.User, *entities.TokenPair, error)
+	SignUp(ctx context.Context, user *entities.User) (*entities.User, *entities.TokenPair, error)
+	// SignInMFA exchanges the mfa pending token returned by SignIn and a second factor code for a TokenPair.
+	SignInMFA(ctx context.Context, mfaToken, code string) (*entities.User, *entities.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*entities.TokenPair, error)
+	SignOut(ctx context.Context, claims *entities.TokenClaims) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, email, code, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	// StartSocialLogin returns the URL that sends the user to provider to sign in.
+	StartSocialLogin(ctx context.Context, provider string) (string, error)
+	// CompleteSocialLogin signs in with the code and state the provider redirected back with.
+	CompleteSocialLogin(ctx context.Context, provider, code, state string) (*entities.User, *entities.TokenPair, error)
+	// Impersonate returns a short-lived access token that lets the admin impersonatorID act as userID.
+	Impersonate(ctx context.Context, impersonatorID, userID string) (string, error)
 }
@@ -8,6 +8,14 @@ import (
 type Cache interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	// SetNX sets key only when it does not exist yet and reports whether it did, so that
+	// concurrent callers can claim a key once.
+	SetNX(ctx context.Context, key string, value []byte, expiration time.Duration) (bool, error)
+	// Incr atomically increments the counter at key and returns its new value. A counter
+	// created by Incr expires after expiration, later increments keep that expiry.
+	Incr(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	// Decr atomically decrements the counter at key, with the same expiry rules as Incr.
+	Decr(ctx context.Context, key string, expiration time.Duration) (int64, error)
 	Delete(ctx context.Context, key string) error
 	InvalidateByPrefix(ctx context.Context, prefix string) error
 	Close() error
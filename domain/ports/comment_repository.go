@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type CommentRepository interface {
+	Save(ctx context.Context, tx Transaction, comment *entities.Comment) (*entities.Comment, error)
+	// GetById returns a comment that is not deleted, or ErrDataNotFound.
+	GetById(ctx context.Context, tx Transaction, id uuid.UUID) (*entities.Comment, error)
+	// Update replaces the body of a comment that is not deleted, or returns ErrDataNotFound.
+	Update(ctx context.Context, tx Transaction, id uuid.UUID, body string) (*entities.Comment, error)
+	// Delete soft-deletes a comment, its replies stay.
+	Delete(ctx context.Context, tx Transaction, id uuid.UUID) error
+	// ListByPost returns up to limit comments of a post in (created_at, id) order, starting
+	// after the cursor when one is given. Deleted comments are included.
+	ListByPost(ctx context.Context, tx Transaction, postID uuid.UUID, after *entities.CommentCursor, limit int) ([]*entities.Comment, error)
+	// ListThreads is ListByPost for top-level comments only, followed by the oldest maxReplies
+	// replies below each of them. ReplyCount of the top-level comments counts every reply.
+	ListThreads(ctx context.Context, tx Transaction, postID uuid.UUID, after *entities.CommentCursor, limit, maxReplies int) ([]*entities.Comment, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type CommentService interface {
+	// Create comments on a post actor may read, or replies to one of its comments.
+	Create(ctx context.Context, actor *entities.TokenClaims, comment *entities.Comment) (*entities.Comment, error)
+	// Update and Delete change a comment of the post postID. Only its author may edit it, a
+	// caller allowed to manage every post may delete it as well.
+	Update(ctx context.Context, actor *entities.TokenClaims, postID, id uuid.UUID, body string) (*entities.Comment, error)
+	Delete(ctx context.Context, actor *entities.TokenClaims, postID, id uuid.UUID) error
+	// List returns a page of the comments of a post, flat or as trees of replies below each
+	// top-level comment. cursor is the NextCursor of the previous page, empty for the first one.
+	List(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, cursor string, limit int, tree bool) (*entities.CommentPage, error)
+}
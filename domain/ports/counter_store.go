@@ -0,0 +1,19 @@
+package ports
+
+import "context"
+
+// CounterStore keeps groups of named counters, such as the reaction counts of one post, in a
+// fast store. Changed groups are remembered as dirty until they are popped for reconciliation.
+type CounterStore interface {
+	// Incr adds delta to the counter field of the group key and marks the group dirty.
+	Incr(ctx context.Context, key, field string, delta int64) (int64, error)
+	// GetAll returns the counters of the group key, or nil when the store does not have it.
+	GetAll(ctx context.Context, key string) (map[string]int64, error)
+	// Replace sets the counters of the group key to counts, dropping the other fields.
+	Replace(ctx context.Context, key string, counts map[string]int64) error
+	// MarkDirty marks the group key dirty again, e.g. when its reconciliation failed.
+	MarkDirty(ctx context.Context, key string) error
+	// PopDirty takes up to max dirty groups off the dirty set.
+	PopDirty(ctx context.Context, max int) ([]string, error)
+	Close() error
+}
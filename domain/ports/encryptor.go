@@ -3,4 +3,7 @@ package ports
 type Encryptor interface {
 	HashPassword(password string) (string, error)
 	CompareHash(hash, password string) error
+	// NeedsRehash reports whether hash was made with another algorithm or other parameters
+	// than HashPassword uses now, so it should be replaced once the password is known.
+	NeedsRehash(hash string) bool
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+)
+
+// IdentityProvider signs users in with an external account using the authorization code flow.
+type IdentityProvider interface {
+	Name() string
+	// AuthCodeURL returns the URL the user is sent to. codeChallenge is the S256 PKCE challenge.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems the code returned to the callback and returns the verified identity.
+	// The ID token must carry nonce.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entities.ExternalIdentity, error)
+}
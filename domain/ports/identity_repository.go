@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+)
+
+type IdentityRepository interface {
+	Save(ctx context.Context, tx Transaction, identity *entities.UserIdentity) (*entities.UserIdentity, error)
+	FindByProviderSubject(ctx context.Context, tx Transaction, provider, subject string) (*entities.UserIdentity, error)
+}
@@ -1,7 +1,13 @@
 package ports
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type MailService interface {
 	SendSignInNotification(ctx context.Context, email, text string) error
+	SendPasswordResetCode(ctx context.Context, email, code string) error
+	SendVerificationEmail(ctx context.Context, email, link string) error
+	SendAccountLockedNotification(ctx context.Context, email string, until time.Time) error
 }
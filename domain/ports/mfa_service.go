@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+)
+
+type MFAService interface {
+	Enroll(ctx context.Context, userID string) (*entities.MFAEnrollment, error)
+	Verify(ctx context.Context, userID, code string) error
+	Disable(ctx context.Context, userID, code string) error
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+type PasswordPolicyService interface {
+	// Validate checks a new password of the user with the given email. Every rule it breaks is
+	// reported in one *errors.ValidationErrors.
+	Validate(ctx context.Context, password, email string) error
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type PersonalAccessTokenRepository interface {
+	Save(ctx context.Context, tx Transaction, token *entities.PersonalAccessToken) (*entities.PersonalAccessToken, error)
+	FindByHash(ctx context.Context, tx Transaction, tokenHash string) (*entities.PersonalAccessToken, error)
+	// FindByUser returns every token of the user, including revoked and expired ones, newest first.
+	FindByUser(ctx context.Context, tx Transaction, userID string) ([]*entities.PersonalAccessToken, error)
+	// Revoke marks a token of the user revoked; revoking twice yields ErrDataNotFound.
+	Revoke(ctx context.Context, tx Transaction, userID string, id uuid.UUID) error
+	TouchLastUsed(ctx context.Context, tx Transaction, id uuid.UUID) error
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type PersonalAccessTokenService interface {
+	// Create stores a token for the user and returns it with the plain token, which is never retrievable again.
+	Create(ctx context.Context, userID string, token *entities.PersonalAccessToken) (*entities.PersonalAccessToken, string, error)
+	List(ctx context.Context, userID string) ([]*entities.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userID string, id uuid.UUID) error
+	// Authenticate returns the claims of the active token matching the plain token sent by a client.
+	Authenticate(ctx context.Context, token string) (*entities.TokenClaims, error)
+}
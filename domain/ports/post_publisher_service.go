@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PostPublisherService publishes scheduled drafts once their publish_at has come.
+type PostPublisherService interface {
+	// PublishDue publishes every due draft and returns their IDs. It returns nil without
+	// publishing when another worker holds the publish lock.
+	PublishDue(ctx context.Context) ([]uuid.UUID, error)
+}
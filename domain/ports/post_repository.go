@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/google/uuid"
@@ -9,8 +10,26 @@ import (
 
 type PostRepository interface {
 	Save(ctx context.Context, tx Transaction, post *entities.Post) (*entities.Post, error)
+	// Update, Patch and Delete bump or check the post version. A non-zero expected version
+	// (post.Version for Update) makes them fail with ErrVersionConflict once the post has changed.
 	Update(ctx context.Context, tx Transaction, post *entities.Post) (*entities.Post, error)
-	Delete(ctx context.Context, tx Transaction, id uuid.UUID) error
+	// Patch updates only the fields set in patch and returns the post as stored, without its author.
+	Patch(ctx context.Context, tx Transaction, id uuid.UUID, version int, patch entities.PostPatch) (*entities.Post, error)
+	Delete(ctx context.Context, tx Transaction, id uuid.UUID, version int) error
 	GetById(ctx context.Context, tx Transaction, id uuid.UUID) (*entities.Post, error)
-	GetAll(ctx context.Context, tx Transaction, search string, pagination entities.PaginationParams) ([]entities.Post, error)
+	// GetAll lists only published posts, GetByAuthor lists the posts of one author in any status.
+	GetAll(ctx context.Context, tx Transaction, filter entities.PostFilter, pagination entities.PaginationParams) ([]entities.Post, error)
+	GetByAuthor(ctx context.Context, tx Transaction, authorID uuid.UUID, status entities.PostStatus, pagination entities.PaginationParams) ([]entities.Post, error)
+	// SetStatus moves a post through its lifecycle, checking and bumping the version like Patch.
+	SetStatus(ctx context.Context, tx Transaction, id uuid.UUID, version int, status entities.PostStatus, publishAt *time.Time) (*entities.Post, error)
+	// PublishDue publishes the drafts whose publish_at is not after now and returns their IDs.
+	PublishDue(ctx context.Context, tx Transaction, now time.Time) ([]uuid.UUID, error)
+
+	// Delete only moves a post to the trash, the methods above ignore posts in it and posts
+	// of authors in it.
+	Restore(ctx context.Context, tx Transaction, id uuid.UUID) (*entities.Post, error)
+	GetDeleted(ctx context.Context, tx Transaction, pagination entities.PaginationParams) ([]entities.Post, error)
+	// Purge removes for good the posts deleted before cutoff and the posts of users deleted
+	// before cutoff, and returns how many there were.
+	Purge(ctx context.Context, tx Transaction, cutoff time.Time) (int64, error)
 }
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type PostRevisionRepository interface {
+	Save(ctx context.Context, tx Transaction, revision *entities.PostRevision) (*entities.PostRevision, error)
+	// FindByPost returns the revisions of a post, newest first.
+	FindByPost(ctx context.Context, tx Transaction, postID uuid.UUID) ([]*entities.PostRevision, error)
+	// FindByRevision returns one revision of a post, or ErrDataNotFound.
+	FindByRevision(ctx context.Context, tx Transaction, postID uuid.UUID, revision int) (*entities.PostRevision, error)
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/google/uuid"
@@ -9,10 +10,30 @@ import (
 
 type PostService interface {
 	Create(ctx context.Context, post *entities.Post) (*entities.Post, error)
-	Update(ctx context.Context, post *entities.Post) (*entities.Post, error)
-	Delete(ctx context.Context, id uuid.UUID) error
-	GetById(ctx context.Context, id uuid.UUID) (*entities.Post, error)
-	GetAll(ctx context.Context, search string, page, limit int) ([]entities.Post, error)
-	StartAsyncUpload(ctx context.Context, postID uuid.UUID, fileName, fileType string, fileData []byte) (uploadID uuid.UUID, err error)
+	// Update and Delete are allowed for the author of the post and for actors with the posts:manage permission.
+	// The expected version (post.Version for Update) must be current, 0 skips the check.
+	Update(ctx context.Context, actor *entities.TokenClaims, post *entities.Post) (*entities.Post, error)
+	// Patch changes only the fields set in patch, with the same rules as Update.
+	Patch(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, version int, patch entities.PostPatch) (*entities.Post, error)
+	// Delete moves the post to the trash, Restore takes it out again with the same rules as Update.
+	Delete(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, version int) error
+	Restore(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID) (*entities.Post, error)
+	// SetStatus publishes, archives or schedules a post, with the same rules as Update. publishAt
+	// is only kept for drafts.
+	SetStatus(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, version int, status entities.PostStatus, publishAt *time.Time) (*entities.Post, error)
+	// GetById, ListRevisions and GetRevision read a published post, or any post for whoever may
+	// update it. actor is nil for anonymous readers.
+	GetById(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID) (*entities.Post, error)
+	// ListRevisions and GetRevision read the history of a post. RestoreRevision writes the
+	// content of an earlier revision as a new version, with the same rules as Update.
+	ListRevisions(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID) ([]*entities.PostRevision, error)
+	GetRevision(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, revision int) (*entities.PostRevisionDiff, error)
+	RestoreRevision(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, revision, version int) (*entities.Post, error)
+	// GetAll lists the published posts, ListOwn the posts of actor in the given status. Both
+	// set the reactions actor left on them.
+	GetAll(ctx context.Context, actor *entities.TokenClaims, filter entities.PostFilter, page, limit int) ([]entities.Post, error)
+	ListOwn(ctx context.Context, actor *entities.TokenClaims, status entities.PostStatus, page, limit int) ([]entities.Post, error)
+	// StartAsyncUpload queues a file to attach to a post, with the same rules as Update.
+	StartAsyncUpload(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, fileName, fileType string, fileData []byte) (uploadID uuid.UUID, err error)
 	GetUploadStatus(ctx context.Context, uploadID uuid.UUID) (entities.UploadStatus, error)
 }
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type ReactionRepository interface {
+	// Add and Remove report whether the reaction was added or removed, false when the user
+	// had already left it, or had not left it.
+	Add(ctx context.Context, tx Transaction, postID, userID uuid.UUID, reaction entities.ReactionType) (bool, error)
+	Remove(ctx context.Context, tx Transaction, postID, userID uuid.UUID, reaction entities.ReactionType) (bool, error)
+	// ListByUser returns the reactions the user left on each of the posts that has any.
+	ListByUser(ctx context.Context, tx Transaction, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID][]entities.ReactionType, error)
+	// Count counts the reactions on a post, SaveCounts stores the result on the post, where
+	// post listings read it.
+	Count(ctx context.Context, tx Transaction, postID uuid.UUID) (entities.ReactionCounts, error)
+	SaveCounts(ctx context.Context, tx Transaction, postID uuid.UUID, counts entities.ReactionCounts) error
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type ReactionService interface {
+	// React leaves a reaction on a post actor may read, Unreact takes it back. Both are
+	// idempotent and return the reactions of the post afterwards.
+	React(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, reaction entities.ReactionType) (*entities.PostReactions, error)
+	Unreact(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, reaction entities.ReactionType) (*entities.PostReactions, error)
+	// Annotate sets the reaction counts of posts from the counter store, falling back to the
+	// reconciled counts they were read with, and the reactions actor left on them.
+	Annotate(ctx context.Context, actor *entities.TokenClaims, posts ...*entities.Post) error
+	// Reconcile writes the counts of the posts that got reactions since the last run into
+	// Postgres and resets their counters. It returns 0 without reconciling when another
+	// instance holds the reconcile lock.
+	Reconcile(ctx context.Context) (int, error)
+}
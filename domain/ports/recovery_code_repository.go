@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+)
+
+type RecoveryCodeRepository interface {
+	// ReplaceAll deletes every recovery code of the user and stores the given hashes instead.
+	ReplaceAll(ctx context.Context, tx Transaction, userID string, codeHashes []string) error
+	FindUnused(ctx context.Context, tx Transaction, userID string) ([]*entities.RecoveryCode, error)
+	MarkUsed(ctx context.Context, tx Transaction, id string) error
+	DeleteAll(ctx context.Context, tx Transaction, userID string) error
+}
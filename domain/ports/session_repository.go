@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, tx Transaction, session *entities.Session) (*entities.Session, error)
+	// FindActiveByUser returns the sessions of the user that are neither revoked nor expired, most recently seen first.
+	FindActiveByUser(ctx context.Context, tx Transaction, userID string) ([]*entities.Session, error)
+	// Touch records activity on an active session and extends it to expiresAt.
+	// It returns ErrDataNotFound when the session does not exist, is revoked or has expired.
+	Touch(ctx context.Context, tx Transaction, id uuid.UUID, expiresAt time.Time) error
+	// Revoke ends an active session of the user, or returns ErrDataNotFound.
+	Revoke(ctx context.Context, tx Transaction, userID string, id uuid.UUID) error
+	RevokeAll(ctx context.Context, tx Transaction, userID string) error
+}
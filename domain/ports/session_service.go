@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type SessionService interface {
+	List(ctx context.Context, userID string) ([]*entities.Session, error)
+	Revoke(ctx context.Context, userID string, id uuid.UUID) error
+	// RevokeAll signs the user out everywhere, including the session making the request.
+	RevokeAll(ctx context.Context, userID string) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+)
+
+type TagRepository interface {
+	// SetPostTags replaces the tags of a post, creating the tags whose slug does not exist yet.
+	SetPostTags(ctx context.Context, tx Transaction, postID uuid.UUID, tags []entities.Tag) error
+	// List returns the tags of published posts with the number of such posts, most used first.
+	List(ctx context.Context, tx Transaction) ([]entities.Tag, error)
+}
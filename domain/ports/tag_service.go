@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+)
+
+type TagService interface {
+	// List returns the tags in use with the number of published posts carrying them.
+	List(ctx context.Context) ([]entities.Tag, error)
+}
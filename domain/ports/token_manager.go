@@ -1,6 +1,17 @@
 package ports
 
+import "github.com/chud-lori/go-boilerplate/domain/entities"
+
 type TokenManager interface {
-	GenerateToken(userID string) (string, error)
-	ValidateToken(tokenStr string) (string, error) // return userId
+	// GenerateTokenPair issues an access and a refresh token for the given family.
+	// An empty familyID starts a new family. The access token carries the user's roles and permissions.
+	GenerateTokenPair(user *entities.User, familyID string) (*entities.TokenPair, error)
+	GenerateEmailVerificationToken(userID, email string) (string, error)
+	GenerateMFAToken(userID string) (string, error)
+	// GenerateImpersonationToken issues a short-lived access token for user that also names
+	// the admin acting as them. No refresh token is issued.
+	GenerateImpersonationToken(user *entities.User, impersonatorID string) (string, error)
+	ValidateToken(tokenStr string) (*entities.TokenClaims, error)
+	// JWKS returns the public keys other services can use to verify our tokens.
+	JWKS() []entities.JSONWebKey
 }
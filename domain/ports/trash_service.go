@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+)
+
+// TrashService shows what has been deleted and removes it for good once it is old enough.
+type TrashService interface {
+	// ListPosts and ListUsers return one page of the trash, most recently deleted first.
+	ListPosts(ctx context.Context, page, limit int) ([]entities.Post, error)
+	ListUsers(ctx context.Context, page, limit int) ([]*entities.User, error)
+	// Purge hard-deletes what has been in the trash for longer than the retention. It returns
+	// nil without purging when another instance holds the purge lock.
+	Purge(ctx context.Context) (*entities.TrashPurge, error)
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 )
@@ -12,5 +13,15 @@ type UserRepository interface {
 	Delete(ctx context.Context, tx Transaction, id string) error
 	FindById(ctx context.Context, tx Transaction, id string) (*entities.User, error)
 	FindByEmail(ctx context.Context, tx Transaction, email string) (*entities.User, error)
+	MarkVerified(ctx context.Context, tx Transaction, id string) error
+	SetTOTPSecret(ctx context.Context, tx Transaction, id, secret string) error
+	EnableMFA(ctx context.Context, tx Transaction, id string) error
+	SetRoles(ctx context.Context, tx Transaction, id string, roles []string) error
 	FindAll(ctx context.Context, tx Transaction) ([]*entities.User, error)
+
+	// Delete only moves a user to the trash, the methods above ignore users in it.
+	Restore(ctx context.Context, tx Transaction, id string) (*entities.User, error)
+	FindDeleted(ctx context.Context, tx Transaction, pagination entities.PaginationParams) ([]*entities.User, error)
+	// Purge removes the users deleted before cutoff for good and returns how many there were.
+	Purge(ctx context.Context, tx Transaction, cutoff time.Time) (int64, error)
 }
@@ -9,7 +9,11 @@ import (
 type UserService interface {
 	Save(ctx context.Context, user *entities.User) (*entities.User, error)
 	Update(ctx context.Context, user *entities.User) (*entities.User, error)
+	// Delete moves a user to the trash, Restore takes them out again.
 	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) (*entities.User, error)
 	FindById(ctx context.Context, id string) (*entities.User, error)
 	FindAll(ctx context.Context) ([]*entities.User, error)
+	// SetRoles replaces the roles of a user. They apply to tokens issued from the next refresh on.
+	SetRoles(ctx context.Context, id string, roles []string) (*entities.User, error)
 }
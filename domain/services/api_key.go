@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// unknownAPIKey is cached for keys that do not exist, so guessing does not reach the database.
+const unknownAPIKey = "unknown"
+
+type APIKeyServiceImpl struct {
+	DB ports.Database
+	ports.APIKeyRepository
+	ports.Cache
+	// StaticKey is the key from the API_KEY setting. It is accepted as the "default" client
+	// with every scope, so a fresh install can be used before any key is created. Empty disables it.
+	StaticKey string
+	// CacheTTL is how long a looked up key is cached. It also bounds how often last_used_at is written.
+	CacheTTL   time.Duration
+	CtxTimeout time.Duration
+}
+
+func (s *APIKeyServiceImpl) Create(c context.Context, key *entities.APIKey) (*entities.APIKey, string, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return nil, "", appErrors.NewBadRequestError("Expiry must be in the future", nil)
+	}
+	if len(key.Scopes) == 0 {
+		key.Scopes = []string{entities.APIKeyScopeRead, entities.APIKeyScopeWrite}
+	}
+
+	plain, err := auth.GenerateAPIKey()
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate api key")
+		return nil, "", err
+	}
+	key.Prefix = auth.APIKeyPrefix(plain)
+	key.KeyHash = auth.HashAPIKey(plain)
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, "", err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	saved, err := s.APIKeyRepository.Save(ctx, tx, key)
+	if err != nil {
+		logger.WithError(err).Error("Failed to save api key")
+		return nil, "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, "", err
+	}
+
+	logger.Infof("API key %s (%s) created", saved.ID, saved.Name)
+	return saved, plain, nil
+}
+
+func (s *APIKeyServiceImpl) List(c context.Context) ([]*entities.APIKey, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	keys, err := s.APIKeyRepository.FindAll(ctx, tx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list api keys")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Revoke disables a key right away; its cache entry is dropped with it.
+func (s *APIKeyServiceImpl) Revoke(c context.Context, id uuid.UUID) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	key, err := s.APIKeyRepository.Revoke(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			err = appErrors.NewNotFoundError("API key not found or already revoked", err)
+			return err
+		}
+		logger.WithError(err).Error("Failed to revoke api key")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	if err := s.Cache.Delete(c, auth.APIKeyCacheKey(key.KeyHash)); err != nil {
+		// The cached copy would keep working until CacheTTL passes.
+		logger.WithError(err).Warn("Failed to drop revoked api key from cache")
+	}
+
+	logger.Infof("API key %s (%s) revoked", key.ID, key.Name)
+	return nil
+}
+
+// Authenticate resolves the key sent by a client. Keys are cached for CacheTTL, including
+// unknown ones; revocation clears the cache entry, expiry is checked on every call.
+func (s *APIKeyServiceImpl) Authenticate(c context.Context, plain string) (*entities.APIKey, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	if plain == "" {
+		return nil, appErrors.NewUnauthorizedError("Missing API key", nil)
+	}
+	if s.StaticKey != "" && subtle.ConstantTimeCompare([]byte(plain), []byte(s.StaticKey)) == 1 {
+		return &entities.APIKey{
+			Name:   "default",
+			Scopes: []string{entities.APIKeyScopeRead, entities.APIKeyScopeWrite},
+		}, nil
+	}
+
+	hash := auth.HashAPIKey(plain)
+	key, unknown, err := s.cachedKey(c, hash)
+	if err != nil {
+		// The database is the source of truth, a broken cache only makes lookups slower.
+		logger.WithError(err).Warn("Failed to read api key from cache")
+	}
+	if unknown {
+		return nil, appErrors.NewUnauthorizedError("Invalid API key", nil)
+	}
+	if key == nil {
+		key, err = s.lookup(c, hash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !key.Active(time.Now()) {
+		return nil, appErrors.NewUnauthorizedError("API key revoked or expired", nil)
+	}
+
+	return key, nil
+}
+
+// cachedKey returns the cached key for hash, or reports that the hash is cached as unknown.
+// Both results are empty when nothing is cached.
+func (s *APIKeyServiceImpl) cachedKey(ctx context.Context, hash string) (*entities.APIKey, bool, error) {
+	cached, err := s.Cache.Get(ctx, auth.APIKeyCacheKey(hash))
+	if err != nil || cached == "" {
+		return nil, false, err
+	}
+	if cached == unknownAPIKey {
+		return nil, true, nil
+	}
+
+	var key entities.APIKey
+	if err := json.Unmarshal([]byte(cached), &key); err != nil {
+		return nil, false, err
+	}
+	return &key, false, nil
+}
+
+// lookup loads the key from the database, records its use and caches the result.
+func (s *APIKeyServiceImpl) lookup(c context.Context, hash string) (*entities.APIKey, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	key, err := s.APIKeyRepository.FindByHash(ctx, tx, hash)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			if cacheErr := s.Cache.Set(c, auth.APIKeyCacheKey(hash), []byte(unknownAPIKey), s.CacheTTL); cacheErr != nil {
+				logger.WithError(cacheErr).Warn("Failed to cache unknown api key")
+			}
+			err = appErrors.NewUnauthorizedError("Invalid API key", err)
+			return nil, err
+		}
+		logger.WithError(err).Error("Failed to find api key")
+		return nil, err
+	}
+
+	if key.Active(time.Now()) {
+		if err = s.APIKeyRepository.TouchLastUsed(ctx, tx, key.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	if data, err := json.Marshal(key); err == nil {
+		if err := s.Cache.Set(c, auth.APIKeyCacheKey(hash), data, s.CacheTTL); err != nil {
+			logger.WithError(err).Warn("Failed to cache api key")
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,280 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyService_Create(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	cache := newMemoryCache()
+
+	service := &services.APIKeyServiceImpl{
+		DB:               mockDB,
+		APIKeyRepository: mockRepo,
+		Cache:            cache,
+		StaticKey:        "static-key",
+		CacheTTL:         time.Minute,
+		CtxTimeout:       2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	stored := &entities.APIKey{}
+	mockRepo.On("Save", mock.Anything, mockTx, mock.AnythingOfType("*entities.APIKey")).
+		Run(func(args mock.Arguments) { *stored = *args.Get(2).(*entities.APIKey) }).
+		Return(stored, nil).Once()
+
+	key, plain, err := service.Create(ctx, &entities.APIKey{Name: "billing"})
+
+	require.NoError(t, err)
+	assert.Equal(t, auth.HashAPIKey(plain), stored.KeyHash)
+	assert.NotEqual(t, plain, stored.KeyHash)
+	assert.Equal(t, plain[:auth.APIKeyPrefixLength], key.Prefix)
+	assert.ElementsMatch(t, []string{entities.APIKeyScopeRead, entities.APIKeyScopeWrite}, key.Scopes)
+}
+
+func TestAPIKeyService_Create_ExpiryInPast(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	cache := newMemoryCache()
+
+	service := &services.APIKeyServiceImpl{
+		DB:               mockDB,
+		APIKeyRepository: mockRepo,
+		Cache:            cache,
+		StaticKey:        "static-key",
+		CacheTTL:         time.Minute,
+		CtxTimeout:       2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	past := time.Now().Add(-time.Hour)
+	_, _, err := service.Create(ctx, &entities.APIKey{Name: "old", ExpiresAt: &past})
+
+	appErr, ok := err.(*appErrors.AppError)
+	require.True(t, ok)
+	assert.Equal(t, 400, appErr.StatusCode)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestAPIKeyService_Authenticate_CachesLookup(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	cache := newMemoryCache()
+
+	service := &services.APIKeyServiceImpl{
+		DB:               mockDB,
+		APIKeyRepository: mockRepo,
+		Cache:            cache,
+		StaticKey:        "static-key",
+		CacheTTL:         time.Minute,
+		CtxTimeout:       2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	stored := &entities.APIKey{ID: uuid.New(), Name: "billing", Scopes: []string{entities.APIKeyScopeRead}}
+	mockRepo.On("FindByHash", mock.Anything, mockTx, auth.HashAPIKey("gbk_live")).Return(stored, nil).Once()
+	mockRepo.On("TouchLastUsed", mock.Anything, mockTx, stored.ID).Return(nil).Once()
+
+	for i := 0; i < 3; i++ {
+		key, err := service.Authenticate(ctx, "gbk_live")
+		require.NoError(t, err)
+		assert.Equal(t, "billing", key.Name)
+	}
+
+	// Only the first call reached the database.
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Authenticate_UnknownKeyIsCached(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	cache := newMemoryCache()
+
+	service := &services.APIKeyServiceImpl{
+		DB:               mockDB,
+		APIKeyRepository: mockRepo,
+		Cache:            cache,
+		StaticKey:        "static-key",
+		CacheTTL:         time.Minute,
+		CtxTimeout:       2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockRepo.On("FindByHash", mock.Anything, mockTx, auth.HashAPIKey("gbk_guess")).Return(nil, appErrors.ErrDataNotFound).Once()
+
+	for i := 0; i < 2; i++ {
+		_, err := service.Authenticate(ctx, "gbk_guess")
+		appErr, ok := err.(*appErrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 401, appErr.StatusCode)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Authenticate_Expired(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	cache := newMemoryCache()
+
+	service := &services.APIKeyServiceImpl{
+		DB:               mockDB,
+		APIKeyRepository: mockRepo,
+		Cache:            cache,
+		StaticKey:        "static-key",
+		CacheTTL:         time.Minute,
+		CtxTimeout:       2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	past := time.Now().Add(-time.Minute)
+	stored := &entities.APIKey{ID: uuid.New(), Name: "old", ExpiresAt: &past}
+	mockRepo.On("FindByHash", mock.Anything, mockTx, auth.HashAPIKey("gbk_old")).Return(stored, nil).Once()
+
+	_, err := service.Authenticate(ctx, "gbk_old")
+
+	appErr, ok := err.(*appErrors.AppError)
+	require.True(t, ok)
+	assert.Equal(t, 401, appErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyService_Authenticate_StaticKey(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	cache := newMemoryCache()
+
+	service := &services.APIKeyServiceImpl{
+		DB:               mockDB,
+		APIKeyRepository: mockRepo,
+		Cache:            cache,
+		StaticKey:        "static-key",
+		CacheTTL:         time.Minute,
+		CtxTimeout:       2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	key, err := service.Authenticate(ctx, "static-key")
+
+	require.NoError(t, err)
+	assert.Equal(t, "default", key.Name)
+	assert.True(t, key.HasScope(entities.APIKeyScopeWrite))
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestAPIKeyService_Revoke_DropsCachedKey(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	cache := newMemoryCache()
+
+	service := &services.APIKeyServiceImpl{
+		DB:               mockDB,
+		APIKeyRepository: mockRepo,
+		Cache:            cache,
+		StaticKey:        "static-key",
+		CacheTTL:         time.Minute,
+		CtxTimeout:       2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	hash := auth.HashAPIKey("gbk_live")
+	stored := &entities.APIKey{ID: uuid.New(), Name: "billing", KeyHash: hash, Scopes: []string{entities.APIKeyScopeRead}}
+	mockRepo.On("FindByHash", mock.Anything, mockTx, hash).Return(stored, nil).Once()
+	mockRepo.On("TouchLastUsed", mock.Anything, mockTx, stored.ID).Return(nil).Once()
+
+	_, err := service.Authenticate(ctx, "gbk_live")
+	require.NoError(t, err)
+
+	now := time.Now()
+	revoked := *stored
+	revoked.RevokedAt = &now
+	mockRepo.On("Revoke", mock.Anything, mockTx, stored.ID).Return(&revoked, nil).Once()
+	require.NoError(t, service.Revoke(ctx, stored.ID))
+
+	cached, _ := cache.Get(ctx, auth.APIKeyCacheKey(hash))
+	assert.Empty(t, cached)
+
+	mockRepo.On("FindByHash", mock.Anything, mockTx, hash).Return(&revoked, nil).Once()
+	_, err = service.Authenticate(ctx, "gbk_live")
+	assert.Error(t, err)
+}
+
+func TestAPIKeyService_Revoke_NotFound(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	cache := newMemoryCache()
+
+	service := &services.APIKeyServiceImpl{
+		DB:               mockDB,
+		APIKeyRepository: mockRepo,
+		Cache:            cache,
+		StaticKey:        "static-key",
+		CacheTTL:         time.Minute,
+		CtxTimeout:       2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	id := uuid.New()
+	mockRepo.On("Revoke", mock.Anything, mockTx, id).Return(nil, appErrors.ErrDataNotFound).Once()
+
+	err := service.Revoke(ctx, id)
+
+	appErr, ok := err.(*appErrors.AppError)
+	require.True(t, ok)
+	assert.Equal(t, 404, appErr.StatusCode)
+}
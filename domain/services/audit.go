@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/audit"
+	"github.com/chud-lori/go-boilerplate/pkg/clientip"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	baseLogger "github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// maxAuditPageSize bounds how many audit events one List call returns.
+const maxAuditPageSize = 200
+
+// AuditServiceImpl writes the audit log and lets admins query it. Every event is written in
+// its own transaction, so failed actions are recorded even though their changes roll back.
+type AuditServiceImpl struct {
+	DB ports.Database
+	ports.AuditRepository
+	CtxTimeout time.Duration
+}
+
+func (s *AuditServiceImpl) Log(c context.Context, event *entities.AuditEvent) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	// The event is written even when the request has just been cancelled or timed out.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c), s.CtxTimeout)
+	defer cancel()
+
+	if event.ActorID == "" {
+		event.ActorID = audit.ActorFromContext(c)
+	}
+	if event.ImpersonatorID == "" {
+		event.ImpersonatorID = audit.ImpersonatorFromContext(c)
+	}
+	if event.IP == "" {
+		event.IP = clientip.FromContext(c)
+	}
+	if event.RequestID == "" {
+		event.RequestID = baseLogger.RequestIDFromContext(c)
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to record audit event %s", event.Action)
+		return
+	}
+
+	if err = s.AuditRepository.Save(ctx, tx, event); err != nil {
+		tx.Rollback()
+		logger.WithError(err).Errorf("Failed to record audit event %s", event.Action)
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Errorf("Failed to record audit event %s", event.Action)
+	}
+}
+
+func (s *AuditServiceImpl) List(c context.Context, filter entities.AuditEventFilter, page, limit int) ([]*entities.AuditEvent, int, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	if limit > maxAuditPageSize {
+		limit = maxAuditPageSize
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, 0, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	events, total, err := s.AuditRepository.Find(ctx, tx, filter, entities.PaginationParams{Page: page, Limit: limit})
+	if err != nil {
+		logger.WithError(err).Error("Failed to query audit events")
+		return nil, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// recordAudit logs event when an audit logger is configured.
+func recordAudit(ctx context.Context, auditLogger ports.AuditLogger, event *entities.AuditEvent) {
+	if auditLogger != nil {
+		auditLogger.Log(ctx, event)
+	}
+}
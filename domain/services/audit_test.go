@@ -0,0 +1,155 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/audit"
+	"github.com/chud-lori/go-boilerplate/pkg/clientip"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_Log_FillsRequestContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = context.WithValue(ctx, logger.RequestIDContextKey, "req-1")
+	ctx = clientip.WithIP(ctx, "203.0.113.7")
+	ctx = audit.WithActor(ctx, "admin-1")
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAuditRepository)
+
+	service := &services.AuditServiceImpl{
+		DB:              mockDB,
+		AuditRepository: mockRepo,
+		CtxTimeout:      2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	var saved *entities.AuditEvent
+	mockRepo.On("Save", mock.Anything, mockTx, mock.Anything).
+		Run(func(args mock.Arguments) { saved = args.Get(2).(*entities.AuditEvent) }).
+		Return(nil)
+
+	service.Log(ctx, &entities.AuditEvent{Action: entities.AuditActionUserDelete, TargetID: "user-1", Outcome: entities.AuditOutcomeSuccess})
+
+	require.NotNil(t, saved)
+	assert.Equal(t, "admin-1", saved.ActorID)
+	assert.Equal(t, "203.0.113.7", saved.IP)
+	assert.Equal(t, "req-1", saved.RequestID)
+	mockTx.AssertCalled(t, "Commit")
+}
+
+func TestAuditService_Log_KeepsExplicitActor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = audit.WithActor(ctx, "someone-else")
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAuditRepository)
+
+	service := &services.AuditServiceImpl{
+		DB:              mockDB,
+		AuditRepository: mockRepo,
+		CtxTimeout:      2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockRepo.On("Save", mock.Anything, mockTx, mock.MatchedBy(func(e *entities.AuditEvent) bool {
+		return e.ActorID == "user-1"
+	})).Return(nil)
+
+	service.Log(ctx, &entities.AuditEvent{ActorID: "user-1", Action: entities.AuditActionSignIn, Outcome: entities.AuditOutcomeSuccess})
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_Log_FailureIsNotReturned(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAuditRepository)
+
+	service := &services.AuditServiceImpl{
+		DB:              mockDB,
+		AuditRepository: mockRepo,
+		CtxTimeout:      2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockRepo.On("Save", mock.Anything, mockTx, mock.Anything).Return(errors.New("db down"))
+
+	assert.NotPanics(t, func() {
+		service.Log(ctx, &entities.AuditEvent{Action: entities.AuditActionSignIn, Outcome: entities.AuditOutcomeFailure})
+	})
+	mockTx.AssertCalled(t, "Rollback")
+	mockTx.AssertNotCalled(t, "Commit")
+}
+
+func TestAuditService_Log_AfterRequestCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New())))
+	cancel()
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAuditRepository)
+
+	service := &services.AuditServiceImpl{
+		DB:              mockDB,
+		AuditRepository: mockRepo,
+		CtxTimeout:      2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockRepo.On("Save", mock.MatchedBy(func(c context.Context) bool { return c.Err() == nil }), mockTx, mock.Anything).Return(nil)
+
+	service.Log(ctx, &entities.AuditEvent{Action: entities.AuditActionSignOut, Outcome: entities.AuditOutcomeSuccess})
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_List_CapsPageSize(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockAuditRepository)
+
+	service := &services.AuditServiceImpl{
+		DB:              mockDB,
+		AuditRepository: mockRepo,
+		CtxTimeout:      2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	filter := entities.AuditEventFilter{Action: entities.AuditActionSignIn}
+	events := []*entities.AuditEvent{{ID: uuid.New(), Action: entities.AuditActionSignIn}}
+	mockRepo.On("Find", mock.Anything, mockTx, filter, entities.PaginationParams{Page: 2, Limit: 200}).Return(events, 201, nil)
+
+	got, total, err := service.List(ctx, filter, 2, 1000)
+
+	require.NoError(t, err)
+	assert.Equal(t, events, got)
+	assert.Equal(t, 201, total)
+}
@@ -2,14 +2,22 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/chud-lori/go-boilerplate/pkg/clientip"
 	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
 	"github.com/chud-lori/go-boilerplate/pkg/logger"
 	baseLogger "github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,19 +27,77 @@ type AuthServiceImpl struct {
 	ports.Encryptor
 	ports.TokenManager
 	ports.MailService
-	ExternalApi ports.ExternalApiClient
-	CtxTimeout  time.Duration
+	ports.Cache
+	ports.RecoveryCodeRepository
+	ports.SessionRepository
+	ExternalApi      ports.ExternalApiClient
+	RefreshTokenTTL  time.Duration
+	PasswordResetTTL time.Duration
+	CtxTimeout       time.Duration
+
+	// PasswordResetResendInterval is the minimum time between two reset codes to one address.
+	PasswordResetResendInterval time.Duration
+
+	// RequireVerifiedEmail makes SignIn refuse accounts that have not confirmed their email,
+	// and SignUp stop issuing tokens before confirmation.
+	RequireVerifiedEmail bool
+	// VerificationURL is the link sent by email; the token is appended as the "token" query parameter.
+	VerificationURL string
+	// VerificationResendInterval is the minimum time between two verification emails to one address.
+	VerificationResendInterval time.Duration
+
+	// LoginThrottle delays and locks out repeated failed sign ins. Nil disables it.
+	LoginThrottle *LoginThrottle
+	// PasswordPolicy checks the passwords chosen on sign up and reset. Nil accepts any password.
+	PasswordPolicy ports.PasswordPolicyService
+	// AuditLogger records sign ins, sign ups, sign outs and password resets. Nil disables it.
+	AuditLogger ports.AuditLogger
+
+	// IdentityProviders are the providers available for social login, by name.
+	IdentityProviders  map[string]ports.IdentityProvider
+	IdentityRepository ports.IdentityRepository
+	// SocialLoginTTL is how long a user has to come back from the identity provider.
+	SocialLoginTTL time.Duration
+}
+
+// maxPasswordResetAttempts bounds how many codes can be tried before the code is discarded.
+const maxPasswordResetAttempts = 5
+
+// passwordResetRecord is the pending reset code of a user. The codes tried against it are
+// counted apart, under auth.PasswordResetAttemptsKey, for PasswordResetTTL after the first
+// try. A new code does not reset the count.
+type passwordResetRecord struct {
+	UserID    string    `json:"user_id"`
+	CodeHash  string    `json:"code_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
-func (s *AuthServiceImpl) SignIn(c context.Context, user *entities.User) (*entities.User, string, error) {
+func (s *AuthServiceImpl) SignIn(c context.Context, user *entities.User) (*entities.User, *entities.TokenPair, error) {
 	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
 	defer cancel()
 
+	ip := clientip.FromContext(c)
+	var attempt *LoginAttempt
+	if s.LoginThrottle != nil {
+		var wait time.Duration
+		var err error
+		attempt, wait, err = s.LoginThrottle.Attempt(ctx, user.Email, ip)
+		if err != nil {
+			logger.WithError(err).Error("Failed to check sign in throttle")
+			return nil, nil, err
+		}
+		if wait > 0 {
+			logger.Warnf("Sign in throttled for %s", wait)
+			s.auditSignIn(ctx, entities.AuditActionSignIn, user.Email, nil, "throttled")
+			return nil, nil, appErrors.NewRetryLaterError("Too many failed sign in attempts, try again later", wait)
+		}
+	}
+
 	tx, err := s.DB.BeginTx(ctx)
 	if err != nil {
 		logger.WithError(err).Error("Failed to begin transaction")
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	defer func() {
@@ -43,23 +109,57 @@ func (s *AuthServiceImpl) SignIn(c context.Context, user *entities.User) (*entit
 	foundUser, err := s.UserRepository.FindByEmail(ctx, tx, user.Email)
 	if err != nil {
 		logger.WithError(err).Warn("User not found by email")
-		return nil, "", appErrors.NewUnauthorizedError("Unauthorized", err)
+		s.recordFailedSignIn(ctx, logger, attempt, nil)
+		s.auditSignIn(ctx, entities.AuditActionSignIn, user.Email, nil, "unknown_email")
+		return nil, nil, appErrors.NewUnauthorizedError("Unauthorized", err)
 	}
 
 	if err := s.Encryptor.CompareHash(foundUser.Password, user.Password); err != nil {
 		logger.WithError(err).Warn("Invalid password")
-		return nil, "", appErrors.NewUnauthorizedError("Unauthorized", err)
+		s.recordFailedSignIn(ctx, logger, attempt, foundUser)
+		s.auditSignIn(ctx, entities.AuditActionSignIn, user.Email, foundUser, "invalid_password")
+		return nil, nil, appErrors.NewUnauthorizedError("Unauthorized", err)
 	}
 
-	token, err := s.TokenManager.GenerateToken(foundUser.ID.String())
+	if attempt != nil {
+		if err := s.LoginThrottle.Succeeded(ctx, attempt); err != nil {
+			logger.WithError(err).Warn("Failed to reset failed sign in attempts")
+		}
+	}
+
+	if s.RequireVerifiedEmail && !foundUser.IsVerified() {
+		logger.Warn("Sign in refused for unverified email")
+		s.auditSignIn(ctx, entities.AuditActionSignIn, user.Email, foundUser, "email_not_verified")
+		err = appErrors.NewForbiddenError("Email address is not verified", nil)
+		return nil, nil, err
+	}
+
+	if foundUser.MFAEnabled() {
+		var mfaToken string
+		mfaToken, err = s.TokenManager.GenerateMFAToken(foundUser.ID.String())
+		if err != nil {
+			logger.WithError(err).Error("Failed to generate mfa token")
+			return nil, nil, err
+		}
+		if err = tx.Commit(); err != nil {
+			logger.WithError(err).Error("Failed to commit transaction")
+			return nil, nil, err
+		}
+		// The password step succeeded, SignInMFA records the second one.
+		s.auditSignIn(ctx, entities.AuditActionSignIn, user.Email, foundUser, "")
+		s.upgradePasswordHash(ctx, logger, foundUser, user.Password)
+		return foundUser, &entities.TokenPair{MFAToken: mfaToken}, nil
+	}
+
+	tokens, err := s.startSession(ctx, tx, foundUser)
 	if err != nil {
-		logger.WithError(err).Error("Failed to generate token")
-		return nil, "", err
+		logger.WithError(err).Error("Failed to start session")
+		return nil, nil, err
 	}
 
 	if err = tx.Commit(); err != nil {
 		logger.WithError(err).Error("Failed to commit transaction")
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Disable throwing error, as this dummy
@@ -85,29 +185,259 @@ func (s *AuthServiceImpl) SignIn(c context.Context, user *entities.User) (*entit
 	// 	logger.Infof("GET external API response: %s", string(getResp))
 	// }
 
+	s.auditSignIn(ctx, entities.AuditActionSignIn, user.Email, foundUser, "")
+	s.upgradePasswordHash(ctx, logger, foundUser, user.Password)
+	s.sendSignInNotification(logger, foundUser)
+
+	return foundUser, tokens, err
+}
+
+// upgradePasswordHash rehashes the password the user just signed in with when their stored
+// hash uses an older algorithm or parameters. It runs after sign in has committed, so a
+// failure only leaves the old hash in place until the next sign in.
+func (s *AuthServiceImpl) upgradePasswordHash(ctx context.Context, logger logrus.FieldLogger, user *entities.User, password string) {
+	if !s.Encryptor.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashed, err := s.Encryptor.HashPassword(password)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to rehash password")
+		return
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to begin transaction")
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	oldHash := user.Password
+	user.Password = hashed
+	if _, err = s.UserRepository.Update(ctx, tx, user); err != nil {
+		user.Password = oldHash
+		logger.WithError(err).Warn("Failed to store upgraded password hash")
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		user.Password = oldHash
+		logger.WithError(err).Warn("Failed to commit transaction")
+		return
+	}
+
+	logger.Infof("Password hash of user %s upgraded", user.ID)
+}
+
+// startSession records a new session for user and issues its first tokens. The session id
+// is used as the token family, so the session can be revoked like a family.
+func (s *AuthServiceImpl) startSession(ctx context.Context, tx ports.Transaction, user *entities.User) (*entities.TokenPair, error) {
+	session := &entities.Session{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		UserAgent: clientip.UserAgentFromContext(ctx),
+		IP:        clientip.FromContext(ctx),
+		ExpiresAt: time.Now().Add(s.RefreshTokenTTL),
+	}
+	if _, err := s.SessionRepository.Create(ctx, tx, session); err != nil {
+		return nil, err
+	}
+
+	return s.TokenManager.GenerateTokenPair(user, session.ID.String())
+}
+
+// auditSignIn records a sign in attempt for email. user is nil when the email does not belong
+// to an account, failure is the reason the attempt was refused or empty when it succeeded.
+func (s *AuthServiceImpl) auditSignIn(ctx context.Context, action, email string, user *entities.User, failure string) {
+	event := &entities.AuditEvent{
+		Action:     action,
+		TargetType: entities.AuditTargetUser,
+		Outcome:    entities.AuditOutcomeSuccess,
+		Details:    map[string]string{},
+	}
+	if email != "" {
+		event.Details["email"] = email
+	}
+	if user != nil {
+		event.TargetID = user.ID.String()
+	}
+	if failure != "" {
+		event.Outcome = entities.AuditOutcomeFailure
+		event.Details["reason"] = failure
+	} else if user != nil {
+		event.ActorID = user.ID.String()
+	}
+	recordAudit(ctx, s.AuditLogger, event)
+}
+
+// recordFailedSignIn settles a failed sign in attempt and tells the owner when it locks their
+// account. user is nil when the email does not belong to an account.
+func (s *AuthServiceImpl) recordFailedSignIn(ctx context.Context, logger logrus.FieldLogger, attempt *LoginAttempt, user *entities.User) {
+	if attempt == nil {
+		return
+	}
+
+	lockedUntil, err := s.LoginThrottle.Failed(ctx, attempt)
+	if err != nil {
+		logger.WithError(err).Error("Failed to record failed sign in")
+		return
+	}
+	if lockedUntil.IsZero() || user == nil {
+		return
+	}
+
+	logger.Warnf("Sign in locked until %s after repeated failures", lockedUntil.Format(time.RFC3339))
+	go func() {
+		backgroundCtx := context.WithValue(context.Background(), baseLogger.LoggerContextKey, logger)
+
+		if err := s.MailService.SendAccountLockedNotification(backgroundCtx, user.Email, lockedUntil); err != nil {
+			logger.WithError(err).Error("Failed to send account locked notification")
+		}
+	}()
+}
+
+func (s *AuthServiceImpl) sendSignInNotification(logger logrus.FieldLogger, user *entities.User) {
 	go func() {
 		backgroundCtx := context.Background()
 
 		backgroundCtx = context.WithValue(backgroundCtx, baseLogger.LoggerContextKey, logger)
 
-		err := s.MailService.SendSignInNotification(backgroundCtx, foundUser.Email, "User logged in just now")
+		err := s.MailService.SendSignInNotification(backgroundCtx, user.Email, "User logged in just now")
 		if err != nil {
 			logger.WithError(err).Error("Failed to send mail")
 		}
 	}()
+}
+
+// maxMFAAttempts bounds how many wrong codes can be tried with one mfa pending token.
+const maxMFAAttempts = 5
+
+// SignInMFA completes a sign in started by SignIn for a user with two-factor enabled.
+// The mfa pending token is single-use and is discarded after too many wrong codes.
+func (s *AuthServiceImpl) SignInMFA(c context.Context, mfaToken, code string) (*entities.User, *entities.TokenPair, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	invalidSession := appErrors.NewUnauthorizedError("Invalid or expired two-factor session", nil)
+
+	claims, err := s.TokenManager.ValidateToken(mfaToken)
+	if err != nil {
+		logger.WithError(err).Warn("Invalid mfa token")
+		return nil, nil, invalidSession
+	}
+	if claims.Type != entities.TokenTypeMFAPending || claims.TokenID == "" {
+		logger.Warnf("Rejected %s token used as mfa token", claims.Type)
+		return nil, nil, invalidSession
+	}
+
+	revoked, err := s.Cache.Get(ctx, auth.RevokedTokenKey(claims.TokenID))
+	if err != nil {
+		logger.WithError(err).Error("Failed to check mfa token revocation")
+		return nil, nil, err
+	}
+	if revoked != "" {
+		return nil, nil, invalidSession
+	}
+
+	// Attempts are counted before the code is checked, so concurrent guesses cannot get past
+	// the limit.
+	attempts, err := s.Cache.Incr(ctx, auth.MFAAttemptsKey(claims.TokenID), time.Until(claims.ExpiresAt))
+	if err != nil {
+		logger.WithError(err).Error("Failed to record mfa attempt")
+		return nil, nil, err
+	}
+	if attempts > maxMFAAttempts {
+		s.discardMFAToken(ctx, logger, claims)
+		return nil, nil, invalidSession
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindById(ctx, tx, claims.UserID)
+	if err != nil {
+		logger.WithError(err).Warn("User of mfa token not found")
+		return nil, nil, invalidSession
+	}
+	if !user.MFAEnabled() {
+		err = invalidSession
+		return nil, nil, err
+	}
+
+	ok, err := verifySecondFactor(ctx, tx, user, code, s.RecoveryCodeRepository, s.Encryptor, s.Cache)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check second factor")
+		return nil, nil, err
+	}
+	if !ok {
+		if attempts >= maxMFAAttempts {
+			s.discardMFAToken(ctx, logger, claims)
+		}
+		s.auditSignIn(ctx, entities.AuditActionSignInMFA, user.Email, user, "invalid_code")
+		err = appErrors.NewUnauthorizedError("Invalid authentication code", nil)
+		return nil, nil, err
+	}
 
-	return foundUser, token, err
+	if err = s.Cache.Set(ctx, auth.RevokedTokenKey(claims.TokenID), []byte(claims.UserID), time.Until(claims.ExpiresAt)); err != nil {
+		logger.WithError(err).Error("Failed to consume mfa token")
+		return nil, nil, err
+	}
+
+	tokens, err := s.startSession(ctx, tx, user)
+	if err != nil {
+		logger.WithError(err).Error("Failed to start session")
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, nil, err
+	}
+
+	s.auditSignIn(ctx, entities.AuditActionSignInMFA, user.Email, user, "")
+	s.sendSignInNotification(logger, user)
+
+	return user, tokens, nil
 }
 
-func (s *AuthServiceImpl) SignUp(c context.Context, user *entities.User) (*entities.User, string, error) {
+// discardMFAToken revokes an mfa pending token that was used for too many codes.
+func (s *AuthServiceImpl) discardMFAToken(ctx context.Context, logger logrus.FieldLogger, claims *entities.TokenClaims) {
+	logger.Warnf("Too many invalid mfa codes for user %s, discarding mfa token", claims.UserID)
+	if err := s.Cache.Set(ctx, auth.RevokedTokenKey(claims.TokenID), []byte(claims.UserID), time.Until(claims.ExpiresAt)); err != nil {
+		logger.WithError(err).Error("Failed to revoke mfa token")
+	}
+}
+
+func (s *AuthServiceImpl) SignUp(c context.Context, user *entities.User) (*entities.User, *entities.TokenPair, error) {
 	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
 	defer cancel()
 
+	if err := validatePassword(ctx, s.PasswordPolicy, user.Password, user.Email); err != nil {
+		logger.Warn("Password rejected by policy")
+		return nil, nil, err
+	}
+
 	tx, err := s.DB.BeginTx(ctx)
 	if err != nil {
 		logger.WithError(err).Error("Failed to begin transaction")
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	defer func() {
@@ -120,31 +450,570 @@ func (s *AuthServiceImpl) SignUp(c context.Context, user *entities.User) (*entit
 	if existing != nil {
 		logger.Warn("Email already in use")
 		err = appErrors.NewBadRequestError("Email already exist", nil)
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	hashedPassword, err := s.Encryptor.HashPassword(user.Password)
 	if err != nil {
 		logger.WithError(err).Error("Failed to hash password")
-		return nil, "", err
+		return nil, nil, err
 	}
 	user.Password = hashedPassword
 
 	if _, err := s.UserRepository.Save(ctx, tx, user); err != nil {
 		logger.WithError(err).Error("Failed to create user")
-		return nil, "", err
+		return nil, nil, err
+	}
+
+	// Without verification there is nothing to sign in with yet.
+	var tokens *entities.TokenPair
+	if !s.RequireVerifiedEmail {
+		tokens, err = s.startSession(ctx, tx, user)
+		if err != nil {
+			logger.WithError(err).Error("Failed to start session")
+			return nil, nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, nil, err
+	}
+
+	recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+		ActorID:    user.ID.String(),
+		Action:     entities.AuditActionSignUp,
+		TargetType: entities.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		Outcome:    entities.AuditOutcomeSuccess,
+	})
+	s.sendVerificationEmail(logger, user)
+
+	return user, tokens, nil
+}
+
+// sendVerificationEmail mails a verification link to the user in the background.
+func (s *AuthServiceImpl) sendVerificationEmail(logger logrus.FieldLogger, user *entities.User) {
+	token, err := s.TokenManager.GenerateEmailVerificationToken(user.ID.String(), user.Email)
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate verification token")
+		return
+	}
+	link := s.VerificationURL + "?token=" + url.QueryEscape(token)
+
+	go func() {
+		backgroundCtx := context.WithValue(context.Background(), baseLogger.LoggerContextKey, logger)
+
+		if err := s.MailService.SendVerificationEmail(backgroundCtx, user.Email, link); err != nil {
+			logger.WithError(err).Error("Failed to send verification mail")
+		}
+	}()
+}
+
+// VerifyEmail marks the user's address as verified if token is a valid verification
+// token for their current address.
+func (s *AuthServiceImpl) VerifyEmail(c context.Context, token string) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	invalidLink := appErrors.NewBadRequestError("Invalid or expired verification link", nil)
+
+	claims, err := s.TokenManager.ValidateToken(token)
+	if err != nil {
+		logger.WithError(err).Warn("Invalid verification token")
+		return invalidLink
+	}
+	if claims.Type != entities.TokenTypeEmailVerification {
+		logger.Warnf("Rejected %s token used as verification token", claims.Type)
+		return invalidLink
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindById(ctx, tx, claims.UserID)
+	if err != nil {
+		logger.WithError(err).Warn("User of verification token not found")
+		return invalidLink
+	}
+
+	// The link was sent to an address the user no longer has.
+	if !strings.EqualFold(user.Email, claims.Email) {
+		err = invalidLink
+		return err
+	}
+
+	if err = s.UserRepository.MarkVerified(ctx, tx, claims.UserID); err != nil {
+		logger.WithError(err).Error("Failed to mark email as verified")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// ResendVerification sends a new verification link, at most once per VerificationResendInterval
+// per address. Unknown and already verified addresses are reported as success.
+func (s *AuthServiceImpl) ResendVerification(c context.Context, email string) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	key := auth.VerificationResendKey(email)
+	recent, err := s.Cache.Get(ctx, key)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check verification resend limit")
+		return err
+	}
+	if recent != "" {
+		return appErrors.NewTooManyRequestsError("Verification email was sent recently, try again later", nil)
+	}
+	if err := s.Cache.Set(ctx, key, []byte("1"), s.VerificationResendInterval); err != nil {
+		logger.WithError(err).Error("Failed to store verification resend limit")
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindByEmail(ctx, tx, email)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			logger.Info("Verification resend requested for unknown email")
+			err = nil
+			tx.Rollback()
+			return nil
+		}
+		logger.WithError(err).Error("Failed to find user by email")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
 	}
 
-	token, err := s.TokenManager.GenerateToken(user.ID.String())
+	if user.IsVerified() {
+		return nil
+	}
+
+	s.sendVerificationEmail(logger, user)
+
+	return nil
+}
+
+// Refresh rotates a refresh token: the presented token is marked as used and a new
+// pair from the same family is issued. Presenting an already used refresh token is
+// treated as theft and revokes the whole family.
+func (s *AuthServiceImpl) Refresh(c context.Context, refreshToken string) (*entities.TokenPair, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	claims, err := s.TokenManager.ValidateToken(refreshToken)
+	if err != nil {
+		logger.WithError(err).Warn("Invalid refresh token")
+		return nil, appErrors.NewUnauthorizedError("Invalid refresh token", err)
+	}
+
+	if claims.Type != entities.TokenTypeRefresh || claims.TokenID == "" || claims.FamilyID == "" {
+		logger.Warn("Token is not a refresh token")
+		return nil, appErrors.NewUnauthorizedError("Invalid refresh token", nil)
+	}
+
+	revoked, err := s.Cache.Get(ctx, auth.RevokedFamilyKey(claims.FamilyID))
+	if err != nil {
+		logger.WithError(err).Error("Failed to check token family revocation")
+		return nil, err
+	}
+	if revoked != "" {
+		logger.Warnf("Refresh token family %s is revoked", claims.FamilyID)
+		return nil, appErrors.NewUnauthorizedError("Invalid refresh token", nil)
+	}
+
+	revokedAt, err := s.Cache.Get(ctx, auth.RevokedUserKey(claims.UserID))
+	if err != nil {
+		logger.WithError(err).Error("Failed to check user token revocation")
+		return nil, err
+	}
+	if auth.IsRevokedBefore(revokedAt, claims.IssuedAt) {
+		logger.Warnf("Refresh token of user %s was issued before its sessions were revoked", claims.UserID)
+		return nil, appErrors.NewUnauthorizedError("Invalid refresh token", nil)
+	}
+
+	// The token is claimed before the session is resumed, so that of concurrent refreshes
+	// with the same token only one goes on and the others count as reuse.
+	usedKey := auth.UsedRefreshTokenKey(claims.TokenID)
+	claimed, err := s.Cache.SetNX(ctx, usedKey, []byte(claims.FamilyID), time.Until(claims.ExpiresAt))
+	if err != nil {
+		logger.WithError(err).Error("Failed to mark refresh token as used")
+		return nil, err
+	}
+	if !claimed {
+		logger.Warnf("Refresh token reuse detected for user %s, revoking family %s", claims.UserID, claims.FamilyID)
+		if err := s.revokeFamily(ctx, claims.FamilyID); err != nil {
+			logger.WithError(err).Error("Failed to revoke token family")
+			return nil, err
+		}
+		return nil, appErrors.NewUnauthorizedError("Invalid refresh token", nil)
+	}
+
+	// Roles may have changed since the token was issued, so they are read again.
+	user, err := s.resumeSession(ctx, claims)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			logger.Warnf("Refresh token of deleted user %s", claims.UserID)
+			return nil, appErrors.NewUnauthorizedError("Invalid refresh token", err)
+		}
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			logger.Warnf("Refresh token of revoked or expired session %s", claims.FamilyID)
+			return nil, appErrors.NewUnauthorizedError("Invalid refresh token", err)
+		}
+		logger.WithError(err).Error("Failed to resume session")
+		// The token is released on internal errors, retrying it is no reuse.
+		if err := s.Cache.Delete(ctx, usedKey); err != nil {
+			logger.WithError(err).Warn("Failed to release refresh token")
+		}
+		return nil, err
+	}
+
+	tokens, err := s.TokenManager.GenerateTokenPair(user, claims.FamilyID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to generate token")
-		return nil, "", err
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// resumeSession extends the session of a refresh token and loads its user. The database is
+// the source of truth here, so a session revoked there cannot be refreshed even if its cache
+// marker is lost.
+func (s *AuthServiceImpl) resumeSession(ctx context.Context, claims *entities.TokenClaims) (*entities.User, error) {
+	sessionID, err := uuid.Parse(claims.FamilyID)
+	if err != nil {
+		return nil, appErrors.ErrDataNotFound
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = s.SessionRepository.Touch(ctx, tx, sessionID, time.Now().Add(s.RefreshTokenTTL)); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepository.FindById(ctx, tx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SignOut revokes the presented access token and the session it belongs to.
+func (s *AuthServiceImpl) SignOut(c context.Context, claims *entities.TokenClaims) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	if claims.TokenID != "" {
+		if err := s.Cache.Set(ctx, auth.RevokedTokenKey(claims.TokenID), []byte(claims.UserID), time.Until(claims.ExpiresAt)); err != nil {
+			logger.WithError(err).Error("Failed to revoke access token")
+			return err
+		}
+	}
+
+	if claims.FamilyID != "" {
+		if err := s.revokeFamily(ctx, claims.FamilyID); err != nil {
+			logger.WithError(err).Error("Failed to revoke token family")
+			return err
+		}
+		if err := s.endSession(ctx, claims); err != nil {
+			logger.WithError(err).Error("Failed to revoke session")
+			return err
+		}
+	}
+
+	recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+		ActorID:    claims.UserID,
+		Action:     entities.AuditActionSignOut,
+		TargetType: entities.AuditTargetUser,
+		TargetID:   claims.UserID,
+		Outcome:    entities.AuditOutcomeSuccess,
+	})
+
+	return nil
+}
+
+// endSession marks the session of claims revoked. Tokens issued before sessions were
+// recorded have no session row, which is not an error.
+func (s *AuthServiceImpl) endSession(ctx context.Context, claims *entities.TokenClaims) error {
+	sessionID, err := uuid.Parse(claims.FamilyID)
+	if err != nil {
+		return nil
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = s.SessionRepository.Revoke(ctx, tx, claims.UserID, sessionID)
+	if err != nil && !errors.Is(err, appErrors.ErrDataNotFound) {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+// revokeFamily keeps the revocation marker for as long as any token of the family can still be valid.
+func (s *AuthServiceImpl) revokeFamily(ctx context.Context, familyID string) error {
+	return s.Cache.Set(ctx, auth.RevokedFamilyKey(familyID), []byte("1"), s.RefreshTokenTTL)
+}
+
+// revokeUserTokens invalidates every token of the user issued up to now.
+func (s *AuthServiceImpl) revokeUserTokens(ctx context.Context, userID string) error {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	return s.Cache.Set(ctx, auth.RevokedUserKey(userID), []byte(now), s.RefreshTokenTTL)
+}
+
+// ForgotPassword sends a one-time reset code to the address if it belongs to a user.
+// It reports success for unknown addresses too, so it cannot be used to probe accounts.
+func (s *AuthServiceImpl) ForgotPassword(c context.Context, email string) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	// Limited before the lookup, so unknown addresses are answered the same way.
+	allowed, err := s.Cache.SetNX(ctx, auth.PasswordResetResendKey(email), []byte("1"), s.PasswordResetResendInterval)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check password reset limit")
+		return err
+	}
+	if !allowed {
+		return appErrors.NewTooManyRequestsError("A reset code was sent recently, try again later", nil)
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindByEmail(ctx, tx, email)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			logger.Info("Password reset requested for unknown email")
+			err = nil
+			tx.Rollback()
+			return nil
+		}
+		logger.WithError(err).Error("Failed to find user by email")
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
 		logger.WithError(err).Error("Failed to commit transaction")
-		return nil, "", err
+		return err
+	}
+
+	code := auth.GeneratePasscode()
+	codeHash, err := s.Encryptor.HashPassword(code)
+	if err != nil {
+		logger.WithError(err).Error("Failed to hash reset code")
+		return err
+	}
+
+	record, _ := json.Marshal(passwordResetRecord{
+		UserID:    user.ID.String(),
+		CodeHash:  codeHash,
+		ExpiresAt: time.Now().Add(s.PasswordResetTTL),
+	})
+	if err = s.Cache.Set(ctx, auth.PasswordResetKey(user.Email), record, s.PasswordResetTTL); err != nil {
+		logger.WithError(err).Error("Failed to store reset code")
+		return err
 	}
 
-	return user, token, nil
+	go func() {
+		backgroundCtx := context.WithValue(context.Background(), baseLogger.LoggerContextKey, logger)
+
+		if err := s.MailService.SendPasswordResetCode(backgroundCtx, user.Email, code); err != nil {
+			logger.WithError(err).Error("Failed to send password reset mail")
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword sets a new password when the code matches. The code is single-use,
+// and every existing session of the user is revoked on success.
+func (s *AuthServiceImpl) ResetPassword(c context.Context, email, code, newPassword string) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	invalidCode := appErrors.NewBadRequestError("Invalid or expired reset code", nil)
+
+	key := auth.PasswordResetKey(email)
+	stored, err := s.Cache.Get(ctx, key)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load reset code")
+		return err
+	}
+	if stored == "" {
+		return invalidCode
+	}
+
+	var record passwordResetRecord
+	if err := json.Unmarshal([]byte(stored), &record); err != nil {
+		logger.WithError(err).Error("Corrupted reset code record")
+		s.Cache.Delete(ctx, key)
+		return invalidCode
+	}
+
+	// Attempts are counted before the code is compared, so concurrent guesses cannot get
+	// past the limit. Requesting more codes does not buy more attempts.
+	attempts, err := s.Cache.Incr(ctx, auth.PasswordResetAttemptsKey(email), s.PasswordResetTTL)
+	if err != nil {
+		logger.WithError(err).Error("Failed to record reset attempt")
+		return err
+	}
+	if attempts > maxPasswordResetAttempts {
+		logger.Warn("Too many invalid reset codes, discarding code")
+		s.Cache.Delete(ctx, key)
+		return invalidCode
+	}
+
+	if err := s.Encryptor.CompareHash(record.CodeHash, code); err != nil {
+		recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+			Action:     entities.AuditActionPasswordReset,
+			TargetType: entities.AuditTargetUser,
+			TargetID:   record.UserID,
+			Outcome:    entities.AuditOutcomeFailure,
+			Details:    map[string]string{"reason": "invalid_code"},
+		})
+		if attempts >= maxPasswordResetAttempts || !time.Now().Before(record.ExpiresAt) {
+			logger.Warn("Too many invalid reset codes, discarding code")
+			s.Cache.Delete(ctx, key)
+		}
+		return invalidCode
+	}
+
+	// Checked before the code is consumed, so the user can retry with a better password.
+	if err := validatePassword(ctx, s.PasswordPolicy, newPassword, email); err != nil {
+		logger.Warn("Password rejected by policy")
+		return err
+	}
+
+	// Consume the code before changing anything so it cannot be replayed.
+	if err := s.Cache.Delete(ctx, key); err != nil {
+		logger.WithError(err).Error("Failed to consume reset code")
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindByEmail(ctx, tx, email)
+	if err != nil {
+		logger.WithError(err).Error("Failed to find user for password reset")
+		return invalidCode
+	}
+	if user.ID.String() != record.UserID {
+		err = invalidCode
+		return err
+	}
+
+	user.Password, err = s.Encryptor.HashPassword(newPassword)
+	if err != nil {
+		logger.WithError(err).Error("Failed to hash password")
+		return err
+	}
+
+	if _, err = s.UserRepository.Update(ctx, tx, user); err != nil {
+		logger.WithError(err).Error("Failed to update password")
+		return err
+	}
+
+	if err = s.SessionRepository.RevokeAll(ctx, tx, record.UserID); err != nil {
+		logger.WithError(err).Error("Failed to revoke sessions")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	if err := s.revokeUserTokens(ctx, record.UserID); err != nil {
+		logger.WithError(err).Error("Failed to revoke sessions after password reset")
+		return err
+	}
+
+	recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+		ActorID:    record.UserID,
+		Action:     entities.AuditActionPasswordReset,
+		TargetType: entities.AuditTargetUser,
+		TargetID:   record.UserID,
+		Outcome:    entities.AuditOutcomeSuccess,
+	})
+
+	return nil
 }
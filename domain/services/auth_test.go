@@ -2,7 +2,10 @@ package services_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strconv"
+	"sync"
 	"testing"
 
 	"time"
@@ -10,6 +13,8 @@ import (
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/domain/services"
 	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/chud-lori/go-boilerplate/pkg/clientip"
 	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
 	"github.com/chud-lori/go-boilerplate/pkg/logger"
 	"github.com/google/uuid"
@@ -27,15 +32,18 @@ func TestAuthService_SignIn_Success(t *testing.T) {
 	mockToken := new(mocks.MockTokenManager)
 	mockTx := new(mocks.MockTransaction)
 	mockApi := new(mocks.MockExternalApiClient)
+	mockSessions := new(mocks.MockSessionRepository)
 
 	service := &services.AuthServiceImpl{
-		DB:             mockDB,
-		UserRepository: mockRepo,
-		MailService:    mockMailSrv,
-		Encryptor:      mockEnc,
-		TokenManager:   mockToken,
-		ExternalApi:    mockApi,
-		CtxTimeout:     2 * time.Second,
+		DB:                mockDB,
+		UserRepository:    mockRepo,
+		SessionRepository: mockSessions,
+		MailService:       mockMailSrv,
+		Encryptor:         mockEnc,
+		TokenManager:      mockToken,
+		ExternalApi:       mockApi,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
 	}
 
 	userUUID := uuid.New()
@@ -45,7 +53,11 @@ func TestAuthService_SignIn_Success(t *testing.T) {
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
 	mockRepo.On("FindByEmail", mock.Anything, mockTx, mockUser.Email).Return(foundUser, nil)
 	mockEnc.On("CompareHash", foundUser.Password, mockUser.Password).Return(nil)
-	mockToken.On("GenerateToken", mockUser.ID.String()).Return("generatedtoken", nil)
+	mockEnc.On("NeedsRehash", foundUser.Password).Return(false)
+	mockSessions.On("Create", mock.Anything, mockTx, mock.MatchedBy(func(s *entities.Session) bool {
+		return s.UserID == userUUID && s.ExpiresAt.After(time.Now())
+	})).Return(&entities.Session{}, nil)
+	mockToken.On("GenerateTokenPair", foundUser, mock.AnythingOfType("string")).Return(&entities.TokenPair{AccessToken: "generatedtoken", RefreshToken: "refreshtoken"}, nil)
 	mockTx.On("Commit").Return(nil)
 	mockApi.On("DoRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte(`{"result":"ok"}`), nil)
 
@@ -56,7 +68,7 @@ func TestAuthService_SignIn_Success(t *testing.T) {
 			close(done)
 		}).Return(nil)
 
-	_, token, err := service.SignIn(ctx, mockUser)
+	_, tokens, err := service.SignIn(ctx, mockUser)
 
 	// Wait for goroutine to finish
 	select {
@@ -66,7 +78,8 @@ func TestAuthService_SignIn_Success(t *testing.T) {
 	}
 
 	assert.NoError(t, err)
-	assert.Equal(t, "generatedtoken", token)
+	assert.Equal(t, "generatedtoken", tokens.AccessToken)
+	assert.Equal(t, "refreshtoken", tokens.RefreshToken)
 
 	mockDB.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
@@ -98,7 +111,7 @@ func TestAuthService_SignIn_Failed(t *testing.T) {
 	mockRepo.On("FindByEmail", mock.Anything, mockTx, mockUser.Email).Return(nil, errors.New("Not found"))
 	mockTx.On("Rollback").Return(nil)
 
-	user, token, err := service.SignIn(ctx, mockUser)
+	user, tokens, err := service.SignIn(ctx, mockUser)
 
 	assert.Error(t, err)
 	appErr, ok := err.(*appErrors.AppError)
@@ -106,44 +119,100 @@ func TestAuthService_SignIn_Failed(t *testing.T) {
 	assert.Equal(t, "Unauthorized", appErr.Message)
 	assert.Equal(t, 401, appErr.StatusCode)
 	assert.Nil(t, user)
-	assert.Equal(t, "", token)
+	assert.Nil(t, tokens)
 
 	mockDB.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 	mockEnc.AssertNotCalled(t, "CompareHash")
-	mockToken.AssertNotCalled(t, "GenerateToken")
+	mockToken.AssertNotCalled(t, "GenerateTokenPair")
 	mockTx.AssertExpectations(t)
 }
 
-func TestAuthService_SignUp_Success(t *testing.T) {
+func TestAuthService_SignIn_AuditsFailure(t *testing.T) {
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
 	mockDB := new(mocks.MockDatabase)
 	mockRepo := new(mocks.MockUserRepository)
 	mockEnc := new(mocks.MockEncryptor)
-	mockToken := new(mocks.MockTokenManager)
 	mockTx := new(mocks.MockTransaction)
+	mockAudit := new(mocks.MockAuditLogger)
 
 	service := &services.AuthServiceImpl{
 		DB:             mockDB,
 		UserRepository: mockRepo,
 		Encryptor:      mockEnc,
-		TokenManager:   mockToken,
+		AuditLogger:    mockAudit,
 		CtxTimeout:     2 * time.Second,
 	}
 
+	found := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "hash"}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, found.Email).Return(found, nil)
+	mockEnc.On("CompareHash", "hash", "wrong-password").Return(auth.ErrPasswordMismatch)
+	mockTx.On("Rollback").Return(nil)
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:     entities.AuditActionSignIn,
+		TargetType: entities.AuditTargetUser,
+		TargetID:   found.ID.String(),
+		Outcome:    entities.AuditOutcomeFailure,
+		Details:    map[string]string{"email": found.Email, "reason": "invalid_password"},
+	}).Once()
+
+	_, _, err := service.SignIn(ctx, &entities.User{Email: found.Email, Password: "wrong-password"})
+
+	assert.Error(t, err)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestAuthService_SignUp_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockToken := new(mocks.MockTokenManager)
+	mockTx := new(mocks.MockTransaction)
+	mockMailSrv := new(mocks.MockMailService)
+	mockSessions := new(mocks.MockSessionRepository)
+
+	service := &services.AuthServiceImpl{
+		DB:                mockDB,
+		UserRepository:    mockRepo,
+		SessionRepository: mockSessions,
+		Encryptor:         mockEnc,
+		TokenManager:      mockToken,
+		MailService:       mockMailSrv,
+		VerificationURL:   "https://app.local/verify-email",
+		CtxTimeout:        2 * time.Second,
+	}
+
 	mockUser := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "password1234"}
 
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
 	mockRepo.On("FindByEmail", mock.Anything, mockTx, mockUser.Email).Return(nil, errors.New("Not found"))
 	mockEnc.On("HashPassword", mockUser.Password).Return("hashpassword", nil)
 	mockRepo.On("Save", mock.Anything, mockTx, mockUser).Return(mockUser, nil)
-	mockToken.On("GenerateToken", mockUser.ID.String()).Return("generatedtoken", nil)
+	mockSessions.On("Create", mock.Anything, mockTx, mock.Anything).Return(&entities.Session{}, nil)
+	mockToken.On("GenerateTokenPair", mockUser, mock.AnythingOfType("string")).Return(&entities.TokenPair{AccessToken: "generatedtoken", RefreshToken: "refreshtoken"}, nil)
+	mockToken.On("GenerateEmailVerificationToken", mockUser.ID.String(), mockUser.Email).Return("verifytoken", nil)
 	mockTx.On("Commit").Return(nil)
 
-	_, token, err := service.SignUp(ctx, mockUser)
+	done := make(chan struct{})
+	mockMailSrv.On("SendVerificationEmail", mock.Anything, mockUser.Email, "https://app.local/verify-email?token=verifytoken").
+		Run(func(args mock.Arguments) {
+			close(done)
+		}).Return(nil)
+
+	_, tokens, err := service.SignUp(ctx, mockUser)
 
 	assert.NoError(t, err)
-	assert.Equal(t, "generatedtoken", token)
+	assert.Equal(t, "generatedtoken", tokens.AccessToken)
+	assert.Equal(t, "refreshtoken", tokens.RefreshToken)
+
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("expected SendVerificationEmail to be called")
+	}
 
 	mockDB.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
@@ -174,7 +243,7 @@ func TestAuthService_SignUp_Failed(t *testing.T) {
 	mockRepo.On("FindByEmail", mock.Anything, mockTx, mockUser.Email).Return(mockUser, nil)
 	mockTx.On("Rollback").Return(nil)
 
-	user, token, err := service.SignUp(ctx, mockUser)
+	user, tokens, err := service.SignUp(ctx, mockUser)
 
 	assert.Error(t, err)
 	appErr, ok := err.(*appErrors.AppError)
@@ -182,12 +251,1319 @@ func TestAuthService_SignUp_Failed(t *testing.T) {
 	assert.Equal(t, "Email already exist", appErr.Message)
 	assert.Equal(t, 400, appErr.StatusCode)
 	assert.Nil(t, user)
-	assert.Equal(t, "", token)
+	assert.Nil(t, tokens)
 
 	mockDB.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 	mockRepo.AssertNotCalled(t, "Save")
 	mockEnc.AssertNotCalled(t, "HashPassword")
-	mockToken.AssertNotCalled(t, "GenerateToken")
+	mockToken.AssertNotCalled(t, "GenerateTokenPair")
+	mockTx.AssertExpectations(t)
+}
+
+func TestAuthService_SignUp_PasswordRejectedByPolicy(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockPolicy := new(mocks.MockPasswordPolicyService)
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		PasswordPolicy: mockPolicy,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	rejected := &appErrors.ValidationErrors{Messages: []string{"Password must not contain your email address"}}
+	mockPolicy.On("Validate", mock.Anything, "user1234", "user@mail.com").Return(rejected)
+
+	user, tokens, err := service.SignUp(ctx, &entities.User{Email: "user@mail.com", Password: "user1234"})
+
+	assert.Equal(t, rejected, err)
+	assert.Nil(t, user)
+	assert.Nil(t, tokens)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestAuthService_Refresh_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockToken := new(mocks.MockTokenManager)
+	mockCache := new(mocks.MockCache)
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockSessions := new(mocks.MockSessionRepository)
+
+	service := &services.AuthServiceImpl{
+		DB:                mockDB,
+		UserRepository:    mockRepo,
+		SessionRepository: mockSessions,
+		TokenManager:      mockToken,
+		Cache:             mockCache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	sessionID := uuid.New()
+	claims := &entities.TokenClaims{
+		UserID:    "user-1",
+		TokenID:   "refresh-jti",
+		FamilyID:  sessionID.String(),
+		Type:      entities.TokenTypeRefresh,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	user := &entities.User{ID: uuid.New(), Roles: []string{entities.RoleAdmin}}
+	newPair := &entities.TokenPair{AccessToken: "new-access", RefreshToken: "new-refresh"}
+
+	mockToken.On("ValidateToken", "refresh-token").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedFamilyKey(sessionID.String())).Return("", nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedUserKey("user-1")).Return("", nil)
+	mockCache.On("SetNX", mock.Anything, auth.UsedRefreshTokenKey("refresh-jti"), []byte(sessionID.String()), mock.Anything).Return(true, nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockSessions.On("Touch", mock.Anything, mockTx, sessionID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, "user-1").Return(user, nil)
+	mockTx.On("Commit").Return(nil)
+	mockToken.On("GenerateTokenPair", user, sessionID.String()).Return(newPair, nil)
+
+	tokens, err := service.Refresh(ctx, "refresh-token")
+
+	assert.NoError(t, err)
+	assert.Equal(t, newPair, tokens)
+	mockToken.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_DeletedUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockToken := new(mocks.MockTokenManager)
+	mockCache := new(mocks.MockCache)
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockSessions := new(mocks.MockSessionRepository)
+
+	service := &services.AuthServiceImpl{
+		DB:                mockDB,
+		UserRepository:    mockRepo,
+		SessionRepository: mockSessions,
+		TokenManager:      mockToken,
+		Cache:             mockCache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	sessionID := uuid.New()
+	claims := &entities.TokenClaims{
+		UserID:    "user-1",
+		TokenID:   "refresh-jti",
+		FamilyID:  sessionID.String(),
+		Type:      entities.TokenTypeRefresh,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mockToken.On("ValidateToken", "refresh-token").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, mock.Anything).Return("", nil)
+	mockCache.On("SetNX", mock.Anything, auth.UsedRefreshTokenKey("refresh-jti"), mock.Anything, mock.Anything).Return(true, nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockSessions.On("Touch", mock.Anything, mockTx, sessionID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, "user-1").Return(nil, appErrors.ErrUserNotFound)
+	mockTx.On("Rollback").Return(nil)
+
+	tokens, err := service.Refresh(ctx, "refresh-token")
+
+	assert.Nil(t, tokens)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 401, appErr.StatusCode)
+	mockToken.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
+	mockTx.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_RevokedSession(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockToken := new(mocks.MockTokenManager)
+	mockCache := new(mocks.MockCache)
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockSessions := new(mocks.MockSessionRepository)
+
+	service := &services.AuthServiceImpl{
+		DB:                mockDB,
+		SessionRepository: mockSessions,
+		TokenManager:      mockToken,
+		Cache:             mockCache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	sessionID := uuid.New()
+	claims := &entities.TokenClaims{
+		UserID:    "user-1",
+		TokenID:   "refresh-jti",
+		FamilyID:  sessionID.String(),
+		Type:      entities.TokenTypeRefresh,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	// The cache marker is gone, the revoked session row still stops the refresh.
+	mockToken.On("ValidateToken", "refresh-token").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, mock.Anything).Return("", nil)
+	mockCache.On("SetNX", mock.Anything, auth.UsedRefreshTokenKey("refresh-jti"), mock.Anything, mock.Anything).Return(true, nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockSessions.On("Touch", mock.Anything, mockTx, sessionID, mock.Anything).Return(appErrors.ErrDataNotFound)
+	mockTx.On("Rollback").Return(nil)
+
+	tokens, err := service.Refresh(ctx, "refresh-token")
+
+	assert.Nil(t, tokens)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 401, appErr.StatusCode)
+	mockToken.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
 	mockTx.AssertExpectations(t)
 }
+
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockToken := new(mocks.MockTokenManager)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.AuthServiceImpl{
+		TokenManager:    mockToken,
+		Cache:           mockCache,
+		RefreshTokenTTL: time.Hour,
+		CtxTimeout:      2 * time.Second,
+	}
+
+	claims := &entities.TokenClaims{
+		UserID:    "user-1",
+		TokenID:   "refresh-jti",
+		FamilyID:  "family-1",
+		Type:      entities.TokenTypeRefresh,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mockToken.On("ValidateToken", "refresh-token").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedFamilyKey("family-1")).Return("", nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedUserKey("user-1")).Return("", nil)
+	mockCache.On("SetNX", mock.Anything, auth.UsedRefreshTokenKey("refresh-jti"), []byte("family-1"), mock.Anything).Return(false, nil)
+	mockCache.On("Set", mock.Anything, auth.RevokedFamilyKey("family-1"), []byte("1"), time.Hour).Return(nil)
+
+	tokens, err := service.Refresh(ctx, "refresh-token")
+
+	assert.Nil(t, tokens)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 401, appErr.StatusCode)
+	mockToken.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
+	mockCache.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_ConcurrentReuse(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockToken := new(mocks.MockTokenManager)
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockSessions := new(mocks.MockSessionRepository)
+	cache := newMemoryCache()
+
+	service := &services.AuthServiceImpl{
+		DB:                mockDB,
+		UserRepository:    mockRepo,
+		SessionRepository: mockSessions,
+		TokenManager:      mockToken,
+		Cache:             cache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	sessionID := uuid.New()
+	claims := &entities.TokenClaims{
+		UserID:    "user-1",
+		TokenID:   "refresh-jti",
+		FamilyID:  sessionID.String(),
+		Type:      entities.TokenTypeRefresh,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	user := &entities.User{ID: uuid.New()}
+
+	mockToken.On("ValidateToken", "refresh-token").Return(claims, nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockSessions.On("Touch", mock.Anything, mockTx, sessionID, mock.Anything).Return(nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, "user-1").Return(user, nil)
+	mockTx.On("Commit").Return(nil)
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockToken.On("GenerateTokenPair", user, sessionID.String()).Return(&entities.TokenPair{AccessToken: "new-access"}, nil)
+
+	// Of the refreshes racing with the same token, one wins and the others are reuse.
+	const refreshes = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, refreshes)
+	for i := 0; i < refreshes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.Refresh(ctx, "refresh-token")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+	revoked, _ := cache.Get(ctx, auth.RevokedFamilyKey(sessionID.String()))
+	assert.NotEmpty(t, revoked)
+}
+
+func TestAuthService_Refresh_RejectsAccessToken(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockToken := new(mocks.MockTokenManager)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.AuthServiceImpl{
+		TokenManager: mockToken,
+		Cache:        mockCache,
+		CtxTimeout:   2 * time.Second,
+	}
+
+	claims := &entities.TokenClaims{
+		UserID:   "user-1",
+		TokenID:  "access-jti",
+		FamilyID: "family-1",
+		Type:     entities.TokenTypeAccess,
+	}
+	mockToken.On("ValidateToken", "access-token").Return(claims, nil)
+
+	tokens, err := service.Refresh(ctx, "access-token")
+
+	assert.Nil(t, tokens)
+	assert.Error(t, err)
+	mockCache.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignOut_RevokesTokenAndFamily(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockCache := new(mocks.MockCache)
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockSessions := new(mocks.MockSessionRepository)
+
+	service := &services.AuthServiceImpl{
+		DB:                mockDB,
+		SessionRepository: mockSessions,
+		Cache:             mockCache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	sessionID := uuid.New()
+	claims := &entities.TokenClaims{
+		UserID:    "user-1",
+		TokenID:   "access-jti",
+		FamilyID:  sessionID.String(),
+		Type:      entities.TokenTypeAccess,
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}
+
+	mockCache.On("Set", mock.Anything, auth.RevokedTokenKey("access-jti"), []byte("user-1"), mock.Anything).Return(nil)
+	mockCache.On("Set", mock.Anything, auth.RevokedFamilyKey(sessionID.String()), []byte("1"), time.Hour).Return(nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockSessions.On("Revoke", mock.Anything, mockTx, "user-1", sessionID).Return(nil)
+	mockTx.On("Commit").Return(nil)
+
+	err := service.SignOut(ctx, claims)
+
+	assert.NoError(t, err)
+	mockCache.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_RejectsTokenIssuedBeforeUserRevocation(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockToken := new(mocks.MockTokenManager)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.AuthServiceImpl{
+		TokenManager: mockToken,
+		Cache:        mockCache,
+		CtxTimeout:   2 * time.Second,
+	}
+
+	claims := &entities.TokenClaims{
+		UserID:    "user-1",
+		TokenID:   "refresh-jti",
+		FamilyID:  "family-1",
+		Type:      entities.TokenTypeRefresh,
+		IssuedAt:  time.Now().Add(-time.Hour),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	revokedAt := strconv.FormatInt(time.Now().Unix(), 10)
+
+	mockToken.On("ValidateToken", "refresh-token").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedFamilyKey("family-1")).Return("", nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedUserKey("user-1")).Return(revokedAt, nil)
+
+	tokens, err := service.Refresh(ctx, "refresh-token")
+
+	assert.Nil(t, tokens)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 401, appErr.StatusCode)
+	mockToken.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ForgotPassword_SendsCode(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+	mockMailSrv := new(mocks.MockMailService)
+
+	service := &services.AuthServiceImpl{
+		DB:                          mockDB,
+		UserRepository:              mockRepo,
+		Encryptor:                   mockEnc,
+		Cache:                       mockCache,
+		MailService:                 mockMailSrv,
+		PasswordResetTTL:            15 * time.Minute,
+		PasswordResetResendInterval: time.Minute,
+		CtxTimeout:                  2 * time.Second,
+	}
+
+	user := &entities.User{ID: uuid.New(), Email: "user@mail.com"}
+
+	mockCache.On("SetNX", mock.Anything, auth.PasswordResetResendKey(user.Email), []byte("1"), time.Minute).Return(true, nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, user.Email).Return(user, nil)
+	mockTx.On("Commit").Return(nil)
+	mockEnc.On("HashPassword", mock.Anything).Return("hashed-code", nil)
+	mockCache.On("Set", mock.Anything, auth.PasswordResetKey(user.Email), mock.Anything, 15*time.Minute).Return(nil)
+
+	done := make(chan string, 1)
+	mockMailSrv.On("SendPasswordResetCode", mock.Anything, user.Email, mock.Anything).
+		Run(func(args mock.Arguments) {
+			done <- args.String(2)
+		}).Return(nil)
+
+	err := service.ForgotPassword(ctx, user.Email)
+	assert.NoError(t, err)
+
+	select {
+	case code := <-done:
+		assert.Len(t, code, 8)
+		mockEnc.AssertCalled(t, "HashPassword", code)
+	case <-time.After(1 * time.Second):
+		t.Fatal("expected SendPasswordResetCode to be called")
+	}
+
+	mockCache.AssertExpectations(t)
+	// Attempts against earlier codes still count against the new one.
+	mockCache.AssertNotCalled(t, "Delete", mock.Anything, auth.PasswordResetAttemptsKey(user.Email))
+}
+
+func TestAuthService_ForgotPassword_RateLimited(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockCache := new(mocks.MockCache)
+	mockMailSrv := new(mocks.MockMailService)
+
+	service := &services.AuthServiceImpl{
+		DB:                          mockDB,
+		Cache:                       mockCache,
+		MailService:                 mockMailSrv,
+		PasswordResetResendInterval: time.Minute,
+		CtxTimeout:                  2 * time.Second,
+	}
+
+	mockCache.On("SetNX", mock.Anything, auth.PasswordResetResendKey("user@mail.com"), []byte("1"), time.Minute).Return(false, nil)
+
+	err := service.ForgotPassword(ctx, "USER@mail.com")
+
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 429, appErr.StatusCode)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+	mockCache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockMailSrv.AssertNotCalled(t, "SendPasswordResetCode", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ForgotPassword_UnknownEmail(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCache := new(mocks.MockCache)
+	mockMailSrv := new(mocks.MockMailService)
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		Cache:          mockCache,
+		MailService:    mockMailSrv,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockCache.On("SetNX", mock.Anything, auth.PasswordResetResendKey("nobody@mail.com"), []byte("1"), mock.Anything).Return(true, nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, "nobody@mail.com").Return(nil, appErrors.ErrUserNotFound)
+	mockTx.On("Rollback").Return(nil)
+
+	err := service.ForgotPassword(ctx, "nobody@mail.com")
+
+	assert.NoError(t, err)
+	mockCache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockMailSrv.AssertNotCalled(t, "SendPasswordResetCode", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func resetRecord(t *testing.T, userID string) string {
+	t.Helper()
+	record, err := json.Marshal(map[string]interface{}{
+		"user_id":    userID,
+		"code_hash":  "hashed-code",
+		"expires_at": time.Now().Add(10 * time.Minute),
+	})
+	assert.NoError(t, err)
+	return string(record)
+}
+
+func TestAuthService_ResetPassword_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+	mockSessions := new(mocks.MockSessionRepository)
+
+	service := &services.AuthServiceImpl{
+		DB:                mockDB,
+		UserRepository:    mockRepo,
+		SessionRepository: mockSessions,
+		Encryptor:         mockEnc,
+		Cache:             mockCache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	user := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "old-hash"}
+	key := auth.PasswordResetKey(user.Email)
+
+	mockCache.On("Get", mock.Anything, key).Return(resetRecord(t, user.ID.String()), nil)
+	mockCache.On("Incr", mock.Anything, auth.PasswordResetAttemptsKey(user.Email), mock.Anything).Return(int64(1), nil)
+	mockEnc.On("CompareHash", "hashed-code", "ABCD1234").Return(nil)
+	mockCache.On("Delete", mock.Anything, key).Return(nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, user.Email).Return(user, nil)
+	mockEnc.On("HashPassword", "newpassword123").Return("new-hash", nil)
+	mockRepo.On("Update", mock.Anything, mockTx, mock.MatchedBy(func(u *entities.User) bool {
+		return u.Password == "new-hash"
+	})).Return(user, nil)
+	mockSessions.On("RevokeAll", mock.Anything, mockTx, user.ID.String()).Return(nil)
+	mockTx.On("Commit").Return(nil)
+	mockCache.On("Set", mock.Anything, auth.RevokedUserKey(user.ID.String()), mock.Anything, time.Hour).Return(nil)
+
+	err := service.ResetPassword(ctx, user.Email, "ABCD1234", "newpassword123")
+
+	assert.NoError(t, err)
+	mockCache.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthService_ResetPassword_PasswordRejectedKeepsCode(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+	mockPolicy := new(mocks.MockPasswordPolicyService)
+
+	service := &services.AuthServiceImpl{
+		Encryptor:      mockEnc,
+		Cache:          mockCache,
+		PasswordPolicy: mockPolicy,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	userID := uuid.New().String()
+	key := auth.PasswordResetKey("user@mail.com")
+	rejected := &appErrors.ValidationErrors{Messages: []string{"Password has appeared in a data breach, choose another one"}}
+
+	mockCache.On("Get", mock.Anything, key).Return(resetRecord(t, userID), nil)
+	mockCache.On("Incr", mock.Anything, auth.PasswordResetAttemptsKey("user@mail.com"), mock.Anything).Return(int64(1), nil)
+	mockEnc.On("CompareHash", "hashed-code", "ABCD1234").Return(nil)
+	mockPolicy.On("Validate", mock.Anything, "password", "user@mail.com").Return(rejected)
+
+	err := service.ResetPassword(ctx, "user@mail.com", "ABCD1234", "password")
+
+	assert.Equal(t, rejected, err)
+	mockCache.AssertNotCalled(t, "Delete", mock.Anything, key)
+}
+
+func TestAuthService_ResetPassword_WrongCode(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.AuthServiceImpl{
+		UserRepository: mockRepo,
+		Encryptor:      mockEnc,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	key := auth.PasswordResetKey("user@mail.com")
+
+	mockCache.On("Get", mock.Anything, key).Return(resetRecord(t, uuid.NewString()), nil)
+	mockCache.On("Incr", mock.Anything, auth.PasswordResetAttemptsKey("user@mail.com"), mock.Anything).Return(int64(2), nil)
+	mockEnc.On("CompareHash", "hashed-code", "WRONG000").Return(errors.New("mismatch"))
+
+	err := service.ResetPassword(ctx, "user@mail.com", "WRONG000", "newpassword123")
+
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	mockCache.AssertExpectations(t)
+	mockCache.AssertNotCalled(t, "Delete", mock.Anything, key)
+}
+
+func TestAuthService_ResetPassword_TooManyAttemptsDiscardsCode(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.AuthServiceImpl{
+		Encryptor:  mockEnc,
+		Cache:      mockCache,
+		CtxTimeout: 2 * time.Second,
+	}
+
+	key := auth.PasswordResetKey("user@mail.com")
+
+	mockCache.On("Get", mock.Anything, key).Return(resetRecord(t, uuid.NewString()), nil)
+	mockCache.On("Incr", mock.Anything, auth.PasswordResetAttemptsKey("user@mail.com"), mock.Anything).Return(int64(5), nil)
+	mockEnc.On("CompareHash", "hashed-code", "WRONG000").Return(errors.New("mismatch"))
+	mockCache.On("Delete", mock.Anything, key).Return(nil)
+
+	err := service.ResetPassword(ctx, "user@mail.com", "WRONG000", "newpassword123")
+
+	assert.Error(t, err)
+	mockCache.AssertExpectations(t)
+}
+
+func TestAuthService_ResetPassword_ConcurrentGuessesStopAtLimit(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockEnc := new(mocks.MockEncryptor)
+	cache := newMemoryCache()
+
+	service := &services.AuthServiceImpl{
+		Encryptor:  mockEnc,
+		Cache:      cache,
+		CtxTimeout: 2 * time.Second,
+	}
+
+	key := auth.PasswordResetKey("user@mail.com")
+	cache.Set(ctx, key, []byte(resetRecord(t, uuid.NewString())), time.Minute)
+	mockEnc.On("CompareHash", "hashed-code", mock.Anything).Return(errors.New("mismatch"))
+
+	// However the guesses interleave, no more than the limit get to compare the code.
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.ResetPassword(ctx, "user@mail.com", "WRONG000", "newpassword123")
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, len(mockEnc.Calls), 5)
+}
+
+func TestAuthService_ResetPassword_NoCode(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.AuthServiceImpl{
+		Encryptor:  mockEnc,
+		Cache:      mockCache,
+		CtxTimeout: 2 * time.Second,
+	}
+
+	mockCache.On("Get", mock.Anything, auth.PasswordResetKey("user@mail.com")).Return("", nil)
+
+	err := service.ResetPassword(ctx, "user@mail.com", "ABCD1234", "newpassword123")
+
+	assert.Error(t, err)
+	mockEnc.AssertNotCalled(t, "CompareHash", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignIn_UnverifiedEmailRefused(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockToken := new(mocks.MockTokenManager)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.AuthServiceImpl{
+		DB:                   mockDB,
+		UserRepository:       mockRepo,
+		Encryptor:            mockEnc,
+		TokenManager:         mockToken,
+		RequireVerifiedEmail: true,
+		CtxTimeout:           2 * time.Second,
+	}
+
+	foundUser := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "hashpassword"}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, foundUser.Email).Return(foundUser, nil)
+	mockEnc.On("CompareHash", foundUser.Password, "password1234").Return(nil)
+	mockTx.On("Rollback").Return(nil)
+
+	_, tokens, err := service.SignIn(ctx, &entities.User{Email: foundUser.Email, Password: "password1234"})
+
+	assert.Nil(t, tokens)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.StatusCode)
+	mockToken.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignUp_RequireVerifiedEmailIssuesNoTokens(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockToken := new(mocks.MockTokenManager)
+	mockTx := new(mocks.MockTransaction)
+	mockMailSrv := new(mocks.MockMailService)
+
+	service := &services.AuthServiceImpl{
+		DB:                   mockDB,
+		UserRepository:       mockRepo,
+		Encryptor:            mockEnc,
+		TokenManager:         mockToken,
+		MailService:          mockMailSrv,
+		RequireVerifiedEmail: true,
+		VerificationURL:      "https://app.local/verify-email",
+		CtxTimeout:           2 * time.Second,
+	}
+
+	mockUser := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "password1234"}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, mockUser.Email).Return(nil, errors.New("Not found"))
+	mockEnc.On("HashPassword", mockUser.Password).Return("hashpassword", nil)
+	mockRepo.On("Save", mock.Anything, mockTx, mockUser).Return(mockUser, nil)
+	mockToken.On("GenerateEmailVerificationToken", mockUser.ID.String(), mockUser.Email).Return("verifytoken", nil)
+	mockTx.On("Commit").Return(nil)
+
+	done := make(chan struct{})
+	mockMailSrv.On("SendVerificationEmail", mock.Anything, mockUser.Email, mock.Anything).
+		Run(func(args mock.Arguments) {
+			close(done)
+		}).Return(nil)
+
+	user, tokens, err := service.SignUp(ctx, mockUser)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+	assert.Nil(t, tokens)
+	mockToken.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
+
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("expected SendVerificationEmail to be called")
+	}
+}
+
+func TestAuthService_VerifyEmail_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockToken := new(mocks.MockTokenManager)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		TokenManager:   mockToken,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	user := &entities.User{ID: uuid.New(), Email: "user@mail.com"}
+	claims := &entities.TokenClaims{UserID: user.ID.String(), Email: user.Email, Type: entities.TokenTypeEmailVerification}
+
+	mockToken.On("ValidateToken", "verifytoken").Return(claims, nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockRepo.On("MarkVerified", mock.Anything, mockTx, user.ID.String()).Return(nil)
+	mockTx.On("Commit").Return(nil)
+
+	err := service.VerifyEmail(ctx, "verifytoken")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockTx.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyEmail_RejectsAccessToken(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockToken := new(mocks.MockTokenManager)
+
+	service := &services.AuthServiceImpl{
+		DB:           mockDB,
+		TokenManager: mockToken,
+		CtxTimeout:   2 * time.Second,
+	}
+
+	mockToken.On("ValidateToken", "accesstoken").Return(&entities.TokenClaims{UserID: "user-1", Type: entities.TokenTypeAccess}, nil)
+
+	err := service.VerifyEmail(ctx, "accesstoken")
+
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.StatusCode)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestAuthService_VerifyEmail_EmailChanged(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockToken := new(mocks.MockTokenManager)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		TokenManager:   mockToken,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	user := &entities.User{ID: uuid.New(), Email: "new@mail.com"}
+	claims := &entities.TokenClaims{UserID: user.ID.String(), Email: "old@mail.com", Type: entities.TokenTypeEmailVerification}
+
+	mockToken.On("ValidateToken", "verifytoken").Return(claims, nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockTx.On("Rollback").Return(nil)
+
+	err := service.VerifyEmail(ctx, "verifytoken")
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "MarkVerified", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ResendVerification_Sends(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockToken := new(mocks.MockTokenManager)
+	mockTx := new(mocks.MockTransaction)
+	mockCache := new(mocks.MockCache)
+	mockMailSrv := new(mocks.MockMailService)
+
+	service := &services.AuthServiceImpl{
+		DB:                         mockDB,
+		UserRepository:             mockRepo,
+		TokenManager:               mockToken,
+		Cache:                      mockCache,
+		MailService:                mockMailSrv,
+		VerificationURL:            "https://app.local/verify-email",
+		VerificationResendInterval: time.Minute,
+		CtxTimeout:                 2 * time.Second,
+	}
+
+	user := &entities.User{ID: uuid.New(), Email: "user@mail.com"}
+	key := auth.VerificationResendKey(user.Email)
+
+	mockCache.On("Get", mock.Anything, key).Return("", nil)
+	mockCache.On("Set", mock.Anything, key, []byte("1"), time.Minute).Return(nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, user.Email).Return(user, nil)
+	mockTx.On("Commit").Return(nil)
+	mockToken.On("GenerateEmailVerificationToken", user.ID.String(), user.Email).Return("verifytoken", nil)
+
+	done := make(chan struct{})
+	mockMailSrv.On("SendVerificationEmail", mock.Anything, user.Email, "https://app.local/verify-email?token=verifytoken").
+		Run(func(args mock.Arguments) {
+			close(done)
+		}).Return(nil)
+
+	err := service.ResendVerification(ctx, user.Email)
+	assert.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("expected SendVerificationEmail to be called")
+	}
+	mockCache.AssertExpectations(t)
+}
+
+func TestAuthService_ResendVerification_RateLimited(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.AuthServiceImpl{
+		DB:         mockDB,
+		Cache:      mockCache,
+		CtxTimeout: 2 * time.Second,
+	}
+
+	mockCache.On("Get", mock.Anything, auth.VerificationResendKey("user@mail.com")).Return("1", nil)
+
+	err := service.ResendVerification(ctx, "user@mail.com")
+
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 429, appErr.StatusCode)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestAuthService_ResendVerification_AlreadyVerified(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockToken := new(mocks.MockTokenManager)
+	mockTx := new(mocks.MockTransaction)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.AuthServiceImpl{
+		DB:                         mockDB,
+		UserRepository:             mockRepo,
+		TokenManager:               mockToken,
+		Cache:                      mockCache,
+		VerificationResendInterval: time.Minute,
+		CtxTimeout:                 2 * time.Second,
+	}
+
+	verifiedAt := time.Now()
+	user := &entities.User{ID: uuid.New(), Email: "user@mail.com", VerifiedAt: &verifiedAt}
+
+	mockCache.On("Get", mock.Anything, mock.Anything).Return("", nil)
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, user.Email).Return(user, nil)
+	mockTx.On("Commit").Return(nil)
+
+	err := service.ResendVerification(ctx, user.Email)
+
+	assert.NoError(t, err)
+	mockToken.AssertNotCalled(t, "GenerateEmailVerificationToken", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignIn_MFAEnabledReturnsMFAToken(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockToken := new(mocks.MockTokenManager)
+	mockTx := new(mocks.MockTransaction)
+	mockMailSrv := new(mocks.MockMailService)
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		Encryptor:      mockEnc,
+		TokenManager:   mockToken,
+		MailService:    mockMailSrv,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	enabledAt := time.Now()
+	foundUser := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "hashpassword", TOTPSecret: testTOTPSecret, MFAEnabledAt: &enabledAt}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, foundUser.Email).Return(foundUser, nil)
+	mockEnc.On("CompareHash", foundUser.Password, "password1234").Return(nil)
+	mockEnc.On("NeedsRehash", foundUser.Password).Return(false)
+	mockToken.On("GenerateMFAToken", foundUser.ID.String()).Return("mfatoken", nil)
+	mockTx.On("Commit").Return(nil)
+
+	_, tokens, err := service.SignIn(ctx, &entities.User{Email: foundUser.Email, Password: "password1234"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mfatoken", tokens.MFAToken)
+	assert.Empty(t, tokens.AccessToken)
+	mockToken.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
+	mockMailSrv.AssertNotCalled(t, "SendSignInNotification", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func newSignInMFAService() (*services.AuthServiceImpl, *mocks.MockTransaction, *mocks.MockUserRepository, *mocks.MockTokenManager, *mocks.MockCache, *mocks.MockMailService) {
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockToken := new(mocks.MockTokenManager)
+	mockCache := new(mocks.MockCache)
+	mockMailSrv := new(mocks.MockMailService)
+	mockSessions := new(mocks.MockSessionRepository)
+
+	service := &services.AuthServiceImpl{
+		DB:                     mockDB,
+		UserRepository:         mockRepo,
+		RecoveryCodeRepository: new(mocks.MockRecoveryCodeRepository),
+		SessionRepository:      mockSessions,
+		Encryptor:              new(mocks.MockEncryptor),
+		TokenManager:           mockToken,
+		Cache:                  mockCache,
+		MailService:            mockMailSrv,
+		CtxTimeout:             2 * time.Second,
+	}
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockSessions.On("Create", mock.Anything, mockTx, mock.Anything).Return(&entities.Session{}, nil)
+
+	return service, mockTx, mockRepo, mockToken, mockCache, mockMailSrv
+}
+
+func TestAuthService_SignInMFA_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	service, mockTx, mockRepo, mockToken, mockCache, mockMailSrv := newSignInMFAService()
+
+	enabledAt := time.Now()
+	user := &entities.User{ID: uuid.New(), Email: "user@mail.com", TOTPSecret: testTOTPSecret, MFAEnabledAt: &enabledAt}
+	claims := &entities.TokenClaims{UserID: user.ID.String(), TokenID: "mfa-jti", Type: entities.TokenTypeMFAPending, ExpiresAt: time.Now().Add(5 * time.Minute)}
+	code, _ := auth.TOTPCode(testTOTPSecret, time.Now())
+	pair := &entities.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+
+	mockToken.On("ValidateToken", "mfatoken").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedTokenKey("mfa-jti")).Return("", nil)
+	mockCache.On("Incr", mock.Anything, auth.MFAAttemptsKey("mfa-jti"), mock.Anything).Return(int64(1), nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockCache.On("Get", mock.Anything, auth.MFALastStepKey(user.ID.String())).Return("", nil)
+	mockCache.On("Set", mock.Anything, auth.MFALastStepKey(user.ID.String()), mock.Anything, mock.Anything).Return(nil)
+	mockCache.On("Set", mock.Anything, auth.RevokedTokenKey("mfa-jti"), []byte(user.ID.String()), mock.Anything).Return(nil)
+	mockToken.On("GenerateTokenPair", user, mock.AnythingOfType("string")).Return(pair, nil)
+	mockTx.On("Commit").Return(nil)
+
+	done := make(chan struct{})
+	mockMailSrv.On("SendSignInNotification", mock.Anything, user.Email, mock.Anything).
+		Run(func(args mock.Arguments) {
+			close(done)
+		}).Return(nil)
+
+	_, tokens, err := service.SignInMFA(ctx, "mfatoken", code)
+
+	assert.NoError(t, err)
+	assert.Equal(t, pair, tokens)
+	mockCache.AssertExpectations(t)
+
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("expected SendSignInNotification to be called")
+	}
+}
+
+func TestAuthService_SignInMFA_WrongCodeCountsAttempt(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	service, mockTx, mockRepo, mockToken, mockCache, _ := newSignInMFAService()
+
+	enabledAt := time.Now()
+	user := &entities.User{ID: uuid.New(), TOTPSecret: testTOTPSecret, MFAEnabledAt: &enabledAt}
+	claims := &entities.TokenClaims{UserID: user.ID.String(), TokenID: "mfa-jti", Type: entities.TokenTypeMFAPending, ExpiresAt: time.Now().Add(5 * time.Minute)}
+
+	mockToken.On("ValidateToken", "mfatoken").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedTokenKey("mfa-jti")).Return("", nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockCache.On("Incr", mock.Anything, auth.MFAAttemptsKey("mfa-jti"), mock.Anything).Return(int64(2), nil)
+	mockTx.On("Rollback").Return(nil)
+
+	// Six characters, so it is checked as a TOTP code, and it never matches.
+	_, tokens, err := service.SignInMFA(ctx, "mfatoken", "12345a")
+
+	assert.Nil(t, tokens)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 401, appErr.StatusCode)
+	mockToken.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
+	mockCache.AssertExpectations(t)
+	mockCache.AssertNotCalled(t, "Set", mock.Anything, auth.RevokedTokenKey("mfa-jti"), mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignInMFA_LastAttemptRevokesToken(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	service, mockTx, mockRepo, mockToken, mockCache, _ := newSignInMFAService()
+
+	enabledAt := time.Now()
+	user := &entities.User{ID: uuid.New(), TOTPSecret: testTOTPSecret, MFAEnabledAt: &enabledAt}
+	claims := &entities.TokenClaims{UserID: user.ID.String(), TokenID: "mfa-jti", Type: entities.TokenTypeMFAPending, ExpiresAt: time.Now().Add(5 * time.Minute)}
+
+	mockToken.On("ValidateToken", "mfatoken").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedTokenKey("mfa-jti")).Return("", nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockCache.On("Incr", mock.Anything, auth.MFAAttemptsKey("mfa-jti"), mock.Anything).Return(int64(5), nil)
+	mockCache.On("Set", mock.Anything, auth.RevokedTokenKey("mfa-jti"), []byte(user.ID.String()), mock.Anything).Return(nil)
+	mockTx.On("Rollback").Return(nil)
+
+	_, _, err := service.SignInMFA(ctx, "mfatoken", "12345a")
+
+	assert.Error(t, err)
+	mockCache.AssertExpectations(t)
+}
+
+func TestAuthService_SignInMFA_OverLimitSkipsCheck(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	service, _, mockRepo, mockToken, mockCache, _ := newSignInMFAService()
+
+	claims := &entities.TokenClaims{UserID: "user-1", TokenID: "mfa-jti", Type: entities.TokenTypeMFAPending, ExpiresAt: time.Now().Add(5 * time.Minute)}
+
+	// A guess racing the last allowed one is counted past the limit and never checked.
+	mockToken.On("ValidateToken", "mfatoken").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedTokenKey("mfa-jti")).Return("", nil)
+	mockCache.On("Incr", mock.Anything, auth.MFAAttemptsKey("mfa-jti"), mock.Anything).Return(int64(6), nil)
+	mockCache.On("Set", mock.Anything, auth.RevokedTokenKey("mfa-jti"), []byte("user-1"), mock.Anything).Return(nil)
+
+	_, tokens, err := service.SignInMFA(ctx, "mfatoken", "123456")
+
+	assert.Nil(t, tokens)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 401, appErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything, mock.Anything)
+	mockCache.AssertExpectations(t)
+}
+
+func TestAuthService_SignInMFA_RejectsUsedToken(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	service, _, mockRepo, mockToken, mockCache, _ := newSignInMFAService()
+
+	claims := &entities.TokenClaims{UserID: "user-1", TokenID: "mfa-jti", Type: entities.TokenTypeMFAPending}
+
+	mockToken.On("ValidateToken", "mfatoken").Return(claims, nil)
+	mockCache.On("Get", mock.Anything, auth.RevokedTokenKey("mfa-jti")).Return("user-1", nil)
+
+	_, tokens, err := service.SignInMFA(ctx, "mfatoken", "123456")
+
+	assert.Nil(t, tokens)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 401, appErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignInMFA_RejectsAccessToken(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	service, _, _, mockToken, mockCache, _ := newSignInMFAService()
+
+	mockToken.On("ValidateToken", "accesstoken").Return(&entities.TokenClaims{UserID: "user-1", TokenID: "jti", Type: entities.TokenTypeAccess}, nil)
+
+	_, _, err := service.SignInMFA(ctx, "accesstoken", "123456")
+
+	assert.Error(t, err)
+	mockCache.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignIn_ThrottledBeforeCheckingPassword(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	ctx = clientip.WithIP(ctx, "203.0.113.7")
+	mockDB := new(mocks.MockDatabase)
+
+	cache := newMemoryCache()
+	throttle := newTestLoginThrottle(cache)
+	until := strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10)
+	cache.data[auth.LoginLockIPKey("203.0.113.7")] = until
+
+	service := &services.AuthServiceImpl{
+		DB:            mockDB,
+		LoginThrottle: throttle,
+		CtxTimeout:    2 * time.Second,
+	}
+
+	user, tokens, err := service.SignIn(ctx, &entities.User{Email: "user@mail.com", Password: "password1234"})
+
+	assert.Nil(t, user)
+	assert.Nil(t, tokens)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 429, appErr.StatusCode)
+	assert.Greater(t, appErr.RetryAfter, 9*time.Minute)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestAuthService_SignIn_LockoutNotifiesOwner(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockMailSrv := new(mocks.MockMailService)
+
+	cache := newMemoryCache()
+	throttle := newTestLoginThrottle(cache)
+	throttle.DelayAfter = 0
+	throttle.MaxFailuresPerEmail = 1
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		Encryptor:      mockEnc,
+		MailService:    mockMailSrv,
+		LoginThrottle:  throttle,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	foundUser := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "hashed"}
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, foundUser.Email).Return(foundUser, nil)
+	mockEnc.On("CompareHash", "hashed", "wrong-password").Return(errors.New("mismatch"))
+	mockTx.On("Rollback").Return(nil)
+
+	done := make(chan struct{})
+	mockMailSrv.On("SendAccountLockedNotification", mock.Anything, foundUser.Email, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			close(done)
+		}).Return(nil)
+
+	_, _, err := service.SignIn(ctx, &entities.User{Email: foundUser.Email, Password: "wrong-password"})
+
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 401, appErr.StatusCode)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected account locked notification")
+	}
+	assert.NotEmpty(t, cache.data[auth.LoginLockEmailKey(foundUser.Email)])
+	mockMailSrv.AssertExpectations(t)
+}
+
+func TestAuthService_SignIn_UpgradesPasswordHash(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockToken := new(mocks.MockTokenManager)
+	mockSessions := new(mocks.MockSessionRepository)
+	mockMailSrv := new(mocks.MockMailService)
+
+	service := &services.AuthServiceImpl{
+		DB:                mockDB,
+		UserRepository:    mockRepo,
+		SessionRepository: mockSessions,
+		Encryptor:         mockEnc,
+		TokenManager:      mockToken,
+		MailService:       mockMailSrv,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	foundUser := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "$2a$10$legacy"}
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, foundUser.Email).Return(foundUser, nil)
+	mockEnc.On("CompareHash", "$2a$10$legacy", "password1234").Return(nil)
+	mockSessions.On("Create", mock.Anything, mockTx, mock.Anything).Return(&entities.Session{}, nil)
+	mockToken.On("GenerateTokenPair", foundUser, mock.AnythingOfType("string")).Return(&entities.TokenPair{AccessToken: "access"}, nil)
+	mockEnc.On("NeedsRehash", "$2a$10$legacy").Return(true)
+	mockEnc.On("HashPassword", "password1234").Return("$argon2id$new", nil)
+	mockRepo.On("Update", mock.Anything, mockTx, mock.MatchedBy(func(u *entities.User) bool {
+		return u.ID == foundUser.ID && u.Password == "$argon2id$new"
+	})).Return(foundUser, nil)
+	mockTx.On("Commit").Return(nil)
+	mockMailSrv.On("SendSignInNotification", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	_, tokens, err := service.SignIn(ctx, &entities.User{Email: foundUser.Email, Password: "password1234"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access", tokens.AccessToken)
+	mockRepo.AssertExpectations(t)
+	mockTx.AssertNumberOfCalls(t, "Commit", 2)
+}
+
+func TestAuthService_SignIn_FailedRehashKeepsSignIn(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockToken := new(mocks.MockTokenManager)
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		Encryptor:      mockEnc,
+		TokenManager:   mockToken,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	enabledAt := time.Now()
+	foundUser := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "$2a$10$legacy", TOTPSecret: testTOTPSecret, MFAEnabledAt: &enabledAt}
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, foundUser.Email).Return(foundUser, nil)
+	mockEnc.On("CompareHash", "$2a$10$legacy", "password1234").Return(nil)
+	mockToken.On("GenerateMFAToken", foundUser.ID.String()).Return("mfatoken", nil)
+	mockEnc.On("NeedsRehash", "$2a$10$legacy").Return(true)
+	mockEnc.On("HashPassword", "password1234").Return("$argon2id$new", nil)
+	mockRepo.On("Update", mock.Anything, mockTx, mock.Anything).Return(nil, errors.New("database down"))
+	mockTx.On("Commit").Return(nil)
+	mockTx.On("Rollback").Return(nil)
+
+	_, tokens, err := service.SignIn(ctx, &entities.User{Email: foundUser.Email, Password: "password1234"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mfatoken", tokens.MFAToken)
+	assert.Equal(t, "$2a$10$legacy", foundUser.Password)
+	mockTx.AssertCalled(t, "Rollback")
+}
+
+func TestAuthService_SignIn_SuccessResetsFailures(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockToken := new(mocks.MockTokenManager)
+	mockMailSrv := new(mocks.MockMailService)
+
+	cache := newMemoryCache()
+	throttle := newTestLoginThrottle(cache)
+	failSignIn(t, throttle, "user@mail.com", "")
+
+	mockSessions := new(mocks.MockSessionRepository)
+	service := &services.AuthServiceImpl{
+		DB:                mockDB,
+		UserRepository:    mockRepo,
+		SessionRepository: mockSessions,
+		Encryptor:         mockEnc,
+		TokenManager:      mockToken,
+		MailService:       mockMailSrv,
+		LoginThrottle:     throttle,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	foundUser := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "hashed"}
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindByEmail", mock.Anything, mockTx, foundUser.Email).Return(foundUser, nil)
+	mockEnc.On("CompareHash", "hashed", "password1234").Return(nil)
+	mockEnc.On("NeedsRehash", foundUser.Password).Return(false)
+	mockSessions.On("Create", mock.Anything, mockTx, mock.Anything).Return(&entities.Session{}, nil)
+	mockToken.On("GenerateTokenPair", foundUser, mock.AnythingOfType("string")).Return(&entities.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil)
+	mockTx.On("Commit").Return(nil)
+	mockMailSrv.On("SendSignInNotification", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	_, tokens, err := service.SignIn(ctx, &entities.User{Email: foundUser.Email, Password: "password1234"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access", tokens.AccessToken)
+	assert.Empty(t, cache.data[auth.LoginFailuresEmailKey(foundUser.Email, currentLoginWindow())])
+	assert.Empty(t, cache.data[auth.LoginLastFailureEmailKey(foundUser.Email)])
+}
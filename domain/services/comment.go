@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultCommentPageSize = 20
+	// maxCommentPageSize bounds how many comments, or top-level comments of a tree, one page returns.
+	maxCommentPageSize = 100
+	// maxThreadReplies bounds how many replies a tree nests below each top-level comment, the
+	// flat shape lists the rest.
+	maxThreadReplies = 50
+	maxCommentLength   = 10000
+)
+
+// CommentServiceImpl manages the comments on posts. Anyone who may read a post may comment on
+// it; authors edit their comments, authors and post managers delete them.
+type CommentServiceImpl struct {
+	DB                ports.Database
+	CommentRepository ports.CommentRepository
+	PostRepository    ports.PostRepository
+	// Cache holds the post listings, which show the comment counts.
+	Cache      ports.Cache
+	CtxTimeout time.Duration
+}
+
+func (s *CommentServiceImpl) Create(c context.Context, actor *entities.TokenClaims, comment *entities.Comment) (*entities.Comment, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	authorID := editorOf(actor)
+	if authorID == uuid.Nil {
+		return nil, appErrors.NewUnauthorizedError("Sign in to comment", nil)
+	}
+	body, err := commentBody(comment.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = readablePost(ctx, tx, logger, s.PostRepository, actor, comment.PostID); err != nil {
+		return nil, err
+	}
+
+	if comment.ParentID != nil {
+		var parent *entities.Comment
+		parent, err = s.CommentRepository.GetById(ctx, tx, *comment.ParentID)
+		if err != nil && !errors.Is(err, appErrors.ErrDataNotFound) {
+			logger.WithError(err).Error("Database error")
+			return nil, err
+		}
+		if parent == nil || parent.PostID != comment.PostID {
+			err = appErrors.NewNotFoundError("Parent comment not found", appErrors.ErrDataNotFound)
+			return nil, err
+		}
+	}
+
+	comment.AuthorID = authorID
+	comment.Body = body
+	result, err := s.CommentRepository.Save(ctx, tx, comment)
+	if err != nil {
+		logger.WithError(err).Error("Failed to save comment")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	s.invalidatePosts(c, logger)
+
+	return result, nil
+}
+
+func (s *CommentServiceImpl) Update(c context.Context, actor *entities.TokenClaims, postID, id uuid.UUID, body string) (*entities.Comment, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	body, err := commentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = s.findCommentForWrite(ctx, tx, logger, actor, postID, id, false); err != nil {
+		return nil, err
+	}
+
+	result, err := s.CommentRepository.Update(ctx, tx, id, body)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			err = appErrors.NewNotFoundError("Comment not found", err)
+			return nil, err
+		}
+		logger.WithError(err).Error("Failed to update comment")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Delete leaves a placeholder in the thread, replies to the comment are kept.
+func (s *CommentServiceImpl) Delete(c context.Context, actor *entities.TokenClaims, postID, id uuid.UUID) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = s.findCommentForWrite(ctx, tx, logger, actor, postID, id, true); err != nil {
+		return err
+	}
+
+	if err = s.CommentRepository.Delete(ctx, tx, id); err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			err = appErrors.NewNotFoundError("Comment not found", err)
+			return err
+		}
+		logger.WithError(err).Error("Failed to delete comment")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	s.invalidatePosts(c, logger)
+
+	return nil
+}
+
+func (s *CommentServiceImpl) List(c context.Context, actor *entities.TokenClaims, postID uuid.UUID, cursor string, limit int, tree bool) (*entities.CommentPage, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	var after *entities.CommentCursor
+	if cursor != "" {
+		var err error
+		if after, err = entities.ParseCommentCursor(cursor); err != nil {
+			return nil, appErrors.NewBadRequestError("Invalid cursor", err)
+		}
+	}
+	if limit < 1 {
+		limit = defaultCommentPageSize
+	}
+	if limit > maxCommentPageSize {
+		limit = maxCommentPageSize
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = readablePost(ctx, tx, logger, s.PostRepository, actor, postID); err != nil {
+		return nil, err
+	}
+
+	// One more than asked for tells whether there is a next page.
+	var comments []*entities.Comment
+	if tree {
+		comments, err = s.CommentRepository.ListThreads(ctx, tx, postID, after, limit+1, maxThreadReplies)
+	} else {
+		comments, err = s.CommentRepository.ListByPost(ctx, tx, postID, after, limit+1)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to list comments")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	for _, comment := range comments {
+		if comment.DeletedAt != nil {
+			comment.Body = ""
+			comment.AuthorID = uuid.Nil
+		}
+	}
+	if tree {
+		comments = commentTrees(comments)
+	}
+
+	page := &entities.CommentPage{Comments: comments}
+	if len(comments) > limit {
+		page.Comments = comments[:limit]
+		last := page.Comments[limit-1]
+		page.NextCursor = entities.CommentCursor{CreatedAt: last.CreatedAt, ID: last.ID}.String()
+	}
+
+	return page, nil
+}
+
+// readablePost loads a post actor may read for the comment and reaction services. Posts
+// actor may not read are reported as missing, like PostServiceImpl.GetById does.
+func readablePost(ctx context.Context, tx ports.Transaction, logger logrus.FieldLogger, posts ports.PostRepository, actor *entities.TokenClaims, postID uuid.UUID) (*entities.Post, error) {
+	post, err := posts.GetById(ctx, tx, postID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			return nil, appErrors.NewNotFoundError("Post not found", err)
+		}
+		logger.WithError(err).Error("Database error")
+		return nil, err
+	}
+
+	if !canReadPost(actor, post) {
+		logger.Warnf("User %s is not allowed to read %s post %s", actorID(actor), post.Status, postID)
+		return nil, appErrors.NewNotFoundError("Post not found", appErrors.ErrDataNotFound)
+	}
+
+	return post, nil
+}
+
+// findCommentForWrite loads a comment of the post postID and checks that actor wrote it, or
+// with moderate, that actor may manage every post.
+func (s *CommentServiceImpl) findCommentForWrite(ctx context.Context, tx ports.Transaction, logger logrus.FieldLogger, actor *entities.TokenClaims, postID, id uuid.UUID, moderate bool) (*entities.Comment, error) {
+	comment, err := s.CommentRepository.GetById(ctx, tx, id)
+	if err != nil && !errors.Is(err, appErrors.ErrDataNotFound) {
+		logger.WithError(err).Error("Database error")
+		return nil, err
+	}
+	if comment == nil || comment.PostID != postID {
+		return nil, appErrors.NewNotFoundError("Comment not found", appErrors.ErrDataNotFound)
+	}
+
+	isAuthor := actor != nil && comment.AuthorID != uuid.Nil && comment.AuthorID.String() == actor.UserID
+	if !isAuthor && !(moderate && actor != nil && actor.HasPermission(entities.PermissionPostsManage)) {
+		logger.Warnf("User %s is not allowed to modify comment %s", actorID(actor), id)
+		return nil, appErrors.NewForbiddenError("You are not allowed to modify this comment", nil)
+	}
+
+	return comment, nil
+}
+
+func (s *CommentServiceImpl) invalidatePosts(c context.Context, logger logrus.FieldLogger) {
+	if err := s.Cache.InvalidateByPrefix(c, "posts:"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale comment counts might be served.")
+	}
+}
+
+func commentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", appErrors.NewBadRequestError("Comment must not be empty", nil)
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", appErrors.NewBadRequestError(fmt.Sprintf("Comments are at most %d characters long", maxCommentLength), nil)
+	}
+	return body, nil
+}
+
+// commentTrees nests comments below their parents and returns the top-level ones, keeping the
+// order of comments. Replies whose parent is not among comments are dropped.
+func commentTrees(comments []*entities.Comment) []*entities.Comment {
+	byID := make(map[uuid.UUID]*entities.Comment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+
+	roots := []*entities.Comment{}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+			continue
+		}
+		if parent, ok := byID[*comment.ParentID]; ok {
+			parent.Replies = append(parent.Replies, comment)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,409 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func publishedPost(postID uuid.UUID) *entities.Post {
+	post := postOwnedBy(postID, uuid.New())
+	post.Status = entities.PostStatusPublished
+	return post
+}
+
+func TestCommentService_Create_Reply(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	parentID := uuid.New()
+	authorID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(publishedPost(postID), nil).Once()
+	mockCommentRepo.On("GetById", mock.Anything, mockTx, parentID).Return(&entities.Comment{ID: parentID, PostID: postID}, nil).Once()
+	mockCommentRepo.On("Save", mock.Anything, mockTx, mock.MatchedBy(func(c *entities.Comment) bool {
+		return c.AuthorID == authorID && c.Body == "Agreed" && *c.ParentID == parentID
+	})).Return(&entities.Comment{ID: uuid.New(), PostID: postID, ParentID: &parentID, AuthorID: authorID, Body: "Agreed"}, nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+
+	result, err := service.Create(ctx, authorClaims(authorID), &entities.Comment{PostID: postID, ParentID: &parentID, Body: "  Agreed \n"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Agreed", result.Body)
+	mockTx.AssertCalled(t, "Commit")
+	mockCache.AssertExpectations(t)
+}
+
+func TestCommentService_Create_ParentOnOtherPost(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	parentID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(publishedPost(postID), nil).Once()
+	mockCommentRepo.On("GetById", mock.Anything, mockTx, parentID).Return(&entities.Comment{ID: parentID, PostID: uuid.New()}, nil).Once()
+
+	_, err := service.Create(ctx, authorClaims(uuid.New()), &entities.Comment{PostID: postID, ParentID: &parentID, Body: "Hi"})
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 404, appErr.StatusCode)
+	assert.Equal(t, "Parent comment not found", appErr.Message)
+	mockCommentRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	mockTx.AssertCalled(t, "Rollback")
+}
+
+func TestCommentService_Create_DraftHidden(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(draftOwnedBy(postID, uuid.New()), nil).Once()
+
+	_, err := service.Create(ctx, authorClaims(uuid.New()), &entities.Comment{PostID: postID, Body: "Hi"})
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 404, appErr.StatusCode)
+	mockCommentRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_Create_Rejected(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	for name, tc := range map[string]struct {
+		actor  *entities.TokenClaims
+		body   string
+		status int
+	}{
+		"anonymous":  {nil, "Hi", 401},
+		"blank body": {authorClaims(uuid.New()), "   ", 400},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.Create(ctx, tc.actor, &entities.Comment{PostID: uuid.New(), Body: tc.body})
+
+			var appErr *appErrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tc.status, appErr.StatusCode)
+		})
+	}
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestCommentService_Update_OnlyAuthor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	commentID := uuid.New()
+	mockCommentRepo.On("GetById", mock.Anything, mockTx, commentID).Return(&entities.Comment{ID: commentID, PostID: postID, AuthorID: uuid.New()}, nil)
+
+	admin := &entities.TokenClaims{UserID: uuid.New().String(), Permissions: []string{entities.PermissionPostsManage}}
+	_, err := service.Update(ctx, admin, postID, commentID, "Edited")
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 403, appErr.StatusCode)
+	mockCommentRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_Update(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	commentID := uuid.New()
+	authorID := uuid.New()
+	mockCommentRepo.On("GetById", mock.Anything, mockTx, commentID).Return(&entities.Comment{ID: commentID, PostID: postID, AuthorID: authorID}, nil).Once()
+	mockCommentRepo.On("Update", mock.Anything, mockTx, commentID, "Edited").Return(&entities.Comment{ID: commentID, Body: "Edited"}, nil).Once()
+
+	result, err := service.Update(ctx, authorClaims(authorID), postID, commentID, "Edited")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Edited", result.Body)
+	mockTx.AssertCalled(t, "Commit")
+}
+
+func TestCommentService_Delete_ByModerator(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	commentID := uuid.New()
+	mockCommentRepo.On("GetById", mock.Anything, mockTx, commentID).Return(&entities.Comment{ID: commentID, PostID: postID, AuthorID: uuid.New()}, nil).Once()
+	mockCommentRepo.On("Delete", mock.Anything, mockTx, commentID).Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+
+	admin := &entities.TokenClaims{UserID: uuid.New().String(), Permissions: []string{entities.PermissionPostsManage}}
+	err := service.Delete(ctx, admin, postID, commentID)
+
+	require.NoError(t, err)
+	mockCommentRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestCommentService_Delete_WrongPost(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	commentID := uuid.New()
+	authorID := uuid.New()
+	mockCommentRepo.On("GetById", mock.Anything, mockTx, commentID).Return(&entities.Comment{ID: commentID, PostID: uuid.New(), AuthorID: authorID}, nil).Once()
+
+	err := service.Delete(ctx, authorClaims(authorID), uuid.New(), commentID)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 404, appErr.StatusCode)
+	mockCommentRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_List_FlatPage(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	deletedAt := time.Now()
+	comments := []*entities.Comment{
+		{ID: uuid.New(), PostID: postID, AuthorID: uuid.New(), Body: "gone", CreatedAt: time.Now(), DeletedAt: &deletedAt},
+		{ID: uuid.New(), PostID: postID, AuthorID: uuid.New(), Body: "second", CreatedAt: time.Now()},
+		{ID: uuid.New(), PostID: postID, AuthorID: uuid.New(), Body: "third", CreatedAt: time.Now()},
+	}
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(publishedPost(postID), nil).Once()
+	mockCommentRepo.On("ListByPost", mock.Anything, mockTx, postID, (*entities.CommentCursor)(nil), 3).Return(comments, nil).Once()
+
+	page, err := service.List(ctx, nil, postID, "", 2, false)
+
+	require.NoError(t, err)
+	require.Len(t, page.Comments, 2)
+	assert.Empty(t, page.Comments[0].Body)
+	assert.Equal(t, uuid.Nil, page.Comments[0].AuthorID)
+
+	next, err := entities.ParseCommentCursor(page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, comments[1].ID, next.ID)
+	assert.True(t, comments[1].CreatedAt.Equal(next.CreatedAt))
+}
+
+func TestCommentService_List_Tree(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	root := &entities.Comment{ID: uuid.New(), PostID: postID, Body: "root", ReplyCount: 2}
+	reply := &entities.Comment{ID: uuid.New(), PostID: postID, ParentID: &root.ID, Body: "reply"}
+	nested := &entities.Comment{ID: uuid.New(), PostID: postID, ParentID: &reply.ID, Body: "nested"}
+	cursor := entities.CommentCursor{CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), ID: uuid.New()}
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(publishedPost(postID), nil).Once()
+	mockCommentRepo.On("ListThreads", mock.Anything, mockTx, postID, &cursor, 21, 50).
+		Return([]*entities.Comment{nested, root, reply}, nil).Once()
+
+	page, err := service.List(ctx, nil, postID, cursor.String(), 0, true)
+
+	require.NoError(t, err)
+	require.Len(t, page.Comments, 1)
+	assert.Empty(t, page.NextCursor)
+	require.Len(t, page.Comments[0].Replies, 1)
+	assert.Equal(t, 2, page.Comments[0].ReplyCount)
+	assert.Equal(t, "nested", page.Comments[0].Replies[0].Replies[0].Body)
+}
+
+func TestCommentService_List_InvalidCursor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockCommentRepo := new(mocks.MockCommentRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.CommentServiceImpl{
+		DB:                mockDB,
+		CommentRepository: mockCommentRepo,
+		PostRepository:    mockPostRepo,
+		Cache:             mockCache,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	_, err := service.List(ctx, nil, uuid.New(), "not a cursor", 10, false)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 400, appErr.StatusCode)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
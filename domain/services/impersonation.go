@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// Impersonate issues a short-lived access token that lets the admin impersonatorID act as
+// userID. The token carries the user's roles and permissions and names the admin, so
+// JWTMiddleware can block destructive requests and every request can be traced back.
+// Users who may impersonate others cannot be impersonated themselves.
+func (s *AuthServiceImpl) Impersonate(c context.Context, impersonatorID, userID string) (string, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	if impersonatorID == userID {
+		return "", appErrors.NewBadRequestError("You cannot impersonate yourself", nil)
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return "", err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindById(ctx, tx, userID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			err = appErrors.NewNotFoundError("User not found", err)
+			return "", err
+		}
+		logger.WithError(err).Error("Failed to find user to impersonate")
+		return "", err
+	}
+
+	if user.HasPermission(entities.PermissionUsersImpersonate) {
+		logger.Warnf("User %s refused impersonating privileged user %s", impersonatorID, userID)
+		s.auditImpersonation(ctx, impersonatorID, userID, "privileged_target")
+		err = appErrors.NewForbiddenError("This user cannot be impersonated", nil)
+		return "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return "", err
+	}
+
+	token, err := s.TokenManager.GenerateImpersonationToken(user, impersonatorID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate impersonation token")
+		return "", err
+	}
+
+	s.auditImpersonation(ctx, impersonatorID, userID, "")
+	logger.Infof("User %s started impersonating user %s", impersonatorID, userID)
+	return token, nil
+}
+
+// auditImpersonation records the admin as the actor; failure is empty on success.
+func (s *AuthServiceImpl) auditImpersonation(ctx context.Context, impersonatorID, userID, failure string) {
+	event := &entities.AuditEvent{
+		ActorID:    impersonatorID,
+		Action:     entities.AuditActionImpersonate,
+		TargetType: entities.AuditTargetUser,
+		TargetID:   userID,
+		Outcome:    entities.AuditOutcomeSuccess,
+	}
+	if failure != "" {
+		event.Outcome = entities.AuditOutcomeFailure
+		event.Details = map[string]string{"reason": failure}
+	}
+	recordAudit(ctx, s.AuditLogger, event)
+}
@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_Impersonate_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockUsers := new(mocks.MockUserRepository)
+	mockTokens := new(mocks.MockTokenManager)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockUsers,
+		TokenManager:   mockTokens,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	user := &entities.User{ID: uuid.New(), Permissions: []string{entities.PermissionPostsWrite}}
+	mockUsers.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockTokens.On("GenerateImpersonationToken", user, "admin-1").Return("impersonation-token", nil)
+	mockAudit.On("Log", mock.Anything, mock.MatchedBy(func(e *entities.AuditEvent) bool {
+		return e.Action == entities.AuditActionImpersonate && e.ActorID == "admin-1" &&
+			e.TargetID == user.ID.String() && e.Outcome == entities.AuditOutcomeSuccess
+	})).Return()
+
+	token, err := service.Impersonate(ctx, "admin-1", user.ID.String())
+
+	require.NoError(t, err)
+	assert.Equal(t, "impersonation-token", token)
+	mockAudit.AssertExpectations(t)
+	mockTx.AssertCalled(t, "Commit")
+}
+
+func TestAuthService_Impersonate_PrivilegedUserRefused(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockUsers := new(mocks.MockUserRepository)
+	mockTokens := new(mocks.MockTokenManager)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockUsers,
+		TokenManager:   mockTokens,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	admin := &entities.User{ID: uuid.New(), Permissions: []string{entities.PermissionUsersImpersonate}}
+	mockUsers.On("FindById", mock.Anything, mockTx, admin.ID.String()).Return(admin, nil)
+	mockAudit.On("Log", mock.Anything, mock.MatchedBy(func(e *entities.AuditEvent) bool {
+		return e.Action == entities.AuditActionImpersonate && e.Outcome == entities.AuditOutcomeFailure
+	})).Return()
+
+	_, err := service.Impersonate(ctx, "admin-1", admin.ID.String())
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	mockTokens.AssertNotCalled(t, "GenerateImpersonationToken", mock.Anything, mock.Anything)
+	mockAudit.AssertExpectations(t)
+	mockTx.AssertCalled(t, "Rollback")
+}
+
+func TestAuthService_Impersonate_Self(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockUsers := new(mocks.MockUserRepository)
+	mockTokens := new(mocks.MockTokenManager)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.AuthServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockUsers,
+		TokenManager:   mockTokens,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	_, err := service.Impersonate(ctx, "admin-1", "admin-1")
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	mockUsers.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything, mock.Anything)
+}
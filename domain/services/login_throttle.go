@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+)
+
+// LoginThrottle slows down and then locks out repeated failed sign ins. Failures are counted
+// per email and per client IP over a sliding window, estimated from the counters of the current
+// and the previous fixed window: the previous one weighs as much as it still overlaps the last
+// Window. Counters only ever change atomically, so concurrent guesses are all counted.
+type LoginThrottle struct {
+	ports.Cache
+	// Window is how far back failures are counted.
+	Window time.Duration
+	// MaxFailuresPerEmail and MaxFailuresPerIP are the failures within Window that trigger a lockout.
+	MaxFailuresPerEmail int
+	MaxFailuresPerIP    int
+	LockoutDuration     time.Duration
+	// DelayAfter is the number of failures for one email after which each attempt has to
+	// wait DelayBase since the last failure, doubling with every further failure.
+	DelayAfter int
+	DelayBase  time.Duration
+}
+
+// LoginAttempt is a sign in counted as failed until it is settled with Succeeded. An attempt
+// that ends in an error is never settled and stays counted.
+type LoginAttempt struct {
+	email  string
+	ip     string
+	window int64
+	// emailFailures and ipFailures are the failures in the sliding window, this attempt included.
+	emailFailures float64
+	ipFailures    float64
+}
+
+// Attempt counts a sign in attempt before the password is checked, so that guesses sent in
+// parallel cannot all get in before the first of them fails. When the attempt is refused it
+// returns how long the caller has to wait instead.
+func (t *LoginThrottle) Attempt(ctx context.Context, email, ip string) (*LoginAttempt, time.Duration, error) {
+	now := time.Now()
+
+	// Refused attempts are not counted, they would only push the delay further.
+	wait, err := t.Check(ctx, email, ip)
+	if err != nil || wait > 0 {
+		return nil, wait, err
+	}
+
+	attempt := &LoginAttempt{email: email, ip: ip, window: t.window(now)}
+	attempt.emailFailures, err = t.count(ctx, auth.LoginFailuresEmailKey(email, attempt.window), auth.LoginFailuresEmailKey(email, attempt.window-1), now)
+	if err != nil {
+		return nil, 0, err
+	}
+	if ip != "" {
+		attempt.ipFailures, err = t.count(ctx, auth.LoginFailuresIPKey(ip, attempt.window), auth.LoginFailuresIPKey(ip, attempt.window-1), now)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// A lockout may have started while this attempt was being counted.
+	wait, err = t.lockWait(ctx, email, ip, now)
+	if err != nil || wait > 0 {
+		return nil, wait, err
+	}
+
+	// The attempt reaching the limit is still checked, it locks sign in when it fails.
+	if exceeds(attempt.emailFailures, t.MaxFailuresPerEmail) || exceeds(attempt.ipFailures, t.MaxFailuresPerIP) {
+		return nil, t.LockoutDuration, nil
+	}
+	return attempt, 0, nil
+}
+
+// Check returns how long the caller has to wait before trying to sign in, or zero when it may try now.
+func (t *LoginThrottle) Check(ctx context.Context, email, ip string) (time.Duration, error) {
+	now := time.Now()
+
+	wait, err := t.lockWait(ctx, email, ip, now)
+	if err != nil || wait > 0 {
+		return wait, err
+	}
+
+	window := t.window(now)
+	failures, err := t.recent(ctx, auth.LoginFailuresEmailKey(email, window), auth.LoginFailuresEmailKey(email, window-1), now)
+	if err != nil {
+		return 0, err
+	}
+	last, err := t.lastFailure(ctx, email)
+	if err != nil {
+		return 0, err
+	}
+	return t.delay(int(failures), last, now), nil
+}
+
+// Failed settles attempt as a failed sign in. When it locks the email it returns the time
+// the lockout ends, otherwise the zero time.
+func (t *LoginThrottle) Failed(ctx context.Context, attempt *LoginAttempt) (time.Time, error) {
+	now := time.Now()
+
+	// Concurrent failures race only on which of them is the latest, not on the count.
+	if err := t.Cache.Set(ctx, auth.LoginLastFailureEmailKey(attempt.email), []byte(strconv.FormatInt(now.UnixMilli(), 10)), t.Window); err != nil {
+		return time.Time{}, err
+	}
+
+	if attempt.ip != "" && reaches(attempt.ipFailures, t.MaxFailuresPerIP) {
+		if _, err := t.lock(ctx, auth.LoginLockIPKey(attempt.ip), now,
+			auth.LoginFailuresIPKey(attempt.ip, attempt.window), auth.LoginFailuresIPKey(attempt.ip, attempt.window-1)); err != nil {
+			return time.Time{}, err
+		}
+	}
+	if reaches(attempt.emailFailures, t.MaxFailuresPerEmail) {
+		return t.lock(ctx, auth.LoginLockEmailKey(attempt.email), now,
+			auth.LoginFailuresEmailKey(attempt.email, attempt.window), auth.LoginFailuresEmailKey(attempt.email, attempt.window-1))
+	}
+	return time.Time{}, nil
+}
+
+// Succeeded settles attempt as a successful sign in. The failures of its email are forgotten,
+// those of its IP are kept, so signing in to one account does not clear an IP guessing others.
+func (t *LoginThrottle) Succeeded(ctx context.Context, attempt *LoginAttempt) error {
+	if attempt.ip != "" {
+		if _, err := t.Cache.Decr(ctx, auth.LoginFailuresIPKey(attempt.ip, attempt.window), 2*t.Window); err != nil {
+			return err
+		}
+	}
+	for _, key := range []string{
+		auth.LoginFailuresEmailKey(attempt.email, attempt.window),
+		auth.LoginFailuresEmailKey(attempt.email, attempt.window-1),
+		auth.LoginLastFailureEmailKey(attempt.email),
+	} {
+		if err := t.Cache.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lock locks sign in until LockoutDuration from now. Counting starts over once the lockout
+// ends, so the counters are dropped; the lock is written first, so no attempt slips in between.
+func (t *LoginThrottle) lock(ctx context.Context, lockKey string, now time.Time, counterKeys ...string) (time.Time, error) {
+	until := now.Add(t.LockoutDuration)
+	if err := t.Cache.Set(ctx, lockKey, []byte(strconv.FormatInt(until.Unix(), 10)), t.LockoutDuration); err != nil {
+		return time.Time{}, err
+	}
+	for _, key := range counterKeys {
+		if err := t.Cache.Delete(ctx, key); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return until, nil
+}
+
+// lockWait returns how long the email or the IP is still locked.
+func (t *LoginThrottle) lockWait(ctx context.Context, email, ip string, now time.Time) (time.Duration, error) {
+	lockKeys := []string{auth.LoginLockEmailKey(email)}
+	if ip != "" {
+		lockKeys = append(lockKeys, auth.LoginLockIPKey(ip))
+	}
+	for _, key := range lockKeys {
+		stored, err := t.Cache.Get(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		if stored == "" {
+			continue
+		}
+		until, err := strconv.ParseInt(stored, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if wait := time.Unix(until, 0).Sub(now); wait > 0 {
+			return wait, nil
+		}
+	}
+	return 0, nil
+}
+
+// window numbers the fixed window now falls in.
+func (t *LoginThrottle) window(now time.Time) int64 {
+	return now.UnixNano() / int64(t.Window)
+}
+
+// count adds an attempt to the counter of the current window and returns the failures in
+// the sliding window that ends now, the new one included.
+func (t *LoginThrottle) count(ctx context.Context, currentKey, previousKey string, now time.Time) (float64, error) {
+	// A counter is still read as the previous window during the window after its own.
+	current, err := t.Cache.Incr(ctx, currentKey, 2*t.Window)
+	if err != nil {
+		return 0, err
+	}
+	previous, err := t.counter(ctx, previousKey)
+	if err != nil {
+		return 0, err
+	}
+	return float64(current) + float64(previous)*t.overlap(now), nil
+}
+
+// recent returns the failures in the sliding window that ends now.
+func (t *LoginThrottle) recent(ctx context.Context, currentKey, previousKey string, now time.Time) (float64, error) {
+	current, err := t.counter(ctx, currentKey)
+	if err != nil {
+		return 0, err
+	}
+	previous, err := t.counter(ctx, previousKey)
+	if err != nil {
+		return 0, err
+	}
+	return float64(current) + float64(previous)*t.overlap(now), nil
+}
+
+func (t *LoginThrottle) counter(ctx context.Context, key string) (int64, error) {
+	stored, err := t.Cache.Get(ctx, key)
+	if err != nil || stored == "" {
+		return 0, err
+	}
+	return strconv.ParseInt(stored, 10, 64)
+}
+
+// overlap is the share of the previous fixed window still inside the sliding window ending now.
+func (t *LoginThrottle) overlap(now time.Time) float64 {
+	elapsed := now.UnixNano() % int64(t.Window)
+	return 1 - float64(elapsed)/float64(t.Window)
+}
+
+// lastFailure returns when email last failed to sign in, or the zero time.
+func (t *LoginThrottle) lastFailure(ctx context.Context, email string) (time.Time, error) {
+	stored, err := t.Cache.Get(ctx, auth.LoginLastFailureEmailKey(email))
+	if err != nil || stored == "" {
+		return time.Time{}, err
+	}
+	millis, err := strconv.ParseInt(stored, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(millis), nil
+}
+
+func (t *LoginThrottle) delay(failures int, last, now time.Time) time.Duration {
+	if t.DelayAfter <= 0 || failures < t.DelayAfter || last.IsZero() {
+		return 0
+	}
+
+	wait := t.DelayBase << (failures - t.DelayAfter)
+	if wait <= 0 || wait > t.LockoutDuration {
+		wait = t.LockoutDuration
+	}
+
+	if remaining := last.Add(wait).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// exceeds reports whether failures are past a limit of max, reaches whether one more failure
+// would be. A limit of zero or less disables the lockout.
+func exceeds(failures float64, max int) bool {
+	return max > 0 && failures > float64(max)
+}
+
+func reaches(failures float64, max int) bool {
+	return max > 0 && failures > float64(max-1)
+}
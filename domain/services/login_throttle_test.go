@@ -0,0 +1,272 @@
+package services_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCache is a ports.Cache kept in a map. Expirations are ignored, the tests
+// control time through the values they store.
+type memoryCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{data: map[string]string{}}
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data[key], nil
+}
+
+func (m *memoryCache) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = string(value)
+	return nil
+}
+
+func (m *memoryCache) SetNX(ctx context.Context, key string, value []byte, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.data[key]; ok {
+		return false, nil
+	}
+	m.data[key] = string(value)
+	return true, nil
+}
+
+func (m *memoryCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, _ := strconv.ParseInt(m.data[key], 10, 64)
+	n++
+	m.data[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+func (m *memoryCache) Decr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, _ := strconv.ParseInt(m.data[key], 10, 64)
+	n--
+	m.data[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+func (m *memoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	return nil
+}
+
+func (m *memoryCache) InvalidateByPrefix(ctx context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.data {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.data, key)
+		}
+	}
+	return nil
+}
+
+func (m *memoryCache) Close() error {
+	return nil
+}
+
+func newTestLoginThrottle(cache *memoryCache) *services.LoginThrottle {
+	return &services.LoginThrottle{
+		Cache:               cache,
+		Window:              15 * time.Minute,
+		MaxFailuresPerEmail: 5,
+		MaxFailuresPerIP:    10,
+		LockoutDuration:     15 * time.Minute,
+		DelayAfter:          3,
+		DelayBase:           time.Minute,
+	}
+}
+
+// failSignIn counts a sign in attempt that is let through and settles it as failed.
+func failSignIn(t *testing.T, throttle *services.LoginThrottle, email, ip string) time.Time {
+	t.Helper()
+	attempt, wait, err := throttle.Attempt(context.Background(), email, ip)
+	require.NoError(t, err)
+	require.Zero(t, wait)
+	require.NotNil(t, attempt)
+
+	until, err := throttle.Failed(context.Background(), attempt)
+	require.NoError(t, err)
+	return until
+}
+
+func currentLoginWindow() int64 {
+	return time.Now().UnixNano() / int64(15*time.Minute)
+}
+
+func TestLoginThrottle_ProgressiveDelay(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemoryCache()
+	throttle := newTestLoginThrottle(cache)
+
+	for i := 0; i < 2; i++ {
+		failSignIn(t, throttle, "user@mail.com", "203.0.113.7")
+	}
+	wait, err := throttle.Check(ctx, "user@mail.com", "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Zero(t, wait)
+
+	failSignIn(t, throttle, "user@mail.com", "203.0.113.7")
+	attempt, wait, err := throttle.Attempt(ctx, "USER@mail.com", "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Nil(t, attempt)
+	assert.InDelta(t, time.Minute, wait, float64(time.Second))
+	assert.Equal(t, "3", cache.data[auth.LoginFailuresEmailKey("user@mail.com", currentLoginWindow())], "a refused attempt is not counted")
+
+	cache.data[auth.LoginLastFailureEmailKey("user@mail.com")] = strconv.FormatInt(time.Now().Add(-61*time.Second).UnixMilli(), 10)
+	failSignIn(t, throttle, "user@mail.com", "203.0.113.7")
+	wait, err = throttle.Check(ctx, "user@mail.com", "203.0.113.7")
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*time.Minute, wait, float64(time.Second))
+}
+
+func TestLoginThrottle_LocksEmail(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemoryCache()
+	throttle := newTestLoginThrottle(cache)
+	throttle.DelayAfter = 0
+
+	var lockedUntil time.Time
+	for i := 0; i < 5; i++ {
+		until := failSignIn(t, throttle, "user@mail.com", "")
+		if i < 4 {
+			assert.True(t, until.IsZero())
+		}
+		lockedUntil = until
+	}
+
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), lockedUntil, 2*time.Second)
+	assert.Empty(t, cache.data[auth.LoginFailuresEmailKey("user@mail.com", currentLoginWindow())])
+
+	attempt, wait, err := throttle.Attempt(ctx, "user@mail.com", "198.51.100.1")
+	assert.NoError(t, err)
+	assert.Nil(t, attempt)
+	assert.InDelta(t, 15*time.Minute, wait, float64(2*time.Second))
+}
+
+func TestLoginThrottle_LocksIP(t *testing.T) {
+	ctx := context.Background()
+	throttle := newTestLoginThrottle(newMemoryCache())
+
+	for i := 0; i < 10; i++ {
+		until := failSignIn(t, throttle, "user"+string(rune('a'+i))+"@mail.com", "203.0.113.7")
+		assert.True(t, until.IsZero(), "an IP lockout is not an account lockout")
+	}
+
+	wait, err := throttle.Check(ctx, "other@mail.com", "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Greater(t, wait, 14*time.Minute)
+
+	wait, err = throttle.Check(ctx, "other@mail.com", "198.51.100.1")
+	assert.NoError(t, err)
+	assert.Zero(t, wait)
+}
+
+func TestLoginThrottle_DelayRunsFromLastFailure(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemoryCache()
+	throttle := newTestLoginThrottle(cache)
+
+	cache.data[auth.LoginFailuresEmailKey("user@mail.com", currentLoginWindow())] = "4"
+	cache.data[auth.LoginLastFailureEmailKey("user@mail.com")] = strconv.FormatInt(time.Now().Add(-20*time.Minute).UnixMilli(), 10)
+
+	wait, err := throttle.Check(ctx, "user@mail.com", "")
+	assert.NoError(t, err)
+	assert.Zero(t, wait)
+
+	until := failSignIn(t, throttle, "user@mail.com", "")
+	assert.False(t, until.IsZero(), "the fifth failure in the window locks the email")
+}
+
+func TestLoginThrottle_ParallelAttemptsStopAtLimit(t *testing.T) {
+	ctx := context.Background()
+	throttle := newTestLoginThrottle(newMemoryCache())
+	throttle.DelayAfter = 0
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempt, wait, err := throttle.Attempt(ctx, "user@mail.com", "203.0.113.7")
+			assert.NoError(t, err)
+			if attempt == nil {
+				assert.Positive(t, wait)
+				return
+			}
+			mu.Lock()
+			allowed++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 5, allowed, "guesses in flight together are counted before any of them fails")
+}
+
+func TestLoginThrottle_SlidingWindowWeighsPreviousWindow(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemoryCache()
+	throttle := newTestLoginThrottle(cache)
+	throttle.DelayAfter = 0
+	// In a window this long now stays about three quarters of the way into window 0, so the
+	// previous window still weighs about a quarter.
+	throttle.Window = 1 << 61
+
+	cache.data[auth.LoginFailuresEmailKey("user@mail.com", -1)] = "100"
+	attempt, wait, err := throttle.Attempt(ctx, "user@mail.com", "")
+	assert.NoError(t, err)
+	assert.Nil(t, attempt)
+	assert.Equal(t, throttle.LockoutDuration, wait)
+
+	cache.data[auth.LoginFailuresEmailKey("other@mail.com", -2)] = "100"
+	attempt, wait, err = throttle.Attempt(ctx, "other@mail.com", "")
+	assert.NoError(t, err)
+	assert.NotNil(t, attempt, "failures from before the previous window are forgotten")
+	assert.Zero(t, wait)
+}
+
+func TestLoginThrottle_SucceededClearsEmailOnly(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemoryCache()
+	throttle := newTestLoginThrottle(cache)
+
+	failSignIn(t, throttle, "user@mail.com", "203.0.113.7")
+
+	attempt, wait, err := throttle.Attempt(ctx, "user@mail.com", "203.0.113.7")
+	require.NoError(t, err)
+	require.Zero(t, wait)
+	assert.NoError(t, throttle.Succeeded(ctx, attempt))
+
+	window := currentLoginWindow()
+	assert.Empty(t, cache.data[auth.LoginFailuresEmailKey("user@mail.com", window)])
+	assert.Empty(t, cache.data[auth.LoginLastFailureEmailKey("user@mail.com")])
+	assert.Equal(t, "1", cache.data[auth.LoginFailuresIPKey("203.0.113.7", window)], "only the successful attempt is taken back")
+}
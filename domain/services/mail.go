@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/ports"
 	"github.com/chud-lori/go-boilerplate/pkg/logger"
@@ -24,3 +26,42 @@ func (s *MailServiceImpl) SendSignInNotification(ctx context.Context, email, tex
 
 	return nil
 }
+
+func (s *MailServiceImpl) SendPasswordResetCode(ctx context.Context, email, code string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	message := fmt.Sprintf("Your password reset code is %s. If you did not request a password reset, you can ignore this email.", code)
+	err := s.MailClient.SendMail(ctx, email, message)
+	if err != nil {
+		logger.WithError(err).Error("Error send password reset code")
+		return err
+	}
+
+	return nil
+}
+
+func (s *MailServiceImpl) SendVerificationEmail(ctx context.Context, email, link string) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	message := fmt.Sprintf("Confirm your email address by opening this link: %s", link)
+	err := s.MailClient.SendMail(ctx, email, message)
+	if err != nil {
+		logger.WithError(err).Error("Error send verification email")
+		return err
+	}
+
+	return nil
+}
+
+func (s *MailServiceImpl) SendAccountLockedNotification(ctx context.Context, email string, until time.Time) error {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	message := fmt.Sprintf("Signing in to your account was locked until %s after too many failed attempts. If this was not you, consider changing your password.", until.UTC().Format(time.RFC1123))
+	err := s.MailClient.SendMail(ctx, email, message)
+	if err != nil {
+		logger.WithError(err).Error("Error send account locked notification")
+		return err
+	}
+
+	return nil
+}
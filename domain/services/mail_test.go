@@ -3,7 +3,9 @@ package services_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/services"
 	"github.com/chud-lori/go-boilerplate/mocks"
@@ -62,3 +64,59 @@ func TestMailService_SendSignInNotification_MailClientError(t *testing.T) {
 	assert.EqualError(t, err, mailClientErr.Error()) // Check if the error matches
 	mockMailClient.AssertExpectations(t)
 }
+
+func TestMailService_SendPasswordResetCode_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	mockMailClient := new(mocks.MockMailClient)
+	service := &services.MailServiceImpl{
+		MailClient: mockMailClient,
+	}
+
+	mockMailClient.On("SendMail", mock.Anything, "test@example.com", mock.MatchedBy(func(message string) bool {
+		return strings.Contains(message, "ABCD1234")
+	})).Return(nil)
+
+	err := service.SendPasswordResetCode(ctx, "test@example.com", "ABCD1234")
+
+	assert.NoError(t, err)
+	mockMailClient.AssertExpectations(t)
+}
+
+func TestMailService_SendVerificationEmail_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	mockMailClient := new(mocks.MockMailClient)
+	service := &services.MailServiceImpl{
+		MailClient: mockMailClient,
+	}
+
+	link := "https://app.local/api/verify-email?token=abc"
+	mockMailClient.On("SendMail", mock.Anything, "test@example.com", mock.MatchedBy(func(message string) bool {
+		return strings.Contains(message, link)
+	})).Return(nil)
+
+	err := service.SendVerificationEmail(ctx, "test@example.com", link)
+
+	assert.NoError(t, err)
+	mockMailClient.AssertExpectations(t)
+}
+
+func TestMailService_SendAccountLockedNotification_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+
+	mockMailClient := new(mocks.MockMailClient)
+	service := &services.MailServiceImpl{
+		MailClient: mockMailClient,
+	}
+
+	until := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	mockMailClient.On("SendMail", mock.Anything, "test@example.com", mock.MatchedBy(func(message string) bool {
+		return strings.Contains(message, until.Format(time.RFC1123))
+	})).Return(nil)
+
+	err := service.SendAccountLockedNotification(ctx, "test@example.com", until)
+
+	assert.NoError(t, err)
+	mockMailClient.AssertExpectations(t)
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// recoveryCodeCount is how many recovery codes are issued on enrollment.
+const recoveryCodeCount = 10
+
+type MFAServiceImpl struct {
+	DB ports.Database
+	ports.UserRepository
+	ports.RecoveryCodeRepository
+	ports.Encryptor
+	ports.Cache
+	// Issuer is the account issuer shown by authenticator apps.
+	Issuer     string
+	CtxTimeout time.Duration
+}
+
+// Enroll creates a new authenticator secret and recovery codes for the user. Two-factor
+// stays off until the secret is confirmed with Verify.
+func (s *MFAServiceImpl) Enroll(c context.Context, userID string) (*entities.MFAEnrollment, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate TOTP secret")
+		return nil, err
+	}
+
+	codes := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i], err = s.Encryptor.HashPassword(code)
+		if err != nil {
+			logger.WithError(err).Error("Failed to hash recovery code")
+			return nil, err
+		}
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindById(ctx, tx, userID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return nil, appErrors.NewNotFoundError("User not found", err)
+		}
+		logger.WithError(err).Error("Failed to find user")
+		return nil, err
+	}
+
+	if user.MFAEnabled() {
+		err = appErrors.NewBadRequestError("Two-factor authentication is already enabled", nil)
+		return nil, err
+	}
+
+	if err = s.UserRepository.SetTOTPSecret(ctx, tx, userID, secret); err != nil {
+		logger.WithError(err).Error("Failed to store TOTP secret")
+		return nil, err
+	}
+
+	if err = s.RecoveryCodeRepository.ReplaceAll(ctx, tx, userID, hashes); err != nil {
+		logger.WithError(err).Error("Failed to store recovery codes")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	return &entities.MFAEnrollment{
+		Secret:        secret,
+		URI:           auth.TOTPURI(s.Issuer, user.Email, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Verify confirms enrollment with a code from the authenticator and turns two-factor on.
+func (s *MFAServiceImpl) Verify(c context.Context, userID, code string) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindById(ctx, tx, userID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return appErrors.NewNotFoundError("User not found", err)
+		}
+		logger.WithError(err).Error("Failed to find user")
+		return err
+	}
+
+	if user.MFAEnabled() {
+		err = appErrors.NewBadRequestError("Two-factor authentication is already enabled", nil)
+		return err
+	}
+	if user.TOTPSecret == "" {
+		err = appErrors.NewBadRequestError("Two-factor enrollment has not been started", nil)
+		return err
+	}
+
+	ok, err := useTOTP(ctx, s.Cache, user, code)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check TOTP code")
+		return err
+	}
+	if !ok {
+		err = appErrors.NewBadRequestError("Invalid authentication code", nil)
+		return err
+	}
+
+	if err = s.UserRepository.EnableMFA(ctx, tx, userID); err != nil {
+		logger.WithError(err).Error("Failed to enable two-factor authentication")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// Disable turns two-factor off after checking a TOTP or recovery code, and removes the
+// secret and the remaining recovery codes.
+func (s *MFAServiceImpl) Disable(c context.Context, userID, code string) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindById(ctx, tx, userID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return appErrors.NewNotFoundError("User not found", err)
+		}
+		logger.WithError(err).Error("Failed to find user")
+		return err
+	}
+
+	if !user.MFAEnabled() {
+		err = appErrors.NewBadRequestError("Two-factor authentication is not enabled", nil)
+		return err
+	}
+
+	ok, err := verifySecondFactor(ctx, tx, user, code, s.RecoveryCodeRepository, s.Encryptor, s.Cache)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check second factor")
+		return err
+	}
+	if !ok {
+		err = appErrors.NewBadRequestError("Invalid authentication code", nil)
+		return err
+	}
+
+	if err = s.UserRepository.SetTOTPSecret(ctx, tx, userID, ""); err != nil {
+		logger.WithError(err).Error("Failed to remove TOTP secret")
+		return err
+	}
+
+	if err = s.RecoveryCodeRepository.DeleteAll(ctx, tx, userID); err != nil {
+		logger.WithError(err).Error("Failed to delete recovery codes")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// useTOTP accepts a code from the user's authenticator once: a code whose time step is not
+// newer than the last accepted one is refused.
+func useTOTP(ctx context.Context, cache ports.Cache, user *entities.User, code string) (bool, error) {
+	step, ok := auth.ValidateTOTP(user.TOTPSecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	key := auth.MFALastStepKey(user.ID.String())
+	last, err := cache.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if lastStep, err := strconv.ParseInt(last, 10, 64); err == nil && step <= lastStep {
+		return false, nil
+	}
+
+	// Codes stay valid for one period on either side of their own, so remember the step a bit longer.
+	if err := cache.Set(ctx, key, []byte(strconv.FormatInt(step, 10)), 3*auth.TOTPPeriod); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// verifySecondFactor accepts a TOTP code or one of the user's unused recovery codes.
+// A matching recovery code is consumed in tx.
+func verifySecondFactor(ctx context.Context, tx ports.Transaction, user *entities.User, code string,
+	recoveryCodes ports.RecoveryCodeRepository, encryptor ports.Encryptor, cache ports.Cache) (bool, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+
+	if len(code) == auth.TOTPDigits {
+		return useTOTP(ctx, cache, user, code)
+	}
+
+	unused, err := recoveryCodes.FindUnused(ctx, tx, user.ID.String())
+	if err != nil {
+		return false, err
+	}
+
+	for _, rc := range unused {
+		if encryptor.CompareHash(rc.CodeHash, code) != nil {
+			continue
+		}
+		if err := recoveryCodes.MarkUsed(ctx, tx, rc.ID.String()); err != nil {
+			if errors.Is(err, appErrors.ErrDataNotFound) {
+				// Used by a concurrent request.
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
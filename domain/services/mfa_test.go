@@ -0,0 +1,282 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func TestMFAService_Enroll_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCodes := new(mocks.MockRecoveryCodeRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.MFAServiceImpl{
+		DB:                     mockDB,
+		UserRepository:         mockRepo,
+		RecoveryCodeRepository: mockCodes,
+		Encryptor:              mockEnc,
+		Cache:                  mockCache,
+		Issuer:                 "TestApp",
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+
+	user := &entities.User{ID: uuid.New(), Email: "user@mail.com"}
+
+	mockEnc.On("HashPassword", mock.Anything).Return("hashed-code", nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockRepo.On("SetTOTPSecret", mock.Anything, mockTx, user.ID.String(), mock.Anything).Return(nil)
+	mockCodes.On("ReplaceAll", mock.Anything, mockTx, user.ID.String(), mock.MatchedBy(func(hashes []string) bool {
+		return len(hashes) == 10
+	})).Return(nil)
+	mockTx.On("Commit").Return(nil)
+
+	enrollment, err := service.Enroll(ctx, user.ID.String())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/TestApp:user@mail.com")
+	assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+	assert.Len(t, enrollment.RecoveryCodes, 10)
+	mockRepo.AssertCalled(t, "SetTOTPSecret", mock.Anything, mockTx, user.ID.String(), enrollment.Secret)
+	mockEnc.AssertNumberOfCalls(t, "HashPassword", 10)
+	mockCodes.AssertExpectations(t)
+}
+
+func TestMFAService_Enroll_AlreadyEnabled(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCodes := new(mocks.MockRecoveryCodeRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.MFAServiceImpl{
+		DB:                     mockDB,
+		UserRepository:         mockRepo,
+		RecoveryCodeRepository: mockCodes,
+		Encryptor:              mockEnc,
+		Cache:                  mockCache,
+		Issuer:                 "TestApp",
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+
+	enabledAt := time.Now()
+	user := &entities.User{ID: uuid.New(), TOTPSecret: testTOTPSecret, MFAEnabledAt: &enabledAt}
+
+	mockEnc.On("HashPassword", mock.Anything).Return("hashed-code", nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockTx.On("Rollback").Return(nil)
+
+	enrollment, err := service.Enroll(ctx, user.ID.String())
+
+	assert.Nil(t, enrollment)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.StatusCode)
+	mockCodes.AssertNotCalled(t, "ReplaceAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMFAService_Verify_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCodes := new(mocks.MockRecoveryCodeRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.MFAServiceImpl{
+		DB:                     mockDB,
+		UserRepository:         mockRepo,
+		RecoveryCodeRepository: mockCodes,
+		Encryptor:              mockEnc,
+		Cache:                  mockCache,
+		Issuer:                 "TestApp",
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+
+	user := &entities.User{ID: uuid.New(), TOTPSecret: testTOTPSecret}
+	code, _ := auth.TOTPCode(testTOTPSecret, time.Now())
+	stepKey := auth.MFALastStepKey(user.ID.String())
+
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockCache.On("Get", mock.Anything, stepKey).Return("", nil)
+	mockCache.On("Set", mock.Anything, stepKey, mock.Anything, 3*auth.TOTPPeriod).Return(nil)
+	mockRepo.On("EnableMFA", mock.Anything, mockTx, user.ID.String()).Return(nil)
+	mockTx.On("Commit").Return(nil)
+
+	err := service.Verify(ctx, user.ID.String(), code)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestMFAService_Verify_InvalidCode(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCodes := new(mocks.MockRecoveryCodeRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.MFAServiceImpl{
+		DB:                     mockDB,
+		UserRepository:         mockRepo,
+		RecoveryCodeRepository: mockCodes,
+		Encryptor:              mockEnc,
+		Cache:                  mockCache,
+		Issuer:                 "TestApp",
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+
+	user := &entities.User{ID: uuid.New(), TOTPSecret: testTOTPSecret}
+
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockTx.On("Rollback").Return(nil)
+
+	err := service.Verify(ctx, user.ID.String(), "000000x")
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "EnableMFA", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMFAService_Verify_RejectsReplayedCode(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCodes := new(mocks.MockRecoveryCodeRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.MFAServiceImpl{
+		DB:                     mockDB,
+		UserRepository:         mockRepo,
+		RecoveryCodeRepository: mockCodes,
+		Encryptor:              mockEnc,
+		Cache:                  mockCache,
+		Issuer:                 "TestApp",
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+
+	user := &entities.User{ID: uuid.New(), TOTPSecret: testTOTPSecret}
+	now := time.Now()
+	code, _ := auth.TOTPCode(testTOTPSecret, now)
+	currentStep := now.Unix() / int64(auth.TOTPPeriod.Seconds())
+
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockCache.On("Get", mock.Anything, auth.MFALastStepKey(user.ID.String())).Return(strconv.FormatInt(currentStep+1, 10), nil)
+	mockTx.On("Rollback").Return(nil)
+
+	err := service.Verify(ctx, user.ID.String(), code)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "EnableMFA", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMFAService_Disable_WithRecoveryCode(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCodes := new(mocks.MockRecoveryCodeRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.MFAServiceImpl{
+		DB:                     mockDB,
+		UserRepository:         mockRepo,
+		RecoveryCodeRepository: mockCodes,
+		Encryptor:              mockEnc,
+		Cache:                  mockCache,
+		Issuer:                 "TestApp",
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+
+	enabledAt := time.Now()
+	user := &entities.User{ID: uuid.New(), TOTPSecret: testTOTPSecret, MFAEnabledAt: &enabledAt}
+	first := &entities.RecoveryCode{ID: uuid.New(), CodeHash: "hash-1"}
+	second := &entities.RecoveryCode{ID: uuid.New(), CodeHash: "hash-2"}
+
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockCodes.On("FindUnused", mock.Anything, mockTx, user.ID.String()).Return([]*entities.RecoveryCode{first, second}, nil)
+	mockEnc.On("CompareHash", "hash-1", "abcde-fghjk").Return(errors.New("mismatch"))
+	mockEnc.On("CompareHash", "hash-2", "abcde-fghjk").Return(nil)
+	mockCodes.On("MarkUsed", mock.Anything, mockTx, second.ID.String()).Return(nil)
+	mockRepo.On("SetTOTPSecret", mock.Anything, mockTx, user.ID.String(), "").Return(nil)
+	mockCodes.On("DeleteAll", mock.Anything, mockTx, user.ID.String()).Return(nil)
+	mockTx.On("Commit").Return(nil)
+
+	err := service.Disable(ctx, user.ID.String(), "ABCDE-FGHJK")
+
+	assert.NoError(t, err)
+	mockCodes.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMFAService_Disable_NotEnabled(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCodes := new(mocks.MockRecoveryCodeRepository)
+	mockEnc := new(mocks.MockEncryptor)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.MFAServiceImpl{
+		DB:                     mockDB,
+		UserRepository:         mockRepo,
+		RecoveryCodeRepository: mockCodes,
+		Encryptor:              mockEnc,
+		Cache:                  mockCache,
+		Issuer:                 "TestApp",
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+
+	user := &entities.User{ID: uuid.New()}
+
+	mockRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockTx.On("Rollback").Return(nil)
+
+	err := service.Disable(ctx, user.ID.String(), "123456")
+
+	assert.Error(t, err)
+	mockCodes.AssertNotCalled(t, "DeleteAll", mock.Anything, mock.Anything, mock.Anything)
+}
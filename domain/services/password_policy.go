@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+)
+
+// minEmailPartLength is the shortest local part of an email address that is looked for in
+// passwords, shorter ones would reject too many unrelated passwords.
+const minEmailPartLength = 3
+
+// PasswordPolicyServiceImpl checks new passwords against the configured rules. Lengths are
+// counted in characters, not bytes.
+type PasswordPolicyServiceImpl struct {
+	MinLength int
+	// MaxLength of zero means no upper bound.
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// Breached is the list of passwords known from data breaches. Nil skips the check.
+	Breached *auth.BreachedPasswords
+}
+
+func (s *PasswordPolicyServiceImpl) Validate(ctx context.Context, password, email string) error {
+	var messages []string
+
+	length := utf8.RuneCountInString(password)
+	if length < s.MinLength {
+		messages = append(messages, fmt.Sprintf("Password must be at least %d characters long", s.MinLength))
+	}
+	if s.MaxLength > 0 && length > s.MaxLength {
+		messages = append(messages, fmt.Sprintf("Password must be at most %d characters long", s.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if s.RequireUpper && !upper {
+		messages = append(messages, "Password must contain an uppercase letter")
+	}
+	if s.RequireLower && !lower {
+		messages = append(messages, "Password must contain a lowercase letter")
+	}
+	if s.RequireDigit && !digit {
+		messages = append(messages, "Password must contain a digit")
+	}
+	if s.RequireSymbol && !symbol {
+		messages = append(messages, "Password must contain a symbol")
+	}
+
+	if containsEmail(password, email) {
+		messages = append(messages, "Password must not contain your email address")
+	}
+
+	if s.Breached.Contains(password) {
+		messages = append(messages, "Password has appeared in a data breach, choose another one")
+	}
+
+	if len(messages) > 0 {
+		return &appErrors.ValidationErrors{Messages: messages}
+	}
+	return nil
+}
+
+// containsEmail reports whether password contains email or the part before the @, ignoring case.
+func containsEmail(password, email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+	password = strings.ToLower(password)
+	if strings.Contains(password, email) {
+		return true
+	}
+	local, _, _ := strings.Cut(email, "@")
+	return utf8.RuneCountInString(local) >= minEmailPartLength && strings.Contains(password, local)
+}
+
+// validatePassword applies policy to a new password, a nil policy accepts any password.
+func validatePassword(ctx context.Context, policy ports.PasswordPolicyService, password, email string) error {
+	if policy == nil {
+		return nil
+	}
+	return policy.Validate(ctx, password, email)
+}
@@ -0,0 +1,86 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPasswordPolicy(t *testing.T) *services.PasswordPolicyServiceImpl {
+	sum := sha1.Sum([]byte("Password1!"))
+	breached, err := auth.ReadBreachedPasswords(strings.NewReader(hex.EncodeToString(sum[:]) + ":42\n"))
+	require.NoError(t, err)
+	return &services.PasswordPolicyServiceImpl{
+		MinLength:     10,
+		MaxLength:     64,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		Breached:      breached,
+	}
+}
+
+func TestPasswordPolicy_Valid(t *testing.T) {
+	policy := newPasswordPolicy(t)
+
+	err := policy.Validate(context.Background(), "Tr0ub4dor&3xyz", "alice@example.com")
+
+	assert.NoError(t, err)
+}
+
+func TestPasswordPolicy_ReportsEveryBrokenRule(t *testing.T) {
+	policy := newPasswordPolicy(t)
+
+	err := policy.Validate(context.Background(), "short", "alice@example.com")
+
+	var validationErr *appErrors.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{
+		"Password must be at least 10 characters long",
+		"Password must contain an uppercase letter",
+		"Password must contain a digit",
+		"Password must contain a symbol",
+	}, validationErr.Messages)
+}
+
+func TestPasswordPolicy_MaxLengthCountsCharacters(t *testing.T) {
+	policy := &services.PasswordPolicyServiceImpl{MinLength: 4, MaxLength: 4}
+
+	assert.NoError(t, policy.Validate(context.Background(), "ünïç", ""))
+	assert.Error(t, policy.Validate(context.Background(), "ünïçø", ""))
+}
+
+func TestPasswordPolicy_RejectsEmail(t *testing.T) {
+	policy := newPasswordPolicy(t)
+
+	for _, password := range []string{"Alice@Example.com1", "My-ALICE-pass9"} {
+		err := policy.Validate(context.Background(), password, "alice@example.com")
+
+		var validationErr *appErrors.ValidationErrors
+		require.ErrorAs(t, err, &validationErr, password)
+		assert.Equal(t, []string{"Password must not contain your email address"}, validationErr.Messages)
+	}
+
+	// Very short local parts are not looked for.
+	assert.NoError(t, policy.Validate(context.Background(), "Jo-Tr0ub4dor&3", "jo@example.com"))
+}
+
+func TestPasswordPolicy_RejectsBreachedPassword(t *testing.T) {
+	policy := newPasswordPolicy(t)
+	policy.MinLength = 8
+
+	err := policy.Validate(context.Background(), "Password1!", "alice@example.com")
+
+	var validationErr *appErrors.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{"Password has appeared in a data breach, choose another one"}, validationErr.Messages)
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// PersonalAccessTokenServiceImpl manages the tokens users mint for scripts. Tokens are looked
+// up in the database on every request, so revoking one or taking a role away applies at once.
+type PersonalAccessTokenServiceImpl struct {
+	DB ports.Database
+	ports.PersonalAccessTokenRepository
+	ports.UserRepository
+	CtxTimeout time.Duration
+}
+
+func (s *PersonalAccessTokenServiceImpl) Create(c context.Context, userID string, token *entities.PersonalAccessToken) (*entities.PersonalAccessToken, string, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(time.Now()) {
+		return nil, "", appErrors.NewBadRequestError("Expiry must be in the future", nil)
+	}
+	if len(token.Scopes) == 0 {
+		return nil, "", appErrors.NewBadRequestError("At least one scope is required", nil)
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, "", err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.FindById(ctx, tx, userID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to find user")
+		return nil, "", err
+	}
+	for _, scope := range token.Scopes {
+		if !user.HasPermission(scope) {
+			err = appErrors.NewBadRequestError("Scope "+scope+" is not granted to you", nil)
+			return nil, "", err
+		}
+	}
+
+	plain, err := auth.GeneratePersonalAccessToken()
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate personal access token")
+		return nil, "", err
+	}
+	token.UserID = user.ID
+	token.Prefix = auth.APIKeyPrefix(plain)
+	token.TokenHash = auth.HashAPIKey(plain)
+
+	saved, err := s.PersonalAccessTokenRepository.Save(ctx, tx, token)
+	if err != nil {
+		logger.WithError(err).Error("Failed to save personal access token")
+		return nil, "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, "", err
+	}
+
+	logger.Infof("Personal access token %s (%s) created for user %s", saved.ID, saved.Name, userID)
+	return saved, plain, nil
+}
+
+func (s *PersonalAccessTokenServiceImpl) List(c context.Context, userID string) ([]*entities.PersonalAccessToken, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	tokens, err := s.PersonalAccessTokenRepository.FindByUser(ctx, tx, userID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list personal access tokens")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (s *PersonalAccessTokenServiceImpl) Revoke(c context.Context, userID string, id uuid.UUID) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = s.PersonalAccessTokenRepository.Revoke(ctx, tx, userID, id); err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			err = appErrors.NewNotFoundError("Personal access token not found or already revoked", err)
+			return err
+		}
+		logger.WithError(err).Error("Failed to revoke personal access token")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	logger.Infof("Personal access token %s of user %s revoked", id, userID)
+	return nil
+}
+
+// Authenticate resolves a token sent by a client into claims for its user. The claims carry
+// the token's scopes that the user's roles still grant, and record the use of the token.
+func (s *PersonalAccessTokenServiceImpl) Authenticate(c context.Context, plain string) (*entities.TokenClaims, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	token, err := s.PersonalAccessTokenRepository.FindByHash(ctx, tx, auth.HashAPIKey(plain))
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			err = appErrors.NewUnauthorizedError("Invalid personal access token", err)
+			return nil, err
+		}
+		logger.WithError(err).Error("Failed to find personal access token")
+		return nil, err
+	}
+	if !token.Active(time.Now()) {
+		err = appErrors.NewUnauthorizedError("Personal access token revoked or expired", nil)
+		return nil, err
+	}
+
+	user, err := s.UserRepository.FindById(ctx, tx, token.UserID.String())
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			err = appErrors.NewUnauthorizedError("Invalid personal access token", err)
+			return nil, err
+		}
+		logger.WithError(err).Error("Failed to find user of personal access token")
+		return nil, err
+	}
+
+	if err = s.PersonalAccessTokenRepository.TouchLastUsed(ctx, tx, token.ID); err != nil {
+		logger.WithError(err).Error("Failed to record personal access token use")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	permissions := []string{}
+	for _, scope := range token.Scopes {
+		if slices.Contains(user.Permissions, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	claims := &entities.TokenClaims{
+		UserID:      user.ID.String(),
+		TokenID:     token.ID.String(),
+		Type:        entities.TokenTypePersonalAccess,
+		IssuedAt:    token.CreatedAt,
+		Roles:       user.Roles,
+		Permissions: permissions,
+	}
+	if token.ExpiresAt != nil {
+		claims.ExpiresAt = *token.ExpiresAt
+	}
+	return claims, nil
+}
@@ -0,0 +1,245 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func patContext() context.Context {
+	return context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+}
+
+func TestPersonalAccessTokenService_Create(t *testing.T) {
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockPersonalAccessTokenRepository)
+	mockUsers := new(mocks.MockUserRepository)
+
+	service := &services.PersonalAccessTokenServiceImpl{
+		DB:                            mockDB,
+		PersonalAccessTokenRepository: mockRepo,
+		UserRepository:                mockUsers,
+		CtxTimeout:                    2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	user := &entities.User{ID: uuid.New(), Permissions: []string{entities.PermissionPostsWrite, entities.PermissionUsersRead}}
+	mockUsers.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+
+	stored := &entities.PersonalAccessToken{}
+	mockRepo.On("Save", mock.Anything, mockTx, mock.AnythingOfType("*entities.PersonalAccessToken")).
+		Run(func(args mock.Arguments) { *stored = *args.Get(2).(*entities.PersonalAccessToken) }).
+		Return(stored, nil).Once()
+
+	token, plain, err := service.Create(patContext(), user.ID.String(), &entities.PersonalAccessToken{
+		Name:   "ci",
+		Scopes: []string{entities.PermissionPostsWrite},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, auth.IsPersonalAccessToken(plain))
+	assert.Equal(t, auth.HashAPIKey(plain), stored.TokenHash)
+	assert.Equal(t, plain[:auth.APIKeyPrefixLength], token.Prefix)
+	assert.Equal(t, user.ID, stored.UserID)
+}
+
+func TestPersonalAccessTokenService_Create_ScopeNotGranted(t *testing.T) {
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockPersonalAccessTokenRepository)
+	mockUsers := new(mocks.MockUserRepository)
+
+	service := &services.PersonalAccessTokenServiceImpl{
+		DB:                            mockDB,
+		PersonalAccessTokenRepository: mockRepo,
+		UserRepository:                mockUsers,
+		CtxTimeout:                    2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	user := &entities.User{ID: uuid.New(), Permissions: []string{entities.PermissionPostsWrite}}
+	mockUsers.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+
+	_, _, err := service.Create(patContext(), user.ID.String(), &entities.PersonalAccessToken{
+		Name:   "ci",
+		Scopes: []string{entities.PermissionUsersManage},
+	})
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	mockTx.AssertCalled(t, "Rollback")
+}
+
+func TestPersonalAccessTokenService_Create_ExpiryInPast(t *testing.T) {
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockPersonalAccessTokenRepository)
+	mockUsers := new(mocks.MockUserRepository)
+
+	service := &services.PersonalAccessTokenServiceImpl{
+		DB:                            mockDB,
+		PersonalAccessTokenRepository: mockRepo,
+		UserRepository:                mockUsers,
+		CtxTimeout:                    2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	past := time.Now().Add(-time.Minute)
+	_, _, err := service.Create(patContext(), uuid.New().String(), &entities.PersonalAccessToken{
+		Name:      "ci",
+		Scopes:    []string{entities.PermissionPostsWrite},
+		ExpiresAt: &past,
+	})
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	mockUsers.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPersonalAccessTokenService_Authenticate(t *testing.T) {
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockPersonalAccessTokenRepository)
+	mockUsers := new(mocks.MockUserRepository)
+
+	service := &services.PersonalAccessTokenServiceImpl{
+		DB:                            mockDB,
+		PersonalAccessTokenRepository: mockRepo,
+		UserRepository:                mockUsers,
+		CtxTimeout:                    2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	user := &entities.User{ID: uuid.New(), Roles: []string{entities.RoleUser}, Permissions: []string{entities.PermissionPostsWrite}}
+	token := &entities.PersonalAccessToken{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		// users:read was taken away from the user after the token was created.
+		Scopes:    []string{entities.PermissionPostsWrite, entities.PermissionUsersRead},
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+	mockRepo.On("FindByHash", mock.Anything, mockTx, auth.HashAPIKey("gbp_plain")).Return(token, nil)
+	mockUsers.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil)
+	mockRepo.On("TouchLastUsed", mock.Anything, mockTx, token.ID).Return(nil)
+
+	claims, err := service.Authenticate(patContext(), "gbp_plain")
+
+	require.NoError(t, err)
+	assert.Equal(t, user.ID.String(), claims.UserID)
+	assert.Equal(t, token.ID.String(), claims.TokenID)
+	assert.Equal(t, entities.TokenTypePersonalAccess, claims.Type)
+	assert.Equal(t, []string{entities.PermissionPostsWrite}, claims.Permissions)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPersonalAccessTokenService_Authenticate_Revoked(t *testing.T) {
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockPersonalAccessTokenRepository)
+	mockUsers := new(mocks.MockUserRepository)
+
+	service := &services.PersonalAccessTokenServiceImpl{
+		DB:                            mockDB,
+		PersonalAccessTokenRepository: mockRepo,
+		UserRepository:                mockUsers,
+		CtxTimeout:                    2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	revokedAt := time.Now().Add(-time.Minute)
+	token := &entities.PersonalAccessToken{ID: uuid.New(), UserID: uuid.New(), RevokedAt: &revokedAt}
+	mockRepo.On("FindByHash", mock.Anything, mockTx, auth.HashAPIKey("gbp_plain")).Return(token, nil)
+
+	claims, err := service.Authenticate(patContext(), "gbp_plain")
+
+	assert.Nil(t, claims)
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusUnauthorized, appErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPersonalAccessTokenService_Authenticate_Unknown(t *testing.T) {
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockPersonalAccessTokenRepository)
+	mockUsers := new(mocks.MockUserRepository)
+
+	service := &services.PersonalAccessTokenServiceImpl{
+		DB:                            mockDB,
+		PersonalAccessTokenRepository: mockRepo,
+		UserRepository:                mockUsers,
+		CtxTimeout:                    2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockRepo.On("FindByHash", mock.Anything, mockTx, mock.Anything).Return(nil, appErrors.ErrDataNotFound)
+
+	_, err := service.Authenticate(patContext(), "gbp_unknown")
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusUnauthorized, appErr.StatusCode)
+}
+
+func TestPersonalAccessTokenService_Revoke_NotFound(t *testing.T) {
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockPersonalAccessTokenRepository)
+	mockUsers := new(mocks.MockUserRepository)
+
+	service := &services.PersonalAccessTokenServiceImpl{
+		DB:                            mockDB,
+		PersonalAccessTokenRepository: mockRepo,
+		UserRepository:                mockUsers,
+		CtxTimeout:                    2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	id := uuid.New()
+	mockRepo.On("Revoke", mock.Anything, mockTx, "user-1", id).Return(appErrors.ErrDataNotFound)
+
+	err := service.Revoke(patContext(), "user-1", id)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"time"
 
@@ -19,20 +20,47 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// postsByTagCachePrefix prefixes the cached listings filtered by tag, within the "posts:" prefix.
+const postsByTagCachePrefix = "posts:tags:"
+
 type PostServiceImpl struct {
 	DB ports.Database
 	ports.PostRepository
 	ports.UserRepository
 	ports.Cache
+	// PostRevisionRepository keeps the content of every version, written with the version itself.
+	PostRevisionRepository ports.PostRevisionRepository
+	// TagRepository stores the tags set on create and update.
+	TagRepository ports.TagRepository
 	JobQueue ports.JobQueue // Injected dependency
+	// AuditLogger records changes to posts and refused attempts to change them. Nil disables it.
+	AuditLogger ports.AuditLogger
+	// ReactionService sets the reaction counts and the reader's own reactions on the posts
+	// read. Nil serves the reconciled counts only.
+	ReactionService ports.ReactionService
 	CtxTimeout time.Duration
 }
 
+// Create saves a new post. Without a status it is published, or a draft when publish_at schedules it.
 func (s *PostServiceImpl) Create(c context.Context, post *entities.Post) (*entities.Post, error) {
 	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
 	defer cancel()
 
+	if post.Status == "" {
+		post.Status = entities.PostStatusPublished
+		if post.PublishAt != nil {
+			post.Status = entities.PostStatusDraft
+		}
+	}
+	if err := checkPostSchedule(post.Status, post.PublishAt); err != nil {
+		return nil, err
+	}
+	tags, err := postTags(post.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := s.DB.BeginTx(ctx)
 	if err != nil {
 		logger.WithError(err).Error("Failed to begin transaction")
@@ -58,20 +86,52 @@ func (s *PostServiceImpl) Create(c context.Context, post *entities.Post) (*entit
 		return nil, err
 	}
 
+	if err = s.saveRevision(ctx, tx, result, post.User.ID); err != nil {
+		logger.WithError(err).Error("Failed to save post revision")
+		return nil, err
+	}
+
+	result.Tags = nil
+	if len(tags) > 0 {
+		if err = s.TagRepository.SetPostTags(ctx, tx, result.ID, tags); err != nil {
+			logger.WithError(err).Error("Failed to set post tags")
+			return nil, err
+		}
+		result.Tags = tagSlugs(tags)
+	}
+
 	if err = tx.Commit(); err != nil {
 		logger.WithError(err).Error("Failed to commit transaction")
 		return nil, err
 	}
 
+	if err := s.Cache.InvalidateByPrefix(c, "posts:"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale data might be served.")
+	}
+
+	s.auditPost(ctx, entities.AuditActionPostCreate, result.ID, entities.AuditOutcomeSuccess)
+
 	return result, nil
 }
 
-func (s *PostServiceImpl) Update(c context.Context, post *entities.Post) (*entities.Post, error) {
+// Update changes the title and body of a post. Only its author, or a caller allowed to
+// manage every post, may update it. A non-zero post.Version must be the current version.
+func (s *PostServiceImpl) Update(c context.Context, actor *entities.TokenClaims, post *entities.Post) (*entities.Post, error) {
 	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
 	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
 	defer cancel()
 
+	// nil tags leave the post's tags as they are, an empty list removes them.
+	var tags []entities.Tag
+	setTags := post.Tags != nil
+	if setTags {
+		var err error
+		if tags, err = postTags(post.Tags); err != nil {
+			return nil, err
+		}
+	}
+
 	tx, err := s.DB.BeginTx(ctx)
 	if err != nil {
 		logger.WithError(err).Error("Failed to begin transaction")
@@ -86,6 +146,15 @@ func (s *PostServiceImpl) Update(c context.Context, post *entities.Post) (*entit
 		}
 	}()
 
+	existing, err := s.findPostForWrite(ctx, tx, logger, actor, post.ID, post.Version, entities.AuditActionPostUpdate)
+	if err != nil {
+		return nil, err
+	}
+	post.User = existing.User
+	post.CreatedAt = existing.CreatedAt
+	post.Status = existing.Status
+	post.PublishAt = existing.PublishAt
+
 	result, err := s.PostRepository.Update(ctx, tx, post)
 
 	if err != nil {
@@ -93,15 +162,34 @@ func (s *PostServiceImpl) Update(c context.Context, post *entities.Post) (*entit
 			logger.Errorf("PostID %d not found", post.ID)
 			return nil, appErrors.NewNotFoundError("Post not found", err)
 		}
+		if errors.Is(err, appErrors.ErrVersionConflict) {
+			return nil, postVersionConflict(err)
+		}
 
 		logger.WithError(err).Error("Database error")
 		return nil, err
 	}
 
+	if err = s.saveRevision(ctx, tx, result, editorOf(actor)); err != nil {
+		logger.WithError(err).Error("Failed to save post revision")
+		return nil, err
+	}
+
+	result.Tags = existing.Tags
+	result.CommentCount = existing.CommentCount
+	if setTags {
+		if err = s.TagRepository.SetPostTags(ctx, tx, result.ID, tags); err != nil {
+			logger.WithError(err).Error("Failed to set post tags")
+			return nil, err
+		}
+		result.Tags = tagSlugs(tags)
+	}
+
 	if err = tx.Commit(); err != nil {
 		logger.WithError(err).Error("Failed to commit transaction")
 		return nil, err
 	}
+	// Tag changes are covered as well, the tagged listings share the prefix.
 	err = s.Cache.InvalidateByPrefix(c, "posts:") // Assuming "posts:" is the prefix for all your post-related keys
 	if err != nil {
 		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale data might be served.")
@@ -109,10 +197,84 @@ func (s *PostServiceImpl) Update(c context.Context, post *entities.Post) (*entit
 		logger.Debug("Successfully invalidated 'posts:' cache keys.")
 	}
 
+	s.auditPost(ctx, entities.AuditActionPostUpdate, post.ID, entities.AuditOutcomeSuccess)
+
 	return result, nil
 }
 
-func (s *PostServiceImpl) Delete(c context.Context, id uuid.UUID) error {
+// Patch changes only the fields set in patch. Only the author of the post, or a caller
+// allowed to manage every post, may patch it. An empty patch returns the post unchanged.
+// A non-zero version must be the current version.
+func (s *PostServiceImpl) Patch(c context.Context, actor *entities.TokenClaims, id uuid.UUID, version int, patch entities.PostPatch) (*entities.Post, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	existing, err := s.findPostForWrite(ctx, tx, logger, actor, id, version, entities.AuditActionPostUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.IsEmpty() {
+		if err = tx.Commit(); err != nil {
+			logger.WithError(err).Error("Failed to commit transaction")
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	result, err := s.PostRepository.Patch(ctx, tx, id, version, patch)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			err = appErrors.NewNotFoundError("Post not found", err)
+			return nil, err
+		}
+		if errors.Is(err, appErrors.ErrVersionConflict) {
+			err = postVersionConflict(err)
+			return nil, err
+		}
+		logger.WithError(err).Error("Database error")
+		return nil, err
+	}
+	result.User = existing.User
+	result.Tags = existing.Tags
+	result.CommentCount = existing.CommentCount
+
+	if err = s.saveRevision(ctx, tx, result, editorOf(actor)); err != nil {
+		logger.WithError(err).Error("Failed to save post revision")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	if err := s.Cache.InvalidateByPrefix(c, "posts:"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale data might be served.")
+	}
+
+	s.auditPost(ctx, entities.AuditActionPostUpdate, id, entities.AuditOutcomeSuccess)
+
+	return result, nil
+}
+
+// Delete removes a post. Only its author, or a caller allowed to manage every post, may delete it.
+// A non-zero version must be the current version.
+func (s *PostServiceImpl) Delete(c context.Context, actor *entities.TokenClaims, id uuid.UUID, version int) error {
 	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
 	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
@@ -132,12 +294,19 @@ func (s *PostServiceImpl) Delete(c context.Context, id uuid.UUID) error {
 		}
 	}()
 
-	err = s.PostRepository.Delete(ctx, tx, id)
+	if _, err = s.findPostForWrite(ctx, tx, logger, actor, id, version, entities.AuditActionPostDelete); err != nil {
+		return err
+	}
+
+	err = s.PostRepository.Delete(ctx, tx, id, version)
 	if err != nil {
 		if errors.Is(err, appErrors.ErrDataNotFound) {
 			logger.Errorf("PostID %d not found", id)
 			return appErrors.NewNotFoundError("Post not found", err)
 		}
+		if errors.Is(err, appErrors.ErrVersionConflict) {
+			return postVersionConflict(err)
+		}
 
 		logger.WithError(err).Error("Database error")
 		return err
@@ -148,10 +317,18 @@ func (s *PostServiceImpl) Delete(c context.Context, id uuid.UUID) error {
 		return err
 	}
 
+	if err := s.Cache.InvalidateByPrefix(c, "posts:"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale data might be served.")
+	}
+
+	s.auditPost(ctx, entities.AuditActionPostDelete, id, entities.AuditOutcomeSuccess)
+
 	return nil
 }
 
-func (s *PostServiceImpl) GetById(c context.Context, id uuid.UUID) (*entities.Post, error) {
+// Restore takes a post out of the trash. Only its author, or a caller allowed to manage every
+// post, may restore it.
+func (s *PostServiceImpl) Restore(c context.Context, actor *entities.TokenClaims, id uuid.UUID) (*entities.Post, error) {
 	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
 	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
@@ -165,48 +342,216 @@ func (s *PostServiceImpl) GetById(c context.Context, id uuid.UUID) (*entities.Po
 
 	defer func() {
 		if r := recover(); r != nil || err != nil {
-			logger.Errorf("Transaction rollback due to error: %v", err)
-			logger.Errorf("Transaction rollback due to panic: %v", r)
 			tx.Rollback()
 		}
 	}()
 
-	result, err := s.PostRepository.GetById(ctx, tx, id)
+	// The post is only readable once restored, the check is rolled back with it when refused.
+	result, err := s.PostRepository.Restore(ctx, tx, id)
 	if err != nil {
 		if errors.Is(err, appErrors.ErrDataNotFound) {
-			logger.Errorf("PostId %d not found", id)
+			err = appErrors.NewNotFoundError("Post not found in trash", err)
+			return nil, err
+		}
+		logger.WithError(err).Error("Failed to restore post")
+		return nil, err
+	}
+
+	if !canModifyPost(actor, result) {
+		logger.Warnf("User %s is not allowed to restore post %s", actorID(actor), id)
+		s.auditPost(ctx, entities.AuditActionPostRestore, id, entities.AuditOutcomeFailure)
+		err = appErrors.NewForbiddenError("You are not allowed to modify this post", nil)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	if err := s.Cache.InvalidateByPrefix(c, "posts:"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale data might be served.")
+	}
+
+	s.auditPost(ctx, entities.AuditActionPostRestore, id, entities.AuditOutcomeSuccess)
+
+	return result, nil
+}
+
+// findPostForWrite loads the post about to be changed and checks that actor may change it
+// and, unless version is 0, that it is still at version. Permission refusals are audited as a failed action.
+func (s *PostServiceImpl) findPostForWrite(ctx context.Context, tx ports.Transaction, logger logrus.FieldLogger, actor *entities.TokenClaims, id uuid.UUID, version int, action string) (*entities.Post, error) {
+	post, err := s.PostRepository.GetById(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			logger.Warnf("PostID %s not found", id)
 			return nil, appErrors.NewNotFoundError("Post not found", err)
 		}
+		logger.WithError(err).Error("Database error")
+		return nil, err
+	}
+
+	if !canModifyPost(actor, post) {
+		logger.Warnf("User %s is not allowed to modify post %s", actorID(actor), id)
+		s.auditPost(ctx, action, id, entities.AuditOutcomeFailure)
+		return nil, appErrors.NewForbiddenError("You are not allowed to modify this post", nil)
+	}
+
+	if version > 0 && post.Version != version {
+		logger.Warnf("Post %s is at version %d, not %d", id, post.Version, version)
+		return nil, postVersionConflict(appErrors.ErrVersionConflict)
+	}
+
+	return post, nil
+}
+
+func postVersionConflict(err error) error {
+	return appErrors.NewPreconditionFailedError("Post was modified by someone else, reload it and try again", err)
+}
 
+// findReadablePost loads a post actor may read. Posts actor may not read are reported as
+// missing, so that the existence of a draft is not given away.
+func (s *PostServiceImpl) findReadablePost(ctx context.Context, tx ports.Transaction, logger logrus.FieldLogger, actor *entities.TokenClaims, id uuid.UUID) (*entities.Post, error) {
+	post, err := s.PostRepository.GetById(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			logger.Warnf("PostID %s not found", id)
+			return nil, appErrors.NewNotFoundError("Post not found", err)
+		}
 		logger.WithError(err).Error("Database error")
 		return nil, err
 	}
 
+	if !canReadPost(actor, post) {
+		logger.Warnf("User %s is not allowed to read %s post %s", actorID(actor), post.Status, id)
+		return nil, appErrors.NewNotFoundError("Post not found", appErrors.ErrDataNotFound)
+	}
+
+	return post, nil
+}
+
+// canReadPost reports whether post is published or actor may modify it.
+func canReadPost(actor *entities.TokenClaims, post *entities.Post) bool {
+	return post.Status == entities.PostStatusPublished || canModifyPost(actor, post)
+}
+
+// canModifyPost reports whether actor is the author of post or may manage every post.
+func canModifyPost(actor *entities.TokenClaims, post *entities.Post) bool {
+	if actor == nil {
+		return false
+	}
+	if post.User != nil && post.User.ID.String() == actor.UserID {
+		return true
+	}
+	return actor.HasPermission(entities.PermissionPostsManage)
+}
+
+func (s *PostServiceImpl) auditPost(ctx context.Context, action string, id uuid.UUID, outcome string) {
+	recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+		Action:     action,
+		TargetType: entities.AuditTargetPost,
+		TargetID:   id.String(),
+		Outcome:    outcome,
+	})
+}
+
+func actorID(actor *entities.TokenClaims) string {
+	if actor == nil {
+		return "<anonymous>"
+	}
+	return actor.UserID
+}
+
+// GetById returns a post that actor may read, drafts and archived posts are hidden from
+// everyone who may not update them.
+func (s *PostServiceImpl) GetById(c context.Context, actor *entities.TokenClaims, id uuid.UUID) (*entities.Post, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			logger.Errorf("Transaction rollback due to error: %v", err)
+			logger.Errorf("Transaction rollback due to panic: %v", r)
+			tx.Rollback()
+		}
+	}()
+
+	result, err := s.findReadablePost(ctx, tx, logger, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(); err != nil {
 		logger.WithError(err).Error("Failed to commit transaction")
 		return nil, err
 	}
 
+	s.annotateReactions(c, logger, actor, result)
+
 	return result, nil
 }
 
-func (s *PostServiceImpl) GetAll(c context.Context, search string, page, limit int) ([]entities.Post, error) {
+// annotateReactions sets the reactions on posts read by actor. Failing to is not fatal, the
+// posts keep the reconciled counts they were read with.
+func (s *PostServiceImpl) annotateReactions(ctx context.Context, logger logrus.FieldLogger, actor *entities.TokenClaims, posts ...*entities.Post) {
+	if s.ReactionService == nil || len(posts) == 0 {
+		return
+	}
+	if err := s.ReactionService.Annotate(ctx, actor, posts...); err != nil {
+		logger.WithError(err).Warn("Failed to annotate post reactions")
+	}
+}
+
+// annotateReactionList is annotateReactions for a listing.
+func (s *PostServiceImpl) annotateReactionList(ctx context.Context, logger logrus.FieldLogger, actor *entities.TokenClaims, posts []entities.Post) {
+	refs := make([]*entities.Post, len(posts))
+	for i := range posts {
+		refs[i] = &posts[i]
+	}
+	s.annotateReactions(ctx, logger, actor, refs...)
+}
+
+// GetAll lists the published posts matching filter. Tags may be given as names or slugs. The
+// reactions of actor are set after caching, the cached listings are shared by everyone.
+func (s *PostServiceImpl) GetAll(c context.Context, actor *entities.TokenClaims, filter entities.PostFilter, page, limit int) ([]entities.Post, error) {
 	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
 	defer cancel()
 
 	var posts []entities.Post
 
-	// cache key based on payload
-	queryParams := fmt.Sprintf("search=%s:page=%d:limit=%d", search, page, limit)
+	filter.Search = strings.TrimSpace(filter.Search)
+	filter.Tags = filterTagSlugs(filter.Tags)
+
+	// cache key based on payload, listings filtered by tag get their own prefix so that new
+	// tagged posts only invalidate those
+	queryParams := fmt.Sprintf("search=%s:page=%d:limit=%d", filter.Search, page, limit)
+	prefix := "posts:"
+	if len(filter.Tags) > 0 {
+		match := "any"
+		if filter.MatchAllTags {
+			match = "all"
+		}
+		queryParams += fmt.Sprintf(":tags=%s:match=%s", strings.Join(filter.Tags, ","), match)
+		prefix = postsByTagCachePrefix
+	}
 	hasher := sha256.New()
 	hasher.Write([]byte(queryParams))
-	cacheKey := "posts:" + hex.EncodeToString(hasher.Sum(nil))
+	cacheKey := prefix + hex.EncodeToString(hasher.Sum(nil))
 
 	// if cached err, won't interupt and using db instead
 	postsCached, errCache := s.Cache.Get(c, cacheKey)
 	if errCache = json.Unmarshal([]byte(postsCached), &posts); errCache == nil {
-		return posts, errCache
+		s.annotateReactionList(c, logger, actor, posts)
+		return posts, nil
 	}
 
 	tx, err := s.DB.BeginTx(ctx)
@@ -228,7 +573,7 @@ func (s *PostServiceImpl) GetAll(c context.Context, search string, page, limit i
 		Limit: limit,
 	}
 
-	posts, err = s.PostRepository.GetAll(ctx, tx, search, pagination)
+	posts, err = s.PostRepository.GetAll(ctx, tx, filter, pagination)
 
 	if err != nil {
 		logger.WithError(err).Error("Failed to get all posts")
@@ -247,13 +592,20 @@ func (s *PostServiceImpl) GetAll(c context.Context, search string, page, limit i
 		return nil, err
 	}
 
+	s.annotateReactionList(c, logger, actor, posts)
+
 	return posts, nil
 }
 
 // StartAsyncUpload begins an async upload for a post attachment, returning an upload ID for tracking.
-func (s *PostServiceImpl) StartAsyncUpload(ctx context.Context, postID uuid.UUID, fileName, fileType string, fileData []byte) (uploadID uuid.UUID, err error) {
+// Only whoever may update the post may attach files to it.
+func (s *PostServiceImpl) StartAsyncUpload(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, fileName, fileType string, fileData []byte) (uploadID uuid.UUID, err error) {
+	if err = s.checkUploadAllowed(ctx, actor, postID); err != nil {
+		return uuid.Nil, err
+	}
+
 	uploadID = uuid.New()
-	requestID, _ := ctx.Value("request_id").(string) // or use your logger's key
+	requestID := logger.RequestIDFromContext(ctx)
 	job := struct {
 		UploadID  string `json:"upload_id"`
 		PostID    string `json:"post_id"`
@@ -287,6 +639,35 @@ func (s *PostServiceImpl) StartAsyncUpload(ctx context.Context, postID uuid.UUID
 	return uploadID, nil
 }
 
+// checkUploadAllowed applies the ownership rules of Update to an upload for postID.
+func (s *PostServiceImpl) checkUploadAllowed(c context.Context, actor *entities.TokenClaims, postID uuid.UUID) (err error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = s.findPostForWrite(ctx, tx, logger, actor, postID, 0, entities.AuditActionPostUpdate); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+	return nil
+}
+
 // GetUploadStatus returns the current status of an async upload by upload ID.
 func (s *PostServiceImpl) GetUploadStatus(ctx context.Context, uploadID uuid.UUID) (entities.UploadStatus, error) {
 	statusKey := "upload_status:" + uploadID.String()
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const postPublishLockKey = "lock:post_publish"
+
+// PostPublisherServiceImpl publishes scheduled drafts, it runs in the post publisher worker.
+type PostPublisherServiceImpl struct {
+	DB             ports.Database
+	PostRepository ports.PostRepository
+	ports.Cache
+	// Locking keeps several workers from publishing at the same time. Nil publishes without a lock.
+	Locking ports.Locking
+	// AuditLogger records every post published. Nil disables it.
+	AuditLogger ports.AuditLogger
+	CtxTimeout  time.Duration
+}
+
+func (s *PostPublisherServiceImpl) PublishDue(c context.Context) ([]uuid.UUID, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	if s.Locking != nil {
+		acquired, lockValue, err := s.Locking.AcquireLock(postPublishLockKey, s.CtxTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if !acquired {
+			logger.Debug("Post publishing is running on another worker")
+			return nil, nil
+		}
+		defer func() {
+			if err := s.Locking.ReleaseLock(postPublishLockKey, lockValue); err != nil {
+				logger.WithError(err).Warn("Failed to release post publish lock")
+			}
+		}()
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	published, err := s.PostRepository.PublishDue(ctx, tx, time.Now())
+	if err != nil {
+		logger.WithError(err).Error("Failed to publish due posts")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	if len(published) == 0 {
+		return published, nil
+	}
+
+	logger.Infof("Published %d scheduled posts", len(published))
+	if err := s.Cache.InvalidateByPrefix(c, "posts:"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale data might be served.")
+	}
+	for _, id := range published {
+		recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+			Action:     entities.AuditActionPostPublish,
+			TargetType: entities.AuditTargetPost,
+			TargetID:   id.String(),
+			Outcome:    entities.AuditOutcomeSuccess,
+		})
+	}
+
+	return published, nil
+}
+
+// Run publishes due posts every interval until ctx is done. ctx carries the worker's logger.
+func (s *PostPublisherServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PublishDue(ctx); err != nil {
+			logger.WithError(err).Error("Publishing scheduled posts failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostPublisher_PublishDue(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.PostPublisherServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	due := []uuid.UUID{uuid.New(), uuid.New()}
+	before := time.Now()
+	mockPostRepo.On("PublishDue", mock.Anything, mockTx, mock.MatchedBy(func(now time.Time) bool {
+		return !now.Before(before) && !now.After(time.Now())
+	})).Return(due, nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+	for _, id := range due {
+		mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+			Action:     entities.AuditActionPostPublish,
+			TargetType: entities.AuditTargetPost,
+			TargetID:   id.String(),
+			Outcome:    entities.AuditOutcomeSuccess,
+		}).Once()
+	}
+
+	published, err := service.PublishDue(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, due, published)
+	mockTx.AssertCalled(t, "Commit")
+	mockCache.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestPostPublisher_NothingDue(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostPublisherServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockPostRepo.On("PublishDue", mock.Anything, mockTx, mock.Anything).Return([]uuid.UUID{}, nil).Once()
+
+	published, err := service.PublishDue(ctx)
+
+	require.NoError(t, err)
+	assert.Empty(t, published)
+	mockCache.AssertNotCalled(t, "InvalidateByPrefix", mock.Anything, mock.Anything)
+}
+
+func TestPostPublisher_SkipsWhenLockIsHeld(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockLock := new(mocks.MockLocking)
+
+	service := &services.PostPublisherServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		Locking:        mockLock,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockLock.On("AcquireLock", "lock:post_publish", service.CtxTimeout).Return(false, "", nil).Once()
+
+	published, err := service.PublishDue(ctx)
+
+	require.NoError(t, err)
+	assert.Nil(t, published)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+	mockPostRepo.AssertNotCalled(t, "PublishDue", mock.Anything, mock.Anything, mock.Anything)
+	mockLock.AssertNotCalled(t, "ReleaseLock", mock.Anything, mock.Anything)
+}
+
+func TestPostPublisher_ReleasesLock(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockLock := new(mocks.MockLocking)
+
+	service := &services.PostPublisherServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		Locking:        mockLock,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockLock.On("AcquireLock", "lock:post_publish", service.CtxTimeout).Return(true, "token", nil).Once()
+	mockLock.On("ReleaseLock", "lock:post_publish", "token").Return(nil).Once()
+	mockPostRepo.On("PublishDue", mock.Anything, mockTx, mock.Anything).Return([]uuid.UUID{}, nil).Once()
+
+	_, err := service.PublishDue(ctx)
+
+	require.NoError(t, err)
+	mockLock.AssertExpectations(t)
+}
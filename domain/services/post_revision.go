@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/sirupsen/logrus"
+)
+
+// ListRevisions returns the revisions of a post, newest first. The history of a post is only
+// readable by those who may read the post.
+func (s *PostServiceImpl) ListRevisions(c context.Context, actor *entities.TokenClaims, id uuid.UUID) ([]*entities.PostRevision, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// The history of a post in the trash is hidden along with it.
+	if _, err = s.findReadablePost(ctx, tx, logger, actor, id); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.PostRevisionRepository.FindByPost(ctx, tx, id)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list post revisions")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetRevision returns a revision of a post with a unified diff from it to the current version.
+func (s *PostServiceImpl) GetRevision(c context.Context, actor *entities.TokenClaims, id uuid.UUID, revision int) (*entities.PostRevisionDiff, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	current, err := s.findReadablePost(ctx, tx, logger, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.findRevision(ctx, tx, logger, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	diff, err := postRevisionDiff(rev, current)
+	if err != nil {
+		logger.WithError(err).Error("Failed to diff post revision")
+		return nil, err
+	}
+
+	return &entities.PostRevisionDiff{
+		Revision:       rev,
+		CurrentVersion: current.Version,
+		Diff:           diff,
+	}, nil
+}
+
+// RestoreRevision writes the title and body of an earlier revision as a new version of the post.
+// Only its author, or a caller allowed to manage every post, may do so. A non-zero version must
+// be the current version.
+func (s *PostServiceImpl) RestoreRevision(c context.Context, actor *entities.TokenClaims, id uuid.UUID, revision, version int) (*entities.Post, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	existing, err := s.findPostForWrite(ctx, tx, logger, actor, id, version, entities.AuditActionPostRevert)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.findRevision(ctx, tx, logger, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// The version read above guards the update when the caller sent none.
+	result, err := s.PostRepository.Update(ctx, tx, &entities.Post{
+		ID:      id,
+		Title:   rev.Title,
+		Body:    rev.Body,
+		Version: existing.Version,
+	})
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			err = appErrors.NewNotFoundError("Post not found", err)
+			return nil, err
+		}
+		if errors.Is(err, appErrors.ErrVersionConflict) {
+			err = postVersionConflict(err)
+			return nil, err
+		}
+		logger.WithError(err).Error("Database error")
+		return nil, err
+	}
+	result.User = existing.User
+	result.CreatedAt = existing.CreatedAt
+	result.Status = existing.Status
+	result.PublishAt = existing.PublishAt
+	result.Tags = existing.Tags
+	result.CommentCount = existing.CommentCount
+
+	if err = s.saveRevision(ctx, tx, result, editorOf(actor)); err != nil {
+		logger.WithError(err).Error("Failed to save post revision")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	if err := s.Cache.InvalidateByPrefix(c, "posts:"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale data might be served.")
+	}
+
+	recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+		Action:     entities.AuditActionPostRevert,
+		TargetType: entities.AuditTargetPost,
+		TargetID:   id.String(),
+		Outcome:    entities.AuditOutcomeSuccess,
+		Details:    map[string]string{"revision": strconv.Itoa(revision)},
+	})
+
+	return result, nil
+}
+
+func (s *PostServiceImpl) findRevision(ctx context.Context, tx ports.Transaction, logger logrus.FieldLogger, id uuid.UUID, revision int) (*entities.PostRevision, error) {
+	rev, err := s.PostRevisionRepository.FindByRevision(ctx, tx, id, revision)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			return nil, appErrors.NewNotFoundError("Revision not found", err)
+		}
+		logger.WithError(err).Error("Database error")
+		return nil, err
+	}
+	return rev, nil
+}
+
+// saveRevision stores post as written at its current version, in the transaction that wrote it.
+func (s *PostServiceImpl) saveRevision(ctx context.Context, tx ports.Transaction, post *entities.Post, editorID uuid.UUID) error {
+	_, err := s.PostRevisionRepository.Save(ctx, tx, &entities.PostRevision{
+		PostID:   post.ID,
+		Revision: post.Version,
+		Title:    post.Title,
+		Body:     post.Body,
+		EditorID: editorID,
+	})
+	return err
+}
+
+// editorOf is the user a change is attributed to, the impersonated user while impersonating.
+func editorOf(actor *entities.TokenClaims) uuid.UUID {
+	if actor == nil {
+		return uuid.Nil
+	}
+	id, err := uuid.Parse(actor.UserID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// postRevisionDiff is a unified diff of the title and body of a post from rev to current,
+// one section per field that changed.
+func postRevisionDiff(rev *entities.PostRevision, current *entities.Post) (string, error) {
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"title", rev.Title, current.Title},
+		{"body", rev.Body, current.Body},
+	}
+
+	var b strings.Builder
+	for _, field := range fields {
+		if field.from == field.to {
+			continue
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        diffLines(field.from),
+			B:        diffLines(field.to),
+			FromFile: field.name,
+			FromDate: fmt.Sprintf("revision %d", rev.Revision),
+			ToFile:   field.name,
+			ToDate:   fmt.Sprintf("version %d", current.Version),
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(diff)
+	}
+	return b.String(), nil
+}
+
+// diffLines splits text into lines that all end in a newline, unlike difflib.SplitLines it
+// adds no empty line after a trailing newline.
+func diffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
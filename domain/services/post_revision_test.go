@@ -0,0 +1,278 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostService_Update_SavesRevision(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockRevisionRepo := new(mocks.MockPostRevisionRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: mockRevisionRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
+	mockPostRepo.On("Update", mock.Anything, mockTx, mock.Anything).Return(&entities.Post{ID: postID, Title: "Edited", Body: "New body", Version: 3}, nil).Once()
+	mockRevisionRepo.On("Save", mock.Anything, mockTx, &entities.PostRevision{
+		PostID:   postID,
+		Revision: 3,
+		Title:    "Edited",
+		Body:     "New body",
+		EditorID: authorID,
+	}).Return(&entities.PostRevision{}, nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+
+	_, err := service.Update(ctx, authorClaims(authorID), &entities.Post{ID: postID, Title: "Edited", Body: "New body"})
+
+	require.NoError(t, err)
+	mockRevisionRepo.AssertExpectations(t)
+}
+
+func TestPostService_Update_RevisionErrorRollsBack(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockRevisionRepo := new(mocks.MockPostRevisionRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: mockRevisionRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
+	mockPostRepo.On("Update", mock.Anything, mockTx, mock.Anything).Return(&entities.Post{ID: postID, Title: "Edited", Version: 2}, nil).Once()
+	mockRevisionRepo.On("Save", mock.Anything, mockTx, mock.Anything).Return(nil, assert.AnError).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+
+	_, err := service.Update(ctx, authorClaims(authorID), &entities.Post{ID: postID, Title: "Edited"})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	mockTx.AssertExpectations(t)
+	mockTx.AssertNotCalled(t, "Commit")
+}
+
+func TestPostService_GetRevision_Diff(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockRevisionRepo := new(mocks.MockPostRevisionRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: mockRevisionRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	current := &entities.Post{ID: postID, Title: "Same title", Body: "one\ntwo\nthree\n", Version: 4, Status: entities.PostStatusPublished}
+	revision := &entities.PostRevision{PostID: postID, Revision: 2, Title: "Same title", Body: "one\n2\nthree\n"}
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(current, nil).Once()
+	mockRevisionRepo.On("FindByRevision", mock.Anything, mockTx, postID, 2).Return(revision, nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+
+	result, err := service.GetRevision(ctx, nil, postID, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, revision, result.Revision)
+	assert.Equal(t, 4, result.CurrentVersion)
+	assert.Equal(t, "--- body\trevision 2\n+++ body\tversion 4\n@@ -1,3 +1,3 @@\n one\n-2\n+two\n three\n", result.Diff)
+}
+
+func TestPostService_GetRevision_NotFound(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockRevisionRepo := new(mocks.MockPostRevisionRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: mockRevisionRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(&entities.Post{ID: postID, Version: 1, Status: entities.PostStatusPublished}, nil).Once()
+	mockRevisionRepo.On("FindByRevision", mock.Anything, mockTx, postID, 9).Return(nil, appErrors.ErrDataNotFound).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+
+	result, err := service.GetRevision(ctx, nil, postID, 9)
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.StatusCode)
+	assert.Equal(t, "Revision not found", appErr.Message)
+}
+
+func TestPostService_ListRevisions_PostNotFound(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockRevisionRepo := new(mocks.MockPostRevisionRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: mockRevisionRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(nil, appErrors.ErrDataNotFound).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+
+	result, err := service.ListRevisions(ctx, nil, postID)
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.StatusCode)
+	mockRevisionRepo.AssertNotCalled(t, "FindByPost", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostService_RestoreRevision(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockRevisionRepo := new(mocks.MockPostRevisionRepository)
+	mockCache := new(mocks.MockCache)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: mockRevisionRepo,
+		Cache:                  mockCache,
+		AuditLogger:            mockAudit,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	current := postOwnedBy(postID, authorID)
+	current.Version = 5
+
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(current, nil).Once()
+	mockRevisionRepo.On("FindByRevision", mock.Anything, mockTx, postID, 2).
+		Return(&entities.PostRevision{PostID: postID, Revision: 2, Title: "Old title", Body: "Old body"}, nil).Once()
+	// The update is guarded by the version just read.
+	mockPostRepo.On("Update", mock.Anything, mockTx, &entities.Post{ID: postID, Title: "Old title", Body: "Old body", Version: 5}).
+		Return(&entities.Post{ID: postID, Title: "Old title", Body: "Old body", Version: 6}, nil).Once()
+	mockRevisionRepo.On("Save", mock.Anything, mockTx, &entities.PostRevision{
+		PostID:   postID,
+		Revision: 6,
+		Title:    "Old title",
+		Body:     "Old body",
+		EditorID: authorID,
+	}).Return(&entities.PostRevision{}, nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:     entities.AuditActionPostRevert,
+		TargetType: entities.AuditTargetPost,
+		TargetID:   postID.String(),
+		Outcome:    entities.AuditOutcomeSuccess,
+		Details:    map[string]string{"revision": "2"},
+	}).Once()
+
+	result, err := service.RestoreRevision(ctx, authorClaims(authorID), postID, 2, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.Version)
+	assert.Equal(t, authorID, result.User.ID)
+	mockPostRepo.AssertExpectations(t)
+	mockRevisionRepo.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestPostService_RestoreRevision_ForbiddenForOtherUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockRevisionRepo := new(mocks.MockPostRevisionRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: mockRevisionRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, uuid.New()), nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+
+	result, err := service.RestoreRevision(ctx, authorClaims(uuid.New()), postID, 1, 0)
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.StatusCode)
+	mockRevisionRepo.AssertNotCalled(t, "FindByRevision", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// SetStatus moves a post to status. A draft may be scheduled with publishAt, which is cleared
+// for any other status. Only its author, or a caller allowed to manage every post, may do so.
+// A non-zero version must be the current version.
+func (s *PostServiceImpl) SetStatus(c context.Context, actor *entities.TokenClaims, id uuid.UUID, version int, status entities.PostStatus, publishAt *time.Time) (*entities.Post, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	if err := checkPostSchedule(status, publishAt); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	existing, err := s.findPostForWrite(ctx, tx, logger, actor, id, version, entities.AuditActionPostStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.PostRepository.SetStatus(ctx, tx, id, version, status, publishAt)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			err = appErrors.NewNotFoundError("Post not found", err)
+			return nil, err
+		}
+		if errors.Is(err, appErrors.ErrVersionConflict) {
+			err = postVersionConflict(err)
+			return nil, err
+		}
+		logger.WithError(err).Error("Database error")
+		return nil, err
+	}
+	result.User = existing.User
+	result.Tags = existing.Tags
+	result.CommentCount = existing.CommentCount
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	if err := s.Cache.InvalidateByPrefix(c, "posts:"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale data might be served.")
+	}
+
+	recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+		Action:     entities.AuditActionPostStatus,
+		TargetType: entities.AuditTargetPost,
+		TargetID:   id.String(),
+		Outcome:    entities.AuditOutcomeSuccess,
+		Details:    map[string]string{"from": string(existing.Status), "to": string(status)},
+	})
+
+	return result, nil
+}
+
+// ListOwn lists the posts of actor in the given status, newest first.
+func (s *PostServiceImpl) ListOwn(c context.Context, actor *entities.TokenClaims, status entities.PostStatus, page, limit int) ([]entities.Post, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	if !status.Valid() {
+		return nil, appErrors.NewBadRequestError("Status must be draft, published or archived", nil)
+	}
+	authorID := editorOf(actor)
+	if authorID == uuid.Nil {
+		return nil, appErrors.NewUnauthorizedError("Sign in to list your own posts", nil)
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	posts, err := s.PostRepository.GetByAuthor(ctx, tx, authorID, status, entities.PaginationParams{Page: page, Limit: limit})
+	if err != nil {
+		logger.WithError(err).Error("Failed to list own posts")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	s.annotateReactionList(c, logger, actor, posts)
+
+	return posts, nil
+}
+
+// checkPostSchedule refuses unknown statuses, and schedules on anything but a draft or in the past.
+func checkPostSchedule(status entities.PostStatus, publishAt *time.Time) error {
+	if !status.Valid() {
+		return appErrors.NewBadRequestError("Status must be draft, published or archived", nil)
+	}
+	if publishAt == nil {
+		return nil
+	}
+	if status != entities.PostStatusDraft {
+		return appErrors.NewBadRequestError("Only drafts can be scheduled, publish_at needs the draft status", nil)
+	}
+	if !publishAt.After(time.Now()) {
+		return appErrors.NewBadRequestError("publish_at must be in the future", nil)
+	}
+	return nil
+}
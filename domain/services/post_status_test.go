@@ -0,0 +1,352 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func draftOwnedBy(postID, authorID uuid.UUID) *entities.Post {
+	post := postOwnedBy(postID, authorID)
+	post.Status = entities.PostStatusDraft
+	return post
+}
+
+func TestPostService_Create_PublishAtMakesDraft(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		Cache:                  mockCache,
+		UserRepository:         mockUserRepo,
+		PostRevisionRepository: acceptRevisions(),
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	publishAt := time.Now().Add(time.Hour)
+	user := &entities.User{ID: uuid.New()}
+	post := &entities.Post{User: user, Title: "Later", PublishAt: &publishAt}
+	mockUserRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil).Once()
+	mockPostRepo.On("Save", mock.Anything, mockTx, mock.MatchedBy(func(p *entities.Post) bool {
+		return p.Status == entities.PostStatusDraft && p.PublishAt == &publishAt
+	})).Return(post, nil).Once()
+
+	_, err := service.Create(ctx, post)
+
+	require.NoError(t, err)
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestPostService_Create_DefaultsToPublished(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		Cache:                  mockCache,
+		UserRepository:         mockUserRepo,
+		PostRevisionRepository: acceptRevisions(),
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	user := &entities.User{ID: uuid.New()}
+	post := &entities.Post{User: user, Title: "Now"}
+	mockUserRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil).Once()
+	mockPostRepo.On("Save", mock.Anything, mockTx, post).Return(post, nil).Once()
+
+	result, err := service.Create(ctx, post)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.PostStatusPublished, result.Status)
+}
+
+func TestPostService_Create_RejectsScheduledNonDraft(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	publishAt := time.Now().Add(time.Hour)
+	_, err := service.Create(ctx, &entities.Post{
+		User:      &entities.User{ID: uuid.New()},
+		Status:    entities.PostStatusPublished,
+		PublishAt: &publishAt,
+	})
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 400, appErr.StatusCode)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestPostService_GetById_DraftHiddenFromOthers(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(draftOwnedBy(postID, uuid.New()), nil)
+
+	for name, actor := range map[string]*entities.TokenClaims{
+		"anonymous":  nil,
+		"other user": authorClaims(uuid.New()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := service.GetById(ctx, actor, postID)
+
+			assert.Nil(t, result)
+			var appErr *appErrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, 404, appErr.StatusCode)
+			assert.Equal(t, "Post not found", appErr.Message)
+		})
+	}
+}
+
+func TestPostService_GetById_DraftVisibleToAuthor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(draftOwnedBy(postID, authorID), nil).Once()
+
+	result, err := service.GetById(ctx, authorClaims(authorID), postID)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.PostStatusDraft, result.Status)
+}
+
+func TestPostService_SetStatus_Publish(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	existing := draftOwnedBy(postID, authorID)
+	existing.Version = 2
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(existing, nil).Once()
+	mockPostRepo.On("SetStatus", mock.Anything, mockTx, postID, 2, entities.PostStatusPublished, (*time.Time)(nil)).
+		Return(&entities.Post{ID: postID, Status: entities.PostStatusPublished, Version: 3}, nil).Once()
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:     entities.AuditActionPostStatus,
+		TargetType: entities.AuditTargetPost,
+		TargetID:   postID.String(),
+		Outcome:    entities.AuditOutcomeSuccess,
+		Details:    map[string]string{"from": "draft", "to": "published"},
+	}).Once()
+
+	result, err := service.SetStatus(ctx, authorClaims(authorID), postID, 2, entities.PostStatusPublished, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Version)
+	assert.Equal(t, existing.User, result.User)
+	mockTx.AssertCalled(t, "Commit")
+	mockAudit.AssertExpectations(t)
+}
+
+func TestPostService_SetStatus_ForbiddenForOtherUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	postID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(draftOwnedBy(postID, uuid.New()), nil).Once()
+
+	_, err := service.SetStatus(ctx, authorClaims(uuid.New()), postID, 0, entities.PostStatusPublished, nil)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 403, appErr.StatusCode)
+	mockPostRepo.AssertNotCalled(t, "SetStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostService_SetStatus_RejectsPastSchedule(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	publishAt := time.Now().Add(-time.Minute)
+	_, err := service.SetStatus(ctx, authorClaims(uuid.New()), uuid.New(), 0, entities.PostStatusDraft, &publishAt)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 400, appErr.StatusCode)
+	assert.Equal(t, "publish_at must be in the future", appErr.Message)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestPostService_ListOwn(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	authorID := uuid.New()
+	drafts := []entities.Post{{Title: "Draft", Status: entities.PostStatusDraft}}
+	mockPostRepo.On("GetByAuthor", mock.Anything, mockTx, authorID, entities.PostStatusDraft, entities.PaginationParams{Page: 2, Limit: 5}).Return(drafts, nil).Once()
+
+	got, err := service.ListOwn(ctx, authorClaims(authorID), entities.PostStatusDraft, 2, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, drafts, got)
+}
+
+func TestPostService_ListOwn_Anonymous(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Maybe()
+
+	_, err := service.ListOwn(ctx, nil, entities.PostStatusDraft, 1, 10)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 401, appErr.StatusCode)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
@@ -28,15 +28,16 @@ func TestPostService_Create_Success(t *testing.T) {
 	mockDB := new(mocks.MockDatabase)
 	mockPostRepo := new(mocks.MockPostRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
-	mockCache := new(mocks.MockCache) // Not used in Create, but good to include in service struct
+	mockCache := new(mocks.MockCache)
 	mockTx := new(mocks.MockTransaction)
 
 	service := &services.PostServiceImpl{
-		DB:             mockDB,
-		PostRepository: mockPostRepo,
-		UserRepository: mockUserRepo,
-		Cache:          mockCache,
-		CtxTimeout:     2 * time.Second,
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		UserRepository:         mockUserRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
 	}
 
 	user := &entities.User{ID: uuid.New(), Email: "author@example.com"}
@@ -53,6 +54,8 @@ func TestPostService_Create_Success(t *testing.T) {
 	mockTx.On("Rollback").Return(nil).Maybe() // Rollback might be called on error
 	mockUserRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil).Once()
 	mockPostRepo.On("Save", mock.Anything, mockTx, post).Return(post, nil).Once()
+	// Every listing may include the new post, not only those filtered by its tags.
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
 
 	// Call the service method
 	result, err := service.Create(ctx, post)
@@ -64,6 +67,7 @@ func TestPostService_Create_Success(t *testing.T) {
 	assert.Equal(t, post.Title, result.Title)
 
 	// Verify mock expectations
+	mockCache.AssertExpectations(t)
 	mockDB.AssertExpectations(t)
 	mockPostRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
@@ -219,11 +223,12 @@ func TestPostService_Create_CommitError(t *testing.T) {
 	mockTx := new(mocks.MockTransaction)
 
 	service := &services.PostServiceImpl{
-		DB:             mockDB,
-		PostRepository: mockPostRepo,
-		UserRepository: mockUserRepo,
-		Cache:          mockCache,
-		CtxTimeout:     2 * time.Second,
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		UserRepository:         mockUserRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
 	}
 
 	author := &entities.User{ID: uuid.New(), Email: "author@example.com"}
@@ -266,14 +271,17 @@ func TestPostService_Update_Success(t *testing.T) {
 	mockTx := new(mocks.MockTransaction)
 
 	service := &services.PostServiceImpl{
-		DB:             mockDB,
-		PostRepository: mockPostRepo,
-		UserRepository: mockUserRepo,
-		Cache:          mockCache,
-		CtxTimeout:     2 * time.Second,
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		UserRepository:         mockUserRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
 	}
 
 	postID := uuid.New()
+	authorID := uuid.New()
+	actor := authorClaims(authorID)
 	post := &entities.Post{
 		ID:    postID,
 		Title: "Updated Title",
@@ -282,13 +290,14 @@ func TestPostService_Update_Success(t *testing.T) {
 
 	// Setup mock expectations
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
 	mockTx.On("Commit").Return(nil).Once()
 	mockTx.On("Rollback").Return(nil).Maybe()
 	mockPostRepo.On("Update", mock.Anything, mockTx, post).Return(post, nil).Once()
 	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
 
 	// Call the service method
-	result, err := service.Update(ctx, post)
+	result, err := service.Update(ctx, actor, post)
 
 	// Assertions
 	assert.NoError(t, err)
@@ -321,10 +330,11 @@ func TestPostService_Update_BeginTxError(t *testing.T) {
 
 	post := &entities.Post{ID: uuid.New()}
 
+	actor := authorClaims(uuid.New())
 	expectedErr := errors.New("failed to begin transaction")
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, expectedErr).Once()
 
-	result, err := service.Update(ctx, post)
+	result, err := service.Update(ctx, actor, post)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -351,13 +361,15 @@ func TestPostService_Update_PostNotFound(t *testing.T) {
 	}
 
 	postID := uuid.New()
+	authorID := uuid.New()
+	actor := authorClaims(authorID)
 	post := &entities.Post{ID: postID}
 
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
 	mockTx.On("Rollback").Return(nil).Once()
-	mockPostRepo.On("Update", mock.Anything, mockTx, post).Return(nil, appErrors.ErrDataNotFound).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(nil, appErrors.ErrDataNotFound).Once()
 
-	result, err := service.Update(ctx, post)
+	result, err := service.Update(ctx, actor, post)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -369,6 +381,7 @@ func TestPostService_Update_PostNotFound(t *testing.T) {
 	mockDB.AssertExpectations(t)
 	mockPostRepo.AssertExpectations(t)
 	mockTx.AssertExpectations(t)
+	mockPostRepo.AssertNotCalled(t, "Update")
 	mockCache.AssertNotCalled(t, "InvalidateByPrefix") // Should not be called on failure
 }
 
@@ -389,14 +402,17 @@ func TestPostService_Update_GenericUpdateError(t *testing.T) {
 	}
 
 	postID := uuid.New()
+	authorID := uuid.New()
+	actor := authorClaims(authorID)
 	post := &entities.Post{ID: postID}
 
 	expectedErr := errors.New("database update error")
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
 	mockTx.On("Rollback").Return(nil).Once()
 	mockPostRepo.On("Update", mock.Anything, mockTx, post).Return(nil, expectedErr).Once()
 
-	result, err := service.Update(ctx, post)
+	result, err := service.Update(ctx, actor, post)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -417,23 +433,27 @@ func TestPostService_Update_CommitError(t *testing.T) {
 	mockTx := new(mocks.MockTransaction)
 
 	service := &services.PostServiceImpl{
-		DB:             mockDB,
-		PostRepository: mockPostRepo,
-		UserRepository: mockUserRepo,
-		Cache:          mockCache,
-		CtxTimeout:     2 * time.Second,
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		UserRepository:         mockUserRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
 	}
 
 	postID := uuid.New()
+	authorID := uuid.New()
+	actor := authorClaims(authorID)
 	post := &entities.Post{ID: postID}
 
 	expectedErr := errors.New("failed to commit transaction")
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
 	mockTx.On("Commit").Return(expectedErr).Once()
 	mockTx.On("Rollback").Return(nil).Once()
 	mockPostRepo.On("Update", mock.Anything, mockTx, post).Return(post, nil).Once()
 
-	result, err := service.Update(ctx, post)
+	result, err := service.Update(ctx, actor, post)
 
 	assert.Error(t, err)
 	assert.Nil(t, result) // Result should be nil on commit error
@@ -454,14 +474,17 @@ func TestPostService_Update_CacheInvalidationError(t *testing.T) {
 	mockTx := new(mocks.MockTransaction)
 
 	service := &services.PostServiceImpl{
-		DB:             mockDB,
-		PostRepository: mockPostRepo,
-		UserRepository: mockUserRepo,
-		Cache:          mockCache,
-		CtxTimeout:     2 * time.Second,
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		UserRepository:         mockUserRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
 	}
 
 	postID := uuid.New()
+	authorID := uuid.New()
+	actor := authorClaims(authorID)
 	post := &entities.Post{
 		ID:    postID,
 		Title: "Updated Title",
@@ -471,13 +494,14 @@ func TestPostService_Update_CacheInvalidationError(t *testing.T) {
 	// Setup mock expectations
 	expectedCacheErr := errors.New("failed to invalidate cache")
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
 	mockTx.On("Commit").Return(nil).Once()
 	mockTx.On("Rollback").Return(nil).Maybe()
 	mockPostRepo.On("Update", mock.Anything, mockTx, post).Return(post, nil).Once()
 	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(expectedCacheErr).Once()
 
 	// Call the service method
-	result, err := service.Update(ctx, post)
+	result, err := service.Update(ctx, actor, post)
 
 	// Assertions: The main operation should still succeed, error is just logged/warned
 	assert.NoError(t, err) // This is crucial: cache error does not return an error from Update
@@ -491,6 +515,112 @@ func TestPostService_Update_CacheInvalidationError(t *testing.T) {
 	mockTx.AssertExpectations(t)
 }
 
+func TestPostService_Update_ForbiddenForOtherUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+	post := &entities.Post{ID: postID, Title: "Hijacked"}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, uuid.New()), nil).Once()
+
+	result, err := service.Update(ctx, authorClaims(uuid.New()), post)
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.StatusCode)
+
+	mockTx.AssertExpectations(t)
+	mockPostRepo.AssertNotCalled(t, "Update")
+	mockCache.AssertNotCalled(t, "InvalidateByPrefix")
+}
+
+func TestPostService_Update_ForbiddenIsAudited(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, uuid.New()), nil).Once()
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:     entities.AuditActionPostUpdate,
+		TargetType: entities.AuditTargetPost,
+		TargetID:   postID.String(),
+		Outcome:    entities.AuditOutcomeFailure,
+	}).Once()
+
+	_, err := service.Update(ctx, authorClaims(uuid.New()), &entities.Post{ID: postID, Title: "Hijacked"})
+
+	assert.Error(t, err)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestPostService_Update_AllowedForAdmin(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	post := &entities.Post{ID: postID, Title: "Moderated"}
+	admin := &entities.TokenClaims{
+		UserID:      uuid.NewString(),
+		Roles:       []string{entities.RoleAdmin},
+		Permissions: []string{entities.PermissionPostsManage},
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
+	mockPostRepo.On("Update", mock.Anything, mockTx, post).Return(post, nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+
+	result, err := service.Update(ctx, admin, post)
+
+	assert.NoError(t, err)
+	// The author stays the original one, not the admin who edited the post.
+	assert.Equal(t, authorID, result.User.ID)
+
+	mockPostRepo.AssertExpectations(t)
+	mockTx.AssertExpectations(t)
+}
+
 func TestPostService_Delete_Success(t *testing.T) {
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
 	mockDB := new(mocks.MockDatabase)
@@ -508,21 +638,63 @@ func TestPostService_Delete_Success(t *testing.T) {
 	}
 
 	postID := uuid.New()
+	authorID := uuid.New()
+	actor := authorClaims(authorID)
 
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
 	mockTx.On("Commit").Return(nil).Once()
 	mockTx.On("Rollback").Return(nil).Maybe()
-	mockPostRepo.On("Delete", mock.Anything, mockTx, postID).Return(nil).Once()
+	mockPostRepo.On("Delete", mock.Anything, mockTx, postID, 0).Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
 
-	err := service.Delete(ctx, postID)
+	err := service.Delete(ctx, actor, postID, 0)
 
 	assert.NoError(t, err)
 
+	mockCache.AssertExpectations(t)
 	mockDB.AssertExpectations(t)
 	mockPostRepo.AssertExpectations(t)
 	mockTx.AssertExpectations(t)
 }
 
+func TestPostService_Delete_IsAudited(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+	mockCache := new(mocks.MockCache)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+	authorID := uuid.New()
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
+	mockPostRepo.On("Delete", mock.Anything, mockTx, postID, 0).Return(nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:     entities.AuditActionPostDelete,
+		TargetType: entities.AuditTargetPost,
+		TargetID:   postID.String(),
+		Outcome:    entities.AuditOutcomeSuccess,
+	}).Once()
+
+	err := service.Delete(ctx, authorClaims(authorID), postID, 0)
+
+	assert.NoError(t, err)
+	mockAudit.AssertExpectations(t)
+}
+
 func TestPostService_Delete_BeginTxError(t *testing.T) {
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
 	mockDB := new(mocks.MockDatabase)
@@ -540,10 +712,11 @@ func TestPostService_Delete_BeginTxError(t *testing.T) {
 	}
 
 	postID := uuid.New()
+	actor := authorClaims(uuid.New())
 	expectedErr := errors.New("failed to begin transaction")
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, expectedErr).Once()
 
-	err := service.Delete(ctx, postID)
+	err := service.Delete(ctx, actor, postID, 0)
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
@@ -569,12 +742,14 @@ func TestPostService_Delete_PostNotFound(t *testing.T) {
 	}
 
 	postID := uuid.New()
+	authorID := uuid.New()
+	actor := authorClaims(authorID)
 
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
 	mockTx.On("Rollback").Return(nil).Once()
-	mockPostRepo.On("Delete", mock.Anything, mockTx, postID).Return(appErrors.ErrDataNotFound).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(nil, appErrors.ErrDataNotFound).Once()
 
-	err := service.Delete(ctx, postID)
+	err := service.Delete(ctx, actor, postID, 0)
 
 	assert.Error(t, err)
 
@@ -586,6 +761,7 @@ func TestPostService_Delete_PostNotFound(t *testing.T) {
 	mockDB.AssertExpectations(t)
 	mockPostRepo.AssertExpectations(t)
 	mockTx.AssertExpectations(t)
+	mockPostRepo.AssertNotCalled(t, "Delete")
 }
 
 func TestPostService_Delete_GenericDeleteError(t *testing.T) {
@@ -605,13 +781,16 @@ func TestPostService_Delete_GenericDeleteError(t *testing.T) {
 	}
 
 	postID := uuid.New()
+	authorID := uuid.New()
+	actor := authorClaims(authorID)
 	expectedErr := errors.New("database delete error")
 
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
 	mockTx.On("Rollback").Return(nil).Once()
-	mockPostRepo.On("Delete", mock.Anything, mockTx, postID).Return(expectedErr).Once()
+	mockPostRepo.On("Delete", mock.Anything, mockTx, postID, 0).Return(expectedErr).Once()
 
-	err := service.Delete(ctx, postID)
+	err := service.Delete(ctx, actor, postID, 0)
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
@@ -638,14 +817,17 @@ func TestPostService_Delete_CommitError(t *testing.T) {
 	}
 
 	postID := uuid.New()
+	authorID := uuid.New()
+	actor := authorClaims(authorID)
 	expectedErr := errors.New("failed to commit transaction")
 
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
-	mockPostRepo.On("Delete", mock.Anything, mockTx, postID).Return(nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
+	mockPostRepo.On("Delete", mock.Anything, mockTx, postID, 0).Return(nil).Once()
 	mockTx.On("Commit").Return(expectedErr).Once()
 	mockTx.On("Rollback").Return(nil).Once()
 
-	err := service.Delete(ctx, postID)
+	err := service.Delete(ctx, actor, postID, 0)
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
@@ -655,6 +837,69 @@ func TestPostService_Delete_CommitError(t *testing.T) {
 	mockTx.AssertExpectations(t)
 }
 
+func TestPostService_Delete_ForbiddenForOtherUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, uuid.New()), nil).Once()
+
+	err := service.Delete(ctx, authorClaims(uuid.New()), postID, 0)
+
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.StatusCode)
+
+	mockTx.AssertExpectations(t)
+	mockPostRepo.AssertNotCalled(t, "Delete")
+}
+
+func TestPostService_Delete_AllowedForAdmin(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+	admin := &entities.TokenClaims{
+		UserID:      uuid.NewString(),
+		Roles:       []string{entities.RoleAdmin},
+		Permissions: []string{entities.PermissionPostsManage},
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, uuid.New()), nil).Once()
+	mockPostRepo.On("Delete", mock.Anything, mockTx, postID, 0).Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+
+	err := service.Delete(ctx, admin, postID, 0)
+
+	assert.NoError(t, err)
+	mockPostRepo.AssertExpectations(t)
+	mockTx.AssertExpectations(t)
+}
+
 func TestPostService_GetById_Success(t *testing.T) {
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
 	mockDB := new(mocks.MockDatabase)
@@ -672,14 +917,14 @@ func TestPostService_GetById_Success(t *testing.T) {
 	}
 
 	postID := uuid.New()
-	expectedPost := &entities.Post{ID: postID, Title: "Found Post"}
+	expectedPost := &entities.Post{ID: postID, Title: "Found Post", Status: entities.PostStatusPublished}
 
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
 	mockTx.On("Commit").Return(nil).Once()
 	mockTx.On("Rollback").Return(nil).Maybe()
 	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(expectedPost, nil).Once()
 
-	result, err := service.GetById(ctx, postID)
+	result, err := service.GetById(ctx, nil, postID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -710,7 +955,7 @@ func TestPostService_GetById_BeginTxError(t *testing.T) {
 	expectedErr := errors.New("failed to begin transaction")
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, expectedErr).Once()
 
-	result, err := service.GetById(ctx, postID)
+	result, err := service.GetById(ctx, nil, postID)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -742,7 +987,7 @@ func TestPostService_GetById_PostNotFound(t *testing.T) {
 	mockTx.On("Rollback").Return(nil).Once()
 	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(nil, appErrors.ErrDataNotFound).Once()
 
-	result, err := service.GetById(ctx, postID)
+	result, err := service.GetById(ctx, nil, postID)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -780,7 +1025,7 @@ func TestPostService_GetById_GenericGetError(t *testing.T) {
 	mockTx.On("Rollback").Return(nil).Once()
 	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(nil, expectedErr).Once()
 
-	result, err := service.GetById(ctx, postID)
+	result, err := service.GetById(ctx, nil, postID)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -808,7 +1053,7 @@ func TestPostService_GetById_CommitError(t *testing.T) {
 	}
 
 	postID := uuid.New()
-	expectedPost := &entities.Post{ID: postID, Title: "Found Post"}
+	expectedPost := &entities.Post{ID: postID, Title: "Found Post", Status: entities.PostStatusPublished}
 	expectedErr := errors.New("failed to commit transaction")
 
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
@@ -816,7 +1061,7 @@ func TestPostService_GetById_CommitError(t *testing.T) {
 	mockTx.On("Rollback").Return(nil).Once()
 	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(expectedPost, nil).Once()
 
-	result, err := service.GetById(ctx, postID)
+	result, err := service.GetById(ctx, nil, postID)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -858,7 +1103,7 @@ func TestPostService_GetAll_CacheHit(t *testing.T) {
 
 	mockCache.On("Get", mock.Anything, cacheKey).Return(string(expectedPostsJSON), nil).Once()
 
-	result, err := service.GetAll(ctx, search, page, limit)
+	result, err := service.GetAll(ctx, nil, entities.PostFilter{Search: search}, page, limit)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -906,11 +1151,11 @@ func TestPostService_GetAll_CacheMiss_DBSucceeds(t *testing.T) {
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
 	mockTx.On("Commit").Return(nil).Once()
 	mockTx.On("Rollback").Return(nil).Maybe()
-	mockPostRepo.On("GetAll", mock.Anything, mockTx, search, entities.PaginationParams{Page: page, Limit: limit}).Return(expectedPosts, nil).Once()
+	mockPostRepo.On("GetAll", mock.Anything, mockTx, entities.PostFilter{Search: search}, entities.PaginationParams{Page: page, Limit: limit}).Return(expectedPosts, nil).Once()
 	// Cache set after successful DB fetch
 	mockCache.On("Set", mock.Anything, cacheKey, expectedPostsJSON, 30*time.Second).Return(nil).Once()
 
-	result, err := service.GetAll(ctx, search, page, limit)
+	result, err := service.GetAll(ctx, nil, entities.PostFilter{Search: search}, page, limit)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -954,7 +1199,7 @@ func TestPostService_GetAll_BeginTxError(t *testing.T) {
 	expectedErr := errors.New("failed to begin transaction")
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, expectedErr).Once()
 
-	result, err := service.GetAll(ctx, search, page, limit)
+	result, err := service.GetAll(ctx, nil, entities.PostFilter{Search: search}, page, limit)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -996,9 +1241,9 @@ func TestPostService_GetAll_RepoError(t *testing.T) {
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
 	mockTx.On("Rollback").Return(nil).Once()
 	expectedRepoErr := errors.New("database get all error")
-	mockPostRepo.On("GetAll", mock.Anything, mockTx, search, entities.PaginationParams{Page: page, Limit: limit}).Return(nil, expectedRepoErr).Once()
+	mockPostRepo.On("GetAll", mock.Anything, mockTx, entities.PostFilter{Search: search}, entities.PaginationParams{Page: page, Limit: limit}).Return(nil, expectedRepoErr).Once()
 
-	result, err := service.GetAll(ctx, search, page, limit)
+	result, err := service.GetAll(ctx, nil, entities.PostFilter{Search: search}, page, limit)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -1047,10 +1292,10 @@ func TestPostService_GetAll_CommitError(t *testing.T) {
 	expectedCommitErr := errors.New("failed to commit transaction")
 	mockTx.On("Commit").Return(expectedCommitErr).Once()
 	mockTx.On("Rollback").Return(nil).Once()
-	mockPostRepo.On("GetAll", mock.Anything, mockTx, search, entities.PaginationParams{Page: page, Limit: limit}).Return(expectedPosts, nil).Once()
+	mockPostRepo.On("GetAll", mock.Anything, mockTx, entities.PostFilter{Search: search}, entities.PaginationParams{Page: page, Limit: limit}).Return(expectedPosts, nil).Once()
 	mockCache.On("Set", mock.Anything, cacheKey, expectedPostsJSON, 30*time.Second).Return(nil).Once() // Cache set should still happen before commit
 
-	result, err := service.GetAll(ctx, search, page, limit)
+	result, err := service.GetAll(ctx, nil, entities.PostFilter{Search: search}, page, limit)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -1097,12 +1342,12 @@ func TestPostService_GetAll_CacheSetError(t *testing.T) {
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
 	mockTx.On("Commit").Return(nil).Once()
 	mockTx.On("Rollback").Return(nil).Maybe()
-	mockPostRepo.On("GetAll", mock.Anything, mockTx, search, entities.PaginationParams{Page: page, Limit: limit}).Return(expectedPosts, nil).Once()
+	mockPostRepo.On("GetAll", mock.Anything, mockTx, entities.PostFilter{Search: search}, entities.PaginationParams{Page: page, Limit: limit}).Return(expectedPosts, nil).Once()
 	// Simulate Cache Set error
 	expectedCacheSetErr := errors.New("failed to set cache")
 	mockCache.On("Set", mock.Anything, cacheKey, expectedPostsJSON, 30*time.Second).Return(expectedCacheSetErr).Once()
 
-	result, err := service.GetAll(ctx, search, page, limit)
+	result, err := service.GetAll(ctx, nil, entities.PostFilter{Search: search}, page, limit)
 
 	// Assertions: The main operation should still succeed, error is just logged/warned
 	assert.NoError(t, err) // Crucial: cache set error does not return an error from GetAll
@@ -1119,22 +1364,364 @@ func TestPostService_GetAll_CacheSetError(t *testing.T) {
 func TestStartAsyncUpload_PublishesJobWithRequestID(t *testing.T) {
 	mq := new(mocks.MockJobQueue)
 	cache := new(mocks.MockCache)
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
 	svc := &services.PostServiceImpl{
-		JobQueue: mq,
-		Cache:    cache,
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		JobQueue:       mq,
+		Cache:          cache,
+		CtxTimeout:     2 * time.Second,
 	}
 	ctx := context.WithValue(context.Background(), "request_id", "req-123")
+	authorID := uuid.New()
 	postID := uuid.New()
 	fileName := "file.txt"
 	fileType := "text/plain"
 	fileData := []byte("data")
 
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
 	mq.On("PublishJob", ctx, "post_upload_queue", mock.AnythingOfType("[]uint8")).Return(nil)
 	cache.On("Set", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	uploadID, err := svc.StartAsyncUpload(ctx, postID, fileName, fileType, fileData)
+	uploadID, err := svc.StartAsyncUpload(ctx, authorClaims(authorID), postID, fileName, fileType, fileData)
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, uploadID)
 	mq.AssertExpectations(t)
 	cache.AssertExpectations(t)
+	mockTx.AssertExpectations(t)
+}
+
+func TestStartAsyncUpload_ForbiddenForOtherUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mq := new(mocks.MockJobQueue)
+	cache := new(mocks.MockCache)
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+	svc := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		JobQueue:       mq,
+		Cache:          cache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, uuid.New()), nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+
+	uploadID, err := svc.StartAsyncUpload(ctx, authorClaims(uuid.New()), postID, "file.txt", "text/plain", []byte("data"))
+
+	assert.Equal(t, uuid.Nil, uploadID)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.StatusCode)
+	mockTx.AssertExpectations(t)
+	mq.AssertNotCalled(t, "PublishJob", mock.Anything, mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStartAsyncUpload_ManagerMayUploadToAnyPost(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mq := new(mocks.MockJobQueue)
+	cache := new(mocks.MockCache)
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+	svc := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		JobQueue:       mq,
+		Cache:          cache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+	manager := authorClaims(uuid.New())
+	manager.Permissions = append(manager.Permissions, entities.PermissionPostsManage)
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, uuid.New()), nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+	mq.On("PublishJob", mock.Anything, "post_upload_queue", mock.AnythingOfType("[]uint8")).Return(nil)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	uploadID, err := svc.StartAsyncUpload(ctx, manager, postID, "file.txt", "text/plain", []byte("data"))
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, uploadID)
+	mq.AssertExpectations(t)
+}
+
+func authorClaims(userID uuid.UUID) *entities.TokenClaims {
+	return &entities.TokenClaims{
+		UserID:      userID.String(),
+		Roles:       []string{entities.RoleUser},
+		Permissions: []string{entities.PermissionUsersRead, entities.PermissionUsersWrite, entities.PermissionPostsWrite},
+	}
+}
+
+func postOwnedBy(postID, authorID uuid.UUID) *entities.Post {
+	return &entities.Post{ID: postID, Title: "Original Title", User: &entities.User{ID: authorID}}
+}
+
+func TestPostService_Patch_OnlyGivenFields(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	title := "Patched Title"
+	patch := entities.PostPatch{Title: &title}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, authorID), nil).Once()
+	mockPostRepo.On("Patch", mock.Anything, mockTx, postID, 0, patch).Return(&entities.Post{ID: postID, Title: title, Body: "Original"}, nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+
+	result, err := service.Patch(ctx, authorClaims(authorID), postID, 0, patch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, title, result.Title)
+	assert.Equal(t, "Original", result.Body)
+	assert.Equal(t, authorID, result.User.ID)
+	mockPostRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestPostService_Patch_EmptyPatchReturnsPost(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	existing := postOwnedBy(postID, authorID)
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(existing, nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+
+	result, err := service.Patch(ctx, authorClaims(authorID), postID, 0, entities.PostPatch{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing, result)
+	mockPostRepo.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockCache.AssertNotCalled(t, "InvalidateByPrefix", mock.Anything, mock.Anything)
+}
+
+func TestPostService_Patch_ForbiddenForOtherUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+	title := "Hijacked"
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, uuid.New()), nil).Once()
+
+	result, err := service.Patch(ctx, authorClaims(uuid.New()), postID, 0, entities.PostPatch{Title: &title})
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.StatusCode)
+	mockTx.AssertExpectations(t)
+	mockPostRepo.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostService_Update_StaleVersion(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	current := postOwnedBy(postID, authorID)
+	current.Version = 3
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(current, nil).Once()
+
+	result, err := service.Update(ctx, authorClaims(authorID), &entities.Post{ID: postID, Title: "Edited", Version: 2})
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 412, appErr.StatusCode)
+	mockTx.AssertExpectations(t)
+	mockPostRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostService_Delete_VersionConflict(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	current := postOwnedBy(postID, authorID)
+	current.Version = 2
+
+	// The post is changed by someone else between the read and the delete.
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(current, nil).Once()
+	mockPostRepo.On("Delete", mock.Anything, mockTx, postID, 2).Return(appErrors.ErrVersionConflict).Once()
+
+	err := service.Delete(ctx, authorClaims(authorID), postID, 2)
+
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 412, appErr.StatusCode)
+	mockTx.AssertExpectations(t)
+}
+
+func TestPostService_Restore_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	restored := postOwnedBy(postID, authorID)
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("Restore", mock.Anything, mockTx, postID).Return(restored, nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+
+	result, err := service.Restore(ctx, authorClaims(authorID), postID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, restored, result)
+	mockTx.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestPostService_Restore_ForbiddenForOtherUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+
+	// The restore is rolled back, the post stays in the trash.
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("Restore", mock.Anything, mockTx, postID).Return(postOwnedBy(postID, uuid.New()), nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+
+	result, err := service.Restore(ctx, authorClaims(uuid.New()), postID)
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.StatusCode)
+	mockTx.AssertExpectations(t)
+	mockTx.AssertNotCalled(t, "Commit")
+	mockCache.AssertNotCalled(t, "InvalidateByPrefix", mock.Anything, mock.Anything)
+}
+
+func TestPostService_Restore_NotInTrash(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.PostServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	postID := uuid.New()
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockPostRepo.On("Restore", mock.Anything, mockTx, postID).Return(nil, appErrors.ErrDataNotFound).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+
+	result, err := service.Restore(ctx, authorClaims(uuid.New()), postID)
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.StatusCode)
+	assert.Equal(t, "Post not found in trash", appErr.Message)
+	mockTx.AssertExpectations(t)
+}
+
+// acceptRevisions is a revision repository that stores whatever it is given.
+func acceptRevisions() *mocks.MockPostRevisionRepository {
+	repo := new(mocks.MockPostRevisionRepository)
+	repo.On("Save", mock.Anything, mock.Anything, mock.Anything).Return(&entities.PostRevision{}, nil).Maybe()
+	return repo
 }
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	reactionReconcileLockKey = "lock:reaction_reconcile"
+	// reactionReconcileBatch bounds how many posts one reconciliation handles, the rest stay
+	// dirty for the next run.
+	reactionReconcileBatch = 500
+	// reactionCounterPrefix prefixes the counter group of a post's reactions.
+	reactionCounterPrefix = "reactions:post:"
+)
+
+// ReactionServiceImpl keeps the reactions on posts. Every reaction is stored in Postgres, the
+// counts are kept in the counter store and reconciled into Postgres every so often, so that
+// listing posts never counts reactions.
+type ReactionServiceImpl struct {
+	DB                 ports.Database
+	ReactionRepository ports.ReactionRepository
+	PostRepository     ports.PostRepository
+	Counters           ports.CounterStore
+	// Locking keeps several API instances from reconciling at the same time. Nil reconciles without a lock.
+	Locking    ports.Locking
+	CtxTimeout time.Duration
+}
+
+func (s *ReactionServiceImpl) React(c context.Context, actor *entities.TokenClaims, postID uuid.UUID, reaction entities.ReactionType) (*entities.PostReactions, error) {
+	return s.change(c, actor, postID, reaction, 1)
+}
+
+func (s *ReactionServiceImpl) Unreact(c context.Context, actor *entities.TokenClaims, postID uuid.UUID, reaction entities.ReactionType) (*entities.PostReactions, error) {
+	return s.change(c, actor, postID, reaction, -1)
+}
+
+// change adds the reaction with a delta of 1, or removes it with -1.
+func (s *ReactionServiceImpl) change(c context.Context, actor *entities.TokenClaims, postID uuid.UUID, reaction entities.ReactionType, delta int64) (*entities.PostReactions, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	userID := editorOf(actor)
+	if userID == uuid.Nil {
+		return nil, appErrors.NewUnauthorizedError("Sign in to react", nil)
+	}
+	if !reaction.Valid() {
+		return nil, appErrors.NewBadRequestError("Unknown reaction type", nil)
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	post, err := readablePost(ctx, tx, logger, s.PostRepository, actor, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed bool
+	if delta > 0 {
+		changed, err = s.ReactionRepository.Add(ctx, tx, postID, userID, reaction)
+	} else {
+		changed, err = s.ReactionRepository.Remove(ctx, tx, postID, userID, reaction)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to change reaction")
+		return nil, err
+	}
+
+	mine, err := s.ReactionRepository.ListByUser(ctx, tx, userID, []uuid.UUID{postID})
+	if err != nil {
+		logger.WithError(err).Error("Failed to list own reactions")
+		return nil, err
+	}
+
+	// Counters missing from the store, say after a restart, are seeded with a count instead
+	// of starting from zero.
+	key := reactionCounterPrefix + postID.String()
+	var seed entities.ReactionCounts
+	if changed {
+		if counters, err := s.Counters.GetAll(c, key); err == nil && counters == nil {
+			if seed, err = s.ReactionRepository.Count(ctx, tx, postID); err != nil {
+				logger.WithError(err).Error("Failed to count reactions")
+				return nil, err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	// Failing to count is not fatal, the reaction is stored and the next reconciliation of the
+	// post corrects the count.
+	if seed != nil {
+		if err := s.Counters.Replace(c, key, reactionCounters(seed)); err != nil {
+			logger.WithError(err).Warn("Failed to seed reaction counters")
+		}
+		// Concurrent seeds may each miss the other's reaction, reconciliation settles them.
+		if err := s.Counters.MarkDirty(c, key); err != nil {
+			logger.WithError(err).Warn("Failed to mark reaction counters dirty")
+		}
+	} else if changed {
+		if _, err := s.Counters.Incr(c, key, string(reaction), delta); err != nil {
+			logger.WithError(err).Warn("Failed to count reaction")
+		}
+	}
+
+	s.countReactions(c, logger, post)
+
+	return &entities.PostReactions{Counts: post.Reactions, Mine: append([]entities.ReactionType{}, mine[postID]...)}, nil
+}
+
+func (s *ReactionServiceImpl) Annotate(c context.Context, actor *entities.TokenClaims, posts ...*entities.Post) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	for _, post := range posts {
+		s.countReactions(c, logger, post)
+	}
+
+	userID := editorOf(actor)
+	if userID == uuid.Nil || len(posts) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	postIDs := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+	mine, err := s.ReactionRepository.ListByUser(ctx, tx, userID, postIDs)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list own reactions")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	for _, post := range posts {
+		post.MyReactions = mine[post.ID]
+	}
+
+	return nil
+}
+
+// countReactions replaces the reconciled counts post was read with by those of the counter
+// store, when it has them.
+func (s *ReactionServiceImpl) countReactions(ctx context.Context, logger logrus.FieldLogger, post *entities.Post) {
+	counters, err := s.Counters.GetAll(ctx, reactionCounterPrefix+post.ID.String())
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get reaction counters, serving the reconciled counts")
+		return
+	}
+	if counters == nil {
+		return
+	}
+
+	counts := entities.ReactionCounts{}
+	for field, n := range counters {
+		if n > 0 {
+			counts[entities.ReactionType(field)] = n
+		}
+	}
+	post.Reactions = counts
+}
+
+func (s *ReactionServiceImpl) Reconcile(c context.Context) (int, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	if s.Locking != nil {
+		acquired, lockValue, err := s.Locking.AcquireLock(reactionReconcileLockKey, s.CtxTimeout)
+		if err != nil {
+			return 0, err
+		}
+		if !acquired {
+			logger.Debug("Reaction reconciliation is running on another instance")
+			return 0, nil
+		}
+		defer func() {
+			if err := s.Locking.ReleaseLock(reactionReconcileLockKey, lockValue); err != nil {
+				logger.WithError(err).Warn("Failed to release reaction reconcile lock")
+			}
+		}()
+	}
+
+	keys, err := s.Counters.PopDirty(c, reactionReconcileBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	reconciled := 0
+	for _, key := range keys {
+		if !strings.HasPrefix(key, reactionCounterPrefix) {
+			continue
+		}
+		postID, err := uuid.Parse(strings.TrimPrefix(key, reactionCounterPrefix))
+		if err != nil {
+			logger.Warnf("Skipping reaction counters with an invalid key %q", key)
+			continue
+		}
+		if err := s.reconcilePost(c, logger, postID); err != nil {
+			logger.WithError(err).Errorf("Failed to reconcile the reactions of post %s", postID)
+			if err := s.Counters.MarkDirty(c, key); err != nil {
+				logger.WithError(err).Warn("Failed to mark reaction counters dirty again")
+			}
+			continue
+		}
+		reconciled++
+	}
+
+	if reconciled > 0 {
+		logger.Infof("Reconciled the reaction counts of %d posts", reconciled)
+	}
+	return reconciled, nil
+}
+
+// reconcilePost counts the reactions on a post, stores the counts in Postgres and resets its
+// counters to them. Reactions that come in meanwhile mark the post dirty again.
+func (s *ReactionServiceImpl) reconcilePost(c context.Context, logger logrus.FieldLogger, postID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	counts, err := s.ReactionRepository.Count(ctx, tx, postID)
+	if err != nil {
+		return err
+	}
+	if err = s.ReactionRepository.SaveCounts(ctx, tx, postID, counts); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	return s.Counters.Replace(ctx, reactionCounterPrefix+postID.String(), reactionCounters(counts))
+}
+
+// reactionCounters are the counters of counts, with every type present so that an existing
+// group is never mistaken for a missing one.
+func reactionCounters(counts entities.ReactionCounts) map[string]int64 {
+	counters := make(map[string]int64, len(entities.ReactionTypes))
+	for _, reaction := range entities.ReactionTypes {
+		counters[string(reaction)] = counts[reaction]
+	}
+	return counters
+}
+
+// RunReconcileJob reconciles reaction counts every interval until ctx is done. ctx carries the job's logger.
+func (s *ReactionServiceImpl) RunReconcileJob(ctx context.Context, interval time.Duration) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Reconcile(ctx); err != nil {
+			logger.WithError(err).Error("Reaction reconciliation failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
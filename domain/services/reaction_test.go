@@ -0,0 +1,433 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReactionService_React(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	userID := uuid.New()
+	key := "reactions:post:" + postID.String()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(publishedPost(postID), nil).Once()
+	mockReactionRepo.On("Add", mock.Anything, mockTx, postID, userID, entities.ReactionLike).Return(true, nil).Once()
+	mockReactionRepo.On("ListByUser", mock.Anything, mockTx, userID, []uuid.UUID{postID}).
+		Return(map[uuid.UUID][]entities.ReactionType{postID: {entities.ReactionLike}}, nil).Once()
+	mockCounters.On("GetAll", mock.Anything, key).Return(map[string]int64{"like": 2, "sad": 0}, nil).Once()
+	mockCounters.On("Incr", mock.Anything, key, "like", int64(1)).Return(int64(3), nil).Once()
+	mockCounters.On("GetAll", mock.Anything, key).Return(map[string]int64{"like": 3, "sad": 0}, nil).Once()
+
+	result, err := service.React(ctx, authorClaims(userID), postID, entities.ReactionLike)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReactionCounts{entities.ReactionLike: 3}, result.Counts)
+	assert.Equal(t, []entities.ReactionType{entities.ReactionLike}, result.Mine)
+	mockTx.AssertCalled(t, "Commit")
+	mockCounters.AssertExpectations(t)
+}
+
+func TestReactionService_React_AgainDoesNotCount(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	userID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(publishedPost(postID), nil).Once()
+	mockReactionRepo.On("Add", mock.Anything, mockTx, postID, userID, entities.ReactionLove).Return(false, nil).Once()
+	mockReactionRepo.On("ListByUser", mock.Anything, mockTx, userID, []uuid.UUID{postID}).
+		Return(map[uuid.UUID][]entities.ReactionType{postID: {entities.ReactionLove}}, nil).Once()
+	mockCounters.On("GetAll", mock.Anything, mock.Anything).Return(map[string]int64{"love": 1}, nil).Once()
+
+	result, err := service.React(ctx, authorClaims(userID), postID, entities.ReactionLove)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReactionCounts{entities.ReactionLove: 1}, result.Counts)
+	mockCounters.AssertNotCalled(t, "Incr", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReactionService_React_SeedsMissingCounters(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	userID := uuid.New()
+	key := "reactions:post:" + postID.String()
+	counts := entities.ReactionCounts{entities.ReactionLike: 11}
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(publishedPost(postID), nil).Once()
+	mockReactionRepo.On("Add", mock.Anything, mockTx, postID, userID, entities.ReactionLike).Return(true, nil).Once()
+	mockReactionRepo.On("ListByUser", mock.Anything, mockTx, userID, []uuid.UUID{postID}).Return(map[uuid.UUID][]entities.ReactionType{}, nil).Once()
+	mockCounters.On("GetAll", mock.Anything, key).Return(nil, nil).Once()
+	mockReactionRepo.On("Count", mock.Anything, mockTx, postID).Return(counts, nil).Once()
+	mockCounters.On("Replace", mock.Anything, key, mock.MatchedBy(func(c map[string]int64) bool {
+		return c["like"] == 11 && c["love"] == 0 && len(c) == len(entities.ReactionTypes)
+	})).Return(nil).Once()
+	mockCounters.On("MarkDirty", mock.Anything, key).Return(nil).Once()
+	mockCounters.On("GetAll", mock.Anything, key).Return(map[string]int64{"like": 11, "love": 0}, nil).Once()
+
+	result, err := service.React(ctx, authorClaims(userID), postID, entities.ReactionLike)
+
+	require.NoError(t, err)
+	assert.Equal(t, counts, result.Counts)
+	assert.Empty(t, result.Mine)
+	assert.NotNil(t, result.Mine)
+	mockCounters.AssertExpectations(t)
+	mockCounters.AssertNotCalled(t, "Incr", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReactionService_Unreact(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	userID := uuid.New()
+	key := "reactions:post:" + postID.String()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(publishedPost(postID), nil).Once()
+	mockReactionRepo.On("Remove", mock.Anything, mockTx, postID, userID, entities.ReactionWow).Return(true, nil).Once()
+	mockReactionRepo.On("ListByUser", mock.Anything, mockTx, userID, []uuid.UUID{postID}).Return(map[uuid.UUID][]entities.ReactionType{}, nil).Once()
+	mockCounters.On("GetAll", mock.Anything, key).Return(map[string]int64{"wow": 1}, nil).Once()
+	mockCounters.On("Incr", mock.Anything, key, "wow", int64(-1)).Return(int64(0), nil).Once()
+	mockCounters.On("GetAll", mock.Anything, key).Return(map[string]int64{"wow": 0}, nil).Once()
+
+	result, err := service.Unreact(ctx, authorClaims(userID), postID, entities.ReactionWow)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReactionCounts{}, result.Counts)
+	mockCounters.AssertExpectations(t)
+}
+
+func TestReactionService_React_Rejected(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	tests := []struct {
+		name     string
+		actor    *entities.TokenClaims
+		reaction entities.ReactionType
+		status   int
+	}{
+		{"anonymous", nil, entities.ReactionLike, 401},
+		{"unknown type", authorClaims(uuid.New()), "meh", 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.React(ctx, tt.actor, uuid.New(), tt.reaction)
+
+			var appErr *appErrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.status, appErr.StatusCode)
+		})
+	}
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestReactionService_React_DraftOfOthers(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(draftOwnedBy(postID, uuid.New()), nil).Once()
+
+	_, err := service.React(ctx, authorClaims(uuid.New()), postID, entities.ReactionLike)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 404, appErr.StatusCode)
+	mockReactionRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReactionService_Annotate(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	userID := uuid.New()
+	counted := &entities.Post{ID: uuid.New(), Reactions: entities.ReactionCounts{entities.ReactionLike: 1}}
+	uncounted := &entities.Post{ID: uuid.New(), Reactions: entities.ReactionCounts{entities.ReactionSad: 4}}
+	mockCounters.On("GetAll", mock.Anything, "reactions:post:"+counted.ID.String()).Return(map[string]int64{"like": 5, "sad": -1}, nil).Once()
+	mockCounters.On("GetAll", mock.Anything, "reactions:post:"+uncounted.ID.String()).Return(nil, errors.New("redis down")).Once()
+	mockReactionRepo.On("ListByUser", mock.Anything, mockTx, userID, []uuid.UUID{counted.ID, uncounted.ID}).
+		Return(map[uuid.UUID][]entities.ReactionType{counted.ID: {entities.ReactionLike}}, nil).Once()
+
+	err := service.Annotate(ctx, authorClaims(userID), counted, uncounted)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReactionCounts{entities.ReactionLike: 5}, counted.Reactions)
+	assert.Equal(t, []entities.ReactionType{entities.ReactionLike}, counted.MyReactions)
+	assert.Equal(t, entities.ReactionCounts{entities.ReactionSad: 4}, uncounted.Reactions)
+	assert.Nil(t, uncounted.MyReactions)
+}
+
+func TestReactionService_Annotate_Anonymous(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	post := &entities.Post{ID: uuid.New()}
+	mockCounters.On("GetAll", mock.Anything, mock.Anything).Return(map[string]int64{"laugh": 2}, nil).Once()
+
+	err := service.Annotate(ctx, nil, post)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReactionCounts{entities.ReactionLaugh: 2}, post.Reactions)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestReactionService_Reconcile(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	reconciled := uuid.New()
+	failed := uuid.New()
+	counts := entities.ReactionCounts{entities.ReactionLike: 7}
+	mockCounters.On("PopDirty", mock.Anything, 500).Return([]string{
+		"reactions:post:" + reconciled.String(),
+		"reactions:post:" + failed.String(),
+		"reactions:post:not-a-uuid",
+	}, nil).Once()
+	mockReactionRepo.On("Count", mock.Anything, mockTx, reconciled).Return(counts, nil).Once()
+	mockReactionRepo.On("SaveCounts", mock.Anything, mockTx, reconciled, counts).Return(nil).Once()
+	mockCounters.On("Replace", mock.Anything, "reactions:post:"+reconciled.String(), mock.MatchedBy(func(c map[string]int64) bool {
+		return c["like"] == 7 && c["angry"] == 0
+	})).Return(nil).Once()
+	mockReactionRepo.On("Count", mock.Anything, mockTx, failed).Return(nil, errors.New("db down")).Once()
+	mockCounters.On("MarkDirty", mock.Anything, "reactions:post:"+failed.String()).Return(nil).Once()
+
+	n, err := service.Reconcile(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	mockReactionRepo.AssertExpectations(t)
+	mockCounters.AssertExpectations(t)
+}
+
+func TestReactionService_Reconcile_SkipsWhenLockIsHeld(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockReactionRepo := new(mocks.MockReactionRepository)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCounters := new(mocks.MockCounterStore)
+	mockLock := new(mocks.MockLocking)
+
+	service := &services.ReactionServiceImpl{
+		DB:                 mockDB,
+		ReactionRepository: mockReactionRepo,
+		PostRepository:     mockPostRepo,
+		Counters:           mockCounters,
+		Locking:            mockLock,
+		CtxTimeout:         2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockLock.On("AcquireLock", "lock:reaction_reconcile", service.CtxTimeout).Return(false, "", nil).Once()
+
+	n, err := service.Reconcile(ctx)
+
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	mockCounters.AssertNotCalled(t, "PopDirty", mock.Anything, mock.Anything)
+}
+
+func TestPostService_GetAll_AnnotatesAfterCaching(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockCache := new(mocks.MockCache)
+	mockReactions := new(mocks.MockReactionService)
+
+	service := &services.PostServiceImpl{
+		DB:              mockDB,
+		PostRepository:  mockPostRepo,
+		Cache:           mockCache,
+		ReactionService: mockReactions,
+		CtxTimeout:      2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	actor := authorClaims(uuid.New())
+	posts := []entities.Post{{ID: uuid.New(), Title: "Liked"}}
+	mockCache.On("Get", mock.Anything, mock.Anything).Return("", errors.New("cache miss")).Once()
+	mockPostRepo.On("GetAll", mock.Anything, mockTx, entities.PostFilter{}, entities.PaginationParams{Page: 1, Limit: 10}).Return(posts, nil).Once()
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.MatchedBy(func(value []byte) bool {
+		var cached []entities.Post
+		return json.Unmarshal(value, &cached) == nil && cached[0].MyReactions == nil
+	}), 30*time.Second).Return(nil).Once()
+	mockReactions.On("Annotate", mock.Anything, actor, mock.MatchedBy(func(posts []*entities.Post) bool {
+		return len(posts) == 1 && posts[0].Title == "Liked"
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).([]*entities.Post)[0].MyReactions = []entities.ReactionType{entities.ReactionLike}
+	}).Return(nil).Once()
+
+	result, err := service.GetAll(ctx, actor, entities.PostFilter{}, 1, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, []entities.ReactionType{entities.ReactionLike}, result[0].MyReactions)
+	mockCache.AssertExpectations(t)
+	mockReactions.AssertExpectations(t)
+}
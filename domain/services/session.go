@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// SessionServiceImpl lets users see and end their sessions. Revocation is written to the
+// database, which refresh checks, and to the cache markers JWTMiddleware checks on every request.
+type SessionServiceImpl struct {
+	DB ports.Database
+	ports.SessionRepository
+	ports.Cache
+	RefreshTokenTTL time.Duration
+	CtxTimeout      time.Duration
+}
+
+func (s *SessionServiceImpl) List(c context.Context, userID string) ([]*entities.Session, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	sessions, err := s.SessionRepository.FindActiveByUser(ctx, tx, userID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list sessions")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (s *SessionServiceImpl) Revoke(c context.Context, userID string, id uuid.UUID) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = s.SessionRepository.Revoke(ctx, tx, userID, id); err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			err = appErrors.NewNotFoundError("Session not found", err)
+			return err
+		}
+		logger.WithError(err).Error("Failed to revoke session")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	// The session id is the token family, see AuthServiceImpl.startSession.
+	if err := s.Cache.Set(ctx, auth.RevokedFamilyKey(id.String()), []byte("1"), s.RefreshTokenTTL); err != nil {
+		logger.WithError(err).Error("Failed to revoke session tokens")
+		return err
+	}
+
+	logger.Infof("Session %s of user %s revoked", id, userID)
+	return nil
+}
+
+func (s *SessionServiceImpl) RevokeAll(c context.Context, userID string) error {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = s.SessionRepository.RevokeAll(ctx, tx, userID); err != nil {
+		logger.WithError(err).Error("Failed to revoke sessions")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	if err := s.Cache.Set(ctx, auth.RevokedUserKey(userID), []byte(now), s.RefreshTokenTTL); err != nil {
+		logger.WithError(err).Error("Failed to revoke user tokens")
+		return err
+	}
+
+	logger.Infof("All sessions of user %s revoked", userID)
+	return nil
+}
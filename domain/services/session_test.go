@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionService_List(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockSessionRepository)
+	cache := newMemoryCache()
+
+	service := &services.SessionServiceImpl{
+		DB:                mockDB,
+		SessionRepository: mockRepo,
+		Cache:             cache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	sessions := []*entities.Session{{ID: uuid.New(), UserAgent: "curl/8.0", IP: "203.0.113.7"}}
+	mockRepo.On("FindActiveByUser", mock.Anything, mockTx, "user-1").Return(sessions, nil)
+
+	got, err := service.List(ctx, "user-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, sessions, got)
+}
+
+func TestSessionService_Revoke(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockSessionRepository)
+	cache := newMemoryCache()
+
+	service := &services.SessionServiceImpl{
+		DB:                mockDB,
+		SessionRepository: mockRepo,
+		Cache:             cache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	id := uuid.New()
+	mockRepo.On("Revoke", mock.Anything, mockTx, "user-1", id).Return(nil)
+
+	err := service.Revoke(ctx, "user-1", id)
+
+	require.NoError(t, err)
+	assert.Equal(t, "1", cache.data[auth.RevokedFamilyKey(id.String())])
+	mockTx.AssertCalled(t, "Commit")
+}
+
+func TestSessionService_Revoke_NotFound(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockSessionRepository)
+	cache := newMemoryCache()
+
+	service := &services.SessionServiceImpl{
+		DB:                mockDB,
+		SessionRepository: mockRepo,
+		Cache:             cache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	id := uuid.New()
+	mockRepo.On("Revoke", mock.Anything, mockTx, "user-1", id).Return(appErrors.ErrDataNotFound)
+
+	err := service.Revoke(ctx, "user-1", id)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	assert.Empty(t, cache.data)
+	mockTx.AssertCalled(t, "Rollback")
+}
+
+func TestSessionService_RevokeAll(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockRepo := new(mocks.MockSessionRepository)
+	cache := newMemoryCache()
+
+	service := &services.SessionServiceImpl{
+		DB:                mockDB,
+		SessionRepository: mockRepo,
+		Cache:             cache,
+		RefreshTokenTTL:   time.Hour,
+		CtxTimeout:        2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockRepo.On("RevokeAll", mock.Anything, mockTx, "user-1").Return(nil)
+
+	err := service.RevokeAll(ctx, "user-1")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, cache.data[auth.RevokedUserKey("user-1")])
+}
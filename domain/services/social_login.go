@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// StartSocialLogin returns the URL that signs the user in at provider. The state, nonce and
+// PKCE verifier it needs on the way back are kept in the cache for SocialLoginTTL.
+func (s *AuthServiceImpl) StartSocialLogin(c context.Context, provider string) (string, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	idp, ok := s.IdentityProviders[provider]
+	if !ok {
+		return "", appErrors.NewNotFoundError("Unknown identity provider", nil)
+	}
+
+	state, err := auth.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	login := entities.SocialLogin{Provider: provider}
+	if login.Nonce, err = auth.RandomToken(32); err != nil {
+		return "", err
+	}
+	if login.CodeVerifier, err = auth.RandomToken(32); err != nil {
+		return "", err
+	}
+
+	authURL, err := idp.AuthCodeURL(ctx, state, login.Nonce, auth.PKCEChallenge(login.CodeVerifier))
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to build authorization url for %s", provider)
+		return "", err
+	}
+
+	data, err := json.Marshal(login)
+	if err != nil {
+		return "", err
+	}
+	if err := s.Cache.Set(ctx, auth.SocialLoginStateKey(state), data, s.SocialLoginTTL); err != nil {
+		logger.WithError(err).Error("Failed to store social login state")
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// CompleteSocialLogin handles the callback from provider. The external account is linked to
+// the user it was linked to before, or else to the verified user with the same verified email,
+// or else to a new user. Like SignIn, it only returns an mfa token when two-factor is enabled.
+func (s *AuthServiceImpl) CompleteSocialLogin(c context.Context, provider, code, state string) (*entities.User, *entities.TokenPair, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	idp, ok := s.IdentityProviders[provider]
+	if !ok {
+		return nil, nil, appErrors.NewNotFoundError("Unknown identity provider", nil)
+	}
+
+	login, err := s.consumeSocialLoginState(ctx, state)
+	if err != nil {
+		logger.WithError(err).Error("Failed to read social login state")
+		return nil, nil, err
+	}
+	if login == nil || login.Provider != provider {
+		s.auditSignIn(ctx, entities.AuditActionSignInSocial, "", nil, "invalid_state")
+		return nil, nil, appErrors.NewBadRequestError("Invalid or expired login state", nil)
+	}
+
+	external, err := idp.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		logger.WithError(err).Warnf("Failed to exchange authorization code with %s", provider)
+		s.auditSignIn(ctx, entities.AuditActionSignInSocial, "", nil, "provider_rejected")
+		return nil, nil, appErrors.NewUnauthorizedError("Sign in with the identity provider failed", err)
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.userForIdentity(ctx, tx, logger, external)
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			s.auditSignIn(ctx, entities.AuditActionSignInSocial, external.Email, nil, "identity_refused")
+		}
+		return nil, nil, err
+	}
+
+	var tokens *entities.TokenPair
+	if user.MFAEnabled() {
+		var mfaToken string
+		mfaToken, err = s.TokenManager.GenerateMFAToken(user.ID.String())
+		if err != nil {
+			logger.WithError(err).Error("Failed to generate mfa token")
+			return nil, nil, err
+		}
+		tokens = &entities.TokenPair{MFAToken: mfaToken}
+	} else {
+		tokens, err = s.startSession(ctx, tx, user)
+		if err != nil {
+			logger.WithError(err).Error("Failed to start session")
+			return nil, nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, nil, err
+	}
+
+	// With two-factor enabled the provider step succeeded, SignInMFA records the second one.
+	s.auditSignIn(ctx, entities.AuditActionSignInSocial, user.Email, user, "")
+	logger.Infof("User %s signed in with %s", user.ID, provider)
+	return user, tokens, nil
+}
+
+// consumeSocialLoginState returns the state stored by StartSocialLogin and removes it, so each
+// state is only good for one callback. It returns nil when the state is unknown, expired or
+// already claimed by another callback.
+func (s *AuthServiceImpl) consumeSocialLoginState(ctx context.Context, state string) (*entities.SocialLogin, error) {
+	if state == "" {
+		return nil, nil
+	}
+	key := auth.SocialLoginStateKey(state)
+	stored, err := s.Cache.Get(ctx, key)
+	if err != nil || stored == "" {
+		return nil, err
+	}
+	// Callbacks racing with the same state may all read it, only the one claiming it goes on.
+	claimed, err := s.Cache.SetNX(ctx, auth.SocialLoginClaimKey(state), []byte("1"), s.SocialLoginTTL)
+	if err != nil || !claimed {
+		return nil, err
+	}
+	if err := s.Cache.Delete(ctx, key); err != nil {
+		return nil, err
+	}
+
+	var login entities.SocialLogin
+	if err := json.Unmarshal([]byte(stored), &login); err != nil {
+		return nil, err
+	}
+	return &login, nil
+}
+
+// userForIdentity finds or creates the user an external identity belongs to and links them.
+func (s *AuthServiceImpl) userForIdentity(ctx context.Context, tx ports.Transaction, logger logrus.FieldLogger, external *entities.ExternalIdentity) (*entities.User, error) {
+	linked, err := s.IdentityRepository.FindByProviderSubject(ctx, tx, external.Provider, external.Subject)
+	if err == nil {
+		user, err := s.UserRepository.FindById(ctx, tx, linked.UserID.String())
+		if err != nil {
+			logger.WithError(err).Error("Failed to find user of linked identity")
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, appErrors.ErrDataNotFound) {
+		logger.WithError(err).Error("Failed to find identity")
+		return nil, err
+	}
+
+	// An unverified address could belong to anyone, linking it would hand over the account.
+	if external.Email == "" || !external.EmailVerified {
+		return nil, appErrors.NewForbiddenError("The identity provider did not confirm your email address", nil)
+	}
+
+	user, err := s.UserRepository.FindByEmail(ctx, tx, external.Email)
+	switch {
+	case err == nil:
+		// Whoever registered an unverified account may not own the address, and linking would
+		// leave them signed in with the password they chose.
+		if !user.IsVerified() {
+			logger.Warnf("Refused to link %s identity to unverified user %s", external.Provider, user.ID)
+			return nil, appErrors.NewConflictError("An account with this email exists but is not verified, verify it or sign in with its password first", nil)
+		}
+	case errors.Is(err, appErrors.ErrUserNotFound):
+		user, err = s.createSocialUser(ctx, tx, logger, external.Email)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		logger.WithError(err).Error("Failed to find user by email")
+		return nil, err
+	}
+
+	if _, err := s.IdentityRepository.Save(ctx, tx, &entities.UserIdentity{
+		Provider: external.Provider,
+		Subject:  external.Subject,
+		UserID:   user.ID,
+		Email:    external.Email,
+	}); err != nil {
+		logger.WithError(err).Error("Failed to link identity")
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createSocialUser creates a verified user for a first social login. Its password is random
+// and never shown, a password can still be set through the reset flow.
+func (s *AuthServiceImpl) createSocialUser(ctx context.Context, tx ports.Transaction, logger logrus.FieldLogger, email string) (*entities.User, error) {
+	password, err := auth.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashed, err := s.Encryptor.HashPassword(password)
+	if err != nil {
+		logger.WithError(err).Error("Failed to hash password")
+		return nil, err
+	}
+
+	user, err := s.UserRepository.Save(ctx, tx, &entities.User{Email: email, Password: hashed})
+	if err != nil {
+		logger.WithError(err).Error("Failed to create user")
+		return nil, err
+	}
+	if err := s.UserRepository.MarkVerified(ctx, tx, user.ID.String()); err != nil {
+		logger.WithError(err).Error("Failed to mark user verified")
+		return nil, err
+	}
+	now := time.Now()
+	user.VerifiedAt = &now
+
+	return user, nil
+}
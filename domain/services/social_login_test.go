@@ -0,0 +1,337 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/infrastructure/identity"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type socialLoginTest struct {
+	service   *services.AuthServiceImpl
+	fake      *testutils.FakeOIDCProvider
+	tx        *mocks.MockTransaction
+	users     *mocks.MockUserRepository
+	idents    *mocks.MockIdentityRepository
+	sessions  *mocks.MockSessionRepository
+	tokens    *mocks.MockTokenManager
+	encryptor *mocks.MockEncryptor
+}
+
+// newSocialLoginTest wires the auth service to the real OIDC client and an in-process provider.
+func newSocialLoginTest(t *testing.T) *socialLoginTest {
+	fake := testutils.NewFakeOIDCProvider("client-id", "client-secret")
+	t.Cleanup(fake.Close)
+
+	st := &socialLoginTest{
+		fake:      fake,
+		tx:        new(mocks.MockTransaction),
+		users:     new(mocks.MockUserRepository),
+		idents:    new(mocks.MockIdentityRepository),
+		sessions:  new(mocks.MockSessionRepository),
+		tokens:    new(mocks.MockTokenManager),
+		encryptor: new(mocks.MockEncryptor),
+	}
+	mockDB := new(mocks.MockDatabase)
+	mockDB.On("BeginTx", mock.Anything).Return(st.tx, nil)
+	st.sessions.On("Create", mock.Anything, st.tx, mock.Anything).Return(&entities.Session{}, nil)
+
+	st.service = &services.AuthServiceImpl{
+		DB:                mockDB,
+		UserRepository:    st.users,
+		SessionRepository: st.sessions,
+		Encryptor:         st.encryptor,
+		TokenManager:      st.tokens,
+		Cache:             newMemoryCache(),
+		CtxTimeout:        5 * time.Second,
+		IdentityProviders: map[string]ports.IdentityProvider{
+			"fake": identity.NewOIDCProvider(identity.OIDCConfig{
+				Name:         "fake",
+				Issuer:       fake.Issuer(),
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				RedirectURL:  "http://localhost:8080/api/auth/oidc/fake/callback",
+			}),
+		},
+		IdentityRepository: st.idents,
+		SocialLoginTTL:     time.Minute,
+	}
+	return st
+}
+
+// authorize starts a social login and signs in at the fake provider, returning the callback parameters.
+func (st *socialLoginTest) authorize(t *testing.T, ctx context.Context) (string, string) {
+	authURL, err := st.service.StartSocialLogin(ctx, "fake")
+	require.NoError(t, err)
+	code, state, err := st.fake.Authorize(authURL)
+	require.NoError(t, err)
+	return code, state
+}
+
+func socialLoginContext() context.Context {
+	return context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+}
+
+func TestAuthService_SocialLogin_CreatesUser(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	code, state := st.authorize(t, ctx)
+
+	created := &entities.User{ID: uuid.New(), Email: "social@example.com"}
+	tokens := &entities.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+
+	st.idents.On("FindByProviderSubject", mock.Anything, st.tx, "fake", "fake-subject").Return(nil, appErrors.ErrDataNotFound)
+	st.users.On("FindByEmail", mock.Anything, st.tx, "social@example.com").Return(nil, appErrors.ErrUserNotFound)
+	st.encryptor.On("HashPassword", mock.Anything).Return("hashed", nil)
+	st.users.On("Save", mock.Anything, st.tx, mock.MatchedBy(func(u *entities.User) bool {
+		return u.Email == "social@example.com" && u.Password == "hashed"
+	})).Return(created, nil)
+	st.users.On("MarkVerified", mock.Anything, st.tx, created.ID.String()).Return(nil)
+	st.idents.On("Save", mock.Anything, st.tx, mock.MatchedBy(func(i *entities.UserIdentity) bool {
+		return i.Provider == "fake" && i.Subject == "fake-subject" && i.UserID == created.ID
+	})).Return(&entities.UserIdentity{}, nil)
+	st.tokens.On("GenerateTokenPair", created, mock.AnythingOfType("string")).Return(tokens, nil)
+	st.tx.On("Commit").Return(nil)
+
+	user, got, err := st.service.CompleteSocialLogin(ctx, "fake", code, state)
+
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, user.ID)
+	assert.True(t, user.IsVerified())
+	assert.Equal(t, "access", got.AccessToken)
+	st.users.AssertExpectations(t)
+	st.idents.AssertExpectations(t)
+}
+
+func TestAuthService_SocialLogin_LinksExistingUserByVerifiedEmail(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	code, state := st.authorize(t, ctx)
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	existing := &entities.User{ID: uuid.New(), Email: "social@example.com", VerifiedAt: &verifiedAt}
+
+	st.idents.On("FindByProviderSubject", mock.Anything, st.tx, "fake", "fake-subject").Return(nil, appErrors.ErrDataNotFound)
+	st.users.On("FindByEmail", mock.Anything, st.tx, "social@example.com").Return(existing, nil)
+	st.idents.On("Save", mock.Anything, st.tx, mock.MatchedBy(func(i *entities.UserIdentity) bool {
+		return i.UserID == existing.ID
+	})).Return(&entities.UserIdentity{}, nil)
+	st.tokens.On("GenerateTokenPair", existing, mock.AnythingOfType("string")).Return(&entities.TokenPair{AccessToken: "access"}, nil)
+	st.tx.On("Commit").Return(nil)
+
+	user, _, err := st.service.CompleteSocialLogin(ctx, "fake", code, state)
+
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, user.ID)
+	st.users.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	st.idents.AssertExpectations(t)
+}
+
+func TestAuthService_SocialLogin_UnverifiedLocalAccountNotLinked(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	code, state := st.authorize(t, ctx)
+
+	// Registered by someone else before the owner of the address signs in with the provider.
+	squatter := &entities.User{ID: uuid.New(), Email: "social@example.com", Password: "attacker-hash"}
+
+	st.idents.On("FindByProviderSubject", mock.Anything, st.tx, "fake", "fake-subject").Return(nil, appErrors.ErrDataNotFound)
+	st.users.On("FindByEmail", mock.Anything, st.tx, "social@example.com").Return(squatter, nil)
+	st.tx.On("Rollback").Return(nil)
+
+	user, tokens, err := st.service.CompleteSocialLogin(ctx, "fake", code, state)
+
+	assert.Nil(t, user)
+	assert.Nil(t, tokens)
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+	st.users.AssertNotCalled(t, "MarkVerified", mock.Anything, mock.Anything, mock.Anything)
+	st.idents.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	st.tokens.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
+	st.tx.AssertExpectations(t)
+}
+
+func TestAuthService_SocialLogin_UsesLinkedIdentity(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	// The provider no longer vouches for the email, the existing link is enough.
+	st.fake.Identity.EmailVerified = false
+	code, state := st.authorize(t, ctx)
+
+	linked := &entities.User{ID: uuid.New(), Email: "old@example.com"}
+
+	st.idents.On("FindByProviderSubject", mock.Anything, st.tx, "fake", "fake-subject").
+		Return(&entities.UserIdentity{Provider: "fake", Subject: "fake-subject", UserID: linked.ID}, nil)
+	st.users.On("FindById", mock.Anything, st.tx, linked.ID.String()).Return(linked, nil)
+	st.tokens.On("GenerateTokenPair", linked, mock.AnythingOfType("string")).Return(&entities.TokenPair{AccessToken: "access"}, nil)
+	st.tx.On("Commit").Return(nil)
+
+	user, _, err := st.service.CompleteSocialLogin(ctx, "fake", code, state)
+
+	require.NoError(t, err)
+	assert.Equal(t, linked.ID, user.ID)
+	st.idents.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SocialLogin_UnverifiedEmailRefused(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	st.fake.Identity.EmailVerified = false
+	code, state := st.authorize(t, ctx)
+
+	st.idents.On("FindByProviderSubject", mock.Anything, st.tx, "fake", "fake-subject").Return(nil, appErrors.ErrDataNotFound)
+	st.tx.On("Rollback").Return(nil)
+
+	_, _, err := st.service.CompleteSocialLogin(ctx, "fake", code, state)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	st.users.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything, mock.Anything)
+	st.tx.AssertExpectations(t)
+}
+
+func TestAuthService_SocialLogin_MFAEnabledReturnsMFAToken(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	code, state := st.authorize(t, ctx)
+
+	enabledAt := time.Now()
+	linked := &entities.User{ID: uuid.New(), Email: "social@example.com", TOTPSecret: "secret", MFAEnabledAt: &enabledAt}
+
+	st.idents.On("FindByProviderSubject", mock.Anything, st.tx, "fake", "fake-subject").
+		Return(&entities.UserIdentity{UserID: linked.ID}, nil)
+	st.users.On("FindById", mock.Anything, st.tx, linked.ID.String()).Return(linked, nil)
+	st.tokens.On("GenerateMFAToken", linked.ID.String()).Return("mfa-token", nil)
+	st.tx.On("Commit").Return(nil)
+
+	_, tokens, err := st.service.CompleteSocialLogin(ctx, "fake", code, state)
+
+	require.NoError(t, err)
+	assert.Equal(t, "mfa-token", tokens.MFAToken)
+	assert.Empty(t, tokens.AccessToken)
+	st.tokens.AssertNotCalled(t, "GenerateTokenPair", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SocialLogin_StateIsSingleUse(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	code, state := st.authorize(t, ctx)
+
+	st.idents.On("FindByProviderSubject", mock.Anything, st.tx, "fake", "fake-subject").
+		Return(&entities.UserIdentity{UserID: uuid.Nil}, nil)
+	st.users.On("FindById", mock.Anything, st.tx, uuid.Nil.String()).Return(&entities.User{}, nil)
+	st.tokens.On("GenerateTokenPair", mock.Anything, mock.AnythingOfType("string")).Return(&entities.TokenPair{}, nil)
+	st.tx.On("Commit").Return(nil)
+
+	_, _, err := st.service.CompleteSocialLogin(ctx, "fake", code, state)
+	require.NoError(t, err)
+
+	_, _, err = st.service.CompleteSocialLogin(ctx, "fake", code, state)
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+}
+
+func TestAuthService_SocialLogin_ClaimedStateRefused(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	code, state := st.authorize(t, ctx)
+
+	// Another callback with the same state got there first and is still signing in.
+	claimed, err := st.service.Cache.SetNX(ctx, auth.SocialLoginClaimKey(state), []byte("1"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	_, _, err = st.service.CompleteSocialLogin(ctx, "fake", code, state)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	st.idents.AssertNotCalled(t, "FindByProviderSubject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SocialLogin_AuditsSuccess(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	mockAudit := new(mocks.MockAuditLogger)
+	st.service.AuditLogger = mockAudit
+	code, state := st.authorize(t, ctx)
+
+	linked := &entities.User{ID: uuid.New(), Email: "old@example.com"}
+
+	st.idents.On("FindByProviderSubject", mock.Anything, st.tx, "fake", "fake-subject").
+		Return(&entities.UserIdentity{Provider: "fake", Subject: "fake-subject", UserID: linked.ID}, nil)
+	st.users.On("FindById", mock.Anything, st.tx, linked.ID.String()).Return(linked, nil)
+	st.tokens.On("GenerateTokenPair", linked, mock.AnythingOfType("string")).Return(&entities.TokenPair{AccessToken: "access"}, nil)
+	st.tx.On("Commit").Return(nil)
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:     entities.AuditActionSignInSocial,
+		ActorID:    linked.ID.String(),
+		TargetType: entities.AuditTargetUser,
+		TargetID:   linked.ID.String(),
+		Outcome:    entities.AuditOutcomeSuccess,
+		Details:    map[string]string{"email": linked.Email},
+	}).Once()
+
+	_, _, err := st.service.CompleteSocialLogin(ctx, "fake", code, state)
+
+	require.NoError(t, err)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestAuthService_SocialLogin_AuditsFailure(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+	mockAudit := new(mocks.MockAuditLogger)
+	st.service.AuditLogger = mockAudit
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:     entities.AuditActionSignInSocial,
+		TargetType: entities.AuditTargetUser,
+		Outcome:    entities.AuditOutcomeFailure,
+		Details:    map[string]string{"reason": "invalid_state"},
+	}).Once()
+
+	_, _, err := st.service.CompleteSocialLogin(ctx, "fake", "code", "forged-state")
+
+	assert.Error(t, err)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestAuthService_SocialLogin_UnknownState(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+
+	_, _, err := st.service.CompleteSocialLogin(ctx, "fake", "code", "forged-state")
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+}
+
+func TestAuthService_SocialLogin_UnknownProvider(t *testing.T) {
+	ctx := socialLoginContext()
+	st := newSocialLoginTest(t)
+
+	_, err := st.service.StartSocialLogin(ctx, "unknown")
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+}
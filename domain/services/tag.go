@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxPostTags bounds how many tags one post may carry.
+	maxPostTags = 10
+	// maxTagLength matches the size of the tags.slug and tags.name columns.
+	maxTagLength = 64
+)
+
+type TagServiceImpl struct {
+	DB            ports.Database
+	TagRepository ports.TagRepository
+	CtxTimeout    time.Duration
+}
+
+func (s *TagServiceImpl) List(c context.Context) ([]entities.Tag, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	tags, err := s.TagRepository.List(ctx, tx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list tags")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// postTags turns the tag names given for a post into tags, sorted by slug. Names with the
+// same slug are the same tag, the first of them names it.
+func postTags(names []string) ([]entities.Tag, error) {
+	tags := []entities.Tag{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if utf8.RuneCountInString(name) > maxTagLength {
+			return nil, appErrors.NewBadRequestError(fmt.Sprintf("Tags are at most %d characters long", maxTagLength), nil)
+		}
+		slug := entities.TagSlug(name)
+		if slug == "" {
+			return nil, appErrors.NewBadRequestError(fmt.Sprintf("Tag %q has no letters or digits", name), nil)
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		tags = append(tags, entities.Tag{Slug: slug, Name: name})
+	}
+
+	if len(tags) > maxPostTags {
+		return nil, appErrors.NewBadRequestError(fmt.Sprintf("A post has at most %d tags", maxPostTags), nil)
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Slug < tags[j].Slug })
+	return tags, nil
+}
+
+// tagSlugs returns the slugs of tags, in order.
+func tagSlugs(tags []entities.Tag) []string {
+	slugs := make([]string, len(tags))
+	for i, tag := range tags {
+		slugs[i] = tag.Slug
+	}
+	return slugs
+}
+
+// filterTagSlugs normalizes the tags of a listing filter: names become slugs, duplicates and
+// tags without a slug are dropped, and the rest is sorted so that equal filters share a cache key.
+func filterTagSlugs(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	slugs := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		slug := entities.TagSlug(tag)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	return slugs
+}
@@ -0,0 +1,263 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	appErrors "github.com/chud-lori/go-boilerplate/pkg/errors"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagService_List(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockTagRepo := new(mocks.MockTagRepository)
+	service := &services.TagServiceImpl{DB: mockDB, TagRepository: mockTagRepo, CtxTimeout: 2 * time.Second}
+
+	tags := []entities.Tag{{Slug: "go", Name: "Go", PostCount: 3}}
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+	mockTagRepo.On("List", mock.Anything, mockTx).Return(tags, nil).Once()
+
+	got, err := service.List(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, tags, got)
+	mockTx.AssertExpectations(t)
+}
+
+func TestPostService_Create_SetsTags(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTagRepo := new(mocks.MockTagRepository)
+	mockCache := new(mocks.MockCache)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		TagRepository:          mockTagRepo,
+		Cache:                  mockCache,
+		UserRepository:         mockUserRepo,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	user := &entities.User{ID: uuid.New()}
+	post := &entities.Post{ID: uuid.New(), User: user, Title: "Tagged", Tags: []string{"Web Dev", " Go ", "go"}}
+	mockUserRepo.On("FindById", mock.Anything, mockTx, user.ID.String()).Return(user, nil).Once()
+	mockPostRepo.On("Save", mock.Anything, mockTx, post).Return(post, nil).Once()
+	mockTagRepo.On("SetPostTags", mock.Anything, mockTx, post.ID, []entities.Tag{
+		{Slug: "go", Name: "Go"},
+		{Slug: "web-dev", Name: "Web Dev"},
+	}).Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+
+	result, err := service.Create(ctx, post)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "web-dev"}, result.Tags)
+	mockTagRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestPostService_Create_RejectsInvalidTags(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTagRepo := new(mocks.MockTagRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		TagRepository:          mockTagRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	for name, tags := range map[string][]string{
+		"no letters":    {"go", "!!!"},
+		"too many tags": {"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.Create(ctx, &entities.Post{User: &entities.User{ID: uuid.New()}, Tags: tags})
+
+			var appErr *appErrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, 400, appErr.StatusCode)
+		})
+	}
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestPostService_Update_KeepsTagsWhenOmitted(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTagRepo := new(mocks.MockTagRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		TagRepository:          mockTagRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	existing := postOwnedBy(postID, authorID)
+	existing.Tags = []string{"go"}
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(existing, nil).Once()
+	mockPostRepo.On("Update", mock.Anything, mockTx, mock.Anything).Return(&entities.Post{ID: postID, Title: "New"}, nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+
+	result, err := service.Update(ctx, authorClaims(authorID), &entities.Post{ID: postID, Title: "New"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, result.Tags)
+	mockTagRepo.AssertNotCalled(t, "SetPostTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostService_Update_ReplacesTags(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTagRepo := new(mocks.MockTagRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		TagRepository:          mockTagRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	postID := uuid.New()
+	authorID := uuid.New()
+	existing := postOwnedBy(postID, authorID)
+	existing.Tags = []string{"go"}
+	mockPostRepo.On("GetById", mock.Anything, mockTx, postID).Return(existing, nil).Once()
+	mockPostRepo.On("Update", mock.Anything, mockTx, mock.Anything).Return(&entities.Post{ID: postID, Title: "New"}, nil).Once()
+	mockTagRepo.On("SetPostTags", mock.Anything, mockTx, postID, []entities.Tag{}).Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+
+	result, err := service.Update(ctx, authorClaims(authorID), &entities.Post{ID: postID, Title: "New", Tags: []string{}})
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Tags)
+	mockTagRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestPostService_GetAll_TagFilterInCacheKey(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTagRepo := new(mocks.MockTagRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		TagRepository:          mockTagRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	// Names and duplicates normalize to the same key as the sorted slugs.
+	hasher := sha256.New()
+	hasher.Write([]byte("search=:page=1:limit=10:tags=go,web-dev:match=all"))
+	cacheKey := "posts:tags:" + hex.EncodeToString(hasher.Sum(nil))
+	cached, _ := json.Marshal([]entities.Post{{Title: "Cached", Tags: []string{"go", "web-dev"}}})
+	mockCache.On("Get", mock.Anything, cacheKey).Return(string(cached), nil).Once()
+
+	posts, err := service.GetAll(ctx, nil, entities.PostFilter{Tags: []string{"Web Dev", "go", "GO"}, MatchAllTags: true}, 1, 10)
+
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, "Cached", posts[0].Title)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+	mockPostRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostService_GetAll_PassesNormalizedTags(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockTagRepo := new(mocks.MockTagRepository)
+	mockCache := new(mocks.MockCache)
+
+	service := &services.PostServiceImpl{
+		DB:                     mockDB,
+		PostRepository:         mockPostRepo,
+		PostRevisionRepository: acceptRevisions(),
+		TagRepository:          mockTagRepo,
+		Cache:                  mockCache,
+		CtxTimeout:             2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockCache.On("Get", mock.Anything, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "posts:tags:")
+	})).Return("", nil).Once()
+	mockPostRepo.On("GetAll", mock.Anything, mockTx, entities.PostFilter{Search: "x", Tags: []string{"go", "web"}}, entities.PaginationParams{Page: 1, Limit: 10}).
+		Return([]entities.Post{}, nil).Once()
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, 30*time.Second).Return(nil).Once()
+
+	_, err := service.GetAll(ctx, nil, entities.PostFilter{Search: "x", Tags: []string{"web", "Go"}}, 1, 10)
+
+	require.NoError(t, err)
+	mockPostRepo.AssertExpectations(t)
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxTrashPageSize bounds how many rows one trash listing returns.
+	maxTrashPageSize  = 100
+	trashPurgeLockKey = "lock:trash_purge"
+)
+
+// TrashServiceImpl lists deleted posts and users for admins and purges them once they have
+// been in the trash for longer than Retention.
+type TrashServiceImpl struct {
+	DB             ports.Database
+	PostRepository ports.PostRepository
+	UserRepository ports.UserRepository
+	// Locking keeps several API instances from purging at the same time. Nil purges without a lock.
+	Locking ports.Locking
+	// AuditLogger records purges that removed something. Nil disables it.
+	AuditLogger ports.AuditLogger
+	Retention   time.Duration
+	CtxTimeout  time.Duration
+}
+
+func (s *TrashServiceImpl) ListPosts(c context.Context, page, limit int) ([]entities.Post, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	posts, err := s.PostRepository.GetDeleted(ctx, tx, trashPage(page, limit))
+	if err != nil {
+		logger.WithError(err).Error("Failed to list deleted posts")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (s *TrashServiceImpl) ListUsers(c context.Context, page, limit int) ([]*entities.User, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	users, err := s.UserRepository.FindDeleted(ctx, tx, trashPage(page, limit))
+	if err != nil {
+		logger.WithError(err).Error("Failed to list deleted users")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (s *TrashServiceImpl) Purge(c context.Context) (*entities.TrashPurge, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	if s.Locking != nil {
+		acquired, lockValue, err := s.Locking.AcquireLock(trashPurgeLockKey, s.CtxTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if !acquired {
+			logger.Debug("Trash purge is running on another instance")
+			return nil, nil
+		}
+		defer func() {
+			if err := s.Locking.ReleaseLock(trashPurgeLockKey, lockValue); err != nil {
+				logger.WithError(err).Warn("Failed to release trash purge lock")
+			}
+		}()
+	}
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	cutoff := time.Now().Add(-s.Retention)
+	purged := &entities.TrashPurge{}
+
+	// Posts go first, the users they belong to cannot be removed while they exist.
+	if purged.Posts, err = s.PostRepository.Purge(ctx, tx, cutoff); err != nil {
+		logger.WithError(err).Error("Failed to purge posts")
+		return nil, err
+	}
+	if purged.Users, err = s.UserRepository.Purge(ctx, tx, cutoff); err != nil {
+		logger.WithError(err).Error("Failed to purge users")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	if purged.Posts > 0 || purged.Users > 0 {
+		logger.Infof("Purged %d posts and %d users deleted before %s", purged.Posts, purged.Users, cutoff.Format(time.RFC3339))
+		recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+			Action:  entities.AuditActionTrashPurge,
+			Outcome: entities.AuditOutcomeSuccess,
+			Details: map[string]string{
+				"posts": strconv.FormatInt(purged.Posts, 10),
+				"users": strconv.FormatInt(purged.Users, 10),
+			},
+		})
+	}
+
+	return purged, nil
+}
+
+// RunPurgeJob purges the trash every interval until ctx is done. ctx carries the job's logger.
+func (s *TrashServiceImpl) RunPurgeJob(ctx context.Context, interval time.Duration) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Purge(ctx); err != nil {
+			logger.WithError(err).Error("Trash purge failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func trashPage(page, limit int) entities.PaginationParams {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxTrashPageSize {
+		limit = maxTrashPageSize
+	}
+	return entities.PaginationParams{Page: page, Limit: limit}
+}
@@ -0,0 +1,182 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/services"
+	"github.com/chud-lori/go-boilerplate/mocks"
+	"github.com/chud-lori/go-boilerplate/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashService_ListPosts_ClampsLimit(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	service := &services.TrashServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		UserRepository: mockUserRepo,
+		Retention:      24 * time.Hour,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	posts := []entities.Post{{Title: "Deleted"}}
+	mockPostRepo.On("GetDeleted", mock.Anything, mockTx, entities.PaginationParams{Page: 1, Limit: 100}).Return(posts, nil).Once()
+
+	got, err := service.ListPosts(ctx, 0, 1000)
+
+	require.NoError(t, err)
+	assert.Equal(t, posts, got)
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestTrashService_Purge_PostsBeforeUsers(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.TrashServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		UserRepository: mockUserRepo,
+		Retention:      24 * time.Hour,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	var order []string
+	before := time.Now().Add(-24 * time.Hour)
+	mockPostRepo.On("Purge", mock.Anything, mockTx, mock.MatchedBy(func(cutoff time.Time) bool {
+		return !cutoff.Before(before) && cutoff.Before(time.Now().Add(-23*time.Hour))
+	})).Return(int64(3), nil).Run(func(mock.Arguments) { order = append(order, "posts") }).Once()
+	mockUserRepo.On("Purge", mock.Anything, mockTx, mock.Anything).Return(int64(1), nil).Run(func(mock.Arguments) { order = append(order, "users") }).Once()
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:  entities.AuditActionTrashPurge,
+		Outcome: entities.AuditOutcomeSuccess,
+		Details: map[string]string{"posts": "3", "users": "1"},
+	}).Once()
+
+	purged, err := service.Purge(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &entities.TrashPurge{Posts: 3, Users: 1}, purged)
+	assert.Equal(t, []string{"posts", "users"}, order)
+	mockTx.AssertCalled(t, "Commit")
+	mockAudit.AssertExpectations(t)
+}
+
+func TestTrashService_Purge_NothingToPurgeIsNotAudited(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.TrashServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		UserRepository: mockUserRepo,
+		Retention:      24 * time.Hour,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockPostRepo.On("Purge", mock.Anything, mockTx, mock.Anything).Return(int64(0), nil).Once()
+	mockUserRepo.On("Purge", mock.Anything, mockTx, mock.Anything).Return(int64(0), nil).Once()
+
+	purged, err := service.Purge(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &entities.TrashPurge{}, purged)
+	mockAudit.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+}
+
+func TestTrashService_Purge_SkipsWhenLockIsHeld(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockLock := new(mocks.MockLocking)
+
+	service := &services.TrashServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		UserRepository: mockUserRepo,
+		Retention:      24 * time.Hour,
+		Locking:        mockLock,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockLock.On("AcquireLock", "lock:trash_purge", service.CtxTimeout).Return(false, "", nil).Once()
+
+	purged, err := service.Purge(ctx)
+
+	require.NoError(t, err)
+	assert.Nil(t, purged)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+	mockPostRepo.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything, mock.Anything)
+	mockLock.AssertNotCalled(t, "ReleaseLock", mock.Anything, mock.Anything)
+}
+
+func TestTrashService_Purge_ReleasesLock(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockTx := new(mocks.MockTransaction)
+	mockPostRepo := new(mocks.MockPostRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockLock := new(mocks.MockLocking)
+
+	service := &services.TrashServiceImpl{
+		DB:             mockDB,
+		PostRepository: mockPostRepo,
+		UserRepository: mockUserRepo,
+		Retention:      24 * time.Hour,
+		Locking:        mockLock,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+
+	mockLock.On("AcquireLock", "lock:trash_purge", service.CtxTimeout).Return(true, "token", nil).Once()
+	mockLock.On("ReleaseLock", "lock:trash_purge", "token").Return(nil).Once()
+	mockPostRepo.On("Purge", mock.Anything, mockTx, mock.Anything).Return(int64(0), nil).Once()
+	mockUserRepo.On("Purge", mock.Anything, mockTx, mock.Anything).Return(int64(0), nil).Once()
+
+	_, err := service.Purge(ctx)
+
+	require.NoError(t, err)
+	mockLock.AssertExpectations(t)
+}
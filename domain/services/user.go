@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 
 	"time"
 
@@ -20,7 +21,11 @@ type UserServiceImpl struct {
 	ports.UserRepository
 	ports.Encryptor
 	ports.Cache
-	CtxTimeout time.Duration
+	// PasswordPolicy checks the passwords set on update. Nil accepts any password.
+	PasswordPolicy ports.PasswordPolicyService
+	// AuditLogger records changes to users. Nil disables it.
+	AuditLogger ports.AuditLogger
+	CtxTimeout  time.Duration
 }
 
 func (s *UserServiceImpl) Save(c context.Context, user *entities.User) (*entities.User, error) {
@@ -60,6 +65,8 @@ func (s *UserServiceImpl) Save(c context.Context, user *entities.User) (*entitie
 		return nil, err
 	}
 
+	s.auditUser(ctx, entities.AuditActionUserCreate, result.ID.String(), nil)
+
 	return result, nil
 }
 
@@ -69,6 +76,11 @@ func (s *UserServiceImpl) Update(c context.Context, user *entities.User) (*entit
 	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
 	defer cancel()
 
+	if err := validatePassword(ctx, s.PasswordPolicy, user.Password, user.Email); err != nil {
+		logger.Warn("Password rejected by policy")
+		return nil, err
+	}
+
 	tx, err := s.DB.BeginTx(ctx)
 	if err != nil {
 		logger.WithError(err).Error("Failed to begin transaction")
@@ -107,6 +119,8 @@ func (s *UserServiceImpl) Update(c context.Context, user *entities.User) (*entit
 		return nil, err
 	}
 
+	s.auditUser(ctx, entities.AuditActionUserUpdate, user.ID.String(), nil)
+
 	return result, nil
 }
 
@@ -146,9 +160,57 @@ func (s *UserServiceImpl) Delete(c context.Context, id string) error {
 		return err
 	}
 
+	s.invalidateUserListings(ctx, logger)
+
+	s.auditUser(ctx, entities.AuditActionUserDelete, id, nil)
+
 	return nil
 }
 
+// Restore takes a user out of the trash, together with their posts.
+func (s *UserServiceImpl) Restore(c context.Context, id string) (*entities.User, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := s.UserRepository.Restore(ctx, tx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrUserNotFound):
+			err = appErrors.NewNotFoundError("User not found in trash", err)
+		case errors.Is(err, appErrors.ErrConflict):
+			err = appErrors.NewConflictError("The email address is used by another account now", err)
+		default:
+			logger.WithError(err).Error("Failed to restore user")
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	s.invalidateUserListings(ctx, logger)
+
+	s.auditUser(ctx, entities.AuditActionUserRestore, id, nil)
+
+	return user, nil
+}
+
 func (s *UserServiceImpl) FindById(c context.Context, id string) (*entities.User, error) {
 	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
 
@@ -231,3 +293,80 @@ func (s *UserServiceImpl) FindAll(c context.Context) ([]*entities.User, error) {
 
 	return users, nil
 }
+
+func (s *UserServiceImpl) SetRoles(c context.Context, id string, roles []string) (*entities.User, error) {
+	logger, _ := c.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	ctx, cancel := context.WithTimeout(c, s.CtxTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction")
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = s.UserRepository.FindById(ctx, tx, id); err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			logger.Warnf("UserID %s not found", id)
+			return nil, appErrors.NewNotFoundError("User not found", err)
+		}
+		logger.WithError(err).Error("Database error")
+		return nil, err
+	}
+
+	if err = s.UserRepository.SetRoles(ctx, tx, id, roles); err != nil {
+		if errors.Is(err, appErrors.ErrDataNotFound) {
+			return nil, appErrors.NewBadRequestError("Unknown role", err)
+		}
+		logger.WithError(err).Error("Failed to set roles")
+		return nil, err
+	}
+
+	result, err := s.UserRepository.FindById(ctx, tx, id)
+	if err != nil {
+		logger.WithError(err).Error("Database error")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithError(err).Error("Failed to commit transaction")
+		return nil, err
+	}
+
+	if err := s.Cache.Delete(ctx, "users"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate users cache")
+	}
+
+	s.auditUser(ctx, entities.AuditActionUserSetRoles, id, map[string]string{"roles": strings.Join(roles, ",")})
+
+	return result, nil
+}
+
+// invalidateUserListings drops the cached user and post listings after a user was moved to
+// or out of the trash, since their posts are hidden and shown with them.
+func (s *UserServiceImpl) invalidateUserListings(ctx context.Context, logger logrus.FieldLogger) {
+	if err := s.Cache.Delete(ctx, "users"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate users cache")
+	}
+	if err := s.Cache.InvalidateByPrefix(ctx, "posts:"); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate 'posts:' cache keys. Stale data might be served.")
+	}
+}
+
+// auditUser records a successful change to the user with the given id by the caller.
+func (s *UserServiceImpl) auditUser(ctx context.Context, action, id string, details map[string]string) {
+	recordAudit(ctx, s.AuditLogger, &entities.AuditEvent{
+		Action:     action,
+		TargetType: entities.AuditTargetUser,
+		TargetID:   id,
+		Outcome:    entities.AuditOutcomeSuccess,
+		Details:    details,
+	})
+}
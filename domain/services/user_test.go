@@ -152,6 +152,31 @@ func TestUserService_Update_Success(t *testing.T) {
 	mockTx.AssertExpectations(t)
 }
 
+func TestUserService_Update_PasswordRejectedByPolicy(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockEnc := new(mocks.MockEncryptor)
+	mockPolicy := new(mocks.MockPasswordPolicyService)
+
+	service := &services.UserServiceImpl{
+		DB:             mockDB,
+		Encryptor:      mockEnc,
+		PasswordPolicy: mockPolicy,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	user := &entities.User{ID: uuid.New(), Email: "user@mail.com", Password: "short"}
+	rejected := &appErrors.ValidationErrors{Messages: []string{"Password must be at least 8 characters long"}}
+	mockPolicy.On("Validate", mock.Anything, "short", "user@mail.com").Return(rejected)
+
+	result, err := service.Update(ctx, user)
+
+	assert.Equal(t, rejected, err)
+	assert.Nil(t, result)
+	mockDB.AssertNotCalled(t, "BeginTx", mock.Anything)
+	mockEnc.AssertNotCalled(t, "HashPassword", mock.Anything)
+}
+
 func TestUserService_Update_UserNotFound(t *testing.T) {
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
 	mockDB := new(mocks.MockDatabase)
@@ -212,16 +237,55 @@ func TestUserService_Delete_Success(t *testing.T) {
 	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
 	mockRepo.On("Delete", mock.Anything, mockTx, userId).Return(nil)
 	mockTx.On("Commit").Return(nil)
+	// The user's posts leave the post listings with them.
+	mockCache.On("Delete", mock.Anything, "users").Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
 
 	err := service.Delete(ctx, userId)
 
 	assert.NoError(t, err)
 
+	mockCache.AssertExpectations(t)
 	mockDB.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 	mockTx.AssertExpectations(t)
 }
 
+func TestUserService_Delete_IsAudited(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockTx := new(mocks.MockTransaction)
+	mockCache := new(mocks.MockCache)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.UserServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		Cache:          mockCache,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	id := uuid.NewString()
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("Delete", mock.Anything, mockTx, id).Return(nil)
+	mockTx.On("Commit").Return(nil)
+	mockCache.On("Delete", mock.Anything, "users").Return(nil)
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil)
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:     entities.AuditActionUserDelete,
+		TargetType: entities.AuditTargetUser,
+		TargetID:   id,
+		Outcome:    entities.AuditOutcomeSuccess,
+	}).Once()
+
+	err := service.Delete(ctx, id)
+
+	assert.NoError(t, err)
+	mockAudit.AssertExpectations(t)
+}
+
 func TestUserService_Delete_UserNotFound(t *testing.T) {
 	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
 	mockDB := new(mocks.MockDatabase)
@@ -421,3 +485,159 @@ func TestUserService_FindAll_SuccessCache(t *testing.T) {
 	mockCache.AssertNotCalled(t, "Set")
 	mockTx.AssertNotCalled(t, "Commit") // Assuming Commit is the only Tx method
 }
+
+func TestUserService_SetRoles_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCache := new(mocks.MockCache)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.UserServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		Cache:          mockCache,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	id := uuid.New()
+	roles := []string{entities.RoleAdmin}
+	before := &entities.User{ID: id, Roles: []string{entities.RoleUser}}
+	after := &entities.User{ID: id, Roles: roles}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, id.String()).Return(before, nil).Once()
+	mockRepo.On("SetRoles", mock.Anything, mockTx, id.String(), roles).Return(nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, id.String()).Return(after, nil).Once()
+	mockTx.On("Commit").Return(nil)
+	mockCache.On("Delete", mock.Anything, "users").Return(nil)
+
+	result, err := service.SetRoles(ctx, id.String(), roles)
+
+	assert.NoError(t, err)
+	assert.Equal(t, roles, result.Roles)
+	mockRepo.AssertExpectations(t)
+	mockTx.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestUserService_SetRoles_UnknownRole(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.UserServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	id := uuid.New()
+	roles := []string{"superuser"}
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, id.String()).Return(&entities.User{ID: id}, nil)
+	mockRepo.On("SetRoles", mock.Anything, mockTx, id.String(), roles).Return(appErrors.ErrDataNotFound)
+	mockTx.On("Rollback").Return(nil)
+
+	result, err := service.SetRoles(ctx, id.String(), roles)
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.StatusCode)
+	assert.Equal(t, "Unknown role", appErr.Message)
+	mockTx.AssertExpectations(t)
+}
+
+func TestUserService_SetRoles_UserNotFound(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.UserServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	id := uuid.New().String()
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("FindById", mock.Anything, mockTx, id).Return(nil, appErrors.ErrUserNotFound)
+	mockTx.On("Rollback").Return(nil)
+
+	_, err := service.SetRoles(ctx, id, []string{entities.RoleAdmin})
+
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "SetRoles", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserService_Restore_Success(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockCache := new(mocks.MockCache)
+	mockTx := new(mocks.MockTransaction)
+	mockAudit := new(mocks.MockAuditLogger)
+
+	service := &services.UserServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		Cache:          mockCache,
+		AuditLogger:    mockAudit,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	user := &entities.User{ID: uuid.New(), Email: "restored@example.com"}
+	id := user.ID.String()
+
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("Restore", mock.Anything, mockTx, id).Return(user, nil)
+	mockTx.On("Commit").Return(nil)
+	mockCache.On("Delete", mock.Anything, "users").Return(nil).Once()
+	mockCache.On("InvalidateByPrefix", mock.Anything, "posts:").Return(nil).Once()
+	mockAudit.On("Log", mock.Anything, &entities.AuditEvent{
+		Action:     entities.AuditActionUserRestore,
+		TargetType: entities.AuditTargetUser,
+		TargetID:   id,
+		Outcome:    entities.AuditOutcomeSuccess,
+	}).Once()
+
+	result, err := service.Restore(ctx, id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, user, result)
+	mockCache.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestUserService_Restore_EmailTaken(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.LoggerContextKey, logrus.NewEntry(logrus.New()))
+	mockDB := new(mocks.MockDatabase)
+	mockRepo := new(mocks.MockUserRepository)
+	mockTx := new(mocks.MockTransaction)
+
+	service := &services.UserServiceImpl{
+		DB:             mockDB,
+		UserRepository: mockRepo,
+		CtxTimeout:     2 * time.Second,
+	}
+
+	id := uuid.NewString()
+	mockDB.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockRepo.On("Restore", mock.Anything, mockTx, id).Return(nil, appErrors.ErrConflict)
+	mockTx.On("Rollback").Return(nil)
+
+	result, err := service.Restore(ctx, id)
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*appErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.StatusCode)
+	mockTx.AssertExpectations(t)
+}
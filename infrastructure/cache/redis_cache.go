@@ -65,6 +65,49 @@ func (r *RedisCache) Set(ctx context.Context, key string, value []byte, expirati
 	return nil
 }
 
+func (r *RedisCache) SetNX(ctx context.Context, key string, value []byte, expiration time.Duration) (bool, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	set, err := r.client.SetNX(ctx, key, value, expiration).Result()
+	if err != nil {
+		logger.WithError(err).Warnf("failed to set key '%s' in Redis: %v", key, err)
+		return false, fmt.Errorf("failed to set key '%s' in Redis: %w", key, err)
+	}
+	return set, nil
+}
+
+func (r *RedisCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	var incr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Warnf("failed to increment key '%s' in Redis: %v", key, err)
+		return 0, fmt.Errorf("failed to increment key '%s' in Redis: %w", key, err)
+	}
+	return incr.Val(), nil
+}
+
+func (r *RedisCache) Decr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	logger, _ := ctx.Value(logger.LoggerContextKey).(logrus.FieldLogger)
+
+	var decr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		decr = pipe.Decr(ctx, key)
+		pipe.ExpireNX(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Warnf("failed to decrement key '%s' in Redis: %v", key, err)
+		return 0, fmt.Errorf("failed to decrement key '%s' in Redis: %w", key, err)
+	}
+	return decr.Val(), nil
+}
+
 func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	err := r.client.Del(ctx, key).Err()
 	if err != nil {
@@ -53,6 +53,49 @@ func TestRedisCache(t *testing.T) {
 		assert.Equal(t, "", got)
 	})
 
+	t.Run("SetNX", func(t *testing.T) {
+		key := "test-setnx"
+
+		set, err := cache.SetNX(ctx, key, []byte("first"), 10*time.Second)
+		assert.NoError(t, err)
+		assert.True(t, set)
+
+		set, err = cache.SetNX(ctx, key, []byte("second"), 10*time.Second)
+		assert.NoError(t, err)
+		assert.False(t, set)
+
+		got, err := cache.Get(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", got)
+	})
+
+	t.Run("Incr", func(t *testing.T) {
+		key := "test-incr"
+
+		n, err := cache.Incr(ctx, key, 10*time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		n, err = cache.Incr(ctx, key, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		got, err := cache.Get(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", got)
+	})
+
+	t.Run("Decr", func(t *testing.T) {
+		key := "test-decr"
+
+		_, err := cache.Incr(ctx, key, 10*time.Second)
+		assert.NoError(t, err)
+
+		n, err := cache.Decr(ctx, key, 10*time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+	})
+
 	// --- New Test for InvalidateByPrefix ---
 	t.Run("InvalidateByPrefix", func(t *testing.T) {
 		prefix := "posts:"
@@ -0,0 +1,114 @@
+package counter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// dirtyKey is the set of counter groups changed since they were last popped.
+const dirtyKey = "counters:dirty"
+
+type RedisCounterStore struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisCounterStore(addr string, password string, db int, logger *logrus.Logger) (ports.CounterStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	redisLogger := logger.WithFields(logrus.Fields{
+		"layer":  "counter_driver",
+		"driver": addr,
+	})
+
+	_, err := client.Ping(ctx).Result()
+	if err != nil {
+		redisLogger.WithError(err).Error("Redis counter connection error")
+		return nil, fmt.Errorf("failed to connect to Redis for counters: %w", err)
+	}
+
+	return &RedisCounterStore{client: client, logger: redisLogger}, nil
+}
+
+func (s *RedisCounterStore) Incr(ctx context.Context, key, field string, delta int64) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.HIncrBy(ctx, key, field, delta)
+		pipe.SAdd(ctx, dirtyKey, key)
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to increment counter %s of %s", field, key)
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (s *RedisCounterStore) GetAll(ctx context.Context, key string) (map[string]int64, error) {
+	values, err := s.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to get counters of %s", key)
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	counts := make(map[string]int64, len(values))
+	for field, value := range values {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("counter %s of %s is not a number: %w", field, key, err)
+		}
+		counts[field] = n
+	}
+	return counts, nil
+}
+
+func (s *RedisCounterStore) Replace(ctx context.Context, key string, counts map[string]int64) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(counts) > 0 {
+			values := make(map[string]interface{}, len(counts))
+			for field, n := range counts {
+				values[field] = n
+			}
+			pipe.HSet(ctx, key, values)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to replace counters of %s", key)
+	}
+	return err
+}
+
+func (s *RedisCounterStore) MarkDirty(ctx context.Context, key string) error {
+	return s.client.SAdd(ctx, dirtyKey, key).Err()
+}
+
+func (s *RedisCounterStore) PopDirty(ctx context.Context, max int) ([]string, error) {
+	keys, err := s.client.SPopN(ctx, dirtyKey, int64(max)).Result()
+	if err != nil && err != redis.Nil {
+		s.logger.WithError(err).Error("Failed to pop dirty counters")
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *RedisCounterStore) Close() error {
+	return s.client.Close()
+}
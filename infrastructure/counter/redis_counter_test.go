@@ -0,0 +1,75 @@
+package counter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/infrastructure/counter"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisCounterStore(t *testing.T) {
+	addr, err := testutils.GetRedisAddr()
+	require.NoError(t, err)
+
+	store, err := counter.NewRedisCounterStore(addr, "", 0, logrus.New())
+	require.NoError(t, err)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	t.Run("Missing group", func(t *testing.T) {
+		counts, err := store.GetAll(ctx, "test-counters-missing")
+		assert.NoError(t, err)
+		assert.Nil(t, counts)
+	})
+
+	t.Run("Incr marks the group dirty", func(t *testing.T) {
+		key := "test-counters-incr"
+
+		n, err := store.Incr(ctx, key, "like", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		n, err = store.Incr(ctx, key, "like", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		_, err = store.Incr(ctx, key, "sad", -1)
+		assert.NoError(t, err)
+
+		counts, err := store.GetAll(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int64{"like": 2, "sad": -1}, counts)
+
+		dirty, err := store.PopDirty(ctx, 100)
+		assert.NoError(t, err)
+		assert.Contains(t, dirty, key)
+
+		dirty, err = store.PopDirty(ctx, 100)
+		assert.NoError(t, err)
+		assert.NotContains(t, dirty, key)
+	})
+
+	t.Run("Replace drops the old counters", func(t *testing.T) {
+		key := "test-counters-replace"
+
+		_, err := store.Incr(ctx, key, "wow", 5)
+		assert.NoError(t, err)
+		assert.NoError(t, store.Replace(ctx, key, map[string]int64{"like": 3, "wow": 0}))
+
+		counts, err := store.GetAll(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int64{"like": 3, "wow": 0}, counts)
+	})
+
+	t.Run("MarkDirty", func(t *testing.T) {
+		key := "test-counters-dirty"
+
+		assert.NoError(t, store.MarkDirty(ctx, key))
+		dirty, err := store.PopDirty(ctx, 100)
+		assert.NoError(t, err)
+		assert.Contains(t, dirty, key)
+	})
+}
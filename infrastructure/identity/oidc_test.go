@@ -0,0 +1,90 @@
+package identity_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/infrastructure/identity"
+	"github.com/chud-lori/go-boilerplate/internal/testutils"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T) (*identity.OIDCProvider, *testutils.FakeOIDCProvider) {
+	fake := testutils.NewFakeOIDCProvider("client-id", "client-secret")
+	t.Cleanup(fake.Close)
+
+	provider := identity.NewOIDCProvider(identity.OIDCConfig{
+		Name:         "fake",
+		Issuer:       fake.Issuer(),
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:8080/api/auth/oidc/fake/callback",
+	})
+	return provider, fake
+}
+
+func TestOIDCProvider_AuthorizationCodeFlow(t *testing.T) {
+	provider, fake := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := auth.RandomToken(32)
+	authURL, err := provider.AuthCodeURL(ctx, "the-state", "the-nonce", auth.PKCEChallenge(verifier))
+	require.NoError(t, err)
+	assert.Contains(t, authURL, fake.Issuer()+"/authorize?")
+
+	code, state, err := fake.Authorize(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "the-state", state)
+
+	got, err := provider.Exchange(ctx, code, verifier, "the-nonce")
+	require.NoError(t, err)
+	assert.Equal(t, "fake", got.Provider)
+	assert.Equal(t, "fake-subject", got.Subject)
+	assert.Equal(t, "social@example.com", got.Email)
+	assert.True(t, got.EmailVerified)
+	assert.Equal(t, "Social User", got.Name)
+
+	// The code is single use.
+	_, err = provider.Exchange(ctx, code, verifier, "the-nonce")
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_Exchange_WrongVerifier(t *testing.T) {
+	provider, fake := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := auth.RandomToken(32)
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", auth.PKCEChallenge(verifier))
+	require.NoError(t, err)
+	code, _, err := fake.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(ctx, code, "some-other-verifier", "nonce")
+	assert.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestOIDCProvider_Exchange_NonceMismatch(t *testing.T) {
+	provider, fake := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := auth.RandomToken(32)
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", auth.PKCEChallenge(verifier))
+	require.NoError(t, err)
+	code, _, err := fake.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(ctx, code, verifier, "another-nonce")
+	assert.ErrorContains(t, err, "nonce")
+}
+
+func TestOIDCProvider_DiscoveryFailure(t *testing.T) {
+	fake := testutils.NewFakeOIDCProvider("client-id", "client-secret")
+	issuer := fake.Issuer()
+	fake.Close()
+
+	provider := identity.NewOIDCProvider(identity.OIDCConfig{Name: "fake", Issuer: issuer, ClientID: "client-id"})
+	_, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	assert.Error(t, err)
+}
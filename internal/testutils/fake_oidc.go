@@ -0,0 +1,222 @@
+package testutils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// FakeOIDCProvider is an in-process OpenID Connect provider for tests. It serves discovery,
+// JWKS, an authorize endpoint that signs in Identity without any prompt, and a token endpoint
+// that checks the client credentials and the PKCE verifier.
+type FakeOIDCProvider struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	// Identity is returned for the next authorization. EmailVerified controls the email_verified claim.
+	Identity entities.ExternalIdentity
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]fakeAuthorization
+}
+
+type fakeAuthorization struct {
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	identity      entities.ExternalIdentity
+}
+
+const fakeOIDCKeyID = "fake-oidc"
+
+// NewFakeOIDCProvider starts a provider accepting the given client. Close it when done.
+func NewFakeOIDCProvider(clientID, clientSecret string) *FakeOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	f := &FakeOIDCProvider{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Identity: entities.ExternalIdentity{
+			Subject:       "fake-subject",
+			Email:         "social@example.com",
+			EmailVerified: true,
+			Name:          "Social User",
+		},
+		key:   key,
+		codes: map[string]fakeAuthorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", f.handleDiscovery)
+	mux.HandleFunc("GET /jwks", f.handleJWKS)
+	mux.HandleFunc("GET /authorize", f.handleAuthorize)
+	mux.HandleFunc("POST /token", f.handleToken)
+	f.Server = httptest.NewServer(mux)
+
+	return f
+}
+
+func (f *FakeOIDCProvider) Issuer() string {
+	return f.Server.URL
+}
+
+func (f *FakeOIDCProvider) Close() {
+	f.Server.Close()
+}
+
+// Authorize follows authURL as a browser would and returns the code and state the provider
+// sends back to the redirect URI.
+func (f *FakeOIDCProvider) Authorize(authURL string) (code, state string, err error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("authorize returned status %d", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+func (f *FakeOIDCProvider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                 f.Issuer(),
+		"authorization_endpoint": f.Issuer() + "/authorize",
+		"token_endpoint":         f.Issuer() + "/token",
+		"jwks_uri":               f.Issuer() + "/jwks",
+	})
+}
+
+func (f *FakeOIDCProvider) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	pub := f.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": fakeOIDCKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (f *FakeOIDCProvider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("response_type") != "code" || q.Get("client_id") != f.ClientID {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+	if q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "pkce required", http.StatusBadRequest)
+		return
+	}
+
+	code := randomCode()
+	f.mu.Lock()
+	f.codes[code] = fakeAuthorization{
+		redirectURI:   q.Get("redirect_uri"),
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+		identity:      f.Identity,
+	}
+	f.mu.Unlock()
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (f *FakeOIDCProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("client_id") != f.ClientID || r.PostForm.Get("client_secret") != f.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// Codes are single use, like at a real provider.
+	f.mu.Lock()
+	auth, ok := f.codes[r.PostForm.Get("code")]
+	delete(f.codes, r.PostForm.Get("code"))
+	f.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || auth.redirectURI != r.PostForm.Get("redirect_uri") ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != auth.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            f.Issuer(),
+		"aud":            f.ClientID,
+		"sub":            auth.identity.Subject,
+		"email":          auth.identity.Email,
+		"email_verified": auth.identity.EmailVerified,
+		"name":           auth.identity.Name,
+		"nonce":          auth.nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	})
+	token.Header["kid"] = fakeOIDCKeyID
+	idToken, err := token.SignedString(f.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomCode(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func randomCode() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN verified_at TIMESTAMP NULL;
+
+-- Accounts created before email verification existed are treated as verified,
+-- so turning on REQUIRE_EMAIL_VERIFICATION does not lock them out.
+UPDATE users SET verified_at = created_at;
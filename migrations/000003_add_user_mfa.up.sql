@@ -0,0 +1,15 @@
+-- totp_secret is set on enrollment; two-factor is only enforced once mfa_enabled_at is set.
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR(64) NULL;
+ALTER TABLE users ADD COLUMN mfa_enabled_at TIMESTAMP NULL;
+
+CREATE TABLE user_recovery_codes (
+    id UUID DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    code_hash VARCHAR(255) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_recovery_codes_user_id ON user_recovery_codes (user_id);
@@ -0,0 +1,47 @@
+CREATE TABLE roles (
+    name VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE permissions (
+    name VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(64) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE user_roles (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role VARCHAR(64) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Manages every user and every post'),
+    ('user', 'Manages their own account and posts');
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:read', 'Read their own profile'),
+    ('users:write', 'Update or delete their own account'),
+    ('users:manage', 'Create, list, update and delete any user and assign roles'),
+    ('posts:write', 'Create posts and edit their own'),
+    ('posts:manage', 'Edit or delete any post');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('user', 'users:read'),
+    ('user', 'users:write'),
+    ('user', 'posts:write'),
+    ('admin', 'users:read'),
+    ('admin', 'users:write'),
+    ('admin', 'users:manage'),
+    ('admin', 'posts:write'),
+    ('admin', 'posts:manage');
+
+-- Every existing account becomes a regular user; admins are promoted explicitly.
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'user' FROM users;
@@ -0,0 +1,2 @@
+DELETE FROM permissions WHERE name = 'apikeys:manage';
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,22 @@
+-- Clients authenticate with X-API-KEY; only the SHA-256 hash of each key is stored.
+CREATE TABLE api_keys (
+    id UUID DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_by UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
+
+INSERT INTO permissions (name, description) VALUES
+    ('apikeys:manage', 'Create, list and revoke API keys');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'apikeys:manage');
@@ -0,0 +1,18 @@
+-- Tokens users mint for scripts. Only the SHA-256 hash of each token is stored.
+CREATE TABLE personal_access_tokens (
+    id UUID DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_personal_access_tokens_token_hash ON personal_access_tokens (token_hash);
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens (user_id);
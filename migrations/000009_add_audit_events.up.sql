@@ -0,0 +1,40 @@
+-- Security relevant actions. Rows are never changed once written, the trigger enforces it.
+CREATE TABLE audit_events (
+    id UUID DEFAULT gen_random_uuid(),
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- No foreign keys: events outlive the users and posts they mention.
+    actor_id UUID NULL,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL DEFAULT '',
+    target_id VARCHAR(64) NOT NULL DEFAULT '',
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    outcome VARCHAR(16) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_audit_events_occurred_at ON audit_events (occurred_at DESC);
+CREATE INDEX idx_audit_events_actor_id ON audit_events (actor_id, occurred_at DESC);
+CREATE INDEX idx_audit_events_target ON audit_events (target_type, target_id, occurred_at DESC);
+CREATE INDEX idx_audit_events_action ON audit_events (action, occurred_at DESC);
+
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+INSERT INTO permissions (name, description) VALUES
+    ('audit:read', 'Read the security audit log');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'audit:read');
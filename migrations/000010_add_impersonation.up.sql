@@ -0,0 +1,11 @@
+-- Set on events recorded while an admin was signed in as another user.
+ALTER TABLE audit_events ADD COLUMN impersonator_id UUID NULL;
+
+CREATE INDEX idx_audit_events_impersonator_id ON audit_events (impersonator_id, occurred_at DESC)
+    WHERE impersonator_id IS NOT NULL;
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:impersonate', 'Sign in as another user for support');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'users:impersonate');
@@ -0,0 +1,2 @@
+-- Bumped on every update, clients send it back in If-Match to detect concurrent edits.
+ALTER TABLE posts ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
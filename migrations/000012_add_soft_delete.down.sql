@@ -0,0 +1,12 @@
+-- Rows still in the trash would break the unique email constraint and come back to life.
+DELETE FROM posts WHERE deleted_at IS NOT NULL
+    OR author_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL);
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_email;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+DROP INDEX IF EXISTS idx_posts_deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
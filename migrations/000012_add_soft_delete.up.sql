@@ -0,0 +1,10 @@
+-- Deleted posts and users stay in the trash until the purge job removes them for good.
+ALTER TABLE posts ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP NULL;
+
+CREATE INDEX idx_posts_deleted_at ON posts (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- A deleted account does not hold on to its address, it can be used to sign up again.
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE UNIQUE INDEX idx_users_email ON users (email) WHERE deleted_at IS NULL;
@@ -0,0 +1,18 @@
+-- One row per version of a post, written in the same transaction as the change.
+-- revision is the post version the content belongs to.
+CREATE TABLE post_revisions (
+    post_id UUID NOT NULL,
+    revision INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    editor_id UUID NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, revision),
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+-- Earlier versions of existing posts are gone, their history starts with what they say now.
+INSERT INTO post_revisions (post_id, revision, title, body, editor_id, created_at)
+SELECT id, version, title, COALESCE(body, ''), author_id, updated_at
+FROM posts;
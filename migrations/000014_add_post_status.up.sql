@@ -0,0 +1,7 @@
+-- Posts written before statuses existed were public, they stay published.
+ALTER TABLE posts ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'published', 'archived'));
+-- publish_at schedules a draft, the publisher worker publishes it once the time has come.
+ALTER TABLE posts ADD COLUMN publish_at TIMESTAMP NULL;
+
+CREATE INDEX idx_posts_publish_at ON posts (publish_at) WHERE status = 'draft' AND publish_at IS NOT NULL;
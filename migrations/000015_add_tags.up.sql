@@ -0,0 +1,18 @@
+-- Tags are shared between posts and identified by their slug, the name is kept as first written.
+CREATE TABLE tags (
+    id UUID DEFAULT gen_random_uuid(),
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE post_tags (
+    post_id UUID NOT NULL,
+    tag_id UUID NOT NULL,
+    PRIMARY KEY (post_id, tag_id),
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_tags_tag_id ON post_tags (tag_id);
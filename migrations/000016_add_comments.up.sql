@@ -0,0 +1,20 @@
+-- Comments on posts. A reply points at its parent; deleted comments are kept, blanked out,
+-- so that the replies below them stay in place.
+CREATE TABLE comments (
+    id UUID DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL,
+    parent_id UUID NULL,
+    author_id UUID NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES comments (id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+-- Pages are read in (created_at, id) order, per post and per parent.
+CREATE INDEX idx_comments_post_id_created_at ON comments (post_id, created_at, id);
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS post_reaction_counts;
+DROP TABLE IF EXISTS post_reactions;
@@ -0,0 +1,22 @@
+-- One row per reaction, a user leaves each type at most once on a post.
+CREATE TABLE post_reactions (
+    post_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('like', 'love', 'laugh', 'wow', 'sad', 'angry')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id, type),
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_reactions_user_id ON post_reactions (user_id, post_id);
+
+-- The counts are served from Redis and reconciled into this table, post listings read them
+-- from here instead of counting post_reactions.
+CREATE TABLE post_reaction_counts (
+    post_id UUID NOT NULL,
+    counts JSONB NOT NULL DEFAULT '{}',
+    reconciled_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id),
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+-- Full-text search over title and body, stemmed with the english configuration. The title
+-- weighs more than the body when ranking. Searches must use the same configuration to match,
+-- see postSearchConfig in the post repository.
+ALTER TABLE posts ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(body, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
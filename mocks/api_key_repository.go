@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyRepository is a mock type for the APIKeyRepository type
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+// Save provides a mock function with given fields: ctx, tx, key
+func (m *MockAPIKeyRepository) Save(ctx context.Context, tx ports.Transaction, key *entities.APIKey) (*entities.APIKey, error) {
+	args := m.Called(ctx, tx, key)
+	var r0 *entities.APIKey
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.APIKey)
+	}
+	return r0, args.Error(1)
+}
+
+// FindByHash provides a mock function with given fields: ctx, tx, keyHash
+func (m *MockAPIKeyRepository) FindByHash(ctx context.Context, tx ports.Transaction, keyHash string) (*entities.APIKey, error) {
+	args := m.Called(ctx, tx, keyHash)
+	var r0 *entities.APIKey
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.APIKey)
+	}
+	return r0, args.Error(1)
+}
+
+// FindAll provides a mock function with given fields: ctx, tx
+func (m *MockAPIKeyRepository) FindAll(ctx context.Context, tx ports.Transaction) ([]*entities.APIKey, error) {
+	args := m.Called(ctx, tx)
+	var r0 []*entities.APIKey
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.APIKey)
+	}
+	return r0, args.Error(1)
+}
+
+// Revoke provides a mock function with given fields: ctx, tx, id
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, tx ports.Transaction, id uuid.UUID) (*entities.APIKey, error) {
+	args := m.Called(ctx, tx, id)
+	var r0 *entities.APIKey
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.APIKey)
+	}
+	return r0, args.Error(1)
+}
+
+// TouchLastUsed provides a mock function with given fields: ctx, tx, id
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, tx ports.Transaction, id uuid.UUID) error {
+	args := m.Called(ctx, tx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyService is a mock type for the APIKeyService type
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, key
+func (m *MockAPIKeyService) Create(ctx context.Context, key *entities.APIKey) (*entities.APIKey, string, error) {
+	args := m.Called(ctx, key)
+	var r0 *entities.APIKey
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.APIKey)
+	}
+	return r0, args.String(1), args.Error(2)
+}
+
+// List provides a mock function with given fields: ctx
+func (m *MockAPIKeyService) List(ctx context.Context) ([]*entities.APIKey, error) {
+	args := m.Called(ctx)
+	var r0 []*entities.APIKey
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.APIKey)
+	}
+	return r0, args.Error(1)
+}
+
+// Revoke provides a mock function with given fields: ctx, id
+func (m *MockAPIKeyService) Revoke(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Authenticate provides a mock function with given fields: ctx, key
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, key string) (*entities.APIKey, error) {
+	args := m.Called(ctx, key)
+	var r0 *entities.APIKey
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.APIKey)
+	}
+	return r0, args.Error(1)
+}
@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditLogger is a mock type for the AuditLogger type
+type MockAuditLogger struct {
+	mock.Mock
+}
+
+// Log provides a mock function with given fields: ctx, event
+func (m *MockAuditLogger) Log(ctx context.Context, event *entities.AuditEvent) {
+	m.Called(ctx, event)
+}
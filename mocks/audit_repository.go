@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditRepository is a mock type for the AuditRepository type
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+// Save provides a mock function with given fields: ctx, tx, event
+func (m *MockAuditRepository) Save(ctx context.Context, tx ports.Transaction, event *entities.AuditEvent) error {
+	args := m.Called(ctx, tx, event)
+	return args.Error(0)
+}
+
+// Find provides a mock function with given fields: ctx, tx, filter, pagination
+func (m *MockAuditRepository) Find(ctx context.Context, tx ports.Transaction, filter entities.AuditEventFilter, pagination entities.PaginationParams) ([]*entities.AuditEvent, int, error) {
+	args := m.Called(ctx, tx, filter, pagination)
+	var r0 []*entities.AuditEvent
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.AuditEvent)
+	}
+	return r0, args.Int(1), args.Error(2)
+}
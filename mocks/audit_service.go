@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditService is a mock type for the AuditService type
+type MockAuditService struct {
+	mock.Mock
+}
+
+// List provides a mock function with given fields: ctx, filter, page, limit
+func (m *MockAuditService) List(ctx context.Context, filter entities.AuditEventFilter, page, limit int) ([]*entities.AuditEvent, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	var r0 []*entities.AuditEvent
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.AuditEvent)
+	}
+	return r0, args.Int(1), args.Error(2)
+}
@@ -13,30 +13,110 @@ type MockAuthService struct {
 
 // SignIn mocks the SignIn method of the AuthService interface.
 // It records the call and returns the values configured by the expectations.
-func (m *MockAuthService) SignIn(ctx context.Context, user *entities.User) (*entities.User, string, error) {
+func (m *MockAuthService) SignIn(ctx context.Context, user *entities.User) (*entities.User, *entities.TokenPair, error) {
 	args := m.Called(ctx, user)
 	var r0 *entities.User
 	if args.Get(0) != nil {
 		r0 = args.Get(0).(*entities.User)
 	}
-	var r1 string
+	var r1 *entities.TokenPair
 	if args.Get(1) != nil {
-		r1 = args.Get(1).(string)
+		r1 = args.Get(1).(*entities.TokenPair)
 	}
 	return r0, r1, args.Error(2)
 }
 
 // SignUp mocks the SignUp method of the AuthService interface.
 // It records the call and returns the values configured by the expectations.
-func (m *MockAuthService) SignUp(ctx context.Context, user *entities.User) (*entities.User, string, error) {
+func (m *MockAuthService) SignUp(ctx context.Context, user *entities.User) (*entities.User, *entities.TokenPair, error) {
 	args := m.Called(ctx, user)
 	var r0 *entities.User
 	if args.Get(0) != nil {
 		r0 = args.Get(0).(*entities.User)
 	}
-	var r1 string
+	var r1 *entities.TokenPair
 	if args.Get(1) != nil {
-		r1 = args.Get(1).(string)
+		r1 = args.Get(1).(*entities.TokenPair)
 	}
 	return r0, r1, args.Error(2)
 }
+
+// Refresh mocks the Refresh method of the AuthService interface.
+func (m *MockAuthService) Refresh(ctx context.Context, refreshToken string) (*entities.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	var r0 *entities.TokenPair
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.TokenPair)
+	}
+	return r0, args.Error(1)
+}
+
+// SignOut mocks the SignOut method of the AuthService interface.
+func (m *MockAuthService) SignOut(ctx context.Context, claims *entities.TokenClaims) error {
+	args := m.Called(ctx, claims)
+	return args.Error(0)
+}
+
+// ForgotPassword mocks the ForgotPassword method of the AuthService interface.
+func (m *MockAuthService) ForgotPassword(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+// ResetPassword mocks the ResetPassword method of the AuthService interface.
+func (m *MockAuthService) ResetPassword(ctx context.Context, email, code, newPassword string) error {
+	args := m.Called(ctx, email, code, newPassword)
+	return args.Error(0)
+}
+
+// VerifyEmail mocks the VerifyEmail method of the AuthService interface.
+func (m *MockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+// ResendVerification mocks the ResendVerification method of the AuthService interface.
+func (m *MockAuthService) ResendVerification(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+// SignInMFA mocks the SignInMFA method of the AuthService interface.
+func (m *MockAuthService) SignInMFA(ctx context.Context, mfaToken, code string) (*entities.User, *entities.TokenPair, error) {
+	args := m.Called(ctx, mfaToken, code)
+	var r0 *entities.User
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.User)
+	}
+	var r1 *entities.TokenPair
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(*entities.TokenPair)
+	}
+	return r0, r1, args.Error(2)
+}
+
+// StartSocialLogin mocks the StartSocialLogin method of the AuthService interface.
+func (m *MockAuthService) StartSocialLogin(ctx context.Context, provider string) (string, error) {
+	args := m.Called(ctx, provider)
+	return args.String(0), args.Error(1)
+}
+
+// CompleteSocialLogin mocks the CompleteSocialLogin method of the AuthService interface.
+func (m *MockAuthService) CompleteSocialLogin(ctx context.Context, provider, code, state string) (*entities.User, *entities.TokenPair, error) {
+	args := m.Called(ctx, provider, code, state)
+	var r0 *entities.User
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.User)
+	}
+	var r1 *entities.TokenPair
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(*entities.TokenPair)
+	}
+	return r0, r1, args.Error(2)
+}
+
+// Impersonate mocks the Impersonate method of the AuthService interface.
+func (m *MockAuthService) Impersonate(ctx context.Context, impersonatorID, userID string) (string, error) {
+	args := m.Called(ctx, impersonatorID, userID)
+	return args.String(0), args.Error(1)
+}
@@ -24,6 +24,24 @@ func (m *MockCache) Set(ctx context.Context, key string, value []byte, expiratio
 	return args.Error(0)
 }
 
+// SetNX mocks the SetNX method of the ports.Cache interface.
+func (m *MockCache) SetNX(ctx context.Context, key string, value []byte, expiration time.Duration) (bool, error) {
+	args := m.Called(ctx, key, value, expiration)
+	return args.Bool(0), args.Error(1)
+}
+
+// Incr mocks the Incr method of the ports.Cache interface.
+func (m *MockCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	args := m.Called(ctx, key, expiration)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Decr mocks the Decr method of the ports.Cache interface.
+func (m *MockCache) Decr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	args := m.Called(ctx, key, expiration)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // Delete mocks the Delete method of the ports.Cache interface.
 func (m *MockCache) Delete(ctx context.Context, key string) error {
 	args := m.Called(ctx, key)
@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCommentRepository is a mock type for the CommentRepository type
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+// Save provides a mock function with given fields: ctx, tx, comment
+func (m *MockCommentRepository) Save(ctx context.Context, tx ports.Transaction, comment *entities.Comment) (*entities.Comment, error) {
+	args := m.Called(ctx, tx, comment)
+	var r0 *entities.Comment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.Comment)
+	}
+	return r0, args.Error(1)
+}
+
+// GetById provides a mock function with given fields: ctx, tx, id
+func (m *MockCommentRepository) GetById(ctx context.Context, tx ports.Transaction, id uuid.UUID) (*entities.Comment, error) {
+	args := m.Called(ctx, tx, id)
+	var r0 *entities.Comment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.Comment)
+	}
+	return r0, args.Error(1)
+}
+
+// Update provides a mock function with given fields: ctx, tx, id, body
+func (m *MockCommentRepository) Update(ctx context.Context, tx ports.Transaction, id uuid.UUID, body string) (*entities.Comment, error) {
+	args := m.Called(ctx, tx, id, body)
+	var r0 *entities.Comment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.Comment)
+	}
+	return r0, args.Error(1)
+}
+
+// Delete provides a mock function with given fields: ctx, tx, id
+func (m *MockCommentRepository) Delete(ctx context.Context, tx ports.Transaction, id uuid.UUID) error {
+	args := m.Called(ctx, tx, id)
+	return args.Error(0)
+}
+
+// ListByPost provides a mock function with given fields: ctx, tx, postID, after, limit
+func (m *MockCommentRepository) ListByPost(ctx context.Context, tx ports.Transaction, postID uuid.UUID, after *entities.CommentCursor, limit int) ([]*entities.Comment, error) {
+	args := m.Called(ctx, tx, postID, after, limit)
+	var r0 []*entities.Comment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.Comment)
+	}
+	return r0, args.Error(1)
+}
+
+// ListThreads provides a mock function with given fields: ctx, tx, postID, after, limit, maxReplies
+func (m *MockCommentRepository) ListThreads(ctx context.Context, tx ports.Transaction, postID uuid.UUID, after *entities.CommentCursor, limit, maxReplies int) ([]*entities.Comment, error) {
+	args := m.Called(ctx, tx, postID, after, limit, maxReplies)
+	var r0 []*entities.Comment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.Comment)
+	}
+	return r0, args.Error(1)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCommentService is a mock type for the CommentService type
+type MockCommentService struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, actor, comment
+func (m *MockCommentService) Create(ctx context.Context, actor *entities.TokenClaims, comment *entities.Comment) (*entities.Comment, error) {
+	args := m.Called(ctx, actor, comment)
+	var r0 *entities.Comment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.Comment)
+	}
+	return r0, args.Error(1)
+}
+
+// Update provides a mock function with given fields: ctx, actor, postID, id, body
+func (m *MockCommentService) Update(ctx context.Context, actor *entities.TokenClaims, postID, id uuid.UUID, body string) (*entities.Comment, error) {
+	args := m.Called(ctx, actor, postID, id, body)
+	var r0 *entities.Comment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.Comment)
+	}
+	return r0, args.Error(1)
+}
+
+// Delete provides a mock function with given fields: ctx, actor, postID, id
+func (m *MockCommentService) Delete(ctx context.Context, actor *entities.TokenClaims, postID, id uuid.UUID) error {
+	args := m.Called(ctx, actor, postID, id)
+	return args.Error(0)
+}
+
+// List provides a mock function with given fields: ctx, actor, postID, cursor, limit, tree
+func (m *MockCommentService) List(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, cursor string, limit int, tree bool) (*entities.CommentPage, error) {
+	args := m.Called(ctx, actor, postID, cursor, limit, tree)
+	var r0 *entities.CommentPage
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.CommentPage)
+	}
+	return r0, args.Error(1)
+}
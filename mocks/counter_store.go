@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCounterStore is a mock type for the CounterStore type
+type MockCounterStore struct {
+	mock.Mock
+}
+
+// Incr provides a mock function with given fields: ctx, key, field, delta
+func (m *MockCounterStore) Incr(ctx context.Context, key, field string, delta int64) (int64, error) {
+	args := m.Called(ctx, key, field, delta)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// GetAll provides a mock function with given fields: ctx, key
+func (m *MockCounterStore) GetAll(ctx context.Context, key string) (map[string]int64, error) {
+	args := m.Called(ctx, key)
+	var r0 map[string]int64
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(map[string]int64)
+	}
+	return r0, args.Error(1)
+}
+
+// Replace provides a mock function with given fields: ctx, key, counts
+func (m *MockCounterStore) Replace(ctx context.Context, key string, counts map[string]int64) error {
+	args := m.Called(ctx, key, counts)
+	return args.Error(0)
+}
+
+// MarkDirty provides a mock function with given fields: ctx, key
+func (m *MockCounterStore) MarkDirty(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+// PopDirty provides a mock function with given fields: ctx, max
+func (m *MockCounterStore) PopDirty(ctx context.Context, max int) ([]string, error) {
+	args := m.Called(ctx, max)
+	var r0 []string
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]string)
+	}
+	return r0, args.Error(1)
+}
+
+// Close provides a mock function with given fields:
+func (m *MockCounterStore) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
@@ -27,3 +27,10 @@ func (m *MockEncryptor) CompareHash(hash, password string) error {
 	args := m.Called(hash, password)
 	return args.Error(0)
 }
+
+// NeedsRehash mocks the NeedsRehash method of the Encryptor interface.
+// It records the call and returns the result configured by the expectations.
+func (m *MockEncryptor) NeedsRehash(hash string) bool {
+	args := m.Called(hash)
+	return args.Bool(0)
+}
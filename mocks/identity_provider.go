@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIdentityProvider is a mock type for the IdentityProvider type
+type MockIdentityProvider struct {
+	mock.Mock
+}
+
+// Name provides a mock function with given fields:
+func (m *MockIdentityProvider) Name() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+// AuthCodeURL provides a mock function with given fields: ctx, state, nonce, codeChallenge
+func (m *MockIdentityProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	args := m.Called(ctx, state, nonce, codeChallenge)
+	return args.String(0), args.Error(1)
+}
+
+// Exchange provides a mock function with given fields: ctx, code, codeVerifier, nonce
+func (m *MockIdentityProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entities.ExternalIdentity, error) {
+	args := m.Called(ctx, code, codeVerifier, nonce)
+	var r0 *entities.ExternalIdentity
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.ExternalIdentity)
+	}
+	return r0, args.Error(1)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIdentityRepository is a mock type for the IdentityRepository type
+type MockIdentityRepository struct {
+	mock.Mock
+}
+
+// Save provides a mock function with given fields: ctx, tx, identity
+func (m *MockIdentityRepository) Save(ctx context.Context, tx ports.Transaction, identity *entities.UserIdentity) (*entities.UserIdentity, error) {
+	args := m.Called(ctx, tx, identity)
+	var r0 *entities.UserIdentity
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.UserIdentity)
+	}
+	return r0, args.Error(1)
+}
+
+// FindByProviderSubject provides a mock function with given fields: ctx, tx, provider, subject
+func (m *MockIdentityRepository) FindByProviderSubject(ctx context.Context, tx ports.Transaction, provider, subject string) (*entities.UserIdentity, error) {
+	args := m.Called(ctx, tx, provider, subject)
+	var r0 *entities.UserIdentity
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.UserIdentity)
+	}
+	return r0, args.Error(1)
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLocking is a mock type for the Locking type
+type MockLocking struct {
+	mock.Mock
+}
+
+// AcquireLock provides a mock function with given fields: lockKey, ttl
+func (m *MockLocking) AcquireLock(lockKey string, ttl time.Duration) (bool, string, error) {
+	args := m.Called(lockKey, ttl)
+	return args.Bool(0), args.String(1), args.Error(2)
+}
+
+// ReleaseLock provides a mock function with given fields: lockKey, uniqueValue
+func (m *MockLocking) ReleaseLock(lockKey string, uniqueValue string) error {
+	args := m.Called(lockKey, uniqueValue)
+	return args.Error(0)
+}
+
+// Close provides a mock function with given fields:
+func (m *MockLocking) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
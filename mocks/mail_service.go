@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -14,3 +15,18 @@ func (_m *MockMailService) SendSignInNotification(ctx context.Context, email, te
 	args := _m.Called(ctx, email, text)
 	return args.Error(0)
 }
+
+func (_m *MockMailService) SendPasswordResetCode(ctx context.Context, email, code string) error {
+	args := _m.Called(ctx, email, code)
+	return args.Error(0)
+}
+
+func (_m *MockMailService) SendVerificationEmail(ctx context.Context, email, link string) error {
+	args := _m.Called(ctx, email, link)
+	return args.Error(0)
+}
+
+func (_m *MockMailService) SendAccountLockedNotification(ctx context.Context, email string, until time.Time) error {
+	args := _m.Called(ctx, email, until)
+	return args.Error(0)
+}
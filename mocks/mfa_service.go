@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMFAService is a mock implementation of the MFAService interface.
+type MockMFAService struct {
+	mock.Mock
+}
+
+// Enroll mocks the Enroll method of the MFAService interface.
+func (m *MockMFAService) Enroll(ctx context.Context, userID string) (*entities.MFAEnrollment, error) {
+	args := m.Called(ctx, userID)
+	var r0 *entities.MFAEnrollment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.MFAEnrollment)
+	}
+	return r0, args.Error(1)
+}
+
+// Verify mocks the Verify method of the MFAService interface.
+func (m *MockMFAService) Verify(ctx context.Context, userID, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+// Disable mocks the Disable method of the MFAService interface.
+func (m *MockMFAService) Disable(ctx context.Context, userID, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPasswordPolicyService is a mock implementation of the PasswordPolicyService interface.
+type MockPasswordPolicyService struct {
+	mock.Mock
+}
+
+func (m *MockPasswordPolicyService) Validate(ctx context.Context, password, email string) error {
+	args := m.Called(ctx, password, email)
+	return args.Error(0)
+}
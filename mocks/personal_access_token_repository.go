@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPersonalAccessTokenRepository is a mock type for the PersonalAccessTokenRepository type
+type MockPersonalAccessTokenRepository struct {
+	mock.Mock
+}
+
+// Save provides a mock function with given fields: ctx, tx, token
+func (m *MockPersonalAccessTokenRepository) Save(ctx context.Context, tx ports.Transaction, token *entities.PersonalAccessToken) (*entities.PersonalAccessToken, error) {
+	args := m.Called(ctx, tx, token)
+	var r0 *entities.PersonalAccessToken
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.PersonalAccessToken)
+	}
+	return r0, args.Error(1)
+}
+
+// FindByHash provides a mock function with given fields: ctx, tx, tokenHash
+func (m *MockPersonalAccessTokenRepository) FindByHash(ctx context.Context, tx ports.Transaction, tokenHash string) (*entities.PersonalAccessToken, error) {
+	args := m.Called(ctx, tx, tokenHash)
+	var r0 *entities.PersonalAccessToken
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.PersonalAccessToken)
+	}
+	return r0, args.Error(1)
+}
+
+// FindByUser provides a mock function with given fields: ctx, tx, userID
+func (m *MockPersonalAccessTokenRepository) FindByUser(ctx context.Context, tx ports.Transaction, userID string) ([]*entities.PersonalAccessToken, error) {
+	args := m.Called(ctx, tx, userID)
+	var r0 []*entities.PersonalAccessToken
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.PersonalAccessToken)
+	}
+	return r0, args.Error(1)
+}
+
+// Revoke provides a mock function with given fields: ctx, tx, userID, id
+func (m *MockPersonalAccessTokenRepository) Revoke(ctx context.Context, tx ports.Transaction, userID string, id uuid.UUID) error {
+	args := m.Called(ctx, tx, userID, id)
+	return args.Error(0)
+}
+
+// TouchLastUsed provides a mock function with given fields: ctx, tx, id
+func (m *MockPersonalAccessTokenRepository) TouchLastUsed(ctx context.Context, tx ports.Transaction, id uuid.UUID) error {
+	args := m.Called(ctx, tx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPersonalAccessTokenService is a mock type for the PersonalAccessTokenService type
+type MockPersonalAccessTokenService struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, userID, token
+func (m *MockPersonalAccessTokenService) Create(ctx context.Context, userID string, token *entities.PersonalAccessToken) (*entities.PersonalAccessToken, string, error) {
+	args := m.Called(ctx, userID, token)
+	var r0 *entities.PersonalAccessToken
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.PersonalAccessToken)
+	}
+	return r0, args.String(1), args.Error(2)
+}
+
+// List provides a mock function with given fields: ctx, userID
+func (m *MockPersonalAccessTokenService) List(ctx context.Context, userID string) ([]*entities.PersonalAccessToken, error) {
+	args := m.Called(ctx, userID)
+	var r0 []*entities.PersonalAccessToken
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.PersonalAccessToken)
+	}
+	return r0, args.Error(1)
+}
+
+// Revoke provides a mock function with given fields: ctx, userID, id
+func (m *MockPersonalAccessTokenService) Revoke(ctx context.Context, userID string, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+// Authenticate provides a mock function with given fields: ctx, token
+func (m *MockPersonalAccessTokenService) Authenticate(ctx context.Context, token string) (*entities.TokenClaims, error) {
+	args := m.Called(ctx, token)
+	var r0 *entities.TokenClaims
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.TokenClaims)
+	}
+	return r0, args.Error(1)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/domain/ports"
@@ -36,9 +37,20 @@ func (_m *MockPostRepository) Update(ctx context.Context, tx ports.Transaction,
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, tx, id
-func (_m *MockPostRepository) Delete(ctx context.Context, tx ports.Transaction, id uuid.UUID) error {
-	args := _m.Called(ctx, tx, id)
+// Patch provides a mock function with given fields: ctx, tx, id, version, patch
+func (_m *MockPostRepository) Patch(ctx context.Context, tx ports.Transaction, id uuid.UUID, version int, patch entities.PostPatch) (*entities.Post, error) {
+	args := _m.Called(ctx, tx, id, version, patch)
+	var r0 *entities.Post
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.Post)
+	}
+	r1 := args.Error(1)
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, tx, id, version
+func (_m *MockPostRepository) Delete(ctx context.Context, tx ports.Transaction, id uuid.UUID, version int) error {
+	args := _m.Called(ctx, tx, id, version)
 	r0 := args.Error(0)
 	return r0
 }
@@ -54,9 +66,9 @@ func (_m *MockPostRepository) GetById(ctx context.Context, tx ports.Transaction,
 	return r0, r1
 }
 
-// GetAll provides a mock function with given fields: ctx, tx, search, pagination
-func (_m *MockPostRepository) GetAll(ctx context.Context, tx ports.Transaction, search string, pagination entities.PaginationParams) ([]entities.Post, error) {
-	args := _m.Called(ctx, tx, search, pagination)
+// GetAll provides a mock function with given fields: ctx, tx, filter, pagination
+func (_m *MockPostRepository) GetAll(ctx context.Context, tx ports.Transaction, filter entities.PostFilter, pagination entities.PaginationParams) ([]entities.Post, error) {
+	args := _m.Called(ctx, tx, filter, pagination)
 	var r0 []entities.Post
 	if args.Get(0) != nil {
 		r0 = args.Get(0).([]entities.Post) // Note: Slice of structs, not pointers
@@ -64,3 +76,64 @@ func (_m *MockPostRepository) GetAll(ctx context.Context, tx ports.Transaction,
 	r1 := args.Error(1)
 	return r0, r1
 }
+
+// GetByAuthor provides a mock function with given fields: ctx, tx, authorID, status, pagination
+func (_m *MockPostRepository) GetByAuthor(ctx context.Context, tx ports.Transaction, authorID uuid.UUID, status entities.PostStatus, pagination entities.PaginationParams) ([]entities.Post, error) {
+	args := _m.Called(ctx, tx, authorID, status, pagination)
+	var r0 []entities.Post
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]entities.Post)
+	}
+	r1 := args.Error(1)
+	return r0, r1
+}
+
+// SetStatus provides a mock function with given fields: ctx, tx, id, version, status, publishAt
+func (_m *MockPostRepository) SetStatus(ctx context.Context, tx ports.Transaction, id uuid.UUID, version int, status entities.PostStatus, publishAt *time.Time) (*entities.Post, error) {
+	args := _m.Called(ctx, tx, id, version, status, publishAt)
+	var r0 *entities.Post
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.Post)
+	}
+	r1 := args.Error(1)
+	return r0, r1
+}
+
+// PublishDue provides a mock function with given fields: ctx, tx, now
+func (_m *MockPostRepository) PublishDue(ctx context.Context, tx ports.Transaction, now time.Time) ([]uuid.UUID, error) {
+	args := _m.Called(ctx, tx, now)
+	var r0 []uuid.UUID
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]uuid.UUID)
+	}
+	r1 := args.Error(1)
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, tx, id
+func (_m *MockPostRepository) Restore(ctx context.Context, tx ports.Transaction, id uuid.UUID) (*entities.Post, error) {
+	args := _m.Called(ctx, tx, id)
+	var r0 *entities.Post
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.Post)
+	}
+	r1 := args.Error(1)
+	return r0, r1
+}
+
+// GetDeleted provides a mock function with given fields: ctx, tx, pagination
+func (_m *MockPostRepository) GetDeleted(ctx context.Context, tx ports.Transaction, pagination entities.PaginationParams) ([]entities.Post, error) {
+	args := _m.Called(ctx, tx, pagination)
+	var r0 []entities.Post
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]entities.Post)
+	}
+	r1 := args.Error(1)
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, tx, cutoff
+func (_m *MockPostRepository) Purge(ctx context.Context, tx ports.Transaction, cutoff time.Time) (int64, error) {
+	args := _m.Called(ctx, tx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
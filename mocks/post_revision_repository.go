@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostRevisionRepository is a mock type for the PostRevisionRepository type
+type MockPostRevisionRepository struct {
+	mock.Mock
+}
+
+// Save provides a mock function with given fields: ctx, tx, revision
+func (m *MockPostRevisionRepository) Save(ctx context.Context, tx ports.Transaction, revision *entities.PostRevision) (*entities.PostRevision, error) {
+	args := m.Called(ctx, tx, revision)
+	var r0 *entities.PostRevision
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.PostRevision)
+	}
+	return r0, args.Error(1)
+}
+
+// FindByPost provides a mock function with given fields: ctx, tx, postID
+func (m *MockPostRevisionRepository) FindByPost(ctx context.Context, tx ports.Transaction, postID uuid.UUID) ([]*entities.PostRevision, error) {
+	args := m.Called(ctx, tx, postID)
+	var r0 []*entities.PostRevision
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.PostRevision)
+	}
+	return r0, args.Error(1)
+}
+
+// FindByRevision provides a mock function with given fields: ctx, tx, postID, revision
+func (m *MockPostRevisionRepository) FindByRevision(ctx context.Context, tx ports.Transaction, postID uuid.UUID, revision int) (*entities.PostRevision, error) {
+	args := m.Called(ctx, tx, postID, revision)
+	var r0 *entities.PostRevision
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.PostRevision)
+	}
+	return r0, args.Error(1)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/google/uuid"
@@ -22,33 +23,87 @@ func (_m *MockPostService) Create(ctx context.Context, post *entities.Post) (*en
 	return nil, args.Error(1)
 }
 
-// Update provides a mock function with given fields: ctx, post
-func (_m *MockPostService) Update(ctx context.Context, post *entities.Post) (*entities.Post, error) {
-	args := _m.Called(ctx, post)
+// Update provides a mock function with given fields: ctx, actor, post
+func (_m *MockPostService) Update(ctx context.Context, actor *entities.TokenClaims, post *entities.Post) (*entities.Post, error) {
+	args := _m.Called(ctx, actor, post)
+	if result := args.Get(0); result != nil {
+		return result.(*entities.Post), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Patch provides a mock function with given fields: ctx, actor, id, version, patch
+func (_m *MockPostService) Patch(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, version int, patch entities.PostPatch) (*entities.Post, error) {
+	args := _m.Called(ctx, actor, id, version, patch)
 	if result := args.Get(0); result != nil {
 		return result.(*entities.Post), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *MockPostService) Delete(ctx context.Context, id uuid.UUID) error {
-	args := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, actor, id, version
+func (_m *MockPostService) Delete(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, version int) error {
+	args := _m.Called(ctx, actor, id, version)
 	return args.Error(0)
 }
 
-// GetById provides a mock function with given fields: ctx, id
-func (_m *MockPostService) GetById(ctx context.Context, id uuid.UUID) (*entities.Post, error) {
-	args := _m.Called(ctx, id)
+// Restore provides a mock function with given fields: ctx, actor, id
+func (_m *MockPostService) Restore(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID) (*entities.Post, error) {
+	args := _m.Called(ctx, actor, id)
+	if result := args.Get(0); result != nil {
+		return result.(*entities.Post), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// SetStatus provides a mock function with given fields: ctx, actor, id, version, status, publishAt
+func (_m *MockPostService) SetStatus(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, version int, status entities.PostStatus, publishAt *time.Time) (*entities.Post, error) {
+	args := _m.Called(ctx, actor, id, version, status, publishAt)
+	if result := args.Get(0); result != nil {
+		return result.(*entities.Post), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetById provides a mock function with given fields: ctx, actor, id
+func (_m *MockPostService) GetById(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID) (*entities.Post, error) {
+	args := _m.Called(ctx, actor, id)
+	if result := args.Get(0); result != nil {
+		return result.(*entities.Post), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// ListRevisions provides a mock function with given fields: ctx, actor, id
+func (_m *MockPostService) ListRevisions(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID) ([]*entities.PostRevision, error) {
+	args := _m.Called(ctx, actor, id)
+	if result := args.Get(0); result != nil {
+		return result.([]*entities.PostRevision), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetRevision provides a mock function with given fields: ctx, actor, id, revision
+func (_m *MockPostService) GetRevision(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, revision int) (*entities.PostRevisionDiff, error) {
+	args := _m.Called(ctx, actor, id, revision)
+	if result := args.Get(0); result != nil {
+		return result.(*entities.PostRevisionDiff), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// RestoreRevision provides a mock function with given fields: ctx, actor, id, revision, version
+func (_m *MockPostService) RestoreRevision(ctx context.Context, actor *entities.TokenClaims, id uuid.UUID, revision int, version int) (*entities.Post, error) {
+	args := _m.Called(ctx, actor, id, revision, version)
 	if result := args.Get(0); result != nil {
 		return result.(*entities.Post), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-// GetAll provides a mock function with given fields: ctx, search, page, limit
-func (_m *MockPostService) GetAll(ctx context.Context, search string, page int, limit int) ([]entities.Post, error) {
-	args := _m.Called(ctx, search, page, limit)
+// GetAll provides a mock function with given fields: ctx, actor, filter, page, limit
+func (_m *MockPostService) GetAll(ctx context.Context, actor *entities.TokenClaims, filter entities.PostFilter, page int, limit int) ([]entities.Post, error) {
+	args := _m.Called(ctx, actor, filter, page, limit)
 	if result := args.Get(0); result != nil {
 		// Note: GetAll returns []entities.Post, not []*entities.Post
 		return args.Get(0).([]entities.Post), args.Error(1)
@@ -56,9 +111,18 @@ func (_m *MockPostService) GetAll(ctx context.Context, search string, page int,
 	return nil, args.Error(1)
 }
 
+// ListOwn provides a mock function with given fields: ctx, actor, status, page, limit
+func (_m *MockPostService) ListOwn(ctx context.Context, actor *entities.TokenClaims, status entities.PostStatus, page int, limit int) ([]entities.Post, error) {
+	args := _m.Called(ctx, actor, status, page, limit)
+	if result := args.Get(0); result != nil {
+		return result.([]entities.Post), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // StartAsyncUpload provides a mock function for async upload
-func (_m *MockPostService) StartAsyncUpload(ctx context.Context, postID uuid.UUID, fileName, fileType string, fileData []byte) (uuid.UUID, error) {
-	args := _m.Called(ctx, postID, fileName, fileType, fileData)
+func (_m *MockPostService) StartAsyncUpload(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, fileName, fileType string, fileData []byte) (uuid.UUID, error) {
+	args := _m.Called(ctx, actor, postID, fileName, fileType, fileData)
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
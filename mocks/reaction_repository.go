@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReactionRepository is a mock type for the ReactionRepository type
+type MockReactionRepository struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: ctx, tx, postID, userID, reaction
+func (m *MockReactionRepository) Add(ctx context.Context, tx ports.Transaction, postID, userID uuid.UUID, reaction entities.ReactionType) (bool, error) {
+	args := m.Called(ctx, tx, postID, userID, reaction)
+	return args.Bool(0), args.Error(1)
+}
+
+// Remove provides a mock function with given fields: ctx, tx, postID, userID, reaction
+func (m *MockReactionRepository) Remove(ctx context.Context, tx ports.Transaction, postID, userID uuid.UUID, reaction entities.ReactionType) (bool, error) {
+	args := m.Called(ctx, tx, postID, userID, reaction)
+	return args.Bool(0), args.Error(1)
+}
+
+// ListByUser provides a mock function with given fields: ctx, tx, userID, postIDs
+func (m *MockReactionRepository) ListByUser(ctx context.Context, tx ports.Transaction, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID][]entities.ReactionType, error) {
+	args := m.Called(ctx, tx, userID, postIDs)
+	var r0 map[uuid.UUID][]entities.ReactionType
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(map[uuid.UUID][]entities.ReactionType)
+	}
+	return r0, args.Error(1)
+}
+
+// Count provides a mock function with given fields: ctx, tx, postID
+func (m *MockReactionRepository) Count(ctx context.Context, tx ports.Transaction, postID uuid.UUID) (entities.ReactionCounts, error) {
+	args := m.Called(ctx, tx, postID)
+	var r0 entities.ReactionCounts
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(entities.ReactionCounts)
+	}
+	return r0, args.Error(1)
+}
+
+// SaveCounts provides a mock function with given fields: ctx, tx, postID, counts
+func (m *MockReactionRepository) SaveCounts(ctx context.Context, tx ports.Transaction, postID uuid.UUID, counts entities.ReactionCounts) error {
+	args := m.Called(ctx, tx, postID, counts)
+	return args.Error(0)
+}
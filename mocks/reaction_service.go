@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReactionService is a mock type for the ReactionService type
+type MockReactionService struct {
+	mock.Mock
+}
+
+// React provides a mock function with given fields: ctx, actor, postID, reaction
+func (m *MockReactionService) React(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, reaction entities.ReactionType) (*entities.PostReactions, error) {
+	args := m.Called(ctx, actor, postID, reaction)
+	var r0 *entities.PostReactions
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.PostReactions)
+	}
+	return r0, args.Error(1)
+}
+
+// Unreact provides a mock function with given fields: ctx, actor, postID, reaction
+func (m *MockReactionService) Unreact(ctx context.Context, actor *entities.TokenClaims, postID uuid.UUID, reaction entities.ReactionType) (*entities.PostReactions, error) {
+	args := m.Called(ctx, actor, postID, reaction)
+	var r0 *entities.PostReactions
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.PostReactions)
+	}
+	return r0, args.Error(1)
+}
+
+// Annotate provides a mock function with given fields: ctx, actor, posts
+func (m *MockReactionService) Annotate(ctx context.Context, actor *entities.TokenClaims, posts ...*entities.Post) error {
+	args := m.Called(ctx, actor, posts)
+	return args.Error(0)
+}
+
+// Reconcile provides a mock function with given fields: ctx
+func (m *MockReactionService) Reconcile(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRecoveryCodeRepository is a mock type for the RecoveryCodeRepository type
+type MockRecoveryCodeRepository struct {
+	mock.Mock
+}
+
+// ReplaceAll provides a mock function with given fields: ctx, tx, userID, codeHashes
+func (m *MockRecoveryCodeRepository) ReplaceAll(ctx context.Context, tx ports.Transaction, userID string, codeHashes []string) error {
+	args := m.Called(ctx, tx, userID, codeHashes)
+	return args.Error(0)
+}
+
+// FindUnused provides a mock function with given fields: ctx, tx, userID
+func (m *MockRecoveryCodeRepository) FindUnused(ctx context.Context, tx ports.Transaction, userID string) ([]*entities.RecoveryCode, error) {
+	args := m.Called(ctx, tx, userID)
+	var r0 []*entities.RecoveryCode
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.RecoveryCode)
+	}
+	return r0, args.Error(1)
+}
+
+// MarkUsed provides a mock function with given fields: ctx, tx, id
+func (m *MockRecoveryCodeRepository) MarkUsed(ctx context.Context, tx ports.Transaction, id string) error {
+	args := m.Called(ctx, tx, id)
+	return args.Error(0)
+}
+
+// DeleteAll provides a mock function with given fields: ctx, tx, userID
+func (m *MockRecoveryCodeRepository) DeleteAll(ctx context.Context, tx ports.Transaction, userID string) error {
+	args := m.Called(ctx, tx, userID)
+	return args.Error(0)
+}
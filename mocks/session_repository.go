@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSessionRepository is a mock type for the SessionRepository type
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, tx, session
+func (m *MockSessionRepository) Create(ctx context.Context, tx ports.Transaction, session *entities.Session) (*entities.Session, error) {
+	args := m.Called(ctx, tx, session)
+	var r0 *entities.Session
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.Session)
+	}
+	return r0, args.Error(1)
+}
+
+// FindActiveByUser provides a mock function with given fields: ctx, tx, userID
+func (m *MockSessionRepository) FindActiveByUser(ctx context.Context, tx ports.Transaction, userID string) ([]*entities.Session, error) {
+	args := m.Called(ctx, tx, userID)
+	var r0 []*entities.Session
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.Session)
+	}
+	return r0, args.Error(1)
+}
+
+// Touch provides a mock function with given fields: ctx, tx, id, expiresAt
+func (m *MockSessionRepository) Touch(ctx context.Context, tx ports.Transaction, id uuid.UUID, expiresAt time.Time) error {
+	args := m.Called(ctx, tx, id, expiresAt)
+	return args.Error(0)
+}
+
+// Revoke provides a mock function with given fields: ctx, tx, userID, id
+func (m *MockSessionRepository) Revoke(ctx context.Context, tx ports.Transaction, userID string, id uuid.UUID) error {
+	args := m.Called(ctx, tx, userID, id)
+	return args.Error(0)
+}
+
+// RevokeAll provides a mock function with given fields: ctx, tx, userID
+func (m *MockSessionRepository) RevokeAll(ctx context.Context, tx ports.Transaction, userID string) error {
+	args := m.Called(ctx, tx, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSessionService is a mock type for the SessionService type
+type MockSessionService struct {
+	mock.Mock
+}
+
+// List provides a mock function with given fields: ctx, userID
+func (m *MockSessionService) List(ctx context.Context, userID string) ([]*entities.Session, error) {
+	args := m.Called(ctx, userID)
+	var r0 []*entities.Session
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.Session)
+	}
+	return r0, args.Error(1)
+}
+
+// Revoke provides a mock function with given fields: ctx, userID, id
+func (m *MockSessionService) Revoke(ctx context.Context, userID string, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+// RevokeAll provides a mock function with given fields: ctx, userID
+func (m *MockSessionService) RevokeAll(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/domain/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagRepository is a mock type for the TagRepository type
+type MockTagRepository struct {
+	mock.Mock
+}
+
+// SetPostTags provides a mock function with given fields: ctx, tx, postID, tags
+func (m *MockTagRepository) SetPostTags(ctx context.Context, tx ports.Transaction, postID uuid.UUID, tags []entities.Tag) error {
+	args := m.Called(ctx, tx, postID, tags)
+	return args.Error(0)
+}
+
+// List provides a mock function with given fields: ctx, tx
+func (m *MockTagRepository) List(ctx context.Context, tx ports.Transaction) ([]entities.Tag, error) {
+	args := m.Called(ctx, tx)
+	var r0 []entities.Tag
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]entities.Tag)
+	}
+	return r0, args.Error(1)
+}
@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagService is a mock type for the TagService type
+type MockTagService struct {
+	mock.Mock
+}
+
+// List provides a mock function with given fields: ctx
+func (m *MockTagService) List(ctx context.Context) ([]entities.Tag, error) {
+	args := m.Called(ctx)
+	var r0 []entities.Tag
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]entities.Tag)
+	}
+	return r0, args.Error(1)
+}
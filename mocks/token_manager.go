@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -10,24 +11,52 @@ type MockTokenManager struct {
 	mock.Mock
 }
 
-// GenerateToken mocks the GenerateToken method of the TokenManager interface.
+// GenerateTokenPair mocks the GenerateTokenPair method of the TokenManager interface.
 // It records the call and returns the values configured by the expectations.
-func (m *MockTokenManager) GenerateToken(userID string) (string, error) {
-	args := m.Called(userID)
-	var r0 string
+func (m *MockTokenManager) GenerateTokenPair(user *entities.User, familyID string) (*entities.TokenPair, error) {
+	args := m.Called(user, familyID)
+	var r0 *entities.TokenPair
 	if args.Get(0) != nil {
-		r0 = args.Get(0).(string)
+		r0 = args.Get(0).(*entities.TokenPair)
 	}
 	return r0, args.Error(1)
 }
 
+// GenerateEmailVerificationToken mocks the GenerateEmailVerificationToken method of the TokenManager interface.
+func (m *MockTokenManager) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	args := m.Called(userID, email)
+	return args.String(0), args.Error(1)
+}
+
+// GenerateMFAToken mocks the GenerateMFAToken method of the TokenManager interface.
+func (m *MockTokenManager) GenerateMFAToken(userID string) (string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+// GenerateImpersonationToken mocks the GenerateImpersonationToken method of the TokenManager interface.
+func (m *MockTokenManager) GenerateImpersonationToken(user *entities.User, impersonatorID string) (string, error) {
+	args := m.Called(user, impersonatorID)
+	return args.String(0), args.Error(1)
+}
+
 // ValidateToken mocks the ValidateToken method of the TokenManager interface.
-// It records the call and returns the values configured by the expectations (userID and error).
-func (m *MockTokenManager) ValidateToken(tokenStr string) (string, error) {
+// It records the call and returns the values configured by the expectations (claims and error).
+func (m *MockTokenManager) ValidateToken(tokenStr string) (*entities.TokenClaims, error) {
 	args := m.Called(tokenStr)
-	var r0 string
+	var r0 *entities.TokenClaims
 	if args.Get(0) != nil {
-		r0 = args.Get(0).(string)
+		r0 = args.Get(0).(*entities.TokenClaims)
 	}
 	return r0, args.Error(1)
 }
+
+// JWKS mocks the JWKS method of the TokenManager interface.
+func (m *MockTokenManager) JWKS() []entities.JSONWebKey {
+	args := m.Called()
+	var r0 []entities.JSONWebKey
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]entities.JSONWebKey)
+	}
+	return r0
+}
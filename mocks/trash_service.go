@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTrashService is a mock type for the TrashService type
+type MockTrashService struct {
+	mock.Mock
+}
+
+// ListPosts provides a mock function with given fields: ctx, page, limit
+func (m *MockTrashService) ListPosts(ctx context.Context, page, limit int) ([]entities.Post, error) {
+	args := m.Called(ctx, page, limit)
+	var r0 []entities.Post
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]entities.Post)
+	}
+	return r0, args.Error(1)
+}
+
+// ListUsers provides a mock function with given fields: ctx, page, limit
+func (m *MockTrashService) ListUsers(ctx context.Context, page, limit int) ([]*entities.User, error) {
+	args := m.Called(ctx, page, limit)
+	var r0 []*entities.User
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.User)
+	}
+	return r0, args.Error(1)
+}
+
+// Purge provides a mock function with given fields: ctx
+func (m *MockTrashService) Purge(ctx context.Context) (*entities.TrashPurge, error) {
+	args := m.Called(ctx)
+	var r0 *entities.TrashPurge
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.TrashPurge)
+	}
+	return r0, args.Error(1)
+}
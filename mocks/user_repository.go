@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/domain/ports"
@@ -42,6 +43,30 @@ func (m *MockUserRepository) Delete(ctx context.Context, tx ports.Transaction, i
 	return args.Error(0) // Assuming Delete only returns an error, it's the first (and only) error
 }
 
+// MarkVerified provides a mock function with given fields: ctx, tx, id
+func (m *MockUserRepository) MarkVerified(ctx context.Context, tx ports.Transaction, id string) error {
+	args := m.Called(ctx, tx, id)
+	return args.Error(0)
+}
+
+// SetTOTPSecret provides a mock function with given fields: ctx, tx, id, secret
+func (m *MockUserRepository) SetTOTPSecret(ctx context.Context, tx ports.Transaction, id, secret string) error {
+	args := m.Called(ctx, tx, id, secret)
+	return args.Error(0)
+}
+
+// EnableMFA provides a mock function with given fields: ctx, tx, id
+func (m *MockUserRepository) EnableMFA(ctx context.Context, tx ports.Transaction, id string) error {
+	args := m.Called(ctx, tx, id)
+	return args.Error(0)
+}
+
+// SetRoles provides a mock function with given fields: ctx, tx, id, roles
+func (m *MockUserRepository) SetRoles(ctx context.Context, tx ports.Transaction, id string, roles []string) error {
+	args := m.Called(ctx, tx, id, roles)
+	return args.Error(0)
+}
+
 // FindById provides a mock function with given fields: ctx, tx, id
 func (m *MockUserRepository) FindById(ctx context.Context, tx ports.Transaction, id string) (*entities.User, error) {
 	args := m.Called(ctx, tx, id)
@@ -74,3 +99,31 @@ func (m *MockUserRepository) FindAll(ctx context.Context, tx ports.Transaction)
 	r1 := args.Error(1)
 	return r0, r1
 }
+
+// Restore provides a mock function with given fields: ctx, tx, id
+func (m *MockUserRepository) Restore(ctx context.Context, tx ports.Transaction, id string) (*entities.User, error) {
+	args := m.Called(ctx, tx, id)
+	var r0 *entities.User
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*entities.User)
+	}
+	r1 := args.Error(1)
+	return r0, r1
+}
+
+// FindDeleted provides a mock function with given fields: ctx, tx, pagination
+func (m *MockUserRepository) FindDeleted(ctx context.Context, tx ports.Transaction, pagination entities.PaginationParams) ([]*entities.User, error) {
+	args := m.Called(ctx, tx, pagination)
+	var r0 []*entities.User
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]*entities.User)
+	}
+	r1 := args.Error(1)
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, tx, cutoff
+func (m *MockUserRepository) Purge(ctx context.Context, tx ports.Transaction, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, tx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
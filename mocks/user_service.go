@@ -32,6 +32,14 @@ func (m *MockUserService) Delete(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+func (m *MockUserService) Restore(ctx context.Context, id string) (*entities.User, error) {
+	args := m.Called(ctx, id)
+	if result := args.Get(0); result != nil {
+		return result.(*entities.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockUserService) FindById(ctx context.Context, id string) (*entities.User, error) {
 	args := m.Called(ctx, id)
 	if result := args.Get(0); result != nil {
@@ -47,3 +55,11 @@ func (m *MockUserService) FindAll(ctx context.Context) ([]*entities.User, error)
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockUserService) SetRoles(ctx context.Context, id string, roles []string) (*entities.User, error) {
+	args := m.Called(ctx, id, roles)
+	if result := args.Get(0); result != nil {
+		return result.(*entities.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,29 @@
+package audit
+
+import "context"
+
+// ActorKey holds the ID of the authenticated user making the request.
+const ActorKey string = "audit_actor"
+
+// ImpersonatorKey holds the ID of the admin acting as the actor, when impersonating.
+const ImpersonatorKey string = "audit_impersonator"
+
+func WithActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, ActorKey, userID)
+}
+
+// ActorFromContext returns the actor stored by WithActor, or an empty string.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(ActorKey).(string)
+	return actor
+}
+
+func WithImpersonator(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, ImpersonatorKey, userID)
+}
+
+// ImpersonatorFromContext returns the admin stored by WithImpersonator, or an empty string.
+func ImpersonatorFromContext(ctx context.Context) string {
+	impersonator, _ := ctx.Value(ImpersonatorKey).(string)
+	return impersonator
+}
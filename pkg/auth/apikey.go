@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// apiKeyMarker and personalAccessTokenMarker start every generated key and token, so
+	// leaked ones are easy to grep for and the two kinds cannot be mistaken for each other.
+	apiKeyMarker              = "gbk_"
+	personalAccessTokenMarker = "gbp_"
+	// APIKeyPrefixLength is how many leading characters of a key are stored in clear.
+	APIKeyPrefixLength = 12
+)
+
+// GenerateAPIKey returns a new random API key with 256 bits of entropy.
+func GenerateAPIKey() (string, error) {
+	return generateSecret(apiKeyMarker)
+}
+
+// GeneratePersonalAccessToken returns a new random personal access token with 256 bits of
+// entropy. It is hashed and shortened with HashAPIKey and APIKeyPrefix like an API key.
+func GeneratePersonalAccessToken() (string, error) {
+	return generateSecret(personalAccessTokenMarker)
+}
+
+// IsPersonalAccessToken tells a personal access token apart from a JWT in an Authorization header.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, personalAccessTokenMarker)
+}
+
+func generateSecret(marker string) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return marker + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashAPIKey returns the hex SHA-256 of key. Keys are random, so a fast hash is enough
+// and lets the hash be looked up directly.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyPrefix returns the part of key that is kept in clear to identify it.
+func APIKeyPrefix(key string) string {
+	if len(key) <= APIKeyPrefixLength {
+		return key
+	}
+	return key[:APIKeyPrefixLength]
+}
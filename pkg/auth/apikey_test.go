@@ -0,0 +1,38 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	first, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	second, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "gbk_"))
+	assert.NotEqual(t, first, second)
+	assert.Len(t, auth.APIKeyPrefix(first), auth.APIKeyPrefixLength)
+}
+
+func TestGeneratePersonalAccessToken(t *testing.T) {
+	token, err := auth.GeneratePersonalAccessToken()
+	require.NoError(t, err)
+	key, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.True(t, auth.IsPersonalAccessToken(token))
+	assert.False(t, auth.IsPersonalAccessToken(key))
+	assert.False(t, auth.IsPersonalAccessToken("eyJhbGciOiJSUzI1NiJ9.e30.sig"))
+}
+
+func TestHashAPIKey(t *testing.T) {
+	assert.Equal(t, auth.HashAPIKey("gbk_key"), auth.HashAPIKey("gbk_key"))
+	assert.NotEqual(t, auth.HashAPIKey("gbk_key"), auth.HashAPIKey("gbk_other"))
+	assert.Len(t, auth.HashAPIKey("gbk_key"), 64)
+}
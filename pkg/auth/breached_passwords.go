@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// breachedPrefixLength is the length of the SHA-1 prefix hashes are bucketed by, as in the
+// Have I Been Pwned range API.
+const breachedPrefixLength = 5
+
+// BreachedPasswords is a local list of passwords known from data breaches, such as a subset of
+// the Have I Been Pwned SHA-1 corpus. Hashes are kept in buckets keyed by their first five hex
+// characters, so a lookup only compares the suffixes that share the password's prefix.
+type BreachedPasswords struct {
+	ranges map[string]map[string]struct{}
+}
+
+// LoadBreachedPasswords reads the list at path, see ReadBreachedPasswords for the format.
+func LoadBreachedPasswords(path string) (*BreachedPasswords, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadBreachedPasswords(f)
+}
+
+// ReadBreachedPasswords reads one upper or lower case SHA-1 hex digest per line, optionally
+// followed by ":count" as in the Have I Been Pwned downloads. Blank lines and lines starting
+// with # are skipped.
+func ReadBreachedPasswords(r io.Reader) (*BreachedPasswords, error) {
+	b := &BreachedPasswords{ranges: map[string]map[string]struct{}{}}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(entry, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("line %d: expected a SHA-1 hex digest", line)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		b.add(hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *BreachedPasswords) add(hash string) {
+	prefix, suffix := hash[:breachedPrefixLength], hash[breachedPrefixLength:]
+	suffixes, ok := b.ranges[prefix]
+	if !ok {
+		suffixes = map[string]struct{}{}
+		b.ranges[prefix] = suffixes
+	}
+	suffixes[suffix] = struct{}{}
+}
+
+// Contains reports whether password is on the list. A nil list contains nothing.
+func (b *BreachedPasswords) Contains(password string) bool {
+	if b == nil {
+		return false
+	}
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	_, ok := b.ranges[hash[:breachedPrefixLength]][hash[breachedPrefixLength:]]
+	return ok
+}
+
+// Len returns the number of hashes on the list.
+func (b *BreachedPasswords) Len() int {
+	if b == nil {
+		return 0
+	}
+	n := 0
+	for _, suffixes := range b.ranges {
+		n += len(suffixes)
+	}
+	return n
+}
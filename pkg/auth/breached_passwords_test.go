@@ -0,0 +1,44 @@
+package auth_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBreachedPasswords(t *testing.T) {
+	list, err := auth.ReadBreachedPasswords(strings.NewReader(`# sha1 of "password" and "123456"
+5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824
+
+7c4a8d09ca3762af61e59520943dc26494f8941b
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Contains("password"))
+	assert.True(t, list.Contains("123456"))
+	assert.False(t, list.Contains("Password"))
+	assert.False(t, list.Contains("correct horse battery staple"))
+}
+
+func TestReadBreachedPasswords_InvalidLine(t *testing.T) {
+	_, err := auth.ReadBreachedPasswords(strings.NewReader("5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8\nnot-a-hash\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestLoadBreachedPasswords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte("5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:1\n"), 0o600))
+
+	list, err := auth.LoadBreachedPasswords(path)
+	require.NoError(t, err)
+	assert.True(t, list.Contains("password"))
+
+	var none *auth.BreachedPasswords
+	assert.False(t, none.Contains("password"))
+}
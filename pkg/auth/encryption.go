@@ -1,16 +1,178 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-type BcryptEncryptor struct{}
+var (
+	// ErrPasswordMismatch is returned by CompareHash when the password does not match the hash.
+	ErrPasswordMismatch = errors.New("password does not match hash")
+	// ErrUnknownHashFormat is returned for hashes no encryptor here produced.
+	ErrUnknownHashFormat = errors.New("unknown password hash format")
+)
+
+const argon2idPrefix = "$argon2id$"
+
+// Argon2Params are the Argon2id cost parameters. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follow the second recommended option of RFC 9106 with 64 MiB of memory.
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Argon2idEncryptor hashes passwords with Argon2id in the PHC string format, e.g.
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>. It still verifies bcrypt hashes, picking the
+// algorithm from the hash prefix, and NeedsRehash reports every hash that is not Argon2id
+// with the current Params, so stored hashes can be upgraded when users sign in.
+type Argon2idEncryptor struct {
+	// Params left zero fall back to DefaultArgon2Params.
+	Params Argon2Params
+}
+
+func (e *Argon2idEncryptor) HashPassword(password string) (string, error) {
+	p := e.params()
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (e *Argon2idEncryptor) CompareHash(hash, password string) error {
+	switch {
+	case strings.HasPrefix(hash, argon2idPrefix):
+		p, salt, key, err := decodeArgon2idHash(hash)
+		if err != nil {
+			return err
+		}
+		computed := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+		if subtle.ConstantTimeCompare(computed, key) != 1 {
+			return ErrPasswordMismatch
+		}
+		return nil
+	case isBcryptHash(hash):
+		return compareBcrypt(hash, password)
+	default:
+		return ErrUnknownHashFormat
+	}
+}
+
+func (e *Argon2idEncryptor) NeedsRehash(hash string) bool {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return true
+	}
+	p, _, _, err := decodeArgon2idHash(hash)
+	return err != nil || p != e.params()
+}
+
+func (e *Argon2idEncryptor) params() Argon2Params {
+	p := e.Params
+	if p.Memory == 0 {
+		p.Memory = DefaultArgon2Params.Memory
+	}
+	if p.Iterations == 0 {
+		p.Iterations = DefaultArgon2Params.Iterations
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = DefaultArgon2Params.Parallelism
+	}
+	if p.SaltLength == 0 {
+		p.SaltLength = DefaultArgon2Params.SaltLength
+	}
+	if p.KeyLength == 0 {
+		p.KeyLength = DefaultArgon2Params.KeyLength
+	}
+	return p
+}
+
+// decodeArgon2idHash parses a PHC string written by HashPassword.
+func decodeArgon2idHash(hash string) (Argon2Params, []byte, []byte, error) {
+	var p Argon2Params
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, ErrUnknownHashFormat
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+
+	return p, salt, key, nil
+}
+
+// BcryptEncryptor hashes passwords with bcrypt. Argon2idEncryptor is preferred and can
+// verify and upgrade the hashes written by this one.
+type BcryptEncryptor struct {
+	// Cost left zero falls back to bcrypt.DefaultCost.
+	Cost int
+}
 
 func (e *BcryptEncryptor) HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), e.cost())
 	return string(bytes), err
 }
 
 func (e *BcryptEncryptor) CompareHash(hash, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
+
+func (e *BcryptEncryptor) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != e.cost()
+}
+
+func (e *BcryptEncryptor) cost() int {
+	if e.Cost == 0 {
+		return bcrypt.DefaultCost
+	}
+	return e.Cost
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func compareBcrypt(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+	return err
+}
@@ -1,6 +1,7 @@
 package auth_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/chud-lori/go-boilerplate/pkg/auth"
@@ -31,3 +32,60 @@ func TestBcryptEncryptor_CompareHash_Failure(t *testing.T) {
 	err = encryptor.CompareHash(hash, wrongPassword)
 	assert.ErrorIs(t, err, bcrypt.ErrMismatchedHashAndPassword)
 }
+
+func TestBcryptEncryptor_NeedsRehash(t *testing.T) {
+	encryptor := &auth.BcryptEncryptor{Cost: bcrypt.MinCost}
+	hash, err := encryptor.HashPassword("password")
+	assert.NoError(t, err)
+
+	assert.False(t, encryptor.NeedsRehash(hash))
+	assert.True(t, (&auth.BcryptEncryptor{Cost: bcrypt.MinCost + 1}).NeedsRehash(hash))
+}
+
+// testArgon2Params keeps the tests fast, production uses auth.DefaultArgon2Params.
+var testArgon2Params = auth.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestArgon2idEncryptor_HashAndCompare(t *testing.T) {
+	encryptor := &auth.Argon2idEncryptor{Params: testArgon2Params}
+
+	hash, err := encryptor.HashPassword("MySecurePassword123!")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	assert.NoError(t, encryptor.CompareHash(hash, "MySecurePassword123!"))
+	assert.ErrorIs(t, encryptor.CompareHash(hash, "wrong-password"), auth.ErrPasswordMismatch)
+
+	other, err := encryptor.HashPassword("MySecurePassword123!")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other, "every hash has its own salt")
+}
+
+func TestArgon2idEncryptor_VerifiesBcryptHashes(t *testing.T) {
+	hash, err := (&auth.BcryptEncryptor{Cost: bcrypt.MinCost}).HashPassword("legacy-password")
+	assert.NoError(t, err)
+	encryptor := &auth.Argon2idEncryptor{Params: testArgon2Params}
+
+	assert.NoError(t, encryptor.CompareHash(hash, "legacy-password"))
+	assert.ErrorIs(t, encryptor.CompareHash(hash, "wrong-password"), auth.ErrPasswordMismatch)
+	assert.True(t, encryptor.NeedsRehash(hash))
+}
+
+func TestArgon2idEncryptor_NeedsRehash(t *testing.T) {
+	encryptor := &auth.Argon2idEncryptor{Params: testArgon2Params}
+	hash, err := encryptor.HashPassword("password")
+	assert.NoError(t, err)
+
+	assert.False(t, encryptor.NeedsRehash(hash))
+
+	stronger := testArgon2Params
+	stronger.Iterations = 2
+	assert.True(t, (&auth.Argon2idEncryptor{Params: stronger}).NeedsRehash(hash))
+}
+
+func TestArgon2idEncryptor_UnknownFormat(t *testing.T) {
+	encryptor := &auth.Argon2idEncryptor{Params: testArgon2Params}
+
+	assert.ErrorIs(t, encryptor.CompareHash("plaintext", "plaintext"), auth.ErrUnknownHashFormat)
+	assert.ErrorIs(t, encryptor.CompareHash("$argon2id$v=19$broken", "password"), auth.ErrUnknownHashFormat)
+	assert.True(t, encryptor.NeedsRehash("$argon2id$v=19$broken"))
+}
@@ -4,41 +4,174 @@ import (
 	"errors"
 	"time"
 
+	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// JWTManager signs tokens with the asymmetric Keys when set, and falls back to
+// HS256 with SecretKey otherwise.
 type JWTManager struct {
-	SecretKey  string
-	Expiration time.Duration
+	SecretKey         string
+	Keys              *KeySet
+	Expiration        time.Duration // access token lifetime
+	RefreshExpiration time.Duration
+	// VerificationExpiration is the lifetime of email verification tokens.
+	VerificationExpiration time.Duration
+	// MFAExpiration is the lifetime of the token issued between password and second factor.
+	MFAExpiration time.Duration
+	// ImpersonationExpiration is the lifetime of the access tokens issued to impersonating admins.
+	ImpersonationExpiration time.Duration
 }
 
-func (j *JWTManager) GenerateToken(userID string) (string, error) {
-	claims := jwt.MapClaims{
+// GenerateTokenPair issues tokens for user. The access token carries the user's roles and
+// permissions; the refresh token does not, so they are looked up again on every refresh.
+func (j *JWTManager) GenerateTokenPair(user *entities.User, familyID string) (*entities.TokenPair, error) {
+	if familyID == "" {
+		familyID = uuid.NewString()
+	}
+	userID := user.ID.String()
+
+	accessToken, err := j.sign(entities.TokenTypeAccess, j.Expiration, jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(j.Expiration).Unix(),
+		"fid":     familyID,
+		"roles":   nonNil(user.Roles),
+		"perms":   nonNil(user.Permissions),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := j.sign(entities.TokenTypeRefresh, j.RefreshExpiration, jwt.MapClaims{"user_id": userID, "fid": familyID})
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.SecretKey))
+	return &entities.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// GenerateEmailVerificationToken signs a token proving ownership of email.
+// It is bound to the address, so it stops working once the user changes it.
+func (j *JWTManager) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	return j.sign(entities.TokenTypeEmailVerification, j.VerificationExpiration, jwt.MapClaims{"user_id": userID, "email": email})
+}
+
+// GenerateMFAToken signs a short-lived token for a user who still has to pass the second factor.
+func (j *JWTManager) GenerateMFAToken(userID string) (string, error) {
+	return j.sign(entities.TokenTypeMFAPending, j.MFAExpiration, jwt.MapClaims{"user_id": userID})
 }
 
-func (j *JWTManager) ValidateToken(tokenStr string) (string, error) {
-	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-		return []byte(j.SecretKey), nil
+// GenerateImpersonationToken signs an access token for user in a family of its own. The admin
+// is named in the "act" (actor) claim of RFC 8693.
+func (j *JWTManager) GenerateImpersonationToken(user *entities.User, impersonatorID string) (string, error) {
+	return j.sign(entities.TokenTypeAccess, j.ImpersonationExpiration, jwt.MapClaims{
+		"user_id": user.ID.String(),
+		"fid":     uuid.NewString(),
+		"roles":   nonNil(user.Roles),
+		"perms":   nonNil(user.Permissions),
+		"act":     map[string]interface{}{"sub": impersonatorID},
 	})
+}
+
+// sign adds the registered claims shared by every token type to claims and signs them.
+func (j *JWTManager) sign(tokenType entities.TokenType, ttl time.Duration, claims jwt.MapClaims) (string, error) {
+	now := time.Now()
+	claims["jti"] = uuid.NewString()
+	claims["typ"] = string(tokenType)
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(ttl).Unix()
+
+	if j.Keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(j.SecretKey))
+	}
+
+	key := j.Keys.signingKey()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+func (j *JWTManager) ValidateToken(tokenStr string) (*entities.TokenClaims, error) {
+	var token *jwt.Token
+	var err error
+	if j.Keys == nil {
+		token, err = jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+			return []byte(j.SecretKey), nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	} else {
+		token, err = jwt.Parse(tokenStr, j.Keys.lookup, jwt.WithValidMethods(j.Keys.methods()))
+	}
 	if err != nil || !token.Valid {
-		return "", err
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", errors.New("invalid token claims")
+		return nil, errors.New("invalid token claims")
 	}
 
 	userID, ok := claims["user_id"].(string)
 	if !ok {
-		return "", errors.New("user_id not found in token")
+		return nil, errors.New("user_id not found in token")
 	}
 
-	return userID, nil
+	result := &entities.TokenClaims{
+		UserID: userID,
+		// Tokens without a type predate refresh tokens and are treated as access tokens.
+		Type: entities.TokenTypeAccess,
+	}
+	result.TokenID, _ = claims["jti"].(string)
+	result.FamilyID, _ = claims["fid"].(string)
+	result.Email, _ = claims["email"].(string)
+	if typ, ok := claims["typ"].(string); ok {
+		result.Type = entities.TokenType(typ)
+	}
+	result.Roles = stringsClaim(claims["roles"])
+	result.Permissions = stringsClaim(claims["perms"])
+	if act, ok := claims["act"].(map[string]interface{}); ok {
+		result.ImpersonatorID, _ = act["sub"].(string)
+	}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		result.IssuedAt = iat.Time
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Time
+	}
+
+	return result, nil
+}
+
+// JWKS returns the public keys that verify tokens issued by this manager.
+// It is empty when tokens are signed with the shared HS256 secret.
+func (j *JWTManager) JWKS() []entities.JSONWebKey {
+	if j.Keys == nil {
+		return []entities.JSONWebKey{}
+	}
+	return j.Keys.JWKS()
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// stringsClaim reads a claim holding a JSON array of strings; anything else yields nil.
+func stringsClaim(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
 }
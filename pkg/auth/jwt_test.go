@@ -5,8 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/chud-lori/go-boilerplate/domain/entities"
 	"github.com/chud-lori/go-boilerplate/pkg/auth"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,15 +18,111 @@ func TestJWTManager_GenerateAndValidateToken(t *testing.T) {
 		Expiration: time.Minute,
 	}
 
-	userID := "user123"
+	user := &entities.User{
+		ID:          uuid.New(),
+		Roles:       []string{entities.RoleUser},
+		Permissions: []string{entities.PermissionUsersRead, entities.PermissionPostsWrite},
+	}
+
+	pair, err := manager.GenerateTokenPair(user, "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+
+	claims, err := manager.ValidateToken(pair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID.String(), claims.UserID)
+	assert.Equal(t, entities.TokenTypeAccess, claims.Type)
+	assert.NotEmpty(t, claims.TokenID)
+	assert.NotEmpty(t, claims.FamilyID)
+	assert.Equal(t, user.Roles, claims.Roles)
+	assert.Equal(t, user.Permissions, claims.Permissions)
+	assert.True(t, claims.HasPermission(entities.PermissionPostsWrite))
+	assert.False(t, claims.HasPermission(entities.PermissionUsersManage))
+}
+
+func TestJWTManager_GenerateTokenPair_RefreshSharesFamily(t *testing.T) {
+	manager := &auth.JWTManager{
+		SecretKey:         "supersecret",
+		Expiration:        time.Minute,
+		RefreshExpiration: time.Hour,
+	}
+
+	pair, err := manager.GenerateTokenPair(&entities.User{ID: uuid.New()}, "family-1")
+	assert.NoError(t, err)
+
+	access, err := manager.ValidateToken(pair.AccessToken)
+	assert.NoError(t, err)
+	refresh, err := manager.ValidateToken(pair.RefreshToken)
+	assert.NoError(t, err)
+
+	assert.Equal(t, entities.TokenTypeRefresh, refresh.Type)
+	assert.Equal(t, "family-1", access.FamilyID)
+	assert.Equal(t, "family-1", refresh.FamilyID)
+	assert.Empty(t, refresh.Permissions)
+	assert.NotEqual(t, access.TokenID, refresh.TokenID)
+	assert.True(t, refresh.ExpiresAt.After(access.ExpiresAt))
+}
 
-	tokenStr, err := manager.GenerateToken(userID)
+func TestJWTManager_GenerateEmailVerificationToken(t *testing.T) {
+	manager := &auth.JWTManager{
+		SecretKey:              "supersecret",
+		VerificationExpiration: time.Hour,
+	}
+
+	token, err := manager.GenerateEmailVerificationToken("user123", "user@mail.com")
 	assert.NoError(t, err)
-	assert.NotEmpty(t, tokenStr)
 
-	parsedID, err := manager.ValidateToken(tokenStr)
+	claims, err := manager.ValidateToken(token)
 	assert.NoError(t, err)
-	assert.Equal(t, userID, parsedID)
+	assert.Equal(t, "user123", claims.UserID)
+	assert.Equal(t, "user@mail.com", claims.Email)
+	assert.Equal(t, entities.TokenTypeEmailVerification, claims.Type)
+	assert.Empty(t, claims.FamilyID)
+}
+
+func TestJWTManager_GenerateMFAToken(t *testing.T) {
+	manager := &auth.JWTManager{
+		SecretKey:     "supersecret",
+		MFAExpiration: 5 * time.Minute,
+	}
+
+	token, err := manager.GenerateMFAToken("user123")
+	assert.NoError(t, err)
+
+	claims, err := manager.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", claims.UserID)
+	assert.Equal(t, entities.TokenTypeMFAPending, claims.Type)
+	assert.NotEmpty(t, claims.TokenID)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), claims.ExpiresAt, 2*time.Second)
+}
+
+func TestJWTManager_GenerateImpersonationToken(t *testing.T) {
+	manager := &auth.JWTManager{
+		SecretKey:               "supersecret",
+		Expiration:              time.Hour,
+		ImpersonationExpiration: 10 * time.Minute,
+	}
+
+	user := &entities.User{ID: uuid.New(), Permissions: []string{entities.PermissionPostsWrite}}
+	token, err := manager.GenerateImpersonationToken(user, "admin-1")
+	assert.NoError(t, err)
+
+	claims, err := manager.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID.String(), claims.UserID)
+	assert.Equal(t, "admin-1", claims.ImpersonatorID)
+	assert.True(t, claims.Impersonating())
+	assert.Equal(t, entities.TokenTypeAccess, claims.Type)
+	assert.NotEmpty(t, claims.FamilyID)
+	assert.Equal(t, user.Permissions, claims.Permissions)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), claims.ExpiresAt, 2*time.Second)
+
+	pair, err := manager.GenerateTokenPair(user, "")
+	assert.NoError(t, err)
+	claims, err = manager.ValidateToken(pair.AccessToken)
+	assert.NoError(t, err)
+	assert.False(t, claims.Impersonating())
 }
 
 func TestJWTManager_ValidateToken_InvalidSignature(t *testing.T) {
@@ -38,9 +136,9 @@ func TestJWTManager_ValidateToken_InvalidSignature(t *testing.T) {
 		Expiration: time.Minute,
 	}
 
-	tokenStr, _ := otherManager.GenerateToken("user123")
+	pair, _ := otherManager.GenerateTokenPair(&entities.User{ID: uuid.New()}, "")
 
-	_, err := manager.ValidateToken(tokenStr)
+	_, err := manager.ValidateToken(pair.AccessToken)
 	assert.Error(t, err)
 }
 
@@ -50,10 +148,10 @@ func TestJWTManager_ValidateToken_ExpiredToken(t *testing.T) {
 		Expiration: -time.Minute, // already expired
 	}
 
-	tokenStr, err := manager.GenerateToken("user123")
+	pair, err := manager.GenerateTokenPair(&entities.User{ID: uuid.New()}, "")
 	assert.NoError(t, err)
 
-	_, err = manager.ValidateToken(tokenStr)
+	_, err = manager.ValidateToken(pair.AccessToken)
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, jwt.ErrTokenExpired) || err.Error() == "token is expired")
 }
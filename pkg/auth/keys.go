@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is one entry of a KeySet. Keys loaded from a public key PEM have no
+// PrivateKey: they are retired and only verify tokens issued before the rotation.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// KeySet holds every key tokens may be signed with, indexed by kid.
+type KeySet struct {
+	keys       map[string]*SigningKey
+	signingKID string
+}
+
+// LoadKeySet reads PEM files keyed by kid. signingKID selects the key used for new
+// tokens and must refer to a private key.
+func LoadKeySet(files map[string]string, signingKID string) (*KeySet, error) {
+	keys := make([]*SigningKey, 0, len(files))
+	for kid, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %w", kid, err)
+		}
+		key, err := ParseKeyPEM(kid, data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(signingKID, keys...)
+}
+
+func NewKeySet(signingKID string, keys ...*SigningKey) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*SigningKey, len(keys)), signingKID: signingKID}
+	for _, key := range keys {
+		if _, exists := ks.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %s", key.ID)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	signing, ok := ks.keys[signingKID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q not found", signingKID)
+	}
+	if signing.PrivateKey == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", signingKID)
+	}
+
+	return ks, nil
+}
+
+// ParseKeyPEM accepts RSA or Ed25519 keys, either private (PKCS#1/PKCS#8) or public (PKIX).
+func ParseKeyPEM(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data found", kid)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unsupported PEM block %q", kid, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", kid, err)
+	}
+
+	key := &SigningKey{ID: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.PrivateKey, key.PublicKey = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.PublicKey = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.PrivateKey, key.PublicKey = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.PublicKey = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T", kid, parsed)
+	}
+
+	return key, nil
+}
+
+func (ks *KeySet) signingKey() *SigningKey {
+	return ks.keys[ks.signingKID]
+}
+
+func (ks *KeySet) lookup(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("token has no kid header")
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %s", kid)
+	}
+
+	// A kid must always be used with its own algorithm.
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", token.Method.Alg(), kid)
+	}
+
+	return key.PublicKey, nil
+}
+
+func (ks *KeySet) methods() []string {
+	seen := map[string]struct{}{}
+	var algs []string
+	for _, key := range ks.keys {
+		if _, ok := seen[key.Method.Alg()]; !ok {
+			seen[key.Method.Alg()] = struct{}{}
+			algs = append(algs, key.Method.Alg())
+		}
+	}
+	return algs
+}
+
+// JWKS returns the public half of every key, ordered by kid.
+func (ks *KeySet) JWKS() []entities.JSONWebKey {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := make([]entities.JSONWebKey, 0, len(kids))
+	for _, kid := range kids {
+		key := ks.keys[kid]
+		jwk := entities.JSONWebKey{
+			KeyID:     kid,
+			Use:       "sig",
+			Algorithm: key.Method.Alg(),
+		}
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		jwks = append(jwks, jwk)
+	}
+
+	return jwks
+}
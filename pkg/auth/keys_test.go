@@ -0,0 +1,117 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/domain/entities"
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	require.NoError(t, err)
+	return path
+}
+
+func TestJWTManager_AsymmetricKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaPath := writePEM(t, dir, "old.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+	edPath := writePEM(t, dir, "current.pem", "PRIVATE KEY", edDER)
+
+	// Tokens are signed with the RSA key first.
+	oldKeys, err := auth.LoadKeySet(map[string]string{"old": rsaPath}, "old")
+	require.NoError(t, err)
+	oldManager := &auth.JWTManager{Keys: oldKeys, Expiration: time.Minute, RefreshExpiration: time.Hour}
+	oldUser := &entities.User{ID: uuid.New()}
+	oldPair, err := oldManager.GenerateTokenPair(oldUser, "")
+	require.NoError(t, err)
+
+	// After rotation only the public half of the RSA key is kept.
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	retiredPath := writePEM(t, dir, "old.pub.pem", "PUBLIC KEY", pubDER)
+
+	keys, err := auth.LoadKeySet(map[string]string{"old": retiredPath, "current": edPath}, "current")
+	require.NoError(t, err)
+	manager := &auth.JWTManager{Keys: keys, Expiration: time.Minute, RefreshExpiration: time.Hour}
+
+	claims, err := manager.ValidateToken(oldPair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, oldUser.ID.String(), claims.UserID)
+
+	newUser := &entities.User{ID: uuid.New()}
+	newPair, err := manager.GenerateTokenPair(newUser, "")
+	require.NoError(t, err)
+	claims, err = manager.ValidateToken(newPair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, newUser.ID.String(), claims.UserID)
+
+	// The old manager does not know the new key.
+	_, err = oldManager.ValidateToken(newPair.AccessToken)
+	assert.Error(t, err)
+
+	jwks := manager.JWKS()
+	require.Len(t, jwks, 2)
+	assert.Equal(t, "current", jwks[0].KeyID)
+	assert.Equal(t, "OKP", jwks[0].KeyType)
+	assert.Equal(t, "EdDSA", jwks[0].Algorithm)
+	assert.NotEmpty(t, jwks[0].X)
+	assert.Equal(t, "old", jwks[1].KeyID)
+	assert.Equal(t, "RSA", jwks[1].KeyType)
+	assert.Equal(t, "RS256", jwks[1].Algorithm)
+	assert.Equal(t, "AQAB", jwks[1].E)
+}
+
+func TestJWTManager_AsymmetricRejectsHS256(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	key, err := auth.ParseKeyPEM("current", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}))
+	require.NoError(t, err)
+	keys, err := auth.NewKeySet("current", key)
+	require.NoError(t, err)
+
+	hsManager := &auth.JWTManager{SecretKey: "secret", Expiration: time.Minute}
+	pair, err := hsManager.GenerateTokenPair(&entities.User{ID: uuid.New()}, "")
+	require.NoError(t, err)
+
+	manager := &auth.JWTManager{Keys: keys, Expiration: time.Minute}
+	_, err = manager.ValidateToken(pair.AccessToken)
+	assert.Error(t, err)
+}
+
+func TestNewKeySet_SigningKeyMustBePrivate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	key, err := auth.ParseKeyPEM("retired", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	require.NoError(t, err)
+
+	_, err = auth.NewKeySet("retired", key)
+	assert.Error(t, err)
+}
@@ -1,33 +1,29 @@
 package auth
 
 import (
-	"strconv"
+	"crypto/rand"
+	"math/big"
 	"strings"
-	"time"
-
-	"math/rand"
 )
 
+// GeneratePasscode returns four random capital letters followed by four random digits.
+// It is used for one-time codes, so it draws from crypto/rand.
 func GeneratePasscode() string {
-	// get current ms
-	curMs := time.Now().Nanosecond() / 1000
-
-	// convert ms to str and get first 4 char
-	msStr := strconv.Itoa(curMs)[:4]
-
-	// generate random char between A and Z
-	var alphb []int
+	var sb strings.Builder
 	for i := 0; i < 4; i++ {
-		alphb = append(alphb, rand.Intn(26)+65)
+		sb.WriteRune(rune('A' + randomInt(26)))
 	}
-
-	// Convert ascii values to character and join them
-	var alphChar []string
-	for _, a := range alphb {
-		alphChar = append(alphChar, string(rune(a)))
+	for i := 0; i < 4; i++ {
+		sb.WriteRune(rune('0' + randomInt(10)))
 	}
-	alphStr := strings.Join(alphChar, "")
+	return sb.String()
+}
 
-	// combine alphabet string and ms string
-	return alphStr + msStr
+func randomInt(max int64) int64 {
+	n, err := rand.Int(rand.Reader, big.NewInt(max))
+	if err != nil {
+		// crypto/rand only fails when the OS entropy source is broken.
+		panic(err)
+	}
+	return n.Int64()
 }
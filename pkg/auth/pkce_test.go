@@ -0,0 +1,24 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPKCEChallenge(t *testing.T) {
+	// Example from RFC 7636, appendix B.
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", auth.PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestRandomToken(t *testing.T) {
+	a, err := auth.RandomToken(32)
+	require.NoError(t, err)
+	b, err := auth.RandomToken(32)
+	require.NoError(t, err)
+
+	assert.Len(t, a, 43)
+	assert.NotEqual(t, a, b)
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache keys used by the auth flows. Revocation keys are written by the auth
+// service and read by the JWT middleware, so both sides must build them the same way.
+
+func RevokedTokenKey(tokenID string) string {
+	return "auth:revoked:jti:" + tokenID
+}
+
+func RevokedFamilyKey(familyID string) string {
+	return "auth:revoked:family:" + familyID
+}
+
+// RevokedUserKey holds a unix timestamp; every token of the user issued before it is revoked.
+func RevokedUserKey(userID string) string {
+	return "auth:revoked:user:" + userID
+}
+
+func UsedRefreshTokenKey(tokenID string) string {
+	return "auth:refresh:used:" + tokenID
+}
+
+func PasswordResetKey(email string) string {
+	return "auth:password_reset:" + strings.ToLower(email)
+}
+
+// PasswordResetAttemptsKey counts the codes tried against the reset code of email.
+func PasswordResetAttemptsKey(email string) string {
+	return "auth:password_reset:attempts:" + strings.ToLower(email)
+}
+
+// IsRevokedBefore reports whether a token issued at issuedAt is not newer than the
+// revocation timestamp stored under RevokedUserKey. Tokens only carry whole seconds, so
+// one issued in the same second as the revocation is revoked too.
+func IsRevokedBefore(revokedAt string, issuedAt time.Time) bool {
+	if revokedAt == "" {
+		return false
+	}
+	ts, err := strconv.ParseInt(revokedAt, 10, 64)
+	if err != nil {
+		return false
+	}
+	return issuedAt.Unix() <= ts
+}
+
+// PasswordResetResendKey rate limits reset codes per address.
+func PasswordResetResendKey(email string) string {
+	return "auth:password_reset:resend:" + strings.ToLower(email)
+}
+
+// VerificationResendKey rate limits verification emails per address.
+func VerificationResendKey(email string) string {
+	return "auth:verify_email:resend:" + strings.ToLower(email)
+}
+
+// MFALastStepKey holds the last TOTP time step accepted for the user, so a code cannot be replayed.
+func MFALastStepKey(userID string) string {
+	return "auth:mfa:step:" + userID
+}
+
+// MFAAttemptsKey counts wrong second factor codes submitted with one mfa pending token.
+func MFAAttemptsKey(tokenID string) string {
+	return "auth:mfa:attempts:" + tokenID
+}
+
+// LoginFailuresEmailKey and LoginFailuresIPKey count the failed sign ins in one fixed window,
+// numbered from the unix epoch.
+func LoginFailuresEmailKey(email string, window int64) string {
+	return "auth:login:failures:email:" + strings.ToLower(email) + ":" + strconv.FormatInt(window, 10)
+}
+
+func LoginFailuresIPKey(ip string, window int64) string {
+	return "auth:login:failures:ip:" + ip + ":" + strconv.FormatInt(window, 10)
+}
+
+// LoginLastFailureEmailKey holds the unix milliseconds of the last failed sign in.
+func LoginLastFailureEmailKey(email string) string {
+	return "auth:login:last_failure:email:" + strings.ToLower(email)
+}
+
+// LoginLockEmailKey and LoginLockIPKey hold the unix time at which a lockout ends.
+func LoginLockEmailKey(email string) string {
+	return "auth:login:lock:email:" + strings.ToLower(email)
+}
+
+func LoginLockIPKey(ip string) string {
+	return "auth:login:lock:ip:" + ip
+}
+
+// APIKeyCacheKey caches the API key with the given hash in front of the database.
+func APIKeyCacheKey(keyHash string) string {
+	return "auth:apikey:" + keyHash
+}
+
+// SocialLoginStateKey holds the nonce and PKCE verifier of a social login until its callback.
+func SocialLoginStateKey(state string) string {
+	return "auth:oidc:state:" + state
+}
+
+// SocialLoginClaimKey marks a social login state as taken by the first callback that used it.
+func SocialLoginClaimKey(state string) string {
+	return "auth:oidc:claimed:" + state
+}
@@ -0,0 +1,22 @@
+package auth_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRevokedBefore(t *testing.T) {
+	revokedAt := time.Unix(1700000000, 0)
+	stored := strconv.FormatInt(revokedAt.Unix(), 10)
+
+	assert.True(t, auth.IsRevokedBefore(stored, revokedAt.Add(-time.Second)))
+	// A token from the same second cannot be told apart from one issued just before.
+	assert.True(t, auth.IsRevokedBefore(stored, revokedAt.Add(500*time.Millisecond)))
+	assert.False(t, auth.IsRevokedBefore(stored, revokedAt.Add(time.Second)))
+	assert.False(t, auth.IsRevokedBefore("", revokedAt))
+	assert.False(t, auth.IsRevokedBefore("not-a-timestamp", revokedAt))
+}
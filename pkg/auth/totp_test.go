@@ -0,0 +1,83 @@
+package auth_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chud-lori/go-boilerplate/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6238Secret is the SHA-1 test key from RFC 6238 appendix B.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8 digit codes; the last 6 digits are the 6 digit code.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for ts, want := range vectors {
+		code, err := auth.TOTPCode(rfc6238Secret, time.Unix(ts, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, want, code, "time %d", ts)
+	}
+}
+
+func TestValidateTOTP_AcceptsAdjacentStep(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, _ := auth.TOTPCode(rfc6238Secret, now.Add(-auth.TOTPPeriod))
+
+	step, ok := auth.ValidateTOTP(rfc6238Secret, previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(-auth.TOTPPeriod).Unix()/30, step)
+
+	tooOld, _ := auth.TOTPCode(rfc6238Secret, now.Add(-3*auth.TOTPPeriod))
+	_, ok = auth.ValidateTOTP(rfc6238Secret, tooOld, now)
+	assert.False(t, ok)
+}
+
+func TestValidateTOTP_RejectsMalformed(t *testing.T) {
+	_, ok := auth.ValidateTOTP(rfc6238Secret, "12345", time.Now())
+	assert.False(t, ok)
+
+	_, ok = auth.ValidateTOTP("not base32!", "123456", time.Now())
+	assert.False(t, ok)
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := auth.GenerateTOTPSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	code, err := auth.TOTPCode(secret, time.Now())
+	assert.NoError(t, err)
+	_, ok := auth.ValidateTOTP(secret, code, time.Now())
+	assert.True(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := auth.TOTPURI("My App", "user@mail.com", "JBSWY3DPEHPK3PXP")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/My%20App:user@mail.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=My+App")
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes := auth.GenerateRecoveryCodes(10)
+
+	assert.Len(t, codes, 10)
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-9]{5}-[a-z2-9]{5}$`, code)
+		assert.False(t, seen[code])
+		seen[code] = true
+	}
+}
@@ -0,0 +1,55 @@
+package clientip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const ContextKey string = "client_ip"
+
+// UserAgentKey holds the User-Agent header of the client, recorded with sessions.
+const UserAgentKey string = "client_user_agent"
+
+// FromRequest returns the address of the client that sent r. Forwarding headers
+// are only honoured when trustProxy is set, since any client can forge them.
+func FromRequest(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+		if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func WithIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ContextKey, ip)
+}
+
+// FromContext returns the client IP stored by WithIP, or an empty string.
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ContextKey).(string)
+	return ip
+}
+
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, UserAgentKey, userAgent)
+}
+
+// UserAgentFromContext returns the user agent stored by WithUserAgent, or an empty string.
+func UserAgentFromContext(ctx context.Context) string {
+	userAgent, _ := ctx.Value(UserAgentKey).(string)
+	return userAgent
+}
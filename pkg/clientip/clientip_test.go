@@ -0,0 +1,39 @@
+package clientip
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromRequest_RemoteAddr(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	assert.Equal(t, "203.0.113.7", FromRequest(req, false))
+}
+
+func TestFromRequest_TrustedProxyHeaders(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.2:51234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1, 10.0.0.1")
+
+	assert.Equal(t, "198.51.100.1", FromRequest(req, true))
+
+	req.Header.Del("X-Forwarded-For")
+	req.Header.Set("X-Real-IP", "198.51.100.2")
+	assert.Equal(t, "198.51.100.2", FromRequest(req, true))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, "", FromContext(context.Background()))
+	assert.Equal(t, "198.51.100.1", FromContext(WithIP(context.Background(), "198.51.100.1")))
+}
+
+func TestUserAgentFromContext(t *testing.T) {
+	assert.Equal(t, "", UserAgentFromContext(context.Background()))
+	assert.Equal(t, "curl/8.0", UserAgentFromContext(WithUserAgent(context.Background(), "curl/8.0")))
+}
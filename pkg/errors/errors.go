@@ -3,17 +3,26 @@ package errors
 import (
 	"errors"
 	"net/http"
+	"time"
 )
 
 type AppError struct {
 	Message    string
 	StatusCode int
 	Err        error
+	// RetryAfter tells the client how long to wait before retrying, sent as the Retry-After header.
+	RetryAfter time.Duration
 }
 
 var ErrUserNotFound = errors.New("user not found")
 var ErrDataNotFound = errors.New("data not found")
 
+// ErrVersionConflict is returned when a write expected a version of the data that is no longer current.
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrConflict is returned when a write would duplicate a value that has to be unique.
+var ErrConflict = errors.New("conflicts with existing data")
+
 func (e *AppError) Error() string {
 	return e.Message
 }
@@ -49,3 +58,62 @@ func NewUnauthorizedError(message string, err error) *AppError {
 		Err:        err,
 	}
 }
+
+func NewForbiddenError(message string, err error) *AppError {
+	return &AppError{
+		Message:    message,
+		StatusCode: http.StatusForbidden,
+		Err:        err,
+	}
+}
+
+func NewConflictError(message string, err error) *AppError {
+	return &AppError{
+		Message:    message,
+		StatusCode: http.StatusConflict,
+		Err:        err,
+	}
+}
+
+// NewPreconditionFailedError is a 412 error, e.g. an If-Match header naming an outdated version.
+func NewPreconditionFailedError(message string, err error) *AppError {
+	return &AppError{
+		Message:    message,
+		StatusCode: http.StatusPreconditionFailed,
+		Err:        err,
+	}
+}
+
+// NewPreconditionRequiredError is a 428 error for a conditional request sent without its condition.
+func NewPreconditionRequiredError(message string, err error) *AppError {
+	return &AppError{
+		Message:    message,
+		StatusCode: http.StatusPreconditionRequired,
+		Err:        err,
+	}
+}
+
+func NewUnsupportedMediaTypeError(message string, err error) *AppError {
+	return &AppError{
+		Message:    message,
+		StatusCode: http.StatusUnsupportedMediaType,
+		Err:        err,
+	}
+}
+
+func NewTooManyRequestsError(message string, err error) *AppError {
+	return &AppError{
+		Message:    message,
+		StatusCode: http.StatusTooManyRequests,
+		Err:        err,
+	}
+}
+
+// NewRetryLaterError is a 429 error telling the client when it may try again.
+func NewRetryLaterError(message string, retryAfter time.Duration) *AppError {
+	return &AppError{
+		Message:    message,
+		StatusCode: http.StatusTooManyRequests,
+		RetryAfter: retryAfter,
+	}
+}
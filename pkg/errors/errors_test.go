@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	appErr "github.com/chud-lori/go-boilerplate/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -31,3 +32,41 @@ func TestNewNotFoundError(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, err.StatusCode)
 	assert.Equal(t, "user not found", err.Message)
 }
+
+func TestNewForbiddenError(t *testing.T) {
+	err := appErr.NewForbiddenError("forbidden", nil)
+
+	assert.Equal(t, http.StatusForbidden, err.StatusCode)
+	assert.Equal(t, "forbidden", err.Message)
+}
+
+func TestNewConflictError(t *testing.T) {
+	err := appErr.NewConflictError("taken", appErr.ErrConflict)
+
+	assert.Equal(t, http.StatusConflict, err.StatusCode)
+	assert.ErrorIs(t, err.Err, appErr.ErrConflict)
+}
+
+func TestNewPreconditionErrors(t *testing.T) {
+	assert.Equal(t, http.StatusPreconditionFailed, appErr.NewPreconditionFailedError("stale", nil).StatusCode)
+	assert.Equal(t, http.StatusPreconditionRequired, appErr.NewPreconditionRequiredError("missing", nil).StatusCode)
+}
+
+func TestNewUnsupportedMediaTypeError(t *testing.T) {
+	err := appErr.NewUnsupportedMediaTypeError("unsupported", nil)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, err.StatusCode)
+}
+
+func TestNewTooManyRequestsError(t *testing.T) {
+	err := appErr.NewTooManyRequestsError("slow down", nil)
+
+	assert.Equal(t, http.StatusTooManyRequests, err.StatusCode)
+}
+
+func TestNewRetryLaterError(t *testing.T) {
+	err := appErr.NewRetryLaterError("locked", 90*time.Second)
+
+	assert.Equal(t, http.StatusTooManyRequests, err.StatusCode)
+	assert.Equal(t, 90*time.Second, err.RetryAfter)
+}
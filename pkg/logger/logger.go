@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"strings"
 
@@ -36,3 +37,12 @@ func NewLogger(logLevel string) *logrus.Logger {
 }
 
 const LoggerContextKey string = "logger"
+
+// RequestIDContextKey holds the ID of the request, taken from X-Request-ID or generated.
+const RequestIDContextKey string = "request_id"
+
+// RequestIDFromContext returns the request ID stored under RequestIDContextKey, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDContextKey).(string)
+	return requestID
+}